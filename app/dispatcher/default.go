@@ -17,6 +17,7 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/task"
 	"v2ray.com/core/features/outbound"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
@@ -94,6 +95,10 @@ type DefaultDispatcher struct {
 	router routing.Router
 	policy policy.Manager
 	stats  stats.Manager
+
+	limiters    *RateLimiterGroup
+	connections *ConnectionLimiter
+	cleanup     *task.Periodic
 }
 
 func init() {
@@ -114,23 +119,74 @@ func (d *DefaultDispatcher) Init(config *Config, om outbound.Manager, router rou
 	d.router = router
 	d.policy = pm
 	d.stats = sm
+	d.limiters = NewRateLimiterGroup()
+	d.connections = NewConnectionLimiter()
+	d.cleanup = &task.Periodic{
+		Interval: time.Minute,
+		Execute: func() error {
+			// Limiters of removed or inactive users are dropped after a while.
+			d.limiters.RemoveIdle(time.Minute)
+			d.connections.RemoveExpired()
+			return nil
+		},
+	}
 	return nil
 }
 
+// getRateLimiter returns the RateLimiter shared by all connections of the user in the given direction, and a function to be called when the connection ends.
+// Users without email can't be told apart, so each of their connections gets its own RateLimiter.
+func (d *DefaultDispatcher) getRateLimiter(email string, direction string, limit policy.RateLimit) (*RateLimiter, func()) {
+	if len(email) == 0 {
+		return NewRateLimiter(limit), nil
+	}
+	return d.limiters.Acquire(email+">>>"+direction, limit)
+}
+
 // Type implements common.HasType.
 func (*DefaultDispatcher) Type() interface{} {
 	return routing.DispatcherType()
 }
 
 // Start implements common.Runnable.
-func (*DefaultDispatcher) Start() error {
-	return nil
+func (d *DefaultDispatcher) Start() error {
+	return d.cleanup.Start()
 }
 
 // Close implements common.Closable.
-func (*DefaultDispatcher) Close() error { return nil }
+func (d *DefaultDispatcher) Close() error {
+	return d.cleanup.Close()
+}
 
-func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *transport.Link) {
+// admit checks whether a new connection of the user is allowed. If the connection is subject to connection limits, it returns a function to be called when the connection ends.
+func (d *DefaultDispatcher) admit(inbound *session.Inbound) (func(), error) {
+	user := inbound.User
+	if user.Quota != nil {
+		if err := user.Quota.Check(); err != nil {
+			return nil, err
+		}
+	}
+
+	p := d.policy.ForLevel(user.Level)
+	if !p.Limit.Enabled() || len(user.Email) == 0 {
+		return nil, nil
+	}
+
+	var ip string
+	if inbound.Source.Address != nil {
+		ip = inbound.Source.Address.String()
+	}
+	release, err := d.connections.Acquire(user.Email, ip, p.Limit)
+	if err != nil {
+		if c, _ := stats.GetOrRegisterCounter(d.stats, "user>>>"+user.Email+">>>connection>>>rejected"); c != nil {
+			c.Add(1)
+		}
+		return nil, err
+	}
+	return release, nil
+}
+
+// getLink returns the links of a new connection, and a function to be called when the connection ends, if any.
+func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *transport.Link, func()) {
 	opt := pipe.OptionsFromContext(ctx)
 	uplinkReader, uplinkWriter := pipe.New(opt...)
 	downlinkReader, downlinkWriter := pipe.New(opt...)
@@ -147,33 +203,76 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 
 	sessionInbound := session.InboundFromContext(ctx)
 	var user *protocol.MemoryUser
-	if sessionInbound != nil {
+	// Traffic of looped back connections has been limited and counted when the connections were dispatched for the first time.
+	if sessionInbound != nil && session.LoopbackTagsFromContext(ctx) == nil {
 		user = sessionInbound.User
 	}
 
-	if user != nil && len(user.Email) > 0 {
+	var releases []func()
+	if user != nil {
 		p := d.policy.ForLevel(user.Level)
-		if p.Stats.UserUplink {
+		if p.Bandwidth.Uplink.Enabled() {
+			limiter, release := d.getRateLimiter(user.Email, "uplink", p.Bandwidth.Uplink)
+			inboundLink.Writer = NewRateLimitWriter(limiter, inboundLink.Writer)
+			if release != nil {
+				releases = append(releases, release)
+			}
+		}
+		if p.Bandwidth.Downlink.Enabled() {
+			limiter, release := d.getRateLimiter(user.Email, "downlink", p.Bandwidth.Downlink)
+			outboundLink.Writer = NewRateLimitWriter(limiter, outboundLink.Writer)
+			if release != nil {
+				releases = append(releases, release)
+			}
+		}
+
+		// Traffic of users with quota is always counted, as the quota is measured by the counters.
+		trackQuota := user.Quota != nil && user.Quota.Traffic() > 0
+		var uplinkCounter, downlinkCounter stats.Counter
+		if len(user.Email) > 0 && (p.Stats.UserUplink || trackQuota) {
 			name := "user>>>" + user.Email + ">>>traffic>>>uplink"
 			if c, _ := stats.GetOrRegisterCounter(d.stats, name); c != nil {
+				uplinkCounter = c
 				inboundLink.Writer = &SizeStatWriter{
 					Counter: c,
 					Writer:  inboundLink.Writer,
 				}
 			}
 		}
-		if p.Stats.UserDownlink {
+		if len(user.Email) > 0 && (p.Stats.UserDownlink || trackQuota) {
 			name := "user>>>" + user.Email + ">>>traffic>>>downlink"
 			if c, _ := stats.GetOrRegisterCounter(d.stats, name); c != nil {
+				downlinkCounter = c
 				outboundLink.Writer = &SizeStatWriter{
 					Counter: c,
 					Writer:  outboundLink.Writer,
 				}
 			}
 		}
+		if trackQuota && uplinkCounter != nil && downlinkCounter != nil {
+			user.Quota.Bind(uplinkCounter, downlinkCounter)
+		}
+
+		if user.Quota != nil && p.Quota.CloseConnection {
+			inboundLink.Writer = &QuotaWriter{
+				Quota:  user.Quota,
+				Writer: inboundLink.Writer,
+			}
+			outboundLink.Writer = &QuotaWriter{
+				Quota:  user.Quota,
+				Writer: outboundLink.Writer,
+			}
+		}
 	}
 
-	return inboundLink, outboundLink
+	if len(releases) == 0 {
+		return inboundLink, outboundLink, nil
+	}
+	return inboundLink, outboundLink, func() {
+		for _, release := range releases {
+			release()
+		}
+	}
 }
 
 func shouldOverride(result SniffResult, domainOverride []string) bool {
@@ -190,12 +289,39 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 	if !destination.IsValid() {
 		panic("Dispatcher: Invalid destination.")
 	}
+	var onEnd func()
+	// Users of looped back connections have been admitted when the connections were dispatched for the first time.
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.User != nil && session.LoopbackTagsFromContext(ctx) == nil {
+		release, err := d.admit(inbound)
+		if err != nil {
+			if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+				accessMessage.Status = log.AccessRejected
+				accessMessage.Reason = err
+				log.Record(accessMessage)
+			}
+			return nil, newError("rejected user ", inbound.User.Email).Base(err)
+		}
+		onEnd = release
+	}
+
 	ob := &session.Outbound{
 		Target: destination,
 	}
 	ctx = session.ContextWithOutbound(ctx, ob)
 
-	inbound, outbound := d.getLink(ctx)
+	inbound, outbound, release := d.getLink(ctx)
+	if onEnd == nil {
+		onEnd = release
+	} else if release != nil {
+		admitted := onEnd
+		onEnd = func() {
+			admitted()
+			release()
+		}
+	}
+	if onEnd != nil {
+		trackLink(ctx, inbound, outbound, onEnd)
+	}
 	content := session.ContentFromContext(ctx)
 	if content == nil {
 		content = new(session.Content)
@@ -0,0 +1,185 @@
+// +build !confonly
+
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/signal/done"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport"
+)
+
+type sourceIP struct {
+	active   int
+	lastSeen time.Time
+}
+
+type userConnections struct {
+	active int
+	ips    map[string]*sourceIP
+	window time.Duration
+}
+
+// removeExpired removes the source IPs that have no active connections and are not seen within the window.
+func (u *userConnections) removeExpired(now time.Time) {
+	for k, s := range u.ips {
+		if s.active == 0 && now.Sub(s.lastSeen) > u.window {
+			delete(u.ips, k)
+		}
+	}
+}
+
+// ConnectionLimiter enforces limits on concurrent connections and distinct source IPs of users.
+type ConnectionLimiter struct {
+	sync.Mutex
+	users map[string]*userConnections
+}
+
+// NewConnectionLimiter creates a new ConnectionLimiter.
+func NewConnectionLimiter() *ConnectionLimiter {
+	return &ConnectionLimiter{
+		users: make(map[string]*userConnections),
+	}
+}
+
+// Acquire registers a new connection of the user from the given source IP, which may be empty if unknown.
+// It returns a function to be called when the connection ends, or an error if the connection exceeds the limit.
+func (l *ConnectionLimiter) Acquire(email string, ip string, limit policy.Limit) (func(), error) {
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	u, found := l.users[email]
+	if !found {
+		u = &userConnections{
+			ips: make(map[string]*sourceIP),
+		}
+		l.users[email] = u
+	}
+	u.window = limit.SourceIPWindow
+	u.removeExpired(now)
+
+	if limit.Connections > 0 && u.active >= int(limit.Connections) {
+		return nil, newError("too many connections, limit: ", limit.Connections)
+	}
+
+	s := u.ips[ip]
+	if s == nil && len(ip) > 0 {
+		if limit.SourceIPs > 0 && len(u.ips) >= int(limit.SourceIPs) {
+			return nil, newError("too many source IPs, limit: ", limit.SourceIPs)
+		}
+		s = new(sourceIP)
+		u.ips[ip] = s
+	}
+
+	u.active++
+	if s != nil {
+		s.active++
+		s.lastSeen = now
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.release(email, u, s)
+		})
+	}, nil
+}
+
+func (l *ConnectionLimiter) release(email string, u *userConnections, s *sourceIP) {
+	l.Lock()
+	defer l.Unlock()
+
+	u.active--
+	if s != nil {
+		s.active--
+		s.lastSeen = time.Now()
+	}
+	if u.active == 0 && len(u.ips) == 0 {
+		delete(l.users, email)
+	}
+}
+
+// RemoveExpired removes the source IPs that are out of their window, and the users that have no connections left.
+func (l *ConnectionLimiter) RemoveExpired() {
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	for email, u := range l.users {
+		u.removeExpired(now)
+		if u.active == 0 && len(u.ips) == 0 {
+			delete(l.users, email)
+		}
+	}
+}
+
+// Len returns the number of users being tracked.
+func (l *ConnectionLimiter) Len() int {
+	l.Lock()
+	defer l.Unlock()
+	return len(l.users)
+}
+
+// connectionTracker calls a function once both directions of a link are finished, or the context is done.
+type connectionTracker struct {
+	remaining int32
+	done      *done.Instance
+}
+
+func (t *connectionTracker) finish() {
+	if atomic.AddInt32(&t.remaining, -1) == 0 {
+		t.done.Close()
+	}
+}
+
+type trackedWriter struct {
+	buf.Writer
+	tracker *connectionTracker
+	once    sync.Once
+}
+
+func (w *trackedWriter) finish() {
+	w.once.Do(w.tracker.finish)
+}
+
+// Close implements common.Closable.
+func (w *trackedWriter) Close() error {
+	w.finish()
+	return common.Close(w.Writer)
+}
+
+// Interrupt implements common.Interruptible.
+func (w *trackedWriter) Interrupt() {
+	w.finish()
+	common.Interrupt(w.Writer)
+}
+
+func trackLink(ctx context.Context, inbound *transport.Link, outbound *transport.Link, onEnd func()) {
+	tracker := &connectionTracker{
+		remaining: 2,
+		done:      done.New(),
+	}
+	inbound.Writer = &trackedWriter{
+		Writer:  inbound.Writer,
+		tracker: tracker,
+	}
+	outbound.Writer = &trackedWriter{
+		Writer:  outbound.Writer,
+		tracker: tracker,
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-tracker.done.Wait():
+		}
+		onEnd()
+	}()
+}
@@ -0,0 +1,96 @@
+package dispatcher_test
+
+import (
+	"testing"
+	"time"
+
+	. "v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common"
+	"v2ray.com/core/features/policy"
+)
+
+func TestConnectionLimit(t *testing.T) {
+	limiter := NewConnectionLimiter()
+	limit := policy.Limit{
+		Connections: 2,
+	}
+
+	r1, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit)
+	common.Must(err)
+	_, err = limiter.Acquire("test@v2ray.com", "127.0.0.1", limit)
+	common.Must(err)
+	if _, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit); err == nil {
+		t.Error("expect error for the 3rd connection")
+	}
+	if _, err := limiter.Acquire("another@v2ray.com", "127.0.0.1", limit); err != nil {
+		t.Error("expect no error for another user, but got ", err)
+	}
+
+	r1()
+	r1()
+	if _, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit); err != nil {
+		t.Error("expect no error after a connection is released, but got ", err)
+	}
+	if _, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit); err == nil {
+		t.Error("expect error for the 3rd connection")
+	}
+}
+
+func TestSourceIPLimit(t *testing.T) {
+	limiter := NewConnectionLimiter()
+	limit := policy.Limit{
+		SourceIPs:      1,
+		SourceIPWindow: 100 * time.Millisecond,
+	}
+
+	r1, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit)
+	common.Must(err)
+	r2, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit)
+	common.Must(err)
+	if _, err := limiter.Acquire("test@v2ray.com", "127.0.0.2", limit); err == nil {
+		t.Error("expect error for the 2nd source IP")
+	}
+
+	r1()
+	r2()
+	if _, err := limiter.Acquire("test@v2ray.com", "127.0.0.2", limit); err == nil {
+		t.Error("expect error for the 2nd source IP within window")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if _, err := limiter.Acquire("test@v2ray.com", "127.0.0.2", limit); err != nil {
+		t.Error("expect no error after window, but got ", err)
+	}
+}
+
+func TestConnectionLimiterRemoveExpired(t *testing.T) {
+	limiter := NewConnectionLimiter()
+	limit := policy.Limit{
+		SourceIPs:      1,
+		SourceIPWindow: 100 * time.Millisecond,
+	}
+
+	r1, err := limiter.Acquire("test@v2ray.com", "127.0.0.1", limit)
+	common.Must(err)
+	r2, err := limiter.Acquire("another@v2ray.com", "127.0.0.1", limit)
+	common.Must(err)
+	r1()
+
+	limiter.RemoveExpired()
+	if n := limiter.Len(); n != 2 {
+		t.Error("expect 2 users within window, but got ", n)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	limiter.RemoveExpired()
+	if n := limiter.Len(); n != 1 {
+		t.Error("expect 1 user with active connections after window, but got ", n)
+	}
+
+	r2()
+	time.Sleep(200 * time.Millisecond)
+	limiter.RemoveExpired()
+	if n := limiter.Len(); n != 0 {
+		t.Error("expect no user after window, but got ", n)
+	}
+}
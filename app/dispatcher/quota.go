@@ -0,0 +1,34 @@
+// +build !confonly
+
+package dispatcher
+
+import (
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/protocol"
+)
+
+// QuotaWriter is a buf.Writer that fails once the user runs out of quota or expires, to close existing connections of the user.
+type QuotaWriter struct {
+	Quota  *protocol.Quota
+	Writer buf.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *QuotaWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := w.Quota.Check(); err != nil {
+		buf.ReleaseMulti(mb)
+		return err
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+// Close implements common.Closable.
+func (w *QuotaWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+// Interrupt implements common.Interruptible.
+func (w *QuotaWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
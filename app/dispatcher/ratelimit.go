@@ -0,0 +1,181 @@
+// +build !confonly
+
+package dispatcher
+
+import (
+	"sync"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/signal/done"
+	"v2ray.com/core/features/policy"
+)
+
+// RateLimiter is a token bucket that limits the throughput of one or more connections.
+type RateLimiter struct {
+	sync.Mutex
+	rate   int64
+	burst  int64
+	tokens int64
+	last   time.Time
+}
+
+// NewRateLimiter creates a new RateLimiter with a full bucket.
+func NewRateLimiter(limit policy.RateLimit) *RateLimiter {
+	l := &RateLimiter{
+		last: time.Now(),
+	}
+	l.SetLimit(limit)
+	l.tokens = l.burst
+	return l
+}
+
+// SetLimit changes the rate and burst of this RateLimiter. Tokens already in the bucket are kept up to the new burst.
+func (l *RateLimiter) SetLimit(limit policy.RateLimit) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.rate = limit.BytesPerSecond
+	l.burst = limit.Burst
+	if l.burst <= 0 {
+		l.burst = l.rate
+	}
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// Reserve takes n tokens from the bucket, and returns the duration to wait before the n bytes may be sent.
+func (l *RateLimiter) Reserve(n int64) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		// Compare with the time to fill the bucket first, to avoid overflow after a long idle period.
+		if full := time.Duration(l.burst-l.tokens) * time.Second / time.Duration(l.rate); elapsed >= full {
+			l.tokens = l.burst
+		} else {
+			l.tokens += int64(elapsed) * l.rate / int64(time.Second)
+		}
+	}
+	l.last = now
+	l.tokens -= n
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens) * time.Second / time.Duration(l.rate)
+}
+
+// RateLimitWriter is a buf.Writer that delays writes to keep the throughput under the limit of a RateLimiter.
+type RateLimitWriter struct {
+	Limiter *RateLimiter
+	Writer  buf.Writer
+	done    *done.Instance
+}
+
+// NewRateLimitWriter creates a new RateLimitWriter.
+func NewRateLimitWriter(limiter *RateLimiter, writer buf.Writer) *RateLimitWriter {
+	return &RateLimitWriter{
+		Limiter: limiter,
+		Writer:  writer,
+		done:    done.New(),
+	}
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *RateLimitWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if d := w.Limiter.Reserve(int64(mb.Len())); d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-w.done.Wait():
+			timer.Stop()
+			buf.ReleaseMulti(mb)
+			return newError("rate limited writer closed")
+		}
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+// Close implements common.Closable.
+func (w *RateLimitWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+// Interrupt implements common.Interruptible.
+func (w *RateLimitWriter) Interrupt() {
+	w.done.Close()
+	common.Interrupt(w.Writer)
+}
+
+type sharedRateLimiter struct {
+	limiter  *RateLimiter
+	refs     int
+	lastUsed time.Time
+}
+
+// RateLimiterGroup keeps the RateLimiters shared by connections of the same name, until they are unused for a while.
+type RateLimiterGroup struct {
+	sync.Mutex
+	limiters map[string]*sharedRateLimiter
+}
+
+// NewRateLimiterGroup creates a new RateLimiterGroup.
+func NewRateLimiterGroup() *RateLimiterGroup {
+	return &RateLimiterGroup{
+		limiters: make(map[string]*sharedRateLimiter),
+	}
+}
+
+// Acquire returns the RateLimiter of the name with its limit updated. It returns a function to be called when the RateLimiter is no longer used by the caller.
+func (g *RateLimiterGroup) Acquire(name string, limit policy.RateLimit) (*RateLimiter, func()) {
+	g.Lock()
+	defer g.Unlock()
+
+	s, found := g.limiters[name]
+	if found {
+		s.limiter.SetLimit(limit)
+	} else {
+		s = &sharedRateLimiter{
+			limiter: NewRateLimiter(limit),
+		}
+		g.limiters[name] = s
+	}
+	s.refs++
+
+	var once sync.Once
+	return s.limiter, func() {
+		once.Do(func() {
+			g.Lock()
+			defer g.Unlock()
+			s.refs--
+			s.lastUsed = time.Now()
+		})
+	}
+}
+
+// RemoveIdle removes the RateLimiters that are not used for the given duration.
+func (g *RateLimiterGroup) RemoveIdle(idle time.Duration) {
+	g.Lock()
+	defer g.Unlock()
+
+	now := time.Now()
+	for name, s := range g.limiters {
+		if s.refs == 0 && now.Sub(s.lastUsed) >= idle {
+			delete(g.limiters, name)
+		}
+	}
+}
+
+// Len returns the number of RateLimiters in the group.
+func (g *RateLimiterGroup) Len() int {
+	g.Lock()
+	defer g.Unlock()
+	return len(g.limiters)
+}
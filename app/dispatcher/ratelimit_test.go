@@ -0,0 +1,78 @@
+package dispatcher_test
+
+import (
+	"testing"
+	"time"
+
+	. "v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/features/policy"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(policy.RateLimit{
+		BytesPerSecond: 1000,
+		Burst:          500,
+	})
+
+	if d := limiter.Reserve(500); d != 0 {
+		t.Error("expect no wait within burst, but got ", d)
+	}
+	if d := limiter.Reserve(500); d < 400*time.Millisecond || d > 500*time.Millisecond {
+		t.Error("expect about 500ms wait, but got ", d)
+	}
+}
+
+func TestRateLimitWriter(t *testing.T) {
+	writer := NewRateLimitWriter(NewRateLimiter(policy.RateLimit{
+		BytesPerSecond: 8192,
+	}), buf.Discard)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		b := buf.New()
+		b.Extend(buf.Size)
+		common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	}
+	if d := time.Since(start); d < 400*time.Millisecond {
+		t.Error("expect writes to be delayed by about 500ms, but took ", d)
+	}
+
+	writer.Interrupt()
+	b := buf.New()
+	b.Extend(buf.Size)
+	if err := writer.WriteMultiBuffer(buf.MultiBuffer{b}); err == nil {
+		t.Error("expect error after interrupt")
+	}
+}
+
+func TestRateLimiterGroup(t *testing.T) {
+	group := NewRateLimiterGroup()
+	limit := policy.RateLimit{
+		BytesPerSecond: 1000,
+	}
+
+	l1, release1 := group.Acquire("test@v2ray.com>>>uplink", limit)
+	l2, release2 := group.Acquire("test@v2ray.com>>>uplink", limit)
+	if l1 != l2 {
+		t.Error("expect the same limiter for the same name")
+	}
+
+	release1()
+	group.RemoveIdle(0)
+	if n := group.Len(); n != 1 {
+		t.Error("expect limiter in use to be kept, but got ", n, " limiters")
+	}
+
+	release2()
+	release2()
+	group.RemoveIdle(time.Hour)
+	if n := group.Len(); n != 1 {
+		t.Error("expect recently used limiter to be kept, but got ", n, " limiters")
+	}
+	group.RemoveIdle(0)
+	if n := group.Len(); n != 0 {
+		t.Error("expect idle limiter to be removed, but got ", n, " limiters")
+	}
+}
@@ -0,0 +1,78 @@
+// +build !confonly
+
+package command
+
+//go:generate errorgen
+
+import (
+	"context"
+
+	grpc "google.golang.org/grpc"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/common"
+	feature_policy "v2ray.com/core/features/policy"
+)
+
+// policyServer is an implementation of PolicyService.
+type policyServer struct {
+	policy feature_policy.Manager
+}
+
+func NewPolicyServer(manager feature_policy.Manager) PolicyServiceServer {
+	return &policyServer{
+		policy: manager,
+	}
+}
+
+func (s *policyServer) getInstance() (*policy.Instance, error) {
+	instance, ok := s.policy.(*policy.Instance)
+	if !ok {
+		return nil, newError("PolicyService only works with its own policy.Instance.")
+	}
+	return instance, nil
+}
+
+func (s *policyServer) GetPolicy(ctx context.Context, request *GetPolicyRequest) (*GetPolicyResponse, error) {
+	instance, err := s.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	return &GetPolicyResponse{
+		Policy: instance.GetPolicy(request.Level),
+	}, nil
+}
+
+func (s *policyServer) SetPolicy(ctx context.Context, request *SetPolicyRequest) (*SetPolicyResponse, error) {
+	if request.Policy == nil {
+		return nil, newError("policy is not specified")
+	}
+	instance, err := s.getInstance()
+	if err != nil {
+		return nil, err
+	}
+	return &SetPolicyResponse{
+		Policy: instance.OverridePolicy(request.Level, request.Policy),
+	}, nil
+}
+
+type service struct {
+	policyManager feature_policy.Manager
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterPolicyServiceServer(server, NewPolicyServer(s.policyManager))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(pm feature_policy.Manager) {
+			s.policyManager = pm
+		})
+
+		return s, nil
+	}))
+}
@@ -0,0 +1,360 @@
+package command
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+	policy "v2ray.com/core/app/policy"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type GetPolicyRequest struct {
+	// User level of the policy.
+	Level                uint32   `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPolicyRequest) Reset()         { *m = GetPolicyRequest{} }
+func (m *GetPolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPolicyRequest) ProtoMessage()    {}
+func (*GetPolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{0}
+}
+
+func (m *GetPolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPolicyRequest.Unmarshal(m, b)
+}
+func (m *GetPolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPolicyRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPolicyRequest.Merge(m, src)
+}
+func (m *GetPolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPolicyRequest.Size(m)
+}
+func (m *GetPolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPolicyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPolicyRequest proto.InternalMessageInfo
+
+func (m *GetPolicyRequest) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+type GetPolicyResponse struct {
+	// Effective policy of the level.
+	Policy               *policy.Policy `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetPolicyResponse) Reset()         { *m = GetPolicyResponse{} }
+func (m *GetPolicyResponse) String() string { return proto.CompactTextString(m) }
+func (*GetPolicyResponse) ProtoMessage()    {}
+func (*GetPolicyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{1}
+}
+
+func (m *GetPolicyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPolicyResponse.Unmarshal(m, b)
+}
+func (m *GetPolicyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPolicyResponse.Marshal(b, m, deterministic)
+}
+func (m *GetPolicyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPolicyResponse.Merge(m, src)
+}
+func (m *GetPolicyResponse) XXX_Size() int {
+	return xxx_messageInfo_GetPolicyResponse.Size(m)
+}
+func (m *GetPolicyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPolicyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPolicyResponse proto.InternalMessageInfo
+
+func (m *GetPolicyResponse) GetPolicy() *policy.Policy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+type SetPolicyRequest struct {
+	// User level of the policy.
+	Level uint32 `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	// Settings to override. Settings not present are kept unchanged.
+	Policy               *policy.Policy `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SetPolicyRequest) Reset()         { *m = SetPolicyRequest{} }
+func (m *SetPolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*SetPolicyRequest) ProtoMessage()    {}
+func (*SetPolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{2}
+}
+
+func (m *SetPolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPolicyRequest.Unmarshal(m, b)
+}
+func (m *SetPolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPolicyRequest.Marshal(b, m, deterministic)
+}
+func (m *SetPolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPolicyRequest.Merge(m, src)
+}
+func (m *SetPolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_SetPolicyRequest.Size(m)
+}
+func (m *SetPolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPolicyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPolicyRequest proto.InternalMessageInfo
+
+func (m *SetPolicyRequest) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *SetPolicyRequest) GetPolicy() *policy.Policy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+type SetPolicyResponse struct {
+	// Effective policy of the level after the change.
+	Policy               *policy.Policy `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SetPolicyResponse) Reset()         { *m = SetPolicyResponse{} }
+func (m *SetPolicyResponse) String() string { return proto.CompactTextString(m) }
+func (*SetPolicyResponse) ProtoMessage()    {}
+func (*SetPolicyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{3}
+}
+
+func (m *SetPolicyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPolicyResponse.Unmarshal(m, b)
+}
+func (m *SetPolicyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPolicyResponse.Marshal(b, m, deterministic)
+}
+func (m *SetPolicyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPolicyResponse.Merge(m, src)
+}
+func (m *SetPolicyResponse) XXX_Size() int {
+	return xxx_messageInfo_SetPolicyResponse.Size(m)
+}
+func (m *SetPolicyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPolicyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPolicyResponse proto.InternalMessageInfo
+
+func (m *SetPolicyResponse) GetPolicy() *policy.Policy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+type Config struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{4}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*GetPolicyRequest)(nil), "v2ray.core.app.policy.command.GetPolicyRequest")
+	proto.RegisterType((*GetPolicyResponse)(nil), "v2ray.core.app.policy.command.GetPolicyResponse")
+	proto.RegisterType((*SetPolicyRequest)(nil), "v2ray.core.app.policy.command.SetPolicyRequest")
+	proto.RegisterType((*SetPolicyResponse)(nil), "v2ray.core.app.policy.command.SetPolicyResponse")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.command.Config")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/app/policy/command/command.proto", fileDescriptor_234074e3b0806ec7)
+}
+
+var fileDescriptor_234074e3b0806ec7 = []byte{
+	// 274 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2f, 0x33, 0x2a, 0x4a,
+	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0x2c, 0x28, 0xd0, 0x2f,
+	0xc8, 0xcf, 0xc9, 0x4c, 0xae, 0xd4, 0x4f, 0xce, 0xcf, 0xcd, 0x4d, 0xcc, 0x4b, 0x81, 0xd1, 0x7a,
+	0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0xb2, 0x30, 0x0d, 0x45, 0xa9, 0x7a, 0x89, 0x05, 0x05, 0x7a,
+	0x10, 0xc5, 0x7a, 0x50, 0x45, 0x52, 0x6a, 0xf8, 0xcc, 0xcb, 0x4b, 0xcb, 0x4c, 0x87, 0x18, 0xa3,
+	0xa4, 0xc1, 0x25, 0xe0, 0x9e, 0x5a, 0x12, 0x00, 0x96, 0x09, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d, 0x2e,
+	0x11, 0x12, 0xe1, 0x62, 0xcd, 0x49, 0x2d, 0x4b, 0xcd, 0x91, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0d,
+	0x82, 0x70, 0x94, 0xbc, 0xb8, 0x04, 0x91, 0x54, 0x16, 0x17, 0xe4, 0xe7, 0x15, 0xa7, 0x0a, 0x99,
+	0x72, 0xb1, 0x41, 0x4c, 0x05, 0xab, 0xe5, 0x36, 0x92, 0xd5, 0xc3, 0xee, 0x2c, 0xa8, 0x36, 0xa8,
+	0x62, 0xa5, 0x78, 0x2e, 0x81, 0x60, 0xa2, 0x6c, 0x45, 0xb2, 0x80, 0x89, 0x14, 0x0b, 0xbc, 0xb8,
+	0x04, 0x83, 0xa9, 0xe5, 0x58, 0x0e, 0x2e, 0x36, 0x67, 0x70, 0x90, 0x19, 0x7d, 0x66, 0xe4, 0xe2,
+	0x85, 0x48, 0x06, 0xa7, 0x16, 0x95, 0x65, 0x26, 0xa7, 0x0a, 0x15, 0x70, 0x71, 0xc2, 0x03, 0x45,
+	0x48, 0x5f, 0x0f, 0x6f, 0x9c, 0xe8, 0xa1, 0x07, 0xb4, 0x94, 0x01, 0xf1, 0x1a, 0x20, 0x5e, 0x50,
+	0x62, 0x00, 0xd9, 0x18, 0x4c, 0xb4, 0x8d, 0xc1, 0xa4, 0xda, 0x18, 0x8c, 0x69, 0xa3, 0x93, 0x1f,
+	0x97, 0x62, 0x72, 0x7e, 0x2e, 0x7e, 0x8d, 0x01, 0x8c, 0x51, 0xec, 0x50, 0xe6, 0x2a, 0x26, 0xd9,
+	0x30, 0xa3, 0xa0, 0xc4, 0x4a, 0x3d, 0x67, 0x90, 0x52, 0xc7, 0x82, 0x02, 0x68, 0x78, 0xea, 0x39,
+	0x43, 0xe4, 0x93, 0xd8, 0xc0, 0x29, 0xcf, 0x18, 0x30, 0x00, 0xca, 0x2a, 0x6b, 0x25, 0xf3, 0x02,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PolicyServiceClient is the client API for PolicyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PolicyServiceClient interface {
+	GetPolicy(ctx context.Context, in *GetPolicyRequest, opts ...grpc.CallOption) (*GetPolicyResponse, error)
+	SetPolicy(ctx context.Context, in *SetPolicyRequest, opts ...grpc.CallOption) (*SetPolicyResponse, error)
+}
+
+type policyServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPolicyServiceClient(cc *grpc.ClientConn) PolicyServiceClient {
+	return &policyServiceClient{cc}
+}
+
+func (c *policyServiceClient) GetPolicy(ctx context.Context, in *GetPolicyRequest, opts ...grpc.CallOption) (*GetPolicyResponse, error) {
+	out := new(GetPolicyResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.policy.command.PolicyService/GetPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyServiceClient) SetPolicy(ctx context.Context, in *SetPolicyRequest, opts ...grpc.CallOption) (*SetPolicyResponse, error) {
+	out := new(SetPolicyResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.policy.command.PolicyService/SetPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PolicyServiceServer is the server API for PolicyService service.
+type PolicyServiceServer interface {
+	GetPolicy(context.Context, *GetPolicyRequest) (*GetPolicyResponse, error)
+	SetPolicy(context.Context, *SetPolicyRequest) (*SetPolicyResponse, error)
+}
+
+func RegisterPolicyServiceServer(s *grpc.Server, srv PolicyServiceServer) {
+	s.RegisterService(&_PolicyService_serviceDesc, srv)
+}
+
+func _PolicyService_GetPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyServiceServer).GetPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.policy.command.PolicyService/GetPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyServiceServer).GetPolicy(ctx, req.(*GetPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyService_SetPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyServiceServer).SetPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.policy.command.PolicyService/SetPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyServiceServer).SetPolicy(ctx, req.(*SetPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PolicyService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.app.policy.command.PolicyService",
+	HandlerType: (*PolicyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPolicy",
+			Handler:    _PolicyService_GetPolicy_Handler,
+		},
+		{
+			MethodName: "SetPolicy",
+			Handler:    _PolicyService_SetPolicy_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2ray.com/core/app/policy/command/command.proto",
+}
@@ -0,0 +1,59 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core/app/policy"
+	. "v2ray.com/core/app/policy/command"
+	"v2ray.com/core/common"
+)
+
+func TestSetPolicy(t *testing.T) {
+	m, err := policy.New(context.Background(), &policy.Config{
+		Level: map[uint32]*policy.Policy{
+			0: {
+				Timeout: &policy.Policy_Timeout{
+					Handshake: &policy.Second{Value: 2},
+				},
+			},
+		},
+	})
+	common.Must(err)
+
+	s := NewPolicyServer(m)
+
+	resp, err := s.SetPolicy(context.Background(), &SetPolicyRequest{
+		Level: 0,
+		Policy: &policy.Policy{
+			Timeout: &policy.Policy_Timeout{
+				ConnectionIdle: &policy.Second{Value: 30},
+			},
+			Stats: &policy.Policy_Stats{
+				UserUplink: true,
+			},
+		},
+	})
+	common.Must(err)
+	if v := resp.Policy.Timeout.Handshake.Value; v != 2 {
+		t.Error("expect handshake timeout to be kept, but got ", v)
+	}
+
+	p := m.ForLevel(0)
+	if p.Timeouts.ConnectionIdle != 30*time.Second {
+		t.Error("expect 30 sec idle timeout, but got ", p.Timeouts.ConnectionIdle)
+	}
+	if p.Timeouts.Handshake != 2*time.Second {
+		t.Error("expect 2 sec handshake timeout, but got ", p.Timeouts.Handshake)
+	}
+	if !p.Stats.UserUplink {
+		t.Error("expect user uplink stats to be enabled")
+	}
+
+	getResp, err := s.GetPolicy(context.Background(), &GetPolicyRequest{Level: 1})
+	common.Must(err)
+	if v := getResp.Policy.Timeout.ConnectionIdle.Value; v != 300 {
+		t.Error("expect default idle timeout for level 1, but got ", v)
+	}
+}
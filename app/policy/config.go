@@ -49,7 +49,7 @@ func (p *Policy) overrideWith(another *Policy) {
 	if another.Timeout != nil {
 		p.Timeout.overrideWith(another.Timeout)
 	}
-	if another.Stats != nil && p.Stats == nil {
+	if another.Stats != nil {
 		p.Stats = new(Policy_Stats)
 		*p.Stats = *another.Stats
 	}
@@ -58,6 +58,34 @@ func (p *Policy) overrideWith(another *Policy) {
 			Connection: another.Buffer.Connection,
 		}
 	}
+	if another.Bandwidth != nil {
+		p.Bandwidth = new(Policy_Bandwidth)
+		*p.Bandwidth = *another.Bandwidth
+	}
+	if another.Quota != nil {
+		p.Quota = &Policy_Quota{
+			CloseConnection: another.Quota.CloseConnection,
+		}
+	}
+	if another.Limit != nil {
+		p.Limit = new(Policy_Limit)
+		*p.Limit = *another.Limit
+	}
+}
+
+// ToCoreRateLimit converts this RateLimit to policy.RateLimit.
+func (r *Policy_RateLimit) ToCoreRateLimit() policy.RateLimit {
+	if r == nil || r.Rate == 0 {
+		return policy.RateLimit{}
+	}
+	burst := r.Burst
+	if burst == 0 {
+		burst = r.Rate
+	}
+	return policy.RateLimit{
+		BytesPerSecond: int64(r.Rate),
+		Burst:          int64(burst),
+	}
 }
 
 // ToCorePolicy converts this Policy to policy.Session.
@@ -77,6 +105,18 @@ func (p *Policy) ToCorePolicy() policy.Session {
 	if p.Buffer != nil {
 		cp.Buffer.PerConnection = p.Buffer.Connection
 	}
+	if p.Bandwidth != nil {
+		cp.Bandwidth.Uplink = p.Bandwidth.Uplink.ToCoreRateLimit()
+		cp.Bandwidth.Downlink = p.Bandwidth.Downlink.ToCoreRateLimit()
+	}
+	if p.Quota != nil {
+		cp.Quota.CloseConnection = p.Quota.CloseConnection
+	}
+	if p.Limit != nil {
+		cp.Limit.Connections = p.Limit.Connection
+		cp.Limit.SourceIPs = p.Limit.SourceIp
+		cp.Limit.SourceIPWindow = p.Limit.SourceIpWindow.Duration()
+	}
 	return cp
 }
 
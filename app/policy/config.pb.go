@@ -57,12 +57,15 @@ func (m *Second) GetValue() uint32 {
 }
 
 type Policy struct {
-	Timeout              *Policy_Timeout `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Stats                *Policy_Stats   `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
-	Buffer               *Policy_Buffer  `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Timeout              *Policy_Timeout   `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Stats                *Policy_Stats     `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	Buffer               *Policy_Buffer    `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
+	Bandwidth            *Policy_Bandwidth `protobuf:"bytes,4,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	Quota                *Policy_Quota     `protobuf:"bytes,5,opt,name=quota,proto3" json:"quota,omitempty"`
+	Limit                *Policy_Limit     `protobuf:"bytes,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Policy) Reset()         { *m = Policy{} }
@@ -111,6 +114,27 @@ func (m *Policy) GetBuffer() *Policy_Buffer {
 	return nil
 }
 
+func (m *Policy) GetBandwidth() *Policy_Bandwidth {
+	if m != nil {
+		return m.Bandwidth
+	}
+	return nil
+}
+
+func (m *Policy) GetQuota() *Policy_Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+func (m *Policy) GetLimit() *Policy_Limit {
+	if m != nil {
+		return m.Limit
+	}
+	return nil
+}
+
 // Timeout is a message for timeout settings in various stages, in seconds.
 type Policy_Timeout struct {
 	Handshake            *Second  `protobuf:"bytes,1,opt,name=handshake,proto3" json:"handshake,omitempty"`
@@ -262,6 +286,201 @@ func (m *Policy_Buffer) GetConnection() int32 {
 	return 0
 }
 
+// RateLimit is a token bucket setting for one direction of traffic.
+type Policy_RateLimit struct {
+	// Sustained rate in bytes per second. 0 for unlimited.
+	Rate uint64 `protobuf:"varint,1,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Bucket size in bytes. Defaults to the rate if not set.
+	Burst                uint64   `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Policy_RateLimit) Reset()         { *m = Policy_RateLimit{} }
+func (m *Policy_RateLimit) String() string { return proto.CompactTextString(m) }
+func (*Policy_RateLimit) ProtoMessage()    {}
+func (*Policy_RateLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{1, 3}
+}
+
+func (m *Policy_RateLimit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy_RateLimit.Unmarshal(m, b)
+}
+func (m *Policy_RateLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy_RateLimit.Marshal(b, m, deterministic)
+}
+func (m *Policy_RateLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy_RateLimit.Merge(m, src)
+}
+func (m *Policy_RateLimit) XXX_Size() int {
+	return xxx_messageInfo_Policy_RateLimit.Size(m)
+}
+func (m *Policy_RateLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy_RateLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Policy_RateLimit proto.InternalMessageInfo
+
+func (m *Policy_RateLimit) GetRate() uint64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *Policy_RateLimit) GetBurst() uint64 {
+	if m != nil {
+		return m.Burst
+	}
+	return 0
+}
+
+type Policy_Bandwidth struct {
+	Uplink               *Policy_RateLimit `protobuf:"bytes,1,opt,name=uplink,proto3" json:"uplink,omitempty"`
+	Downlink             *Policy_RateLimit `protobuf:"bytes,2,opt,name=downlink,proto3" json:"downlink,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Policy_Bandwidth) Reset()         { *m = Policy_Bandwidth{} }
+func (m *Policy_Bandwidth) String() string { return proto.CompactTextString(m) }
+func (*Policy_Bandwidth) ProtoMessage()    {}
+func (*Policy_Bandwidth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{1, 4}
+}
+
+func (m *Policy_Bandwidth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy_Bandwidth.Unmarshal(m, b)
+}
+func (m *Policy_Bandwidth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy_Bandwidth.Marshal(b, m, deterministic)
+}
+func (m *Policy_Bandwidth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy_Bandwidth.Merge(m, src)
+}
+func (m *Policy_Bandwidth) XXX_Size() int {
+	return xxx_messageInfo_Policy_Bandwidth.Size(m)
+}
+func (m *Policy_Bandwidth) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy_Bandwidth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Policy_Bandwidth proto.InternalMessageInfo
+
+func (m *Policy_Bandwidth) GetUplink() *Policy_RateLimit {
+	if m != nil {
+		return m.Uplink
+	}
+	return nil
+}
+
+func (m *Policy_Bandwidth) GetDownlink() *Policy_RateLimit {
+	if m != nil {
+		return m.Downlink
+	}
+	return nil
+}
+
+type Policy_Quota struct {
+	// Whether to close existing connections of a user, once the user runs out of traffic quota or expires.
+	CloseConnection      bool     `protobuf:"varint,1,opt,name=close_connection,json=closeConnection,proto3" json:"close_connection,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Policy_Quota) Reset()         { *m = Policy_Quota{} }
+func (m *Policy_Quota) String() string { return proto.CompactTextString(m) }
+func (*Policy_Quota) ProtoMessage()    {}
+func (*Policy_Quota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{1, 5}
+}
+
+func (m *Policy_Quota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy_Quota.Unmarshal(m, b)
+}
+func (m *Policy_Quota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy_Quota.Marshal(b, m, deterministic)
+}
+func (m *Policy_Quota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy_Quota.Merge(m, src)
+}
+func (m *Policy_Quota) XXX_Size() int {
+	return xxx_messageInfo_Policy_Quota.Size(m)
+}
+func (m *Policy_Quota) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy_Quota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Policy_Quota proto.InternalMessageInfo
+
+func (m *Policy_Quota) GetCloseConnection() bool {
+	if m != nil {
+		return m.CloseConnection
+	}
+	return false
+}
+
+// Limit is a message for limits on connections of a user. Users are identified by email.
+type Policy_Limit struct {
+	// Maximum number of concurrent connections. 0 for unlimited.
+	Connection uint32 `protobuf:"varint,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	// Maximum number of distinct source IPs, among active connections and connections closed within source_ip_window. 0 for unlimited.
+	SourceIp             uint32   `protobuf:"varint,2,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	SourceIpWindow       *Second  `protobuf:"bytes,3,opt,name=source_ip_window,json=sourceIpWindow,proto3" json:"source_ip_window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Policy_Limit) Reset()         { *m = Policy_Limit{} }
+func (m *Policy_Limit) String() string { return proto.CompactTextString(m) }
+func (*Policy_Limit) ProtoMessage()    {}
+func (*Policy_Limit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{1, 6}
+}
+
+func (m *Policy_Limit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Policy_Limit.Unmarshal(m, b)
+}
+func (m *Policy_Limit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Policy_Limit.Marshal(b, m, deterministic)
+}
+func (m *Policy_Limit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Policy_Limit.Merge(m, src)
+}
+func (m *Policy_Limit) XXX_Size() int {
+	return xxx_messageInfo_Policy_Limit.Size(m)
+}
+func (m *Policy_Limit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Policy_Limit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Policy_Limit proto.InternalMessageInfo
+
+func (m *Policy_Limit) GetConnection() uint32 {
+	if m != nil {
+		return m.Connection
+	}
+	return 0
+}
+
+func (m *Policy_Limit) GetSourceIp() uint32 {
+	if m != nil {
+		return m.SourceIp
+	}
+	return 0
+}
+
+func (m *Policy_Limit) GetSourceIpWindow() *Second {
+	if m != nil {
+		return m.SourceIpWindow
+	}
+	return nil
+}
+
 type SystemPolicy struct {
 	Stats                *SystemPolicy_Stats `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
@@ -401,6 +620,10 @@ func init() {
 	proto.RegisterType((*Policy_Timeout)(nil), "v2ray.core.app.policy.Policy.Timeout")
 	proto.RegisterType((*Policy_Stats)(nil), "v2ray.core.app.policy.Policy.Stats")
 	proto.RegisterType((*Policy_Buffer)(nil), "v2ray.core.app.policy.Policy.Buffer")
+	proto.RegisterType((*Policy_RateLimit)(nil), "v2ray.core.app.policy.Policy.RateLimit")
+	proto.RegisterType((*Policy_Bandwidth)(nil), "v2ray.core.app.policy.Policy.Bandwidth")
+	proto.RegisterType((*Policy_Quota)(nil), "v2ray.core.app.policy.Policy.Quota")
+	proto.RegisterType((*Policy_Limit)(nil), "v2ray.core.app.policy.Policy.Limit")
 	proto.RegisterType((*SystemPolicy)(nil), "v2ray.core.app.policy.SystemPolicy")
 	proto.RegisterType((*SystemPolicy_Stats)(nil), "v2ray.core.app.policy.SystemPolicy.Stats")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.Config")
@@ -412,38 +635,49 @@ func init() {
 }
 
 var fileDescriptor_48f54a345c1316d1 = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdb, 0x6a, 0x13, 0x41,
-	0x1c, 0xc6, 0xd9, 0xa4, 0xbb, 0xad, 0xff, 0x24, 0x6d, 0x19, 0x2c, 0xc4, 0x05, 0x6b, 0x49, 0xad,
-	0xa4, 0x37, 0x13, 0x48, 0x6f, 0xd4, 0x6a, 0xc5, 0x78, 0x00, 0x41, 0xb1, 0x4c, 0x3c, 0xa0, 0x37,
-	0x61, 0xb3, 0x3b, 0xb1, 0x4b, 0x26, 0x33, 0xc3, 0x1e, 0x22, 0xfb, 0x1a, 0x3e, 0x46, 0x9f, 0xc6,
-	0x27, 0xf0, 0x59, 0x64, 0xe7, 0xe0, 0xa6, 0x92, 0x43, 0xef, 0x26, 0x1f, 0xbf, 0xef, 0x63, 0xbe,
-	0x7f, 0xfe, 0xb3, 0xf0, 0x68, 0xde, 0x4f, 0x82, 0x02, 0x87, 0x62, 0xd6, 0x0b, 0x45, 0x42, 0x7b,
-	0x81, 0x94, 0x3d, 0x29, 0x58, 0x1c, 0x16, 0xbd, 0x50, 0xf0, 0x49, 0xfc, 0x03, 0xcb, 0x44, 0x64,
-	0x02, 0x1d, 0x58, 0x2e, 0xa1, 0x38, 0x90, 0x12, 0x6b, 0xa6, 0x73, 0x08, 0xde, 0x90, 0x86, 0x82,
-	0x47, 0xe8, 0x2e, 0xb8, 0xf3, 0x80, 0xe5, 0xb4, 0xed, 0x1c, 0x39, 0xdd, 0x16, 0xd1, 0x3f, 0x3a,
-	0xbf, 0xb7, 0xc0, 0xbb, 0x54, 0x28, 0x7a, 0x01, 0xdb, 0x59, 0x3c, 0xa3, 0x22, 0xcf, 0x14, 0xd2,
-	0xe8, 0x9f, 0xe0, 0xa5, 0x99, 0x58, 0xf3, 0xf8, 0x93, 0x86, 0x89, 0x75, 0xa1, 0x27, 0xe0, 0xa6,
-	0x59, 0x90, 0xa5, 0xed, 0x9a, 0xb2, 0x1f, 0xaf, 0xb7, 0x0f, 0x4b, 0x94, 0x68, 0x07, 0x7a, 0x06,
-	0xde, 0x38, 0x9f, 0x4c, 0x68, 0xd2, 0xae, 0x2b, 0xef, 0xc3, 0xf5, 0xde, 0x81, 0x62, 0x89, 0xf1,
-	0xf8, 0xbf, 0x6a, 0xb0, 0x6d, 0x6e, 0x83, 0xce, 0xe1, 0xce, 0x55, 0xc0, 0xa3, 0xf4, 0x2a, 0x98,
-	0x52, 0xd3, 0xe3, 0xfe, 0x8a, 0x30, 0x3d, 0x18, 0x52, 0xf1, 0xe8, 0x2d, 0xec, 0x85, 0x82, 0x73,
-	0x1a, 0x66, 0xb1, 0xe0, 0xa3, 0x38, 0x62, 0xd4, 0x74, 0xd9, 0x10, 0xb1, 0x5b, 0xb9, 0xde, 0x45,
-	0x8c, 0xa2, 0x0b, 0x68, 0xe4, 0x92, 0xc5, 0x7c, 0x3a, 0x12, 0x9c, 0x15, 0xa6, 0xd3, 0x86, 0x0c,
-	0xd0, 0x8e, 0x8f, 0x9c, 0x15, 0x68, 0x00, 0xad, 0x48, 0xfc, 0xe4, 0x55, 0xc2, 0xd6, 0x6d, 0x12,
-	0x9a, 0xd6, 0x53, 0x66, 0xf8, 0x1f, 0xc0, 0x55, 0x23, 0x46, 0x0f, 0xa0, 0x91, 0xa7, 0x34, 0x19,
-	0xe9, 0x7c, 0x35, 0x93, 0x1d, 0x02, 0xa5, 0xf4, 0x59, 0x29, 0xe8, 0x18, 0x5a, 0x0a, 0xb0, 0x76,
-	0xd5, 0x79, 0x87, 0x34, 0x4b, 0xf1, 0xb5, 0xd1, 0xfc, 0x2e, 0x78, 0x7a, 0xea, 0xe8, 0x10, 0xa0,
-	0xaa, 0xab, 0xe2, 0x5c, 0xb2, 0xa0, 0x74, 0xae, 0x1d, 0x68, 0x0e, 0x8b, 0x34, 0xa3, 0xb3, 0x7f,
-	0x8b, 0x65, 0xf6, 0x42, 0xff, 0x1d, 0xa7, 0xab, 0x5a, 0x2c, 0x78, 0x6e, 0x6c, 0x87, 0xff, 0xcd,
-	0x56, 0x39, 0x81, 0xdd, 0x98, 0x8f, 0x45, 0xce, 0xa3, 0x9b, 0x6d, 0x5a, 0x46, 0x35, 0x85, 0x4e,
-	0x61, 0xdf, 0x62, 0xff, 0x75, 0xda, 0x33, 0xba, 0xad, 0xd5, 0xf9, 0xe3, 0x80, 0xf7, 0x4a, 0xbd,
-	0x23, 0x74, 0x01, 0x2e, 0xa3, 0x73, 0xca, 0xda, 0xce, 0x51, 0xbd, 0xdb, 0xe8, 0x77, 0x57, 0x5c,
-	0x53, 0xd3, 0xf8, 0x7d, 0x89, 0xbe, 0xe1, 0x59, 0x52, 0x10, 0x6d, 0x43, 0xe7, 0xe0, 0xa5, 0xaa,
-	0xc2, 0x86, 0xfd, 0x5f, 0xec, 0x49, 0x8c, 0xc5, 0xff, 0x0a, 0x50, 0x25, 0xa2, 0x7d, 0xa8, 0x4f,
-	0x69, 0x61, 0x5e, 0x6a, 0x79, 0x44, 0x67, 0xf6, 0xf5, 0xae, 0xdf, 0x47, 0x93, 0xaa, 0xd9, 0xa7,
-	0xb5, 0xc7, 0xce, 0xe0, 0x39, 0xdc, 0x0b, 0xc5, 0x6c, 0x39, 0x7e, 0xe9, 0x7c, 0xf7, 0xf4, 0xe9,
-	0xba, 0x76, 0xf0, 0xa5, 0x4f, 0x82, 0xb2, 0x5d, 0x42, 0xf1, 0x4b, 0x29, 0x4d, 0xd2, 0xd8, 0x53,
-	0x5f, 0x97, 0xb3, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe9, 0xde, 0xee, 0x20, 0x87, 0x04, 0x00,
-	0x00,
+	// 693 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x95, 0x61, 0x4f, 0xd3, 0x40,
+	0x18, 0xc7, 0xd3, 0x6d, 0x2d, 0xdb, 0x33, 0x06, 0xcb, 0x45, 0x92, 0x5a, 0x23, 0x92, 0x21, 0x3a,
+	0xde, 0x74, 0xc9, 0x88, 0x89, 0x8a, 0x42, 0x04, 0xd1, 0x90, 0x60, 0xc4, 0x43, 0x25, 0xfa, 0x66,
+	0xe9, 0xda, 0x43, 0x1a, 0xba, 0xbb, 0xda, 0x5e, 0x59, 0xfa, 0x21, 0x7c, 0xa1, 0xaf, 0xfd, 0x04,
+	0x7c, 0x28, 0x3f, 0x8b, 0xe9, 0xdd, 0x75, 0x1d, 0x08, 0xac, 0xbe, 0xbb, 0x3e, 0xfb, 0xff, 0xfe,
+	0xbd, 0xe7, 0xee, 0xbf, 0xa7, 0xf0, 0xe8, 0xbc, 0x1f, 0x39, 0xa9, 0xed, 0xb2, 0x51, 0xcf, 0x65,
+	0x11, 0xe9, 0x39, 0x61, 0xd8, 0x0b, 0x59, 0xe0, 0xbb, 0x69, 0xcf, 0x65, 0xf4, 0xc4, 0xff, 0x66,
+	0x87, 0x11, 0xe3, 0x0c, 0x2d, 0xe5, 0xba, 0x88, 0xd8, 0x4e, 0x18, 0xda, 0x52, 0xd3, 0x59, 0x06,
+	0xe3, 0x88, 0xb8, 0x8c, 0x7a, 0xe8, 0x0e, 0xe8, 0xe7, 0x4e, 0x90, 0x10, 0x53, 0x5b, 0xd1, 0xba,
+	0x2d, 0x2c, 0x1f, 0x3a, 0xbf, 0x1b, 0x60, 0x1c, 0x0a, 0x29, 0xda, 0x86, 0x39, 0xee, 0x8f, 0x08,
+	0x4b, 0xb8, 0x90, 0x34, 0xfb, 0x6b, 0xf6, 0xb5, 0x9e, 0xb6, 0xd4, 0xdb, 0x1f, 0xa5, 0x18, 0xe7,
+	0x14, 0x7a, 0x06, 0x7a, 0xcc, 0x1d, 0x1e, 0x9b, 0x15, 0x81, 0xaf, 0xde, 0x8e, 0x1f, 0x65, 0x52,
+	0x2c, 0x09, 0xf4, 0x02, 0x8c, 0x61, 0x72, 0x72, 0x42, 0x22, 0xb3, 0x2a, 0xd8, 0x87, 0xb7, 0xb3,
+	0x3b, 0x42, 0x8b, 0x15, 0x83, 0xf6, 0xa0, 0x31, 0x74, 0xa8, 0x37, 0xf6, 0x3d, 0x7e, 0x6a, 0xd6,
+	0x84, 0xc1, 0xe3, 0x19, 0x06, 0xb9, 0x1c, 0x17, 0x64, 0xb6, 0xff, 0xef, 0x09, 0xe3, 0x8e, 0xa9,
+	0x97, 0xd9, 0xff, 0x87, 0x4c, 0x8a, 0x25, 0x91, 0xa1, 0x81, 0x3f, 0xf2, 0xb9, 0x69, 0x94, 0x41,
+	0x0f, 0x32, 0x29, 0x96, 0x84, 0xf5, 0xab, 0x02, 0x73, 0xea, 0x28, 0xd1, 0x26, 0x34, 0x4e, 0x1d,
+	0xea, 0xc5, 0xa7, 0xce, 0x19, 0x51, 0x97, 0x70, 0xff, 0x06, 0x2b, 0x79, 0xab, 0xb8, 0xd0, 0xa3,
+	0x37, 0xb0, 0xe8, 0x32, 0x4a, 0x89, 0xcb, 0x7d, 0x46, 0x07, 0xbe, 0x17, 0x10, 0xb3, 0x52, 0xc6,
+	0x62, 0xa1, 0xa0, 0xf6, 0xbd, 0x80, 0xa0, 0x2d, 0x68, 0x26, 0x61, 0xe0, 0xd3, 0xb3, 0x01, 0xa3,
+	0x41, 0x6a, 0x56, 0xcb, 0x78, 0x80, 0x24, 0xde, 0xd3, 0x20, 0x45, 0x3b, 0xd0, 0xf2, 0xd8, 0x98,
+	0x16, 0x0e, 0xb5, 0x32, 0x0e, 0xf3, 0x39, 0x93, 0x79, 0x58, 0xef, 0x40, 0x17, 0xf9, 0x40, 0x0f,
+	0xa0, 0x99, 0xc4, 0x24, 0x1a, 0x48, 0x7f, 0x71, 0x26, 0x75, 0x0c, 0x59, 0xe9, 0x93, 0xa8, 0xa0,
+	0x55, 0x68, 0x09, 0x41, 0x8e, 0x8b, 0x9e, 0xeb, 0x78, 0x3e, 0x2b, 0xbe, 0x56, 0x35, 0xab, 0x0b,
+	0x86, 0x8c, 0x0c, 0x5a, 0x06, 0x28, 0xda, 0x15, 0x76, 0x3a, 0x9e, 0xaa, 0x58, 0x4f, 0xa0, 0x81,
+	0x1d, 0x4e, 0xc4, 0x0d, 0x21, 0x04, 0xb5, 0xc8, 0xe1, 0xf2, 0x26, 0x6a, 0x58, 0xac, 0xb3, 0xbf,
+	0xd1, 0x30, 0x89, 0x62, 0x2e, 0xde, 0x53, 0xc3, 0xf2, 0xc1, 0xfa, 0xa9, 0x41, 0x63, 0x92, 0x29,
+	0xb4, 0x0d, 0xc6, 0xd4, 0x7e, 0x67, 0x86, 0x71, 0xf2, 0x42, 0xac, 0x30, 0xb4, 0x0b, 0xf5, 0x4b,
+	0xfd, 0xfc, 0x87, 0xc5, 0x04, 0xb4, 0xfa, 0xa0, 0x8b, 0x8c, 0xa2, 0x75, 0x68, 0xbb, 0x01, 0x8b,
+	0xc9, 0xe0, 0x4a, 0xe7, 0x75, 0xbc, 0x28, 0xea, 0xbb, 0x45, 0xfb, 0x3f, 0x34, 0xd0, 0x65, 0xef,
+	0xff, 0x1e, 0x54, 0x6b, 0xfa, 0xa0, 0xd0, 0x3d, 0x68, 0xc4, 0x2c, 0x89, 0x5c, 0x32, 0xf0, 0x43,
+	0xb1, 0xc7, 0x16, 0xae, 0xcb, 0xc2, 0x7e, 0x88, 0xde, 0x42, 0x7b, 0xf2, 0xe3, 0x60, 0xec, 0x53,
+	0x8f, 0x8d, 0xcb, 0xe5, 0x68, 0x21, 0xb7, 0x38, 0x16, 0x50, 0xe7, 0x42, 0x83, 0xf9, 0xa3, 0x34,
+	0xe6, 0x64, 0x34, 0x19, 0x52, 0x6a, 0xc6, 0xc8, 0x93, 0x5d, 0xbf, 0xc9, 0x6e, 0x8a, 0xb9, 0x34,
+	0x69, 0xac, 0x2f, 0x79, 0xb2, 0xd6, 0x60, 0xc1, 0xa7, 0x43, 0x96, 0x50, 0xef, 0x72, 0xb8, 0x5a,
+	0xaa, 0xaa, 0xf2, 0xb5, 0x0e, 0xed, 0x5c, 0x76, 0x25, 0x62, 0x8b, 0xaa, 0x9e, 0xa7, 0xac, 0xf3,
+	0x47, 0x03, 0x63, 0x57, 0xcc, 0x64, 0xb4, 0x05, 0x7a, 0x40, 0xce, 0x49, 0x60, 0x6a, 0x2b, 0xd5,
+	0x6e, 0xb3, 0xdf, 0xbd, 0x61, 0x9b, 0x52, 0x6d, 0x1f, 0x64, 0xd2, 0x3d, 0xca, 0xa3, 0x14, 0x4b,
+	0x0c, 0x6d, 0x82, 0x11, 0x8b, 0x16, 0x66, 0xcc, 0xd2, 0xe9, 0x3e, 0xb1, 0x42, 0xac, 0x63, 0x80,
+	0xc2, 0x11, 0xb5, 0xa1, 0x7a, 0x46, 0x52, 0x75, 0x83, 0xd9, 0x12, 0x6d, 0xe4, 0x5f, 0x82, 0xdb,
+	0xc7, 0x83, 0x72, 0x95, 0xda, 0xe7, 0x95, 0xa7, 0xda, 0xce, 0x4b, 0xb8, 0xeb, 0xb2, 0xd1, 0xf5,
+	0xf2, 0x43, 0xed, 0xab, 0x21, 0x57, 0x17, 0x95, 0xa5, 0xcf, 0x7d, 0xec, 0x64, 0xdd, 0x45, 0xc4,
+	0x7e, 0x15, 0x86, 0xca, 0x69, 0x68, 0x88, 0x2f, 0xd5, 0xc6, 0xdf, 0x01, 0x00, 0xda, 0xeb, 0xe6,
+	0xe8, 0xd3, 0x06, 0x00, 0x00,
 }
@@ -2,6 +2,9 @@ package policy
 
 import (
 	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/features/policy"
@@ -9,6 +12,7 @@ import (
 
 // Instance is an instance of Policy manager.
 type Instance struct {
+	access sync.RWMutex
 	levels map[uint32]*Policy
 	system *SystemPolicy
 }
@@ -37,12 +41,44 @@ func (*Instance) Type() interface{} {
 
 // ForLevel implements policy.Manager.
 func (m *Instance) ForLevel(level uint32) policy.Session {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
 	if p, ok := m.levels[level]; ok {
 		return p.ToCorePolicy()
 	}
 	return policy.SessionDefault()
 }
 
+// GetPolicy returns a copy of the effective Policy for the given user level.
+func (m *Instance) GetPolicy(level uint32) *Policy {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	if p, ok := m.levels[level]; ok {
+		return proto.Clone(p).(*Policy)
+	}
+	return defaultPolicy()
+}
+
+// OverridePolicy overrides the Policy of the given user level with the settings in p. Settings not in p are kept.
+// The new Policy applies to sessions created afterwards. It returns a copy of the new effective Policy.
+func (m *Instance) OverridePolicy(level uint32, p *Policy) *Policy {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	pp, ok := m.levels[level]
+	if ok {
+		pp = proto.Clone(pp).(*Policy)
+	} else {
+		pp = defaultPolicy()
+	}
+	pp.overrideWith(p)
+	m.levels[level] = pp
+
+	return proto.Clone(pp).(*Policy)
+}
+
 // ForSystem implements policy.Manager.
 func (m *Instance) ForSystem() policy.System {
 	if m.system == nil {
@@ -19,6 +19,11 @@ func TestPolicy(t *testing.T) {
 						Value: 2,
 					},
 				},
+				Bandwidth: &Policy_Bandwidth{
+					Uplink: &Policy_RateLimit{
+						Rate: 1024,
+					},
+				},
 			},
 		},
 	})
@@ -34,6 +39,12 @@ func TestPolicy(t *testing.T) {
 		if p.Timeouts.ConnectionIdle != pDefault.Timeouts.ConnectionIdle {
 			t.Error("expect ", pDefault.Timeouts.ConnectionIdle, " sec timeout, but got ", p.Timeouts.ConnectionIdle)
 		}
+		if p.Bandwidth.Uplink.BytesPerSecond != 1024 || p.Bandwidth.Uplink.Burst != 1024 {
+			t.Error("unexpected uplink rate limit: ", p.Bandwidth.Uplink)
+		}
+		if p.Bandwidth.Downlink.Enabled() {
+			t.Error("expect unlimited downlink, but got ", p.Bandwidth.Downlink)
+		}
 	}
 
 	{
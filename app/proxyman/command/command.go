@@ -4,13 +4,18 @@ package command
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	grpc "google.golang.org/grpc"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/features/inbound"
 	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy"
 )
 
@@ -34,15 +39,72 @@ func getInbound(handler inbound.Handler) (proxy.Inbound, error) {
 	return gi.GetInbound(), nil
 }
 
-// ApplyInbound implements InboundOperation.
-func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+func getUserManager(handler inbound.Handler) (proxy.UserManager, error) {
 	p, err := getInbound(handler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	um, ok := p.(proxy.UserManager)
 	if !ok {
-		return newError("proxy is not a UserManager")
+		return nil, newError("proxy is not a UserManager")
+	}
+	return um, nil
+}
+
+func getUserLister(handler inbound.Handler) (proxy.UserLister, error) {
+	p, err := getInbound(handler)
+	if err != nil {
+		return nil, err
+	}
+	ul, ok := p.(proxy.UserLister)
+	if !ok {
+		return nil, newError("proxy is not a UserLister")
+	}
+	return ul, nil
+}
+
+func getUserQuota(ctx context.Context, handler inbound.Handler, email string) (*protocol.Quota, error) {
+	p, err := getInbound(handler)
+	if err != nil {
+		return nil, err
+	}
+	var quota *protocol.Quota
+	switch p := p.(type) {
+	case proxy.UserQuotaGetter:
+		quota = p.GetUserQuota(ctx, email)
+	case proxy.UserLister:
+		for _, user := range p.GetUsers(ctx) {
+			if strings.EqualFold(user.Email, email) {
+				quota = user.Quota
+				break
+			}
+		}
+	default:
+		return nil, newError("proxy doesn't support user quota")
+	}
+	if quota == nil {
+		return nil, newError("user ", email, " not found or has no quota")
+	}
+	return quota, nil
+}
+
+// hasTrafficQuota returns whether the operation sets a traffic quota, which is measured by stats counters.
+func hasTrafficQuota(operation InboundOperation) bool {
+	switch op := operation.(type) {
+	case *AddUserOperation:
+		return op.User != nil && op.User.TrafficQuota > 0
+	case *SetUserQuotaOperation:
+		return op.TrafficQuota > 0
+	default:
+		return false
+	}
+}
+
+// ApplyInbound implements InboundOperation.
+func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	um, err := getUserManager(handler)
+	if err != nil {
+		return err
 	}
 	mUser, err := op.User.ToMemoryUser()
 	if err != nil {
@@ -53,21 +115,70 @@ func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Ha
 
 // ApplyInbound implements InboundOperation.
 func (op *RemoveUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
-	p, err := getInbound(handler)
+	um, err := getUserManager(handler)
 	if err != nil {
 		return err
 	}
-	um, ok := p.(proxy.UserManager)
+	return um.RemoveUser(ctx, op.Email)
+}
+
+// ApplyInbound implements InboundOperation.
+func (op *SetUserQuotaOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	quota, err := getUserQuota(ctx, handler, op.Email)
+	if err != nil {
+		return err
+	}
+	var expire time.Time
+	if op.ExpireTime > 0 {
+		expire = time.Unix(op.ExpireTime, 0)
+	}
+	quota.Set(op.TrafficQuota, expire)
+	return nil
+}
+
+// ApplyInbound implements InboundOperation.
+func (op *ResetUserTrafficOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	quota, err := getUserQuota(ctx, handler, op.Email)
+	if err != nil {
+		return err
+	}
+	quota.Reset()
+	return nil
+}
+
+// ApplyInbound implements InboundOperation.
+func (op *SetPortsOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	ps, ok := handler.(portSetter)
 	if !ok {
-		return newError("proxy is not a UserManager")
+		return newError("handler ", handler.Tag(), " doesn't support setting ports")
 	}
-	return um.RemoveUser(ctx, op.Email)
+	ports := make([]net.Port, 0, len(op.Ports))
+	for _, port := range op.Ports {
+		if port == 0 || port > 65535 {
+			return newError("invalid port: ", port)
+		}
+		ports = append(ports, net.Port(port))
+	}
+	return ps.SetPorts(ports)
+}
+
+type portSetter interface {
+	SetPorts([]net.Port) error
+}
+
+type inboundConfigGetter interface {
+	GetConfig() *core.InboundHandlerConfig
+}
+
+type outboundConfigGetter interface {
+	GetConfig() *core.OutboundHandlerConfig
 }
 
 type handlerServer struct {
 	s   *core.Instance
 	ihm inbound.Manager
 	ohm outbound.Manager
+	sm  stats.Manager
 }
 
 func (s *handlerServer) AddInbound(ctx context.Context, request *AddInboundRequest) (*AddInboundResponse, error) {
@@ -79,9 +190,32 @@ func (s *handlerServer) AddInbound(ctx context.Context, request *AddInboundReque
 }
 
 func (s *handlerServer) RemoveInbound(ctx context.Context, request *RemoveInboundRequest) (*RemoveInboundResponse, error) {
+	if request.DrainTimeout > 0 {
+		gm, ok := s.ihm.(inbound.GracefulManager)
+		if !ok {
+			return nil, newError("inbound manager doesn't support draining handlers")
+		}
+		return &RemoveInboundResponse{}, gm.DrainHandler(ctx, request.Tag, time.Duration(request.DrainTimeout)*time.Second)
+	}
 	return &RemoveInboundResponse{}, s.ihm.RemoveHandler(ctx, request.Tag)
 }
 
+func (s *handlerServer) ReplaceInbound(ctx context.Context, request *ReplaceInboundRequest) (*ReplaceInboundResponse, error) {
+	gm, ok := s.ihm.(inbound.GracefulManager)
+	if !ok {
+		return nil, newError("inbound manager doesn't support replacing handlers")
+	}
+	rawHandler, err := core.CreateObject(s.s, request.Inbound)
+	if err != nil {
+		return nil, err
+	}
+	handler, ok := rawHandler.(inbound.Handler)
+	if !ok {
+		return nil, newError("not an InboundHandler")
+	}
+	return &ReplaceInboundResponse{}, gm.ReplaceHandler(ctx, handler, time.Duration(request.DrainTimeout)*time.Second)
+}
+
 func (s *handlerServer) AlterInbound(ctx context.Context, request *AlterInboundRequest) (*AlterInboundResponse, error) {
 	rawOperation, err := request.Operation.GetInstance()
 	if err != nil {
@@ -91,6 +225,9 @@ func (s *handlerServer) AlterInbound(ctx context.Context, request *AlterInboundR
 	if !ok {
 		return nil, newError("not an inbound operation")
 	}
+	if _, noStats := s.sm.(stats.NoopManager); noStats && hasTrafficQuota(operation) {
+		return nil, newError("traffic quota requires stats")
+	}
 
 	handler, err := s.ihm.GetHandler(ctx, request.Tag)
 	if err != nil {
@@ -108,9 +245,32 @@ func (s *handlerServer) AddOutbound(ctx context.Context, request *AddOutboundReq
 }
 
 func (s *handlerServer) RemoveOutbound(ctx context.Context, request *RemoveOutboundRequest) (*RemoveOutboundResponse, error) {
+	if request.DrainTimeout > 0 {
+		gm, ok := s.ohm.(outbound.GracefulManager)
+		if !ok {
+			return nil, newError("outbound manager doesn't support draining handlers")
+		}
+		return &RemoveOutboundResponse{}, gm.DrainHandler(ctx, request.Tag, time.Duration(request.DrainTimeout)*time.Second)
+	}
 	return &RemoveOutboundResponse{}, s.ohm.RemoveHandler(ctx, request.Tag)
 }
 
+func (s *handlerServer) ReplaceOutbound(ctx context.Context, request *ReplaceOutboundRequest) (*ReplaceOutboundResponse, error) {
+	gm, ok := s.ohm.(outbound.GracefulManager)
+	if !ok {
+		return nil, newError("outbound manager doesn't support replacing handlers")
+	}
+	rawHandler, err := core.CreateObject(s.s, request.Outbound)
+	if err != nil {
+		return nil, err
+	}
+	handler, ok := rawHandler.(outbound.Handler)
+	if !ok {
+		return nil, newError("not an OutboundHandler")
+	}
+	return &ReplaceOutboundResponse{}, gm.ReplaceHandler(ctx, handler, time.Duration(request.DrainTimeout)*time.Second)
+}
+
 func (s *handlerServer) AlterOutbound(ctx context.Context, request *AlterOutboundRequest) (*AlterOutboundResponse, error) {
 	rawOperation, err := request.Operation.GetInstance()
 	if err != nil {
@@ -125,6 +285,60 @@ func (s *handlerServer) AlterOutbound(ctx context.Context, request *AlterOutboun
 	return &AlterOutboundResponse{}, operation.ApplyOutbound(ctx, handler)
 }
 
+func (s *handlerServer) ListInbounds(ctx context.Context, request *ListInboundsRequest) (*ListInboundsResponse, error) {
+	lister, ok := s.ihm.(inbound.HandlerLister)
+	if !ok {
+		return nil, newError("inbound manager doesn't support listing handlers")
+	}
+	response := &ListInboundsResponse{}
+	for _, handler := range lister.ListHandlers(ctx) {
+		config := &core.InboundHandlerConfig{Tag: handler.Tag()}
+		if g, ok := handler.(inboundConfigGetter); ok && g.GetConfig() != nil {
+			config = g.GetConfig()
+		}
+		response.Inbounds = append(response.Inbounds, config)
+	}
+	return response, nil
+}
+
+func (s *handlerServer) ListOutbounds(ctx context.Context, request *ListOutboundsRequest) (*ListOutboundsResponse, error) {
+	lister, ok := s.ohm.(outbound.HandlerLister)
+	if !ok {
+		return nil, newError("outbound manager doesn't support listing handlers")
+	}
+	response := &ListOutboundsResponse{}
+	for _, handler := range lister.ListHandlers(ctx) {
+		config := &core.OutboundHandlerConfig{Tag: handler.Tag()}
+		if g, ok := handler.(outboundConfigGetter); ok && g.GetConfig() != nil {
+			config = g.GetConfig()
+		}
+		response.Outbounds = append(response.Outbounds, config)
+	}
+	return response, nil
+}
+
+func (s *handlerServer) ListUsers(ctx context.Context, request *ListUsersRequest) (*ListUsersResponse, error) {
+	handler, err := s.ihm.GetHandler(ctx, request.Tag)
+	if err != nil {
+		return nil, newError("failed to get handler: ", request.Tag).Base(err)
+	}
+	ul, err := getUserLister(handler)
+	if err != nil {
+		return nil, err
+	}
+	response := &ListUsersResponse{}
+	for _, user := range ul.GetUsers(ctx) {
+		if len(request.Email) > 0 && !strings.EqualFold(user.Email, request.Email) {
+			continue
+		}
+		response.Users = append(response.Users, user.ToProto())
+	}
+	if len(request.Email) > 0 && len(response.Users) == 0 {
+		return nil, newError("user ", request.Email, " not found")
+	}
+	return response, nil
+}
+
 type service struct {
 	v *core.Instance
 }
@@ -133,9 +347,10 @@ func (s *service) Register(server *grpc.Server) {
 	hs := &handlerServer{
 		s: s.v,
 	}
-	common.Must(s.v.RequireFeatures(func(im inbound.Manager, om outbound.Manager) {
+	common.Must(s.v.RequireFeatures(func(im inbound.Manager, om outbound.Manager, sm stats.Manager) {
 		hs.ihm = im
 		hs.ohm = om
+		hs.sm = sm
 	}))
 	RegisterHandlerServiceServer(server, hs)
 }
@@ -100,6 +100,146 @@ func (m *RemoveUserOperation) GetEmail() string {
 	return ""
 }
 
+// SetUserQuotaOperation changes the traffic quota and expire time of a user. Only users added with a quota or an
+// expire time can be changed.
+type SetUserQuotaOperation struct {
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Maximum traffic in bytes. 0 for unlimited.
+	TrafficQuota uint64 `protobuf:"varint,2,opt,name=traffic_quota,json=trafficQuota,proto3" json:"traffic_quota,omitempty"`
+	// Unix timestamp in seconds, after which the user is rejected. 0 for never.
+	ExpireTime           int64    `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetUserQuotaOperation) Reset()         { *m = SetUserQuotaOperation{} }
+func (m *SetUserQuotaOperation) String() string { return proto.CompactTextString(m) }
+func (*SetUserQuotaOperation) ProtoMessage()    {}
+func (*SetUserQuotaOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{2}
+}
+
+func (m *SetUserQuotaOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetUserQuotaOperation.Unmarshal(m, b)
+}
+func (m *SetUserQuotaOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetUserQuotaOperation.Marshal(b, m, deterministic)
+}
+func (m *SetUserQuotaOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetUserQuotaOperation.Merge(m, src)
+}
+func (m *SetUserQuotaOperation) XXX_Size() int {
+	return xxx_messageInfo_SetUserQuotaOperation.Size(m)
+}
+func (m *SetUserQuotaOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetUserQuotaOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetUserQuotaOperation proto.InternalMessageInfo
+
+func (m *SetUserQuotaOperation) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *SetUserQuotaOperation) GetTrafficQuota() uint64 {
+	if m != nil {
+		return m.TrafficQuota
+	}
+	return 0
+}
+
+func (m *SetUserQuotaOperation) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+// ResetUserTrafficOperation sets the traffic counters of a user to zero, so that the user can use the full quota again.
+type ResetUserTrafficOperation struct {
+	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetUserTrafficOperation) Reset()         { *m = ResetUserTrafficOperation{} }
+func (m *ResetUserTrafficOperation) String() string { return proto.CompactTextString(m) }
+func (*ResetUserTrafficOperation) ProtoMessage()    {}
+func (*ResetUserTrafficOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{3}
+}
+
+func (m *ResetUserTrafficOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetUserTrafficOperation.Unmarshal(m, b)
+}
+func (m *ResetUserTrafficOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetUserTrafficOperation.Marshal(b, m, deterministic)
+}
+func (m *ResetUserTrafficOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetUserTrafficOperation.Merge(m, src)
+}
+func (m *ResetUserTrafficOperation) XXX_Size() int {
+	return xxx_messageInfo_ResetUserTrafficOperation.Size(m)
+}
+func (m *ResetUserTrafficOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetUserTrafficOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetUserTrafficOperation proto.InternalMessageInfo
+
+func (m *ResetUserTrafficOperation) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+// SetPortsOperation changes the listening ports of an inbound handler with
+// External allocation strategy.
+type SetPortsOperation struct {
+	Ports                []uint32 `protobuf:"varint,1,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetPortsOperation) Reset()         { *m = SetPortsOperation{} }
+func (m *SetPortsOperation) String() string { return proto.CompactTextString(m) }
+func (*SetPortsOperation) ProtoMessage()    {}
+func (*SetPortsOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{4}
+}
+
+func (m *SetPortsOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPortsOperation.Unmarshal(m, b)
+}
+func (m *SetPortsOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPortsOperation.Marshal(b, m, deterministic)
+}
+func (m *SetPortsOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPortsOperation.Merge(m, src)
+}
+func (m *SetPortsOperation) XXX_Size() int {
+	return xxx_messageInfo_SetPortsOperation.Size(m)
+}
+func (m *SetPortsOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPortsOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPortsOperation proto.InternalMessageInfo
+
+func (m *SetPortsOperation) GetPorts() []uint32 {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
 type AddInboundRequest struct {
 	Inbound              *core.InboundHandlerConfig `protobuf:"bytes,1,opt,name=inbound,proto3" json:"inbound,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
@@ -111,7 +251,7 @@ func (m *AddInboundRequest) Reset()         { *m = AddInboundRequest{} }
 func (m *AddInboundRequest) String() string { return proto.CompactTextString(m) }
 func (*AddInboundRequest) ProtoMessage()    {}
 func (*AddInboundRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{2}
+	return fileDescriptor_e2c30a70a48636a0, []int{5}
 }
 
 func (m *AddInboundRequest) XXX_Unmarshal(b []byte) error {
@@ -149,7 +289,7 @@ func (m *AddInboundResponse) Reset()         { *m = AddInboundResponse{} }
 func (m *AddInboundResponse) String() string { return proto.CompactTextString(m) }
 func (*AddInboundResponse) ProtoMessage()    {}
 func (*AddInboundResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{3}
+	return fileDescriptor_e2c30a70a48636a0, []int{6}
 }
 
 func (m *AddInboundResponse) XXX_Unmarshal(b []byte) error {
@@ -171,7 +311,10 @@ func (m *AddInboundResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_AddInboundResponse proto.InternalMessageInfo
 
 type RemoveInboundRequest struct {
-	Tag                  string   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// If positive, the handler stops accepting new connections, and is closed
+	// after existing connections end, or this number of seconds passes.
+	DrainTimeout         uint32   `protobuf:"varint,2,opt,name=drain_timeout,json=drainTimeout,proto3" json:"drain_timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -181,7 +324,7 @@ func (m *RemoveInboundRequest) Reset()         { *m = RemoveInboundRequest{} }
 func (m *RemoveInboundRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoveInboundRequest) ProtoMessage()    {}
 func (*RemoveInboundRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{4}
+	return fileDescriptor_e2c30a70a48636a0, []int{7}
 }
 
 func (m *RemoveInboundRequest) XXX_Unmarshal(b []byte) error {
@@ -209,6 +352,13 @@ func (m *RemoveInboundRequest) GetTag() string {
 	return ""
 }
 
+func (m *RemoveInboundRequest) GetDrainTimeout() uint32 {
+	if m != nil {
+		return m.DrainTimeout
+	}
+	return 0
+}
+
 type RemoveInboundResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -219,7 +369,7 @@ func (m *RemoveInboundResponse) Reset()         { *m = RemoveInboundResponse{} }
 func (m *RemoveInboundResponse) String() string { return proto.CompactTextString(m) }
 func (*RemoveInboundResponse) ProtoMessage()    {}
 func (*RemoveInboundResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{5}
+	return fileDescriptor_e2c30a70a48636a0, []int{8}
 }
 
 func (m *RemoveInboundResponse) XXX_Unmarshal(b []byte) error {
@@ -252,7 +402,7 @@ func (m *AlterInboundRequest) Reset()         { *m = AlterInboundRequest{} }
 func (m *AlterInboundRequest) String() string { return proto.CompactTextString(m) }
 func (*AlterInboundRequest) ProtoMessage()    {}
 func (*AlterInboundRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{6}
+	return fileDescriptor_e2c30a70a48636a0, []int{9}
 }
 
 func (m *AlterInboundRequest) XXX_Unmarshal(b []byte) error {
@@ -297,7 +447,7 @@ func (m *AlterInboundResponse) Reset()         { *m = AlterInboundResponse{} }
 func (m *AlterInboundResponse) String() string { return proto.CompactTextString(m) }
 func (*AlterInboundResponse) ProtoMessage()    {}
 func (*AlterInboundResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{7}
+	return fileDescriptor_e2c30a70a48636a0, []int{10}
 }
 
 func (m *AlterInboundResponse) XXX_Unmarshal(b []byte) error {
@@ -329,7 +479,7 @@ func (m *AddOutboundRequest) Reset()         { *m = AddOutboundRequest{} }
 func (m *AddOutboundRequest) String() string { return proto.CompactTextString(m) }
 func (*AddOutboundRequest) ProtoMessage()    {}
 func (*AddOutboundRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{8}
+	return fileDescriptor_e2c30a70a48636a0, []int{11}
 }
 
 func (m *AddOutboundRequest) XXX_Unmarshal(b []byte) error {
@@ -367,7 +517,7 @@ func (m *AddOutboundResponse) Reset()         { *m = AddOutboundResponse{} }
 func (m *AddOutboundResponse) String() string { return proto.CompactTextString(m) }
 func (*AddOutboundResponse) ProtoMessage()    {}
 func (*AddOutboundResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{9}
+	return fileDescriptor_e2c30a70a48636a0, []int{12}
 }
 
 func (m *AddOutboundResponse) XXX_Unmarshal(b []byte) error {
@@ -389,7 +539,10 @@ func (m *AddOutboundResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_AddOutboundResponse proto.InternalMessageInfo
 
 type RemoveOutboundRequest struct {
-	Tag                  string   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// If positive, the handler is no longer routable, and is closed after
+	// existing connections end, or this number of seconds passes.
+	DrainTimeout         uint32   `protobuf:"varint,2,opt,name=drain_timeout,json=drainTimeout,proto3" json:"drain_timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -399,7 +552,7 @@ func (m *RemoveOutboundRequest) Reset()         { *m = RemoveOutboundRequest{} }
 func (m *RemoveOutboundRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoveOutboundRequest) ProtoMessage()    {}
 func (*RemoveOutboundRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{10}
+	return fileDescriptor_e2c30a70a48636a0, []int{13}
 }
 
 func (m *RemoveOutboundRequest) XXX_Unmarshal(b []byte) error {
@@ -427,6 +580,13 @@ func (m *RemoveOutboundRequest) GetTag() string {
 	return ""
 }
 
+func (m *RemoveOutboundRequest) GetDrainTimeout() uint32 {
+	if m != nil {
+		return m.DrainTimeout
+	}
+	return 0
+}
+
 type RemoveOutboundResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -437,7 +597,7 @@ func (m *RemoveOutboundResponse) Reset()         { *m = RemoveOutboundResponse{}
 func (m *RemoveOutboundResponse) String() string { return proto.CompactTextString(m) }
 func (*RemoveOutboundResponse) ProtoMessage()    {}
 func (*RemoveOutboundResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{11}
+	return fileDescriptor_e2c30a70a48636a0, []int{14}
 }
 
 func (m *RemoveOutboundResponse) XXX_Unmarshal(b []byte) error {
@@ -470,7 +630,7 @@ func (m *AlterOutboundRequest) Reset()         { *m = AlterOutboundRequest{} }
 func (m *AlterOutboundRequest) String() string { return proto.CompactTextString(m) }
 func (*AlterOutboundRequest) ProtoMessage()    {}
 func (*AlterOutboundRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{12}
+	return fileDescriptor_e2c30a70a48636a0, []int{15}
 }
 
 func (m *AlterOutboundRequest) XXX_Unmarshal(b []byte) error {
@@ -515,7 +675,7 @@ func (m *AlterOutboundResponse) Reset()         { *m = AlterOutboundResponse{} }
 func (m *AlterOutboundResponse) String() string { return proto.CompactTextString(m) }
 func (*AlterOutboundResponse) ProtoMessage()    {}
 func (*AlterOutboundResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{13}
+	return fileDescriptor_e2c30a70a48636a0, []int{16}
 }
 
 func (m *AlterOutboundResponse) XXX_Unmarshal(b []byte) error {
@@ -536,6 +696,399 @@ func (m *AlterOutboundResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AlterOutboundResponse proto.InternalMessageInfo
 
+// ReplaceInboundRequest replaces the inbound handler with the same tag. The
+// old handler is drained, with the same semantics as in RemoveInboundRequest.
+type ReplaceInboundRequest struct {
+	Inbound              *core.InboundHandlerConfig `protobuf:"bytes,1,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	DrainTimeout         uint32                     `protobuf:"varint,2,opt,name=drain_timeout,json=drainTimeout,proto3" json:"drain_timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *ReplaceInboundRequest) Reset()         { *m = ReplaceInboundRequest{} }
+func (m *ReplaceInboundRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplaceInboundRequest) ProtoMessage()    {}
+func (*ReplaceInboundRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{17}
+}
+
+func (m *ReplaceInboundRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaceInboundRequest.Unmarshal(m, b)
+}
+func (m *ReplaceInboundRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaceInboundRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplaceInboundRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaceInboundRequest.Merge(m, src)
+}
+func (m *ReplaceInboundRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplaceInboundRequest.Size(m)
+}
+func (m *ReplaceInboundRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaceInboundRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaceInboundRequest proto.InternalMessageInfo
+
+func (m *ReplaceInboundRequest) GetInbound() *core.InboundHandlerConfig {
+	if m != nil {
+		return m.Inbound
+	}
+	return nil
+}
+
+func (m *ReplaceInboundRequest) GetDrainTimeout() uint32 {
+	if m != nil {
+		return m.DrainTimeout
+	}
+	return 0
+}
+
+type ReplaceInboundResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplaceInboundResponse) Reset()         { *m = ReplaceInboundResponse{} }
+func (m *ReplaceInboundResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplaceInboundResponse) ProtoMessage()    {}
+func (*ReplaceInboundResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{18}
+}
+
+func (m *ReplaceInboundResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaceInboundResponse.Unmarshal(m, b)
+}
+func (m *ReplaceInboundResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaceInboundResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplaceInboundResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaceInboundResponse.Merge(m, src)
+}
+func (m *ReplaceInboundResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplaceInboundResponse.Size(m)
+}
+func (m *ReplaceInboundResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaceInboundResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaceInboundResponse proto.InternalMessageInfo
+
+// ReplaceOutboundRequest replaces the outbound handler with the same tag. The
+// old handler is drained, with the same semantics as in RemoveOutboundRequest.
+type ReplaceOutboundRequest struct {
+	Outbound             *core.OutboundHandlerConfig `protobuf:"bytes,1,opt,name=outbound,proto3" json:"outbound,omitempty"`
+	DrainTimeout         uint32                      `protobuf:"varint,2,opt,name=drain_timeout,json=drainTimeout,proto3" json:"drain_timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *ReplaceOutboundRequest) Reset()         { *m = ReplaceOutboundRequest{} }
+func (m *ReplaceOutboundRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplaceOutboundRequest) ProtoMessage()    {}
+func (*ReplaceOutboundRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{19}
+}
+
+func (m *ReplaceOutboundRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaceOutboundRequest.Unmarshal(m, b)
+}
+func (m *ReplaceOutboundRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaceOutboundRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplaceOutboundRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaceOutboundRequest.Merge(m, src)
+}
+func (m *ReplaceOutboundRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplaceOutboundRequest.Size(m)
+}
+func (m *ReplaceOutboundRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaceOutboundRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaceOutboundRequest proto.InternalMessageInfo
+
+func (m *ReplaceOutboundRequest) GetOutbound() *core.OutboundHandlerConfig {
+	if m != nil {
+		return m.Outbound
+	}
+	return nil
+}
+
+func (m *ReplaceOutboundRequest) GetDrainTimeout() uint32 {
+	if m != nil {
+		return m.DrainTimeout
+	}
+	return 0
+}
+
+type ReplaceOutboundResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplaceOutboundResponse) Reset()         { *m = ReplaceOutboundResponse{} }
+func (m *ReplaceOutboundResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplaceOutboundResponse) ProtoMessage()    {}
+func (*ReplaceOutboundResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{20}
+}
+
+func (m *ReplaceOutboundResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaceOutboundResponse.Unmarshal(m, b)
+}
+func (m *ReplaceOutboundResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaceOutboundResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplaceOutboundResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaceOutboundResponse.Merge(m, src)
+}
+func (m *ReplaceOutboundResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplaceOutboundResponse.Size(m)
+}
+func (m *ReplaceOutboundResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaceOutboundResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaceOutboundResponse proto.InternalMessageInfo
+
+type ListInboundsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListInboundsRequest) Reset()         { *m = ListInboundsRequest{} }
+func (m *ListInboundsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListInboundsRequest) ProtoMessage()    {}
+func (*ListInboundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{21}
+}
+
+func (m *ListInboundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInboundsRequest.Unmarshal(m, b)
+}
+func (m *ListInboundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInboundsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListInboundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInboundsRequest.Merge(m, src)
+}
+func (m *ListInboundsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListInboundsRequest.Size(m)
+}
+func (m *ListInboundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInboundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInboundsRequest proto.InternalMessageInfo
+
+// ListInboundsResponse contains the configs the inbound handlers were created from. Changes made afterwards, such as
+// users added by AlterInbound or ports changed by SetPortsOperation, are not reflected. Use ListUsers for the current
+// users of a handler.
+type ListInboundsResponse struct {
+	Inbounds             []*core.InboundHandlerConfig `protobuf:"bytes,1,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *ListInboundsResponse) Reset()         { *m = ListInboundsResponse{} }
+func (m *ListInboundsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListInboundsResponse) ProtoMessage()    {}
+func (*ListInboundsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{22}
+}
+
+func (m *ListInboundsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInboundsResponse.Unmarshal(m, b)
+}
+func (m *ListInboundsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInboundsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListInboundsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInboundsResponse.Merge(m, src)
+}
+func (m *ListInboundsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListInboundsResponse.Size(m)
+}
+func (m *ListInboundsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInboundsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInboundsResponse proto.InternalMessageInfo
+
+func (m *ListInboundsResponse) GetInbounds() []*core.InboundHandlerConfig {
+	if m != nil {
+		return m.Inbounds
+	}
+	return nil
+}
+
+type ListOutboundsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOutboundsRequest) Reset()         { *m = ListOutboundsRequest{} }
+func (m *ListOutboundsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOutboundsRequest) ProtoMessage()    {}
+func (*ListOutboundsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{23}
+}
+
+func (m *ListOutboundsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOutboundsRequest.Unmarshal(m, b)
+}
+func (m *ListOutboundsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOutboundsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOutboundsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOutboundsRequest.Merge(m, src)
+}
+func (m *ListOutboundsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOutboundsRequest.Size(m)
+}
+func (m *ListOutboundsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOutboundsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOutboundsRequest proto.InternalMessageInfo
+
+// ListOutboundsResponse contains the configs the outbound handlers were created from. Changes made afterwards by
+// AlterOutbound are not reflected.
+type ListOutboundsResponse struct {
+	Outbounds            []*core.OutboundHandlerConfig `protobuf:"bytes,1,rep,name=outbounds,proto3" json:"outbounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *ListOutboundsResponse) Reset()         { *m = ListOutboundsResponse{} }
+func (m *ListOutboundsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListOutboundsResponse) ProtoMessage()    {}
+func (*ListOutboundsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{24}
+}
+
+func (m *ListOutboundsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOutboundsResponse.Unmarshal(m, b)
+}
+func (m *ListOutboundsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOutboundsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListOutboundsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOutboundsResponse.Merge(m, src)
+}
+func (m *ListOutboundsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListOutboundsResponse.Size(m)
+}
+func (m *ListOutboundsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOutboundsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOutboundsResponse proto.InternalMessageInfo
+
+func (m *ListOutboundsResponse) GetOutbounds() []*core.OutboundHandlerConfig {
+	if m != nil {
+		return m.Outbounds
+	}
+	return nil
+}
+
+// ListUsersRequest lists the users of the inbound handler with the given tag.
+type ListUsersRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// If not empty, only the user with this email is returned.
+	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListUsersRequest) Reset()         { *m = ListUsersRequest{} }
+func (m *ListUsersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListUsersRequest) ProtoMessage()    {}
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{25}
+}
+
+func (m *ListUsersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUsersRequest.Unmarshal(m, b)
+}
+func (m *ListUsersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUsersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListUsersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUsersRequest.Merge(m, src)
+}
+func (m *ListUsersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListUsersRequest.Size(m)
+}
+func (m *ListUsersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUsersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUsersRequest proto.InternalMessageInfo
+
+func (m *ListUsersRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *ListUsersRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	Users                []*protocol.User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListUsersResponse) Reset()         { *m = ListUsersResponse{} }
+func (m *ListUsersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListUsersResponse) ProtoMessage()    {}
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{26}
+}
+
+func (m *ListUsersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUsersResponse.Unmarshal(m, b)
+}
+func (m *ListUsersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUsersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListUsersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUsersResponse.Merge(m, src)
+}
+func (m *ListUsersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListUsersResponse.Size(m)
+}
+func (m *ListUsersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUsersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUsersResponse proto.InternalMessageInfo
+
+func (m *ListUsersResponse) GetUsers() []*protocol.User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
 type Config struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -546,7 +1099,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{14}
+	return fileDescriptor_e2c30a70a48636a0, []int{27}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -570,6 +1123,9 @@ var xxx_messageInfo_Config proto.InternalMessageInfo
 func init() {
 	proto.RegisterType((*AddUserOperation)(nil), "v2ray.core.app.proxyman.command.AddUserOperation")
 	proto.RegisterType((*RemoveUserOperation)(nil), "v2ray.core.app.proxyman.command.RemoveUserOperation")
+	proto.RegisterType((*SetUserQuotaOperation)(nil), "v2ray.core.app.proxyman.command.SetUserQuotaOperation")
+	proto.RegisterType((*ResetUserTrafficOperation)(nil), "v2ray.core.app.proxyman.command.ResetUserTrafficOperation")
+	proto.RegisterType((*SetPortsOperation)(nil), "v2ray.core.app.proxyman.command.SetPortsOperation")
 	proto.RegisterType((*AddInboundRequest)(nil), "v2ray.core.app.proxyman.command.AddInboundRequest")
 	proto.RegisterType((*AddInboundResponse)(nil), "v2ray.core.app.proxyman.command.AddInboundResponse")
 	proto.RegisterType((*RemoveInboundRequest)(nil), "v2ray.core.app.proxyman.command.RemoveInboundRequest")
@@ -582,6 +1138,16 @@ func init() {
 	proto.RegisterType((*RemoveOutboundResponse)(nil), "v2ray.core.app.proxyman.command.RemoveOutboundResponse")
 	proto.RegisterType((*AlterOutboundRequest)(nil), "v2ray.core.app.proxyman.command.AlterOutboundRequest")
 	proto.RegisterType((*AlterOutboundResponse)(nil), "v2ray.core.app.proxyman.command.AlterOutboundResponse")
+	proto.RegisterType((*ReplaceInboundRequest)(nil), "v2ray.core.app.proxyman.command.ReplaceInboundRequest")
+	proto.RegisterType((*ReplaceInboundResponse)(nil), "v2ray.core.app.proxyman.command.ReplaceInboundResponse")
+	proto.RegisterType((*ReplaceOutboundRequest)(nil), "v2ray.core.app.proxyman.command.ReplaceOutboundRequest")
+	proto.RegisterType((*ReplaceOutboundResponse)(nil), "v2ray.core.app.proxyman.command.ReplaceOutboundResponse")
+	proto.RegisterType((*ListInboundsRequest)(nil), "v2ray.core.app.proxyman.command.ListInboundsRequest")
+	proto.RegisterType((*ListInboundsResponse)(nil), "v2ray.core.app.proxyman.command.ListInboundsResponse")
+	proto.RegisterType((*ListOutboundsRequest)(nil), "v2ray.core.app.proxyman.command.ListOutboundsRequest")
+	proto.RegisterType((*ListOutboundsResponse)(nil), "v2ray.core.app.proxyman.command.ListOutboundsResponse")
+	proto.RegisterType((*ListUsersRequest)(nil), "v2ray.core.app.proxyman.command.ListUsersRequest")
+	proto.RegisterType((*ListUsersResponse)(nil), "v2ray.core.app.proxyman.command.ListUsersResponse")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.proxyman.command.Config")
 }
 
@@ -590,42 +1156,62 @@ func init() {
 }
 
 var fileDescriptor_e2c30a70a48636a0 = []byte{
-	// 557 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdf, 0x6b, 0xd3, 0x40,
-	0x1c, 0xb7, 0x53, 0xbb, 0xed, 0x3b, 0x1d, 0xf3, 0xda, 0x6e, 0x25, 0x3e, 0x6c, 0x46, 0x90, 0x0d,
-	0xe1, 0xa2, 0x59, 0x37, 0x41, 0xf0, 0xa1, 0xd6, 0x87, 0xf9, 0x20, 0x1d, 0x99, 0xfa, 0xe0, 0x8b,
-	0xdc, 0x92, 0xb3, 0x04, 0x92, 0xbb, 0xf3, 0x92, 0x56, 0x2b, 0x08, 0x82, 0xff, 0x80, 0x7f, 0x87,
-	0x7f, 0xa5, 0x24, 0x77, 0xd7, 0x25, 0x69, 0x21, 0x0d, 0xf8, 0xd4, 0xf4, 0xfa, 0xf9, 0xf5, 0xfd,
-	0xde, 0x27, 0x14, 0x9e, 0xcf, 0x5c, 0x49, 0xe6, 0xd8, 0xe7, 0xb1, 0xe3, 0x73, 0x49, 0x1d, 0x22,
-	0x84, 0x23, 0x24, 0xff, 0x3e, 0x8f, 0x09, 0x73, 0x7c, 0x1e, 0xc7, 0x84, 0x05, 0xe6, 0x13, 0x0b,
-	0xc9, 0x53, 0x8e, 0x0e, 0x0d, 0x45, 0x52, 0x4c, 0x84, 0xc0, 0x06, 0x8e, 0x35, 0xcc, 0x3a, 0xa9,
-	0x68, 0x66, 0xe7, 0x9c, 0x39, 0x39, 0xdb, 0xe7, 0x91, 0x33, 0x4d, 0xa8, 0x54, 0x5a, 0xd6, 0xb3,
-	0xd5, 0xd0, 0x84, 0xca, 0x90, 0x44, 0x4e, 0x3a, 0x17, 0x34, 0xf8, 0x1c, 0xd3, 0x24, 0x21, 0x13,
-	0xaa, 0x19, 0x0f, 0x97, 0x18, 0xec, 0x4b, 0x38, 0x51, 0x3f, 0xda, 0x17, 0xb0, 0x37, 0x0c, 0x82,
-	0x0f, 0x09, 0x95, 0x63, 0x41, 0x25, 0x49, 0x43, 0xce, 0xd0, 0x00, 0xee, 0x64, 0x86, 0xfd, 0xd6,
-	0x51, 0xeb, 0x78, 0xc7, 0x3d, 0xc2, 0x85, 0xf4, 0xca, 0x0d, 0x9b, 0x60, 0x38, 0x23, 0x7a, 0x39,
-	0xda, 0x7e, 0x0a, 0x1d, 0x8f, 0xc6, 0x7c, 0x46, 0xcb, 0x62, 0x5d, 0xb8, 0x4b, 0x63, 0x12, 0x46,
-	0xb9, 0xda, 0xb6, 0xa7, 0xbe, 0xd8, 0x63, 0x78, 0x30, 0x0c, 0x82, 0xb7, 0xec, 0x9a, 0x4f, 0x59,
-	0xe0, 0xd1, 0xaf, 0x53, 0x9a, 0xa4, 0xe8, 0x25, 0x6c, 0x86, 0xea, 0x64, 0x95, 0xb5, 0x06, 0x5f,
-	0x10, 0x16, 0x44, 0x54, 0x8e, 0xf2, 0x21, 0x3c, 0x43, 0xb0, 0xbb, 0x80, 0x8a, 0x82, 0x89, 0xe0,
-	0x2c, 0xa1, 0xf6, 0x31, 0x74, 0x55, 0xa6, 0x8a, 0xd3, 0x1e, 0xdc, 0x4e, 0xc9, 0x44, 0x47, 0xca,
-	0x1e, 0xed, 0x03, 0xe8, 0x55, 0x90, 0x5a, 0x22, 0x86, 0xce, 0x30, 0x4a, 0xa9, 0xac, 0x53, 0x40,
-	0x6f, 0x60, 0x9b, 0x9b, 0xa9, 0xfb, 0x1b, 0x79, 0xfe, 0x27, 0x2b, 0x56, 0xa7, 0x2e, 0x0a, 0xbf,
-	0xcf, 0x2e, 0xea, 0x9d, 0xba, 0x27, 0xef, 0x86, 0x68, 0xef, 0x43, 0xb7, 0x6c, 0xa7, 0x63, 0x5c,
-	0xe5, 0xf3, 0x8d, 0xa7, 0x69, 0x29, 0xc5, 0x2b, 0xd8, 0xe2, 0xfa, 0x48, 0xaf, 0xec, 0x51, 0xd1,
-	0xd2, 0xc0, 0xcb, 0x3b, 0x5b, 0x50, 0xec, 0x1e, 0x74, 0x4a, 0xa2, 0xda, 0xeb, 0xc4, 0xec, 0xa2,
-	0x6a, 0xb7, 0xbc, 0xb6, 0x3e, 0xec, 0x57, 0xa1, 0x5a, 0x84, 0xe9, 0x41, 0x6a, 0x35, 0xfe, 0xd3,
-	0xe2, 0x0e, 0xa0, 0x57, 0xf1, 0xd3, 0x41, 0xb6, 0xa0, 0xad, 0x06, 0x77, 0xff, 0xb4, 0x61, 0x57,
-	0xaf, 0xe2, 0x8a, 0xca, 0x59, 0xe8, 0x53, 0xf4, 0x0d, 0xe0, 0xa6, 0x36, 0xc8, 0xc5, 0x35, 0x2f,
-	0x2a, 0x5e, 0x2a, 0xad, 0x75, 0xda, 0x88, 0xa3, 0x33, 0xdd, 0x42, 0xbf, 0x5a, 0x70, 0xbf, 0x54,
-	0x38, 0x74, 0x56, 0x2b, 0xb4, 0xaa, 0xca, 0xd6, 0x79, 0x53, 0xda, 0x22, 0xc2, 0x4f, 0xb8, 0x57,
-	0xac, 0x1a, 0x1a, 0xd4, 0x4f, 0xb2, 0xfc, 0x22, 0x58, 0x67, 0x0d, 0x59, 0x0b, 0xfb, 0x1f, 0xb0,
-	0x53, 0x28, 0x1f, 0x5a, 0x6b, 0x8f, 0x95, 0x32, 0x59, 0x83, 0x66, 0xa4, 0x85, 0xf7, 0xef, 0x16,
-	0xec, 0x96, 0x7b, 0x8b, 0xd6, 0xdd, 0x63, 0x35, 0xc2, 0x8b, 0xc6, 0xbc, 0x52, 0x07, 0x4a, 0x9d,
-	0x45, 0x6b, 0x2e, 0xb3, 0x9a, 0xe1, 0xbc, 0x29, 0xcd, 0x44, 0x78, 0xed, 0xc1, 0x63, 0x9f, 0xc7,
-	0x75, 0xf4, 0xcb, 0xd6, 0xa7, 0x4d, 0xfd, 0xf8, 0x77, 0xe3, 0xf0, 0xa3, 0xeb, 0x91, 0x39, 0x1e,
-	0x65, 0xe0, 0xa1, 0x10, 0xf8, 0xd2, 0x80, 0x47, 0x0a, 0x71, 0xdd, 0xce, 0xff, 0x1d, 0x4e, 0xff,
-	0x05, 0x00, 0x00, 0xff, 0xff, 0x2f, 0x05, 0xaa, 0x44, 0x29, 0x07, 0x00, 0x00,
+	// 865 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x6f, 0x4f, 0xfb, 0x54,
+	0x14, 0xb6, 0xec, 0xc7, 0x9f, 0x9d, 0x31, 0x84, 0xbb, 0x0d, 0x46, 0x7d, 0xc1, 0x2c, 0x89, 0x19,
+	0x31, 0xe9, 0x64, 0xc0, 0x30, 0x44, 0x63, 0x26, 0xbe, 0xc0, 0x28, 0x82, 0xdd, 0x34, 0xc6, 0x37,
+	0xe4, 0xd2, 0x5e, 0x48, 0x93, 0xb5, 0xb7, 0xdc, 0xde, 0x21, 0x33, 0x9a, 0x98, 0x98, 0xf8, 0x15,
+	0xfc, 0x0e, 0x7e, 0x4a, 0xd3, 0xde, 0xdb, 0xd2, 0x76, 0x35, 0x6d, 0x0d, 0xaf, 0xb6, 0x9d, 0x9d,
+	0xe7, 0x3c, 0xcf, 0x7d, 0x7a, 0xfa, 0x5c, 0x38, 0x7e, 0x1e, 0x32, 0xbc, 0xd0, 0x4d, 0xea, 0x0c,
+	0x4c, 0xca, 0xc8, 0x00, 0x7b, 0xde, 0xc0, 0x63, 0xf4, 0x65, 0xe1, 0x60, 0x77, 0x60, 0x52, 0xc7,
+	0xc1, 0xae, 0x15, 0x7d, 0xea, 0x1e, 0xa3, 0x9c, 0xa2, 0x83, 0x08, 0xc2, 0x88, 0x8e, 0x3d, 0x4f,
+	0x8f, 0xda, 0x75, 0xd9, 0xa6, 0x1e, 0x65, 0x66, 0x06, 0x75, 0xea, 0x0e, 0x42, 0xb4, 0x49, 0x67,
+	0x83, 0xb9, 0x4f, 0x98, 0x98, 0xa5, 0x7e, 0x92, 0xdf, 0xea, 0x13, 0x66, 0xe3, 0xd9, 0x80, 0x2f,
+	0x3c, 0x62, 0xdd, 0x39, 0xc4, 0xf7, 0xf1, 0x23, 0x91, 0x88, 0x0f, 0x96, 0x10, 0xee, 0x83, 0xfd,
+	0x28, 0xfe, 0xd4, 0xae, 0x60, 0x7b, 0x6c, 0x59, 0x3f, 0xf8, 0x84, 0xdd, 0x78, 0x84, 0x61, 0x6e,
+	0x53, 0x17, 0x9d, 0xc2, 0xbb, 0x80, 0xb0, 0xab, 0xf4, 0x94, 0x7e, 0x63, 0xd8, 0xd3, 0x13, 0xea,
+	0x05, 0x9b, 0x1e, 0x09, 0xd3, 0x03, 0xa0, 0x11, 0x76, 0x6b, 0x1f, 0x43, 0xcb, 0x20, 0x0e, 0x7d,
+	0x26, 0xe9, 0x61, 0x6d, 0x58, 0x25, 0x0e, 0xb6, 0x67, 0xe1, 0xb4, 0xba, 0x21, 0x7e, 0x68, 0x3e,
+	0x74, 0x26, 0x84, 0x07, 0x9d, 0xdf, 0xcf, 0x29, 0xc7, 0x05, 0xed, 0xe8, 0x10, 0x9a, 0x9c, 0xe1,
+	0x87, 0x07, 0xdb, 0xbc, 0x7b, 0x0a, 0xfa, 0xbb, 0x2b, 0x3d, 0xa5, 0xff, 0xce, 0xd8, 0x94, 0xc5,
+	0x70, 0x06, 0x3a, 0x80, 0x06, 0x79, 0xf1, 0x6c, 0x46, 0xee, 0xb8, 0xed, 0x90, 0x6e, 0xad, 0xa7,
+	0xf4, 0x6b, 0x06, 0x88, 0xd2, 0xd4, 0x76, 0x88, 0x76, 0x0c, 0xfb, 0x06, 0xf1, 0x05, 0xed, 0x54,
+	0x20, 0x8b, 0x74, 0x1e, 0xc1, 0xce, 0x84, 0xf0, 0x5b, 0xca, 0xb8, 0x9f, 0x6a, 0xf5, 0x82, 0x4a,
+	0x57, 0xe9, 0xd5, 0xfa, 0x4d, 0x43, 0xfc, 0xd0, 0x6e, 0x60, 0x67, 0x6c, 0x59, 0x5f, 0xbb, 0xf7,
+	0x74, 0xee, 0x5a, 0x06, 0x79, 0x9a, 0x13, 0x9f, 0xa3, 0x0b, 0x58, 0xb7, 0x45, 0x25, 0xcf, 0x4d,
+	0xd9, 0x7c, 0x85, 0x5d, 0x6b, 0x46, 0xd8, 0x65, 0xf8, 0x5c, 0x8c, 0x08, 0xa0, 0xb5, 0x01, 0x25,
+	0x07, 0xfa, 0x1e, 0x75, 0x7d, 0xa2, 0x5d, 0x43, 0x5b, 0xd8, 0x9c, 0x61, 0xda, 0x86, 0x1a, 0xc7,
+	0x8f, 0x52, 0x7d, 0xf0, 0x35, 0x30, 0xcd, 0x62, 0xd8, 0x76, 0x43, 0x3b, 0xe8, 0x9c, 0x87, 0xa6,
+	0x35, 0x8d, 0xcd, 0xb0, 0x38, 0x15, 0x35, 0x6d, 0x0f, 0x3a, 0x99, 0x71, 0x92, 0xc7, 0x81, 0xd6,
+	0x78, 0xc6, 0x09, 0x2b, 0xa4, 0xf9, 0x0a, 0xea, 0x34, 0xb2, 0x26, 0xa4, 0x68, 0x0c, 0x3f, 0xca,
+	0x59, 0x19, 0xb1, 0xa0, 0xfa, 0x34, 0x58, 0xd0, 0x6b, 0xb1, 0x9f, 0xc6, 0x2b, 0x50, 0xdb, 0x85,
+	0x76, 0x9a, 0x4e, 0xca, 0x98, 0x84, 0x26, 0xdc, 0xcc, 0x79, 0x4a, 0xc5, 0xe7, 0xb0, 0x41, 0x65,
+	0x49, 0xfa, 0xfa, 0x61, 0x92, 0x32, 0x6a, 0x4f, 0x1b, 0x1b, 0x43, 0xb4, 0x0e, 0xb4, 0x52, 0x43,
+	0x25, 0xd7, 0x77, 0x91, 0x17, 0x59, 0xba, 0xff, 0xe9, 0x6d, 0x17, 0x76, 0xb3, 0xf3, 0x24, 0x93,
+	0x2b, 0x4f, 0x5b, 0x4c, 0xf4, 0x36, 0xee, 0xee, 0x41, 0x27, 0xc3, 0x27, 0x85, 0xbc, 0x04, 0x47,
+	0xf6, 0x66, 0xd8, 0x24, 0x6f, 0xb7, 0xb8, 0x15, 0xcc, 0x49, 0x33, 0x4b, 0x4d, 0xbf, 0xc5, 0xff,
+	0xbc, 0xed, 0x63, 0x2f, 0xa7, 0x6b, 0x1f, 0xf6, 0x96, 0xd8, 0xa5, 0xb0, 0x0e, 0xb4, 0xbe, 0xb5,
+	0x7d, 0x2e, 0xf5, 0xfa, 0x52, 0x95, 0x36, 0x85, 0x76, 0xba, 0x2c, 0xda, 0xd1, 0x67, 0xb0, 0x21,
+	0x1d, 0x11, 0x49, 0x51, 0xc6, 0xc3, 0x18, 0xa1, 0xed, 0x8a, 0xa9, 0x91, 0x88, 0x98, 0xed, 0x27,
+	0xe8, 0x64, 0xea, 0x92, 0xee, 0x0b, 0xa8, 0x47, 0x27, 0x8d, 0xf8, 0x4a, 0xb8, 0xf3, 0x8a, 0xd1,
+	0x2e, 0x60, 0x3b, 0x98, 0x1c, 0xa4, 0xa3, 0xff, 0xdf, 0x0b, 0x19, 0xe7, 0xe4, 0x4a, 0x32, 0x27,
+	0xbf, 0x81, 0x9d, 0x04, 0x56, 0x2a, 0x1a, 0xc1, 0x6a, 0x70, 0x33, 0xe4, 0x9e, 0x3e, 0xf7, 0x22,
+	0x11, 0xed, 0xda, 0x06, 0xac, 0x09, 0x75, 0xc3, 0xbf, 0x1b, 0xb0, 0x25, 0xf5, 0x4e, 0x08, 0x7b,
+	0xb6, 0x4d, 0x82, 0x7e, 0x01, 0x78, 0x4d, 0x45, 0x34, 0xd4, 0x0b, 0xae, 0x56, 0x7d, 0x29, 0x93,
+	0xd5, 0x93, 0x4a, 0x18, 0xf9, 0xec, 0xdf, 0x43, 0x7f, 0x28, 0xd0, 0x4c, 0x45, 0x25, 0x3a, 0x2b,
+	0x1c, 0x94, 0x97, 0xd4, 0xea, 0xa8, 0x2a, 0x2c, 0x96, 0xf0, 0x3b, 0x6c, 0x26, 0x43, 0x12, 0x9d,
+	0x16, 0x9f, 0x64, 0x39, 0xc2, 0xd5, 0xb3, 0x8a, 0xa8, 0x98, 0xfe, 0x57, 0x68, 0x24, 0x62, 0x13,
+	0x95, 0xf2, 0x31, 0xf3, 0x0a, 0xab, 0xa7, 0xd5, 0x40, 0x31, 0xf7, 0x9f, 0x0a, 0x6c, 0xa5, 0xc3,
+	0x14, 0x95, 0xf5, 0x31, 0x2b, 0xe1, 0xbc, 0x32, 0x2e, 0xb5, 0x03, 0xa9, 0x20, 0x45, 0x25, 0xcd,
+	0xcc, 0x6a, 0x18, 0x55, 0x85, 0x65, 0x8c, 0x48, 0x06, 0x67, 0x29, 0x23, 0x72, 0x32, 0x5e, 0x3d,
+	0xaf, 0x8c, 0x8b, 0x55, 0xfc, 0xa5, 0xc0, 0xfb, 0x99, 0x98, 0x44, 0xa5, 0xc7, 0x65, 0xcd, 0xf8,
+	0xb4, 0x3a, 0x30, 0xf9, 0x4a, 0x24, 0xc3, 0xb7, 0xc4, 0x2b, 0x91, 0x13, 0xe1, 0xea, 0x59, 0x45,
+	0x54, 0x6a, 0x21, 0x52, 0x71, 0x8c, 0xca, 0x8d, 0xca, 0xc6, 0xba, 0x3a, 0xaa, 0x0a, 0x8b, 0x25,
+	0x70, 0xa8, 0xc7, 0xd1, 0x8b, 0x8e, 0x4b, 0x8d, 0x49, 0x46, 0xbc, 0x3a, 0xac, 0x02, 0x89, 0x58,
+	0xbf, 0x34, 0xe0, 0xd0, 0xa4, 0x4e, 0x11, 0xf4, 0x56, 0xf9, 0x79, 0x5d, 0x7e, 0xfd, 0x67, 0xe5,
+	0xe0, 0xc7, 0xa1, 0x81, 0x17, 0xfa, 0x65, 0xd0, 0x3c, 0xf6, 0x3c, 0xfd, 0x36, 0x6a, 0xbe, 0x14,
+	0x1d, 0xf7, 0x6b, 0xe1, 0x6d, 0x70, 0xf2, 0xef, 0x00, 0x75, 0xdc, 0xae, 0xd0, 0x62, 0x0d, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -646,6 +1232,11 @@ type HandlerServiceClient interface {
 	AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*AddOutboundResponse, error)
 	RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*RemoveOutboundResponse, error)
 	AlterOutbound(ctx context.Context, in *AlterOutboundRequest, opts ...grpc.CallOption) (*AlterOutboundResponse, error)
+	ReplaceInbound(ctx context.Context, in *ReplaceInboundRequest, opts ...grpc.CallOption) (*ReplaceInboundResponse, error)
+	ReplaceOutbound(ctx context.Context, in *ReplaceOutboundRequest, opts ...grpc.CallOption) (*ReplaceOutboundResponse, error)
+	ListInbounds(ctx context.Context, in *ListInboundsRequest, opts ...grpc.CallOption) (*ListInboundsResponse, error)
+	ListOutbounds(ctx context.Context, in *ListOutboundsRequest, opts ...grpc.CallOption) (*ListOutboundsResponse, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
 }
 
 type handlerServiceClient struct {
@@ -710,6 +1301,51 @@ func (c *handlerServiceClient) AlterOutbound(ctx context.Context, in *AlterOutbo
 	return out, nil
 }
 
+func (c *handlerServiceClient) ReplaceInbound(ctx context.Context, in *ReplaceInboundRequest, opts ...grpc.CallOption) (*ReplaceInboundResponse, error) {
+	out := new(ReplaceInboundResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ReplaceInbound", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) ReplaceOutbound(ctx context.Context, in *ReplaceOutboundRequest, opts ...grpc.CallOption) (*ReplaceOutboundResponse, error) {
+	out := new(ReplaceOutboundResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ReplaceOutbound", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) ListInbounds(ctx context.Context, in *ListInboundsRequest, opts ...grpc.CallOption) (*ListInboundsResponse, error) {
+	out := new(ListInboundsResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ListInbounds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) ListOutbounds(ctx context.Context, in *ListOutboundsRequest, opts ...grpc.CallOption) (*ListOutboundsResponse, error) {
+	out := new(ListOutboundsResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ListOutbounds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HandlerServiceServer is the server API for HandlerService service.
 type HandlerServiceServer interface {
 	AddInbound(context.Context, *AddInboundRequest) (*AddInboundResponse, error)
@@ -718,6 +1354,11 @@ type HandlerServiceServer interface {
 	AddOutbound(context.Context, *AddOutboundRequest) (*AddOutboundResponse, error)
 	RemoveOutbound(context.Context, *RemoveOutboundRequest) (*RemoveOutboundResponse, error)
 	AlterOutbound(context.Context, *AlterOutboundRequest) (*AlterOutboundResponse, error)
+	ReplaceInbound(context.Context, *ReplaceInboundRequest) (*ReplaceInboundResponse, error)
+	ReplaceOutbound(context.Context, *ReplaceOutboundRequest) (*ReplaceOutboundResponse, error)
+	ListInbounds(context.Context, *ListInboundsRequest) (*ListInboundsResponse, error)
+	ListOutbounds(context.Context, *ListOutboundsRequest) (*ListOutboundsResponse, error)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
 }
 
 func RegisterHandlerServiceServer(s *grpc.Server, srv HandlerServiceServer) {
@@ -832,6 +1473,96 @@ func _HandlerService_AlterOutbound_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HandlerService_ReplaceInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceInboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ReplaceInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ReplaceInbound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ReplaceInbound(ctx, req.(*ReplaceInboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_ReplaceOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceOutboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ReplaceOutbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ReplaceOutbound",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ReplaceOutbound(ctx, req.(*ReplaceOutboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_ListInbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInboundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ListInbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ListInbounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ListInbounds(ctx, req.(*ListInboundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_ListOutbounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOutboundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ListOutbounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ListOutbounds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ListOutbounds(ctx, req.(*ListOutboundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _HandlerService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v2ray.core.app.proxyman.command.HandlerService",
 	HandlerType: (*HandlerServiceServer)(nil),
@@ -860,6 +1591,26 @@ var _HandlerService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AlterOutbound",
 			Handler:    _HandlerService_AlterOutbound_Handler,
 		},
+		{
+			MethodName: "ReplaceInbound",
+			Handler:    _HandlerService_ReplaceInbound_Handler,
+		},
+		{
+			MethodName: "ReplaceOutbound",
+			Handler:    _HandlerService_ReplaceOutbound_Handler,
+		},
+		{
+			MethodName: "ListInbounds",
+			Handler:    _HandlerService_ListInbounds_Handler,
+		},
+		{
+			MethodName: "ListOutbounds",
+			Handler:    _HandlerService_ListOutbounds_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _HandlerService_ListUsers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v2ray.com/core/app/proxyman/command/command.proto",
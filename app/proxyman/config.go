@@ -1,5 +1,13 @@
 package proxyman
 
+import (
+	"crypto/rand"
+	"crypto/sha256"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+)
+
 func (s *AllocationStrategy) GetConcurrencyValue() uint32 {
 	if s == nil || s.Concurrency == nil {
 		return 3
@@ -37,3 +45,48 @@ func (c *ReceiverConfig) GetEffectiveSniffingSettings() *SniffingConfig {
 
 	return nil
 }
+
+// GetListenAddresses returns all addresses that the receiver listens on.
+func (c *ReceiverConfig) GetListenAddresses() []net.Address {
+	var addresses []net.Address
+	if c.Listen != nil {
+		addresses = append(addresses, c.Listen.AsAddress())
+	}
+	for _, listen := range c.ListenList {
+		addresses = append(addresses, listen.AsAddress())
+	}
+	if len(addresses) == 0 {
+		addresses = append(addresses, net.AnyIP)
+	}
+	return addresses
+}
+
+// GetPortRanges returns all port ranges that the receiver listens on.
+func (c *ReceiverConfig) GetPortRanges() []*net.PortRange {
+	var ranges []*net.PortRange
+	if c.PortRange != nil {
+		ranges = append(ranges, c.PortRange)
+	}
+	if c.PortList != nil {
+		ranges = append(ranges, c.PortList.Range...)
+	}
+	return ranges
+}
+
+// PickAddress returns an address in the prefix. In User mode, the same user always gets the same address.
+// Connections without a user get a random address.
+func (p *ViaPrefix) PickAddress(user string) net.Address {
+	ip := make([]byte, len(p.Ip))
+	if p.Mode == ViaPrefix_User && len(user) > 0 {
+		hash := sha256.Sum256([]byte(user))
+		copy(ip, hash[:])
+	} else {
+		common.Must2(rand.Read(ip))
+	}
+
+	mask := net.CIDRMask(int(p.Prefix), len(ip)*8)
+	for i := range ip {
+		ip[i] = (p.Ip[i] & mask[i]) | (ip[i] &^ mask[i])
+	}
+	return net.IPAddress(ip)
+}
@@ -52,7 +52,8 @@ const (
 	AllocationStrategy_Always AllocationStrategy_Type = 0
 	// Randomly allocate specific range of handlers.
 	AllocationStrategy_Random AllocationStrategy_Type = 1
-	// External. Not supported yet.
+	// External. Ports are assigned by API calls. Handlers are not listening
+	// until then.
 	AllocationStrategy_External AllocationStrategy_Type = 2
 )
 
@@ -76,6 +77,33 @@ func (AllocationStrategy_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_b07f45dd938bc1b0, []int{1, 0}
 }
 
+type ViaPrefix_Mode int32
+
+const (
+	// A random address for each connection.
+	ViaPrefix_Random ViaPrefix_Mode = 0
+	// A stable address for each user, derived from the email of the user.
+	ViaPrefix_User ViaPrefix_Mode = 1
+)
+
+var ViaPrefix_Mode_name = map[int32]string{
+	0: "Random",
+	1: "User",
+}
+
+var ViaPrefix_Mode_value = map[string]int32{
+	"Random": 0,
+	"User":   1,
+}
+
+func (x ViaPrefix_Mode) String() string {
+	return proto.EnumName(ViaPrefix_Mode_name, int32(x))
+}
+
+func (ViaPrefix_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b07f45dd938bc1b0, []int{8, 0}
+}
+
 type InboundConfig struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -312,11 +340,18 @@ type ReceiverConfig struct {
 	ReceiveOriginalDestination bool                   `protobuf:"varint,5,opt,name=receive_original_destination,json=receiveOriginalDestination,proto3" json:"receive_original_destination,omitempty"`
 	// Override domains for the given protocol.
 	// Deprecated. Use sniffing_settings.
-	DomainOverride       []KnownProtocols `protobuf:"varint,7,rep,packed,name=domain_override,json=domainOverride,proto3,enum=v2ray.core.app.proxyman.KnownProtocols" json:"domain_override,omitempty"` // Deprecated: Do not use.
-	SniffingSettings     *SniffingConfig  `protobuf:"bytes,8,opt,name=sniffing_settings,json=sniffingSettings,proto3" json:"sniffing_settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	DomainOverride   []KnownProtocols `protobuf:"varint,7,rep,packed,name=domain_override,json=domainOverride,proto3,enum=v2ray.core.app.proxyman.KnownProtocols" json:"domain_override,omitempty"` // Deprecated: Do not use.
+	SniffingSettings *SniffingConfig  `protobuf:"bytes,8,opt,name=sniffing_settings,json=sniffingSettings,proto3" json:"sniffing_settings,omitempty"`
+	// PortList specifies additional ports which the Receiver should listen on.
+	PortList *net.PortList `protobuf:"bytes,9,opt,name=port_list,json=portList,proto3" json:"port_list,omitempty"`
+	// ListenList specifies additional addresses that the Receiver should listen
+	// on. A domain starting with "/" or "@" is the path of a Unix domain socket.
+	// Both port_list and listen_list are only available with the Always
+	// allocation strategy.
+	ListenList           []*net.IPOrDomain `protobuf:"bytes,10,rep,name=listen_list,json=listenList,proto3" json:"listen_list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *ReceiverConfig) Reset()         { *m = ReceiverConfig{} }
@@ -394,6 +429,20 @@ func (m *ReceiverConfig) GetSniffingSettings() *SniffingConfig {
 	return nil
 }
 
+func (m *ReceiverConfig) GetPortList() *net.PortList {
+	if m != nil {
+		return m.PortList
+	}
+	return nil
+}
+
+func (m *ReceiverConfig) GetListenList() []*net.IPOrDomain {
+	if m != nil {
+		return m.ListenList
+	}
+	return nil
+}
+
 type InboundHandlerConfig struct {
 	Tag                  string               `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
 	ReceiverSettings     *serial.TypedMessage `protobuf:"bytes,2,opt,name=receiver_settings,json=receiverSettings,proto3" json:"receiver_settings,omitempty"`
@@ -482,13 +531,18 @@ var xxx_messageInfo_OutboundConfig proto.InternalMessageInfo
 
 type SenderConfig struct {
 	// Send traffic through the given IP. Only IP is allowed.
-	Via                  *net.IPOrDomain        `protobuf:"bytes,1,opt,name=via,proto3" json:"via,omitempty"`
-	StreamSettings       *internet.StreamConfig `protobuf:"bytes,2,opt,name=stream_settings,json=streamSettings,proto3" json:"stream_settings,omitempty"`
-	ProxySettings        *internet.ProxyConfig  `protobuf:"bytes,3,opt,name=proxy_settings,json=proxySettings,proto3" json:"proxy_settings,omitempty"`
-	MultiplexSettings    *MultiplexingConfig    `protobuf:"bytes,4,opt,name=multiplex_settings,json=multiplexSettings,proto3" json:"multiplex_settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Via               *net.IPOrDomain        `protobuf:"bytes,1,opt,name=via,proto3" json:"via,omitempty"`
+	StreamSettings    *internet.StreamConfig `protobuf:"bytes,2,opt,name=stream_settings,json=streamSettings,proto3" json:"stream_settings,omitempty"`
+	ProxySettings     *internet.ProxyConfig  `protobuf:"bytes,3,opt,name=proxy_settings,json=proxySettings,proto3" json:"proxy_settings,omitempty"`
+	MultiplexSettings *MultiplexingConfig    `protobuf:"bytes,4,opt,name=multiplex_settings,json=multiplexSettings,proto3" json:"multiplex_settings,omitempty"`
+	// Send traffic through addresses in the given prefix. Overrides via.
+	ViaPrefix *ViaPrefix `protobuf:"bytes,5,opt,name=via_prefix,json=viaPrefix,proto3" json:"via_prefix,omitempty"`
+	// Pre-established connections to the servers of this outbound. Not available
+	// for freedom, which has no fixed server.
+	ConnectionPool       *ConnectionPoolConfig `protobuf:"bytes,6,opt,name=connection_pool,json=connectionPool,proto3" json:"connection_pool,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *SenderConfig) Reset()         { *m = SenderConfig{} }
@@ -544,21 +598,163 @@ func (m *SenderConfig) GetMultiplexSettings() *MultiplexingConfig {
 	return nil
 }
 
+func (m *SenderConfig) GetViaPrefix() *ViaPrefix {
+	if m != nil {
+		return m.ViaPrefix
+	}
+	return nil
+}
+
+func (m *SenderConfig) GetConnectionPool() *ConnectionPoolConfig {
+	if m != nil {
+		return m.ConnectionPool
+	}
+	return nil
+}
+
+type ConnectionPoolConfig struct {
+	// Number of idle connections kept for each destination.
+	Size uint32 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// Seconds before an idle connection is discarded. 0 for the default of 3,
+	// which is below the default handshake timeout of V2Ray servers.
+	MaxIdleTime uint32 `protobuf:"varint,2,opt,name=max_idle_time,json=maxIdleTime,proto3" json:"max_idle_time,omitempty"`
+	// Seconds the pool keeps being refilled after it is last used. 0 for the
+	// default of 60.
+	IdleTimeout          uint32   `protobuf:"varint,3,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionPoolConfig) Reset()         { *m = ConnectionPoolConfig{} }
+func (m *ConnectionPoolConfig) String() string { return proto.CompactTextString(m) }
+func (*ConnectionPoolConfig) ProtoMessage()    {}
+func (*ConnectionPoolConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b07f45dd938bc1b0, []int{7}
+}
+
+func (m *ConnectionPoolConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionPoolConfig.Unmarshal(m, b)
+}
+func (m *ConnectionPoolConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionPoolConfig.Marshal(b, m, deterministic)
+}
+func (m *ConnectionPoolConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionPoolConfig.Merge(m, src)
+}
+func (m *ConnectionPoolConfig) XXX_Size() int {
+	return xxx_messageInfo_ConnectionPoolConfig.Size(m)
+}
+func (m *ConnectionPoolConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionPoolConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionPoolConfig proto.InternalMessageInfo
+
+func (m *ConnectionPoolConfig) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ConnectionPoolConfig) GetMaxIdleTime() uint32 {
+	if m != nil {
+		return m.MaxIdleTime
+	}
+	return 0
+}
+
+func (m *ConnectionPoolConfig) GetIdleTimeout() uint32 {
+	if m != nil {
+		return m.IdleTimeout
+	}
+	return 0
+}
+
+type ViaPrefix struct {
+	// IP address of the prefix, 4 or 16 bytes.
+	Ip []byte `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Length of the prefix in bits.
+	Prefix               uint32         `protobuf:"varint,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Mode                 ViaPrefix_Mode `protobuf:"varint,3,opt,name=mode,proto3,enum=v2ray.core.app.proxyman.ViaPrefix_Mode" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ViaPrefix) Reset()         { *m = ViaPrefix{} }
+func (m *ViaPrefix) String() string { return proto.CompactTextString(m) }
+func (*ViaPrefix) ProtoMessage()    {}
+func (*ViaPrefix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b07f45dd938bc1b0, []int{8}
+}
+
+func (m *ViaPrefix) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ViaPrefix.Unmarshal(m, b)
+}
+func (m *ViaPrefix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ViaPrefix.Marshal(b, m, deterministic)
+}
+func (m *ViaPrefix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ViaPrefix.Merge(m, src)
+}
+func (m *ViaPrefix) XXX_Size() int {
+	return xxx_messageInfo_ViaPrefix.Size(m)
+}
+func (m *ViaPrefix) XXX_DiscardUnknown() {
+	xxx_messageInfo_ViaPrefix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ViaPrefix proto.InternalMessageInfo
+
+func (m *ViaPrefix) GetIp() []byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *ViaPrefix) GetPrefix() uint32 {
+	if m != nil {
+		return m.Prefix
+	}
+	return 0
+}
+
+func (m *ViaPrefix) GetMode() ViaPrefix_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return ViaPrefix_Random
+}
+
 type MultiplexingConfig struct {
 	// Whether or not Mux is enabled.
 	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
 	// Max number of concurrent connections that one Mux connection can handle.
-	Concurrency          uint32   `protobuf:"varint,2,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Concurrency uint32 `protobuf:"varint,2,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	// Max number of underlying Mux connections. 0 for unlimited.
+	MaxConnections uint32 `protobuf:"varint,3,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	// Max number of connections that one Mux connection handles in total,
+	// before it is rotated. 0 for the default of 128.
+	MaxStreams uint32 `protobuf:"varint,4,opt,name=max_streams,json=maxStreams,proto3" json:"max_streams,omitempty"`
+	// Seconds before an idle Mux connection is closed. 0 for the default of 16.
+	IdleTimeout uint32 `protobuf:"varint,5,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	// Seconds between keep-alive frames on a Mux connection. 0 for no keep-alive.
+	KeepAliveInterval uint32 `protobuf:"varint,6,opt,name=keep_alive_interval,json=keepAliveInterval,proto3" json:"keep_alive_interval,omitempty"`
+	// Networks of destinations that go through Mux. Empty for all networks.
+	Networks             []net.Network `protobuf:"varint,7,rep,packed,name=networks,proto3,enum=v2ray.core.common.net.Network" json:"networks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *MultiplexingConfig) Reset()         { *m = MultiplexingConfig{} }
 func (m *MultiplexingConfig) String() string { return proto.CompactTextString(m) }
 func (*MultiplexingConfig) ProtoMessage()    {}
 func (*MultiplexingConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b07f45dd938bc1b0, []int{7}
+	return fileDescriptor_b07f45dd938bc1b0, []int{9}
 }
 
 func (m *MultiplexingConfig) XXX_Unmarshal(b []byte) error {
@@ -593,9 +789,45 @@ func (m *MultiplexingConfig) GetConcurrency() uint32 {
 	return 0
 }
 
+func (m *MultiplexingConfig) GetMaxConnections() uint32 {
+	if m != nil {
+		return m.MaxConnections
+	}
+	return 0
+}
+
+func (m *MultiplexingConfig) GetMaxStreams() uint32 {
+	if m != nil {
+		return m.MaxStreams
+	}
+	return 0
+}
+
+func (m *MultiplexingConfig) GetIdleTimeout() uint32 {
+	if m != nil {
+		return m.IdleTimeout
+	}
+	return 0
+}
+
+func (m *MultiplexingConfig) GetKeepAliveInterval() uint32 {
+	if m != nil {
+		return m.KeepAliveInterval
+	}
+	return 0
+}
+
+func (m *MultiplexingConfig) GetNetworks() []net.Network {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.app.proxyman.KnownProtocols", KnownProtocols_name, KnownProtocols_value)
 	proto.RegisterEnum("v2ray.core.app.proxyman.AllocationStrategy_Type", AllocationStrategy_Type_name, AllocationStrategy_Type_value)
+	proto.RegisterEnum("v2ray.core.app.proxyman.ViaPrefix_Mode", ViaPrefix_Mode_name, ViaPrefix_Mode_value)
 	proto.RegisterType((*InboundConfig)(nil), "v2ray.core.app.proxyman.InboundConfig")
 	proto.RegisterType((*AllocationStrategy)(nil), "v2ray.core.app.proxyman.AllocationStrategy")
 	proto.RegisterType((*AllocationStrategy_AllocationStrategyConcurrency)(nil), "v2ray.core.app.proxyman.AllocationStrategy.AllocationStrategyConcurrency")
@@ -605,6 +837,8 @@ func init() {
 	proto.RegisterType((*InboundHandlerConfig)(nil), "v2ray.core.app.proxyman.InboundHandlerConfig")
 	proto.RegisterType((*OutboundConfig)(nil), "v2ray.core.app.proxyman.OutboundConfig")
 	proto.RegisterType((*SenderConfig)(nil), "v2ray.core.app.proxyman.SenderConfig")
+	proto.RegisterType((*ConnectionPoolConfig)(nil), "v2ray.core.app.proxyman.ConnectionPoolConfig")
+	proto.RegisterType((*ViaPrefix)(nil), "v2ray.core.app.proxyman.ViaPrefix")
 	proto.RegisterType((*MultiplexingConfig)(nil), "v2ray.core.app.proxyman.MultiplexingConfig")
 }
 
@@ -613,57 +847,76 @@ func init() {
 }
 
 var fileDescriptor_b07f45dd938bc1b0 = []byte{
-	// 825 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x95, 0x4f, 0x6f, 0xdb, 0x36,
-	0x18, 0xc6, 0x6b, 0xcb, 0x8d, 0x9d, 0x37, 0x8d, 0xaa, 0xb0, 0x01, 0xaa, 0x79, 0x1b, 0xe0, 0x79,
-	0xc3, 0x6a, 0x74, 0x83, 0xd4, 0xba, 0xd8, 0x61, 0xa7, 0x2d, 0x4d, 0x0a, 0x34, 0xdb, 0x82, 0x78,
-	0xb4, 0xb1, 0x43, 0xb1, 0x41, 0x60, 0x24, 0x46, 0x23, 0x26, 0x91, 0x02, 0x49, 0xbb, 0xd1, 0x57,
-	0xda, 0x79, 0x1f, 0x60, 0xc7, 0x1d, 0xf6, 0xa1, 0x06, 0x89, 0x92, 0xff, 0xd4, 0x56, 0xd6, 0xa0,
-	0x37, 0xda, 0x7a, 0xde, 0x9f, 0xde, 0xf7, 0xe1, 0x43, 0x0a, 0x46, 0x8b, 0xb1, 0x24, 0xb9, 0x17,
-	0x8a, 0xd4, 0x0f, 0x85, 0xa4, 0x3e, 0xc9, 0x32, 0x3f, 0x93, 0xe2, 0x26, 0x4f, 0x09, 0xf7, 0x43,
-	0xc1, 0xaf, 0x59, 0xec, 0x65, 0x52, 0x68, 0x81, 0x1e, 0xd7, 0x4a, 0x49, 0x3d, 0x92, 0x65, 0x5e,
-	0xad, 0xea, 0x3f, 0x79, 0x07, 0x11, 0x8a, 0x34, 0x15, 0xdc, 0xe7, 0x54, 0xfb, 0x24, 0x8a, 0x24,
-	0x55, 0xca, 0x10, 0xfa, 0x5f, 0x34, 0x0b, 0x33, 0x21, 0x75, 0xa5, 0xf2, 0xde, 0x51, 0x69, 0x49,
-	0xb8, 0x2a, 0x9e, 0xfb, 0x8c, 0x6b, 0x2a, 0x0b, 0xf5, 0x7a, 0x5f, 0xfd, 0x67, 0xbb, 0xa9, 0x8a,
-	0x4a, 0x46, 0x12, 0x5f, 0xe7, 0x19, 0x8d, 0x82, 0x94, 0x2a, 0x45, 0x62, 0x6a, 0x2a, 0x86, 0x0f,
-	0xe1, 0xf0, 0x9c, 0x5f, 0x89, 0x39, 0x8f, 0x4e, 0x4b, 0xd0, 0xf0, 0x6f, 0x0b, 0xd0, 0x49, 0x92,
-	0x88, 0x90, 0x68, 0x26, 0xf8, 0x54, 0x4b, 0xa2, 0x69, 0x9c, 0xa3, 0x33, 0xe8, 0x14, 0xe5, 0x6e,
-	0x6b, 0xd0, 0x1a, 0xd9, 0xe3, 0x67, 0x5e, 0x83, 0x01, 0xde, 0x76, 0xa9, 0x37, 0xcb, 0x33, 0x8a,
-	0xcb, 0x6a, 0xf4, 0x07, 0x1c, 0x84, 0x82, 0x87, 0x73, 0x29, 0x29, 0x0f, 0x73, 0xb7, 0x3d, 0x68,
-	0x8d, 0x0e, 0xc6, 0xe7, 0x77, 0x81, 0x6d, 0xff, 0x75, 0xba, 0x02, 0xe2, 0x75, 0x3a, 0x0a, 0xa0,
-	0x2b, 0xe9, 0xb5, 0xa4, 0xea, 0x77, 0xd7, 0x2a, 0x5f, 0xf4, 0xea, 0xc3, 0x5e, 0x84, 0x0d, 0x0c,
-	0xd7, 0xd4, 0xfe, 0x37, 0xf0, 0xe9, 0xad, 0xed, 0xa0, 0x63, 0xb8, 0xbf, 0x20, 0xc9, 0xdc, 0xb8,
-	0x76, 0x88, 0xcd, 0x8f, 0xfe, 0x73, 0xf8, 0xa8, 0x11, 0xbe, 0xbb, 0x64, 0xf8, 0x35, 0x74, 0x0a,
-	0x17, 0x11, 0xc0, 0xde, 0x49, 0xf2, 0x96, 0xe4, 0xca, 0xb9, 0x57, 0xac, 0x31, 0xe1, 0x91, 0x48,
-	0x9d, 0x16, 0x7a, 0x00, 0xbd, 0x57, 0x37, 0x45, 0x20, 0x48, 0xe2, 0xb4, 0x87, 0xbf, 0x81, 0x3d,
-	0xe5, 0xec, 0xfa, 0x9a, 0xf1, 0xd8, 0x6c, 0x2a, 0x72, 0xa1, 0x4b, 0x39, 0xb9, 0x4a, 0x68, 0x54,
-	0x72, 0x7b, 0xb8, 0xfe, 0x89, 0x9e, 0xc3, 0x71, 0x44, 0x95, 0x66, 0xbc, 0xec, 0x26, 0x10, 0x0b,
-	0x2a, 0x25, 0x8b, 0xa8, 0xdb, 0x1e, 0x58, 0xa3, 0x7d, 0xfc, 0x68, 0xed, 0xd9, 0x65, 0xf5, 0x68,
-	0xf8, 0x57, 0x07, 0x6c, 0x4c, 0x43, 0xca, 0x16, 0x54, 0x56, 0xfc, 0xef, 0x00, 0x8a, 0x54, 0x06,
-	0x92, 0xf0, 0xd8, 0xb4, 0x7e, 0x30, 0x1e, 0xac, 0xbb, 0x6d, 0x82, 0xe8, 0x71, 0xaa, 0xbd, 0x89,
-	0x90, 0x1a, 0x17, 0x3a, 0xbc, 0x9f, 0xd5, 0x4b, 0xf4, 0x2d, 0xec, 0x25, 0x4c, 0x69, 0xca, 0xab,
-	0x4c, 0x7c, 0xd6, 0x50, 0x7c, 0x3e, 0xb9, 0x94, 0x67, 0x22, 0x25, 0x8c, 0xe3, 0xaa, 0x00, 0xfd,
-	0x0a, 0x8f, 0xc8, 0xd2, 0xce, 0x40, 0x55, 0x7e, 0x56, 0x5b, 0xfe, 0xd5, 0x1d, 0xb6, 0x1c, 0x23,
-	0xb2, 0x9d, 0xfb, 0x19, 0x3c, 0x54, 0x5a, 0x52, 0x92, 0x06, 0x8a, 0x6a, 0xcd, 0x78, 0xac, 0xdc,
-	0xce, 0x36, 0x79, 0x79, 0x2e, 0xbd, 0xfa, 0x5c, 0x7a, 0xd3, 0xb2, 0xca, 0xf8, 0x83, 0x6d, 0xc3,
-	0x98, 0x56, 0x08, 0xf4, 0x3d, 0x7c, 0x22, 0x8d, 0x83, 0x81, 0x90, 0x2c, 0x66, 0x9c, 0x24, 0xc1,
-	0x9a, 0xd5, 0xee, 0xfd, 0x72, 0x93, 0xfa, 0x95, 0xe6, 0xb2, 0x92, 0x9c, 0xad, 0x14, 0x45, 0x5f,
-	0x51, 0xe9, 0xc3, 0x6a, 0xcb, 0xba, 0x03, 0x6b, 0x64, 0x8f, 0x9f, 0x34, 0x4e, 0xfc, 0x23, 0x17,
-	0x6f, 0xf9, 0xa4, 0x38, 0xf5, 0xa1, 0x48, 0xd4, 0xcb, 0xb6, 0xdb, 0xc2, 0xb6, 0x61, 0xd4, 0x5b,
-	0x8b, 0x66, 0x70, 0xa4, 0xaa, 0xe4, 0xac, 0xe6, 0xed, 0x95, 0xf3, 0x36, 0x73, 0x37, 0xb3, 0x86,
-	0x9d, 0x9a, 0x50, 0x4f, 0xfb, 0x43, 0xa7, 0xb7, 0xe7, 0x74, 0x87, 0xff, 0xb6, 0xe0, 0xb8, 0xba,
-	0x6a, 0x5e, 0x13, 0x1e, 0x25, 0xcb, 0xf0, 0x38, 0x60, 0x69, 0x12, 0x97, 0xa9, 0xd9, 0xc7, 0xc5,
-	0x12, 0x4d, 0xe1, 0xa8, 0x1a, 0x5d, 0xae, 0xda, 0x30, 0xc1, 0xf8, 0x72, 0x47, 0x30, 0xcc, 0xf5,
-	0x56, 0xde, 0x33, 0xd1, 0x85, 0xb9, 0xdd, 0xb0, 0x53, 0x03, 0x96, 0x9e, 0x5f, 0x80, 0x5d, 0xb6,
-	0xbc, 0x22, 0x5a, 0x77, 0x22, 0x1e, 0x96, 0xd5, 0x35, 0x6e, 0xe8, 0x80, 0x7d, 0x39, 0xd7, 0xeb,
-	0x37, 0xe7, 0x3f, 0x6d, 0x78, 0x30, 0xa5, 0x3c, 0x5a, 0x0e, 0xf6, 0x02, 0xac, 0x05, 0x23, 0xd5,
-	0x71, 0x78, 0x8f, 0x44, 0x17, 0xea, 0x5d, 0x81, 0x6b, 0x7f, 0x78, 0xe0, 0x7e, 0x6e, 0x18, 0xfe,
-	0xe9, 0xff, 0x40, 0x27, 0x45, 0x51, 0xc5, 0xdc, 0x34, 0x00, 0xbd, 0x01, 0x94, 0xce, 0x13, 0xcd,
-	0xb2, 0x84, 0xde, 0xdc, 0x7a, 0x38, 0x36, 0xc2, 0x72, 0x51, 0x97, 0xac, 0x02, 0x73, 0xb4, 0xc4,
-	0x2c, 0xcd, 0x9d, 0x00, 0xda, 0x16, 0xde, 0x72, 0x8b, 0x0d, 0xb6, 0xbf, 0x2b, 0x87, 0x1b, 0x1f,
-	0x83, 0xa7, 0x9f, 0x83, 0xbd, 0x99, 0x7f, 0xd4, 0x83, 0xce, 0xeb, 0xd9, 0x6c, 0xe2, 0xdc, 0x43,
-	0x5d, 0xb0, 0x66, 0x3f, 0x4d, 0x9d, 0xd6, 0xcb, 0x53, 0xf8, 0x38, 0x14, 0x69, 0x53, 0xef, 0x93,
-	0xd6, 0x9b, 0x5e, 0xbd, 0xfe, 0xb3, 0xfd, 0xf8, 0x97, 0x31, 0x26, 0xb9, 0x77, 0x5a, 0xa8, 0x4e,
-	0xb2, 0xcc, 0x38, 0x95, 0x12, 0x7e, 0xb5, 0x57, 0x7e, 0x58, 0x5f, 0xfc, 0x17, 0x00, 0x00, 0xff,
-	0xff, 0x4d, 0xe1, 0x89, 0x0d, 0x4e, 0x08, 0x00, 0x00,
+	// 1129 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x96, 0xdd, 0x92, 0xdb, 0xb4,
+	0x1b, 0xc6, 0x9b, 0x8f, 0xcd, 0x26, 0x6f, 0x36, 0xd9, 0xac, 0xba, 0xf3, 0x6f, 0xfe, 0xa1, 0xd0,
+	0xd4, 0x30, 0x74, 0xa7, 0x80, 0xd3, 0xa6, 0xc3, 0x01, 0x1f, 0x33, 0xb0, 0xdd, 0x76, 0xa6, 0x0b,
+	0x5d, 0x36, 0x28, 0xa1, 0x07, 0x1d, 0x18, 0x8f, 0x6a, 0x6b, 0x83, 0xa6, 0xb6, 0x64, 0x64, 0x25,
+	0x4d, 0xb8, 0x08, 0xee, 0x03, 0x2e, 0x80, 0x63, 0x2e, 0x80, 0xbb, 0xe0, 0x46, 0x18, 0x4b, 0xb2,
+	0x93, 0x34, 0x9b, 0xd0, 0x9d, 0x9e, 0xc9, 0xd6, 0xf3, 0xfe, 0x6c, 0x3d, 0xef, 0x23, 0xd9, 0x70,
+	0x34, 0xed, 0x4b, 0x32, 0x77, 0x7d, 0x11, 0xf5, 0x7c, 0x21, 0x69, 0x8f, 0xc4, 0x71, 0x2f, 0x96,
+	0x62, 0x36, 0x8f, 0x08, 0xef, 0xf9, 0x82, 0x5f, 0xb0, 0xb1, 0x1b, 0x4b, 0xa1, 0x04, 0xba, 0x91,
+	0x29, 0x25, 0x75, 0x49, 0x1c, 0xbb, 0x99, 0xaa, 0x73, 0xe7, 0x35, 0x84, 0x2f, 0xa2, 0x48, 0xf0,
+	0x1e, 0xa7, 0xaa, 0x47, 0x82, 0x40, 0xd2, 0x24, 0x31, 0x84, 0xce, 0x07, 0x9b, 0x85, 0xb1, 0x90,
+	0xca, 0xaa, 0xb6, 0xe0, 0x38, 0x55, 0xaf, 0x84, 0x7c, 0x69, 0x85, 0xee, 0x6b, 0x42, 0x25, 0x09,
+	0x4f, 0x52, 0x50, 0x8f, 0x71, 0x45, 0x65, 0x5a, 0xb0, 0xbc, 0x80, 0xce, 0xbd, 0xcb, 0xc1, 0x09,
+	0x95, 0x8c, 0x84, 0x3d, 0x35, 0x8f, 0x69, 0xe0, 0x45, 0x34, 0x49, 0xc8, 0x98, 0x9a, 0x0a, 0x67,
+	0x1f, 0x1a, 0xa7, 0xfc, 0x85, 0x98, 0xf0, 0xe0, 0x44, 0x83, 0x9c, 0xbf, 0x4a, 0x80, 0x8e, 0xc3,
+	0x50, 0xf8, 0x44, 0x31, 0xc1, 0x87, 0x4a, 0x12, 0x45, 0xc7, 0x73, 0xf4, 0x08, 0xca, 0x69, 0x79,
+	0xbb, 0xd0, 0x2d, 0x1c, 0x35, 0xfb, 0xf7, 0xdc, 0x0d, 0x4e, 0xb9, 0xeb, 0xa5, 0xee, 0x68, 0x1e,
+	0x53, 0xac, 0xab, 0xd1, 0x4b, 0xa8, 0xfb, 0x82, 0xfb, 0x13, 0x29, 0x29, 0xf7, 0xe7, 0xed, 0x62,
+	0xb7, 0x70, 0x54, 0xef, 0x9f, 0x5e, 0x05, 0xb6, 0x7e, 0xeb, 0x64, 0x01, 0xc4, 0xcb, 0x74, 0xe4,
+	0xc1, 0xae, 0xa4, 0x17, 0x92, 0x26, 0x3f, 0xb7, 0x4b, 0xfa, 0x41, 0x8f, 0xdf, 0xee, 0x41, 0xd8,
+	0xc0, 0x70, 0x46, 0xed, 0x7c, 0x0a, 0xef, 0x6e, 0x7d, 0x1d, 0x74, 0x08, 0x3b, 0x53, 0x12, 0x4e,
+	0x8c, 0x6b, 0x0d, 0x6c, 0x2e, 0x3a, 0xf7, 0xe1, 0xff, 0x1b, 0xe1, 0x97, 0x97, 0x38, 0x1f, 0x43,
+	0x39, 0x75, 0x11, 0x01, 0x54, 0x8e, 0xc3, 0x57, 0x64, 0x9e, 0xb4, 0xae, 0xa5, 0x63, 0x4c, 0x78,
+	0x20, 0xa2, 0x56, 0x01, 0xed, 0x41, 0xf5, 0xf1, 0x2c, 0x0d, 0x04, 0x09, 0x5b, 0x45, 0xe7, 0x27,
+	0x68, 0x0e, 0x39, 0xbb, 0xb8, 0x60, 0x7c, 0x6c, 0x9a, 0x8a, 0xda, 0xb0, 0x4b, 0x39, 0x79, 0x11,
+	0xd2, 0x40, 0x73, 0xab, 0x38, 0xbb, 0x44, 0xf7, 0xe1, 0x30, 0xa0, 0x89, 0x62, 0x5c, 0xbf, 0x8d,
+	0x27, 0xa6, 0x54, 0x4a, 0x16, 0xd0, 0x76, 0xb1, 0x5b, 0x3a, 0xaa, 0xe1, 0xeb, 0x4b, 0x73, 0xe7,
+	0x76, 0xca, 0xf9, 0x73, 0x07, 0x9a, 0x98, 0xfa, 0x94, 0x4d, 0xa9, 0xb4, 0xfc, 0xaf, 0x00, 0xd2,
+	0x54, 0x7a, 0x92, 0xf0, 0xb1, 0x79, 0xf5, 0x7a, 0xbf, 0xbb, 0xec, 0xb6, 0x09, 0xa2, 0xcb, 0xa9,
+	0x72, 0x07, 0x42, 0x2a, 0x9c, 0xea, 0x70, 0x2d, 0xce, 0x86, 0xe8, 0x33, 0xa8, 0x84, 0x2c, 0x51,
+	0x94, 0xdb, 0x4c, 0xdc, 0xde, 0x50, 0x7c, 0x3a, 0x38, 0x97, 0x8f, 0x44, 0x44, 0x18, 0xc7, 0xb6,
+	0x00, 0xfd, 0x08, 0xd7, 0x49, 0x6e, 0xa7, 0x97, 0x58, 0x3f, 0x6d, 0xcb, 0x3f, 0xba, 0x42, 0xcb,
+	0x31, 0x22, 0xeb, 0xb9, 0x1f, 0xc1, 0x7e, 0xa2, 0x24, 0x25, 0x91, 0x97, 0x50, 0xa5, 0x18, 0x1f,
+	0x27, 0xed, 0xf2, 0x3a, 0x39, 0xdf, 0x97, 0x6e, 0xb6, 0x2f, 0xdd, 0xa1, 0xae, 0x32, 0xfe, 0xe0,
+	0xa6, 0x61, 0x0c, 0x2d, 0x02, 0x7d, 0x0d, 0x37, 0xa5, 0x71, 0xd0, 0x13, 0x92, 0x8d, 0x19, 0x27,
+	0xa1, 0xb7, 0x64, 0x75, 0x7b, 0x47, 0x37, 0xa9, 0x63, 0x35, 0xe7, 0x56, 0xf2, 0x68, 0xa1, 0x48,
+	0xdf, 0x2b, 0xd0, 0x3e, 0x2c, 0x5a, 0xb6, 0xdb, 0x2d, 0x1d, 0x35, 0xfb, 0x77, 0x36, 0xae, 0xf8,
+	0x5b, 0x2e, 0x5e, 0xf1, 0x41, 0xba, 0xeb, 0x7d, 0x11, 0x26, 0x0f, 0x8b, 0xed, 0x02, 0x6e, 0x1a,
+	0x46, 0xd6, 0x5a, 0x34, 0x82, 0x83, 0xc4, 0x26, 0x67, 0xb1, 0xde, 0xaa, 0x5e, 0xef, 0x66, 0xee,
+	0x6a, 0xd6, 0x70, 0x2b, 0x23, 0xe4, 0xab, 0xfd, 0x12, 0x74, 0xa7, 0xbd, 0xb4, 0x61, 0xed, 0x9a,
+	0xa6, 0xdd, 0xda, 0x12, 0x8e, 0xa7, 0x2c, 0x51, 0xb8, 0x1a, 0xdb, 0x11, 0x7a, 0x08, 0x75, 0xd3,
+	0x69, 0x53, 0x0f, 0xdd, 0xd2, 0x9b, 0xe5, 0x03, 0x4c, 0x55, 0xca, 0xf8, 0xa6, 0x5c, 0xad, 0xb4,
+	0x76, 0x9d, 0xbf, 0x0b, 0x70, 0x68, 0x0f, 0xbb, 0x27, 0x84, 0x07, 0x61, 0x1e, 0xdf, 0x16, 0x94,
+	0x14, 0x19, 0xeb, 0xdc, 0xd6, 0x70, 0x3a, 0x44, 0x43, 0x38, 0xb0, 0xe6, 0xcb, 0x85, 0x11, 0x26,
+	0x9a, 0x1f, 0x5e, 0xf2, 0x68, 0x73, 0xc0, 0xea, 0x93, 0x2e, 0x38, 0x33, 0xe7, 0x2b, 0x6e, 0x65,
+	0x80, 0xdc, 0x87, 0x33, 0x68, 0x6a, 0xd3, 0x16, 0xc4, 0xd2, 0x95, 0x88, 0x0d, 0x5d, 0x9d, 0xe1,
+	0x9c, 0x16, 0x34, 0xcf, 0x27, 0x6a, 0xf9, 0xec, 0xfe, 0xa7, 0x04, 0x7b, 0x43, 0xca, 0x83, 0x7c,
+	0x61, 0x0f, 0xa0, 0x34, 0x65, 0xc4, 0x6e, 0xc8, 0x37, 0xf0, 0x2c, 0x55, 0x5f, 0x16, 0xf9, 0xe2,
+	0xdb, 0x47, 0xfe, 0xfb, 0x0d, 0x8b, 0xbf, 0xfb, 0x1f, 0xd0, 0x41, 0x5a, 0x64, 0x99, 0xab, 0x06,
+	0xa0, 0xe7, 0x80, 0xa2, 0x49, 0xa8, 0x58, 0x1c, 0xd2, 0xd9, 0xd6, 0xed, 0xb9, 0x12, 0xd7, 0xb3,
+	0xac, 0x64, 0x11, 0xd9, 0x83, 0x1c, 0x93, 0xb3, 0x8f, 0x01, 0xa6, 0x8c, 0x78, 0xb1, 0xa4, 0x17,
+	0x6c, 0xa6, 0xf7, 0x63, 0xbd, 0xef, 0x6c, 0x64, 0x3e, 0x63, 0x64, 0xa0, 0x95, 0xb8, 0x36, 0xcd,
+	0x86, 0xe8, 0x19, 0xec, 0xfb, 0x82, 0x73, 0xea, 0xeb, 0x83, 0x29, 0x16, 0x22, 0x6c, 0x57, 0x34,
+	0xe7, 0x93, 0x8d, 0x9c, 0x93, 0x5c, 0x3f, 0x10, 0x22, 0xcc, 0x9c, 0xf4, 0x57, 0xee, 0x3a, 0xbf,
+	0xc0, 0xe1, 0x65, 0x3a, 0x84, 0xa0, 0x9c, 0xb0, 0x5f, 0xb3, 0x2f, 0x87, 0x1e, 0x23, 0x07, 0x1a,
+	0x11, 0x99, 0x79, 0x2c, 0x08, 0xa9, 0xa7, 0x58, 0x44, 0x75, 0x27, 0x1b, 0xb8, 0x1e, 0x91, 0xd9,
+	0x69, 0x10, 0xd2, 0x11, 0x8b, 0x28, 0xba, 0x0d, 0x7b, 0xf9, 0xbc, 0x98, 0x28, 0xdd, 0x97, 0x06,
+	0xae, 0x33, 0x3b, 0x2f, 0x26, 0xca, 0xf9, 0xad, 0x00, 0xb5, 0x7c, 0x8d, 0xa8, 0x09, 0x45, 0x16,
+	0xeb, 0xc7, 0xec, 0xe1, 0x22, 0x8b, 0xd1, 0xff, 0xa0, 0x62, 0x7d, 0x32, 0x74, 0x7b, 0x85, 0xbe,
+	0x80, 0x72, 0x24, 0x02, 0xaa, 0x81, 0xdb, 0x0e, 0xa6, 0x9c, 0xec, 0x9e, 0x89, 0x80, 0x62, 0x5d,
+	0xe4, 0xdc, 0x84, 0x72, 0x7a, 0xb5, 0xf4, 0x99, 0xbb, 0x86, 0xaa, 0x50, 0xfe, 0x21, 0xa1, 0xb2,
+	0x55, 0x70, 0x7e, 0x2f, 0x02, 0x5a, 0x6f, 0xe4, 0x96, 0xef, 0x5c, 0x77, 0xfd, 0xcf, 0xa3, 0xb1,
+	0xfa, 0xbb, 0x70, 0x07, 0xf6, 0x53, 0xab, 0x16, 0x66, 0x27, 0xd6, 0x89, 0x66, 0x44, 0x66, 0x0b,
+	0xc3, 0x13, 0x74, 0x0b, 0x52, 0xfb, 0x3c, 0x93, 0x6f, 0x93, 0xb7, 0x06, 0x86, 0x88, 0xcc, 0x4c,
+	0xfe, 0x93, 0x35, 0x43, 0x77, 0xd6, 0x0c, 0x45, 0x2e, 0x5c, 0x7f, 0x49, 0x69, 0xec, 0x91, 0x30,
+	0xfd, 0x06, 0xe8, 0xb0, 0x4f, 0x89, 0xc9, 0x47, 0x03, 0x1f, 0xa4, 0x53, 0xc7, 0xe9, 0xcc, 0xa9,
+	0x9d, 0x40, 0x9f, 0x43, 0xd5, 0xfe, 0x19, 0x26, 0xf6, 0x9c, 0x7f, 0x6f, 0xc3, 0x6e, 0xfe, 0xce,
+	0xc8, 0x70, 0xae, 0xbf, 0xfb, 0x3e, 0x34, 0x57, 0x8f, 0xfe, 0xd4, 0xc7, 0x27, 0xa3, 0xd1, 0xa0,
+	0x75, 0x0d, 0xed, 0x42, 0x69, 0xf4, 0x74, 0xd8, 0x2a, 0x3c, 0x3c, 0x81, 0x77, 0x7c, 0x11, 0x6d,
+	0x6a, 0xd1, 0xa0, 0xf0, 0xbc, 0x9a, 0x8d, 0xff, 0x28, 0xde, 0x78, 0xd6, 0xc7, 0x64, 0xee, 0x9e,
+	0xa4, 0xaa, 0xe3, 0x38, 0x36, 0x5b, 0x34, 0x22, 0xfc, 0x45, 0x45, 0xff, 0x53, 0x3e, 0xf8, 0x77,
+	0x00, 0x2d, 0x81, 0xb2, 0xe6, 0x72, 0x0b, 0x00, 0x00,
 }
@@ -0,0 +1,55 @@
+package proxyman_test
+
+import (
+	"testing"
+
+	. "v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common/net"
+)
+
+func TestViaPrefixPickAddress(t *testing.T) {
+	_, prefix, err := net.ParseCIDR("2001:db8:1:2::/64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	random := &ViaPrefix{
+		Ip:     prefix.IP,
+		Prefix: 64,
+	}
+	addresses := make(map[string]bool)
+	for i := 0; i < 16; i++ {
+		address := random.PickAddress("test@v2ray.com")
+		if !prefix.Contains(address.IP()) {
+			t.Fatal("address ", address, " is not in ", prefix)
+		}
+		addresses[address.String()] = true
+	}
+	if len(addresses) < 2 {
+		t.Error("random addresses are not random: ", addresses)
+	}
+
+	user := &ViaPrefix{
+		Ip:     prefix.IP,
+		Prefix: 64,
+		Mode:   ViaPrefix_User,
+	}
+	a1 := user.PickAddress("test@v2ray.com")
+	if !prefix.Contains(a1.IP()) {
+		t.Fatal("address ", a1, " is not in ", prefix)
+	}
+	if a2 := user.PickAddress("test@v2ray.com"); a2 != a1 {
+		t.Error("expected the same address for the same user, but got ", a1, " and ", a2)
+	}
+	if a3 := user.PickAddress("another@v2ray.com"); a3 == a1 {
+		t.Error("expected different addresses for different users, but got ", a3)
+	}
+
+	v4 := &ViaPrefix{
+		Ip:     []byte{192, 0, 2, 0},
+		Prefix: 24,
+	}
+	if address := v4.PickAddress(""); !address.Family().IsIPv4() || address.IP()[2] != 2 {
+		t.Error("unexpected address ", address)
+	}
+}
@@ -2,6 +2,7 @@ package inbound
 
 import (
 	"context"
+	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
@@ -10,6 +11,7 @@ import (
 	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/mux"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy"
@@ -46,10 +48,26 @@ type AlwaysOnInboundHandler struct {
 	workers []worker
 	mux     *mux.Server
 	tag     string
+	config  *core.InboundHandlerConfig
+}
+
+// contextWithGateway adds the listening address of the receiver into ctx as the gateway of the inbound, if the
+// receiver listens on a single address and port. Proxies that need their own address, such as Shadowsocks with a
+// plugin, get it from there.
+func contextWithGateway(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig) context.Context {
+	addresses := receiverConfig.GetListenAddresses()
+	ranges := receiverConfig.GetPortRanges()
+	if len(addresses) != 1 || internet.IsUnixSocketAddress(addresses[0]) || len(ranges) != 1 || ranges[0].From != ranges[0].To {
+		return ctx
+	}
+	return session.ContextWithInbound(ctx, &session.Inbound{
+		Gateway: net.TCPDestination(addresses[0], net.Port(ranges[0].From)),
+		Tag:     tag,
+	})
 }
 
 func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*AlwaysOnInboundHandler, error) {
-	rawProxy, err := common.CreateObject(ctx, proxyConfig)
+	rawProxy, err := common.CreateObject(contextWithGateway(ctx, tag, receiverConfig), proxyConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +85,6 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 	uplinkCounter, downlinkCounter := getStatCounter(core.MustFromContext(ctx), tag)
 
 	nl := p.Network()
-	pr := receiverConfig.PortRange
-	address := receiverConfig.Listen.AsAddress()
-	if address == nil {
-		address = net.AnyIP
-	}
 
 	mss, err := internet.ToMemoryStreamConfig(receiverConfig.StreamSettings)
 	if err != nil {
@@ -88,13 +101,14 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 		mss.SocketSettings.ReceiveOriginalDestAddress = true
 	}
 
-	for port := pr.From; port <= pr.To; port++ {
-		if net.HasNetwork(nl, net.Network_TCP) {
-			newError("creating stream worker on ", address, ":", port).AtDebug().WriteToLog()
-
-			worker := &tcpWorker{
+	for _, address := range receiverConfig.GetListenAddresses() {
+		if internet.IsUnixSocketAddress(address) {
+			if !net.HasNetwork(nl, net.Network_TCP) {
+				return nil, newError("unable to listen on Unix domain socket ", address, " for a non-stream proxy")
+			}
+			newError("creating stream worker on ", address).AtDebug().WriteToLog()
+			h.workers = append(h.workers, &tcpWorker{
 				address:         address,
-				port:            net.Port(port),
 				proxy:           p,
 				stream:          mss,
 				recvOrigDest:    receiverConfig.ReceiveOriginalDestination,
@@ -103,22 +117,44 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 				sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
-			}
-			h.workers = append(h.workers, worker)
+			})
+			continue
 		}
 
-		if net.HasNetwork(nl, net.Network_UDP) {
-			worker := &udpWorker{
-				tag:             tag,
-				proxy:           p,
-				address:         address,
-				port:            net.Port(port),
-				dispatcher:      h.mux,
-				uplinkCounter:   uplinkCounter,
-				downlinkCounter: downlinkCounter,
-				stream:          mss,
+		for _, pr := range receiverConfig.GetPortRanges() {
+			for port := pr.From; port <= pr.To; port++ {
+				if net.HasNetwork(nl, net.Network_TCP) {
+					newError("creating stream worker on ", address, ":", port).AtDebug().WriteToLog()
+
+					worker := &tcpWorker{
+						address:         address,
+						port:            net.Port(port),
+						proxy:           p,
+						stream:          mss,
+						recvOrigDest:    receiverConfig.ReceiveOriginalDestination,
+						tag:             tag,
+						dispatcher:      h.mux,
+						sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
+						uplinkCounter:   uplinkCounter,
+						downlinkCounter: downlinkCounter,
+					}
+					h.workers = append(h.workers, worker)
+				}
+
+				if net.HasNetwork(nl, net.Network_UDP) {
+					worker := &udpWorker{
+						tag:             tag,
+						proxy:           p,
+						address:         address,
+						port:            net.Port(port),
+						dispatcher:      h.mux,
+						uplinkCounter:   uplinkCounter,
+						downlinkCounter: downlinkCounter,
+						stream:          mss,
+					}
+					h.workers = append(h.workers, worker)
+				}
 			}
-			h.workers = append(h.workers, worker)
 		}
 	}
 
@@ -132,6 +168,9 @@ func (h *AlwaysOnInboundHandler) Start() error {
 			return err
 		}
 	}
+	if r, ok := h.proxy.(common.Runnable); ok {
+		return r.Start()
+	}
 	return nil
 }
 
@@ -141,13 +180,32 @@ func (h *AlwaysOnInboundHandler) Close() error {
 	for _, worker := range h.workers {
 		errs = append(errs, worker.Close())
 	}
-	errs = append(errs, h.mux.Close())
+	errs = append(errs, common.Close(h.proxy), h.mux.Close())
 	if err := errors.Combine(errs...); err != nil {
 		return newError("failed to close all resources").Base(err)
 	}
 	return nil
 }
 
+// pauseListening implements listenPauser.
+func (h *AlwaysOnInboundHandler) pauseListening() error {
+	var errs []error
+	for _, worker := range h.workers {
+		errs = append(errs, worker.StopListening())
+	}
+	return errors.Combine(errs...)
+}
+
+// resumeListening implements listenPauser.
+func (h *AlwaysOnInboundHandler) resumeListening() error {
+	return h.Start()
+}
+
+// Drain implements inbound.Drainer.
+func (h *AlwaysOnInboundHandler) Drain(timeout time.Duration) error {
+	return drainWorkers(h.workers, timeout, h.Close)
+}
+
 func (h *AlwaysOnInboundHandler) GetRandomInboundProxy() (interface{}, net.Port, int) {
 	if len(h.workers) == 0 {
 		return nil, 0, 0
@@ -160,6 +218,11 @@ func (h *AlwaysOnInboundHandler) Tag() string {
 	return h.tag
 }
 
+// GetConfig returns the config this handler was created from, or nil if unknown.
+func (h *AlwaysOnInboundHandler) GetConfig() *core.InboundHandlerConfig {
+	return h.config
+}
+
 func (h *AlwaysOnInboundHandler) GetInbound() proxy.Inbound {
 	return h.proxy
 }
@@ -0,0 +1,54 @@
+package inbound
+
+import (
+	"time"
+
+	"v2ray.com/core/common/errors"
+)
+
+func activeConnections(workers []worker) int {
+	count := 0
+	for _, w := range workers {
+		count += w.ActiveConnections()
+	}
+	return count
+}
+
+// drainWorkers stops the workers from accepting new connections, and calls closeFunc in background after
+// all existing connections end or the timeout expires. Connections still in progress by then are interrupted.
+func drainWorkers(workers []worker, timeout time.Duration, closeFunc func() error) error {
+	var errs []error
+	for _, w := range workers {
+		if err := w.StopListening(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	go func() {
+		deadline := time.NewTimer(timeout)
+		defer deadline.Stop()
+		ticker := time.NewTicker(time.Millisecond * 500)
+		defer ticker.Stop()
+
+	wait:
+		for activeConnections(workers) > 0 {
+			select {
+			case <-ticker.C:
+			case <-deadline.C:
+				break wait
+			}
+		}
+
+		for _, w := range workers {
+			w.InterruptConnections()
+		}
+		if err := closeFunc(); err != nil {
+			newError("failed to close drained handler").Base(err).AtWarning().WriteToLog()
+		}
+	}()
+
+	if err := errors.Combine(errs...); err != nil {
+		return newError("failed to stop listening").Base(err)
+	}
+	return nil
+}
@@ -24,13 +24,19 @@ type DynamicInboundHandler struct {
 	portMutex      sync.Mutex
 	portsInUse     map[net.Port]bool
 	workerMutex    sync.RWMutex
+	externalMutex  sync.Mutex
 	worker         []worker
 	lastRefresh    time.Time
 	mux            *mux.Server
 	task           *task.Periodic
+	config         *core.InboundHandlerConfig
 }
 
 func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*DynamicInboundHandler, error) {
+	if receiverConfig.PortList != nil || len(receiverConfig.ListenList) > 0 {
+		return nil, newError("port list and listen list are only available with Always allocation strategy")
+	}
+
 	v := core.MustFromContext(ctx)
 	h := &DynamicInboundHandler{
 		tag:            tag,
@@ -57,9 +63,11 @@ func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *p
 
 	h.streamSettings = mss
 
-	h.task = &task.Periodic{
-		Interval: time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()),
-		Execute:  h.refresh,
+	if receiverConfig.AllocationStrategy.GetType() != proxyman.AllocationStrategy_External {
+		h.task = &task.Periodic{
+			Interval: time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()),
+			Execute:  h.refresh,
+		}
 	}
 
 	return h, nil
@@ -92,20 +100,19 @@ func (h *DynamicInboundHandler) closeWorkers(workers []worker) {
 		}
 	}
 
+	h.releasePorts(ports2Del)
+}
+
+func (h *DynamicInboundHandler) releasePorts(ports []net.Port) {
 	h.portMutex.Lock()
-	for _, port := range ports2Del {
+	for _, port := range ports {
 		delete(h.portsInUse, port)
 	}
 	h.portMutex.Unlock()
 }
 
-func (h *DynamicInboundHandler) refresh() error {
-	h.lastRefresh = time.Now()
-
-	timeout := time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()) * 2
-	concurrency := h.receiverConfig.AllocationStrategy.GetConcurrencyValue()
-	workers := make([]worker, 0, concurrency)
-
+// createWorkers starts the workers on the port. Either all workers are started, or none.
+func (h *DynamicInboundHandler) createWorkers(port net.Port) ([]worker, error) {
 	address := h.receiverConfig.Listen.AsAddress()
 	if address == nil {
 		address = net.AnyIP
@@ -113,73 +120,188 @@ func (h *DynamicInboundHandler) refresh() error {
 
 	uplinkCounter, downlinkCounter := getStatCounter(h.v, h.tag)
 
+	rawProxy, err := core.CreateObject(h.v, h.proxyConfig)
+	if err != nil {
+		return nil, newError("failed to create proxy instance").Base(err)
+	}
+	p := rawProxy.(proxy.Inbound)
+	nl := p.Network()
+
+	var workers []worker
+	if net.HasNetwork(nl, net.Network_TCP) {
+		worker := &tcpWorker{
+			tag:             h.tag,
+			address:         address,
+			port:            port,
+			proxy:           p,
+			stream:          h.streamSettings,
+			recvOrigDest:    h.receiverConfig.ReceiveOriginalDestination,
+			dispatcher:      h.mux,
+			sniffingConfig:  h.receiverConfig.GetEffectiveSniffingSettings(),
+			uplinkCounter:   uplinkCounter,
+			downlinkCounter: downlinkCounter,
+		}
+		if err := worker.Start(); err != nil {
+			return nil, newError("failed to create TCP worker").Base(err)
+		}
+		workers = append(workers, worker)
+	}
+
+	if net.HasNetwork(nl, net.Network_UDP) {
+		worker := &udpWorker{
+			tag:             h.tag,
+			proxy:           p,
+			address:         address,
+			port:            port,
+			dispatcher:      h.mux,
+			uplinkCounter:   uplinkCounter,
+			downlinkCounter: downlinkCounter,
+			stream:          h.streamSettings,
+		}
+		if err := worker.Start(); err != nil {
+			for _, w := range workers {
+				w.Close() // nolint: errcheck
+			}
+			return nil, newError("failed to create UDP worker").Base(err)
+		}
+		workers = append(workers, worker)
+	}
+
+	return workers, nil
+}
+
+func (h *DynamicInboundHandler) refresh() error {
+	h.lastRefresh = time.Now()
+
+	timeout := time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue()) * 2
+	concurrency := h.receiverConfig.AllocationStrategy.GetConcurrencyValue()
+	workers := make([]worker, 0, concurrency)
+
 	for i := uint32(0); i < concurrency; i++ {
 		port := h.allocatePort()
-		rawProxy, err := core.CreateObject(h.v, h.proxyConfig)
+		ws, err := h.createWorkers(port)
 		if err != nil {
-			newError("failed to create proxy instance").Base(err).AtWarning().WriteToLog()
+			newError("failed to create workers on port ", port).Base(err).AtWarning().WriteToLog()
+			h.releasePorts([]net.Port{port})
 			continue
 		}
-		p := rawProxy.(proxy.Inbound)
-		nl := p.Network()
-		if net.HasNetwork(nl, net.Network_TCP) {
-			worker := &tcpWorker{
-				tag:             h.tag,
-				address:         address,
-				port:            port,
-				proxy:           p,
-				stream:          h.streamSettings,
-				recvOrigDest:    h.receiverConfig.ReceiveOriginalDestination,
-				dispatcher:      h.mux,
-				sniffingConfig:  h.receiverConfig.GetEffectiveSniffingSettings(),
-				uplinkCounter:   uplinkCounter,
-				downlinkCounter: downlinkCounter,
-			}
-			if err := worker.Start(); err != nil {
-				newError("failed to create TCP worker").Base(err).AtWarning().WriteToLog()
-				continue
-			}
-			workers = append(workers, worker)
+		workers = append(workers, ws...)
+	}
+
+	h.workerMutex.Lock()
+	h.worker = workers
+	h.workerMutex.Unlock()
+
+	time.AfterFunc(timeout, func() {
+		h.closeWorkers(workers)
+	})
+
+	return nil
+}
+
+// SetPorts changes the listening ports of a handler with External allocation strategy.
+// Workers on ports that are still in the list are kept, and workers on other ports are closed immediately.
+// If any new port fails to listen, the ports of the handler are left unchanged.
+func (h *DynamicInboundHandler) SetPorts(ports []net.Port) error {
+	if h.receiverConfig.AllocationStrategy.GetType() != proxyman.AllocationStrategy_External {
+		return newError("ports of handler ", h.tag, " are not allocated externally")
+	}
+	pr := h.receiverConfig.PortRange
+	for _, port := range ports {
+		if pr != nil && (uint32(port) < pr.From || uint32(port) > pr.To) {
+			return newError("port ", port, " is out of range ", pr.FromPort(), "-", pr.ToPort())
+		}
+	}
+
+	h.externalMutex.Lock()
+	defer h.externalMutex.Unlock()
+
+	h.workerMutex.RLock()
+	existing := make(map[net.Port][]worker)
+	for _, w := range h.worker {
+		existing[w.Port()] = append(existing[w.Port()], w)
+	}
+	h.workerMutex.RUnlock()
+
+	var workers []worker
+	var created []worker
+	var reserved []net.Port
+	seen := make(map[net.Port]bool)
+	for _, port := range ports {
+		if seen[port] {
+			continue
 		}
+		seen[port] = true
+		if ws, found := existing[port]; found {
+			workers = append(workers, ws...)
+			delete(existing, port)
+			continue
+		}
+		h.portMutex.Lock()
+		h.portsInUse[port] = true
+		h.portMutex.Unlock()
+		reserved = append(reserved, port)
 
-		if net.HasNetwork(nl, net.Network_UDP) {
-			worker := &udpWorker{
-				tag:             h.tag,
-				proxy:           p,
-				address:         address,
-				port:            port,
-				dispatcher:      h.mux,
-				uplinkCounter:   uplinkCounter,
-				downlinkCounter: downlinkCounter,
-				stream:          h.streamSettings,
-			}
-			if err := worker.Start(); err != nil {
-				newError("failed to create UDP worker").Base(err).AtWarning().WriteToLog()
-				continue
+		ws, err := h.createWorkers(port)
+		if err != nil {
+			for _, w := range created {
+				w.Close() // nolint: errcheck
 			}
-			workers = append(workers, worker)
+			h.releasePorts(reserved)
+			return newError("failed to listen on port ", port).Base(err)
 		}
+		workers = append(workers, ws...)
+		created = append(created, ws...)
 	}
 
 	h.workerMutex.Lock()
 	h.worker = workers
+	h.lastRefresh = time.Now()
 	h.workerMutex.Unlock()
 
-	time.AfterFunc(timeout, func() {
-		h.closeWorkers(workers)
-	})
+	for _, ws := range existing {
+		h.closeWorkers(ws)
+	}
 
 	return nil
 }
 
 func (h *DynamicInboundHandler) Start() error {
+	if h.task == nil {
+		return nil
+	}
 	return h.task.Start()
 }
 
 func (h *DynamicInboundHandler) Close() error {
+	if h.task == nil {
+		return h.SetPorts(nil)
+	}
 	return h.task.Close()
 }
 
+// Drain implements inbound.Drainer.
+func (h *DynamicInboundHandler) Drain(timeout time.Duration) error {
+	if h.task == nil {
+		h.workerMutex.RLock()
+		workers := h.worker
+		h.workerMutex.RUnlock()
+		return drainWorkers(workers, timeout, h.Close)
+	}
+
+	if err := h.task.Close(); err != nil {
+		return err
+	}
+	h.workerMutex.Lock()
+	workers := h.worker
+	h.worker = nil
+	h.workerMutex.Unlock()
+	return drainWorkers(workers, timeout, func() error {
+		h.closeWorkers(workers)
+		return nil
+	})
+}
+
 func (h *DynamicInboundHandler) GetRandomInboundProxy() (interface{}, net.Port, int) {
 	h.workerMutex.RLock()
 	defer h.workerMutex.RUnlock()
@@ -188,10 +310,19 @@ func (h *DynamicInboundHandler) GetRandomInboundProxy() (interface{}, net.Port,
 		return nil, 0, 0
 	}
 	w := h.worker[dice.Roll(len(h.worker))]
-	expire := h.receiverConfig.AllocationStrategy.GetRefreshValue() - uint32(time.Since(h.lastRefresh)/time.Minute)
-	return w.Proxy(), w.Port(), int(expire)
+	refresh := h.receiverConfig.AllocationStrategy.GetRefreshValue()
+	elapsed := uint32(time.Since(h.lastRefresh) / time.Minute)
+	if elapsed >= refresh {
+		return w.Proxy(), w.Port(), 0
+	}
+	return w.Proxy(), w.Port(), int(refresh - elapsed)
 }
 
 func (h *DynamicInboundHandler) Tag() string {
 	return h.tag
 }
+
+// GetConfig returns the config this handler was created from, or nil if unknown.
+func (h *DynamicInboundHandler) GetConfig() *core.InboundHandlerConfig {
+	return h.config
+}
@@ -5,6 +5,7 @@ package inbound
 import (
 	"context"
 	"sync"
+	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
@@ -66,6 +67,19 @@ func (m *Manager) GetHandler(ctx context.Context, tag string) (inbound.Handler,
 	return handler, nil
 }
 
+// ListHandlers implements inbound.HandlerLister.
+func (m *Manager) ListHandlers(ctx context.Context) []inbound.Handler {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	handlers := make([]inbound.Handler, 0, len(m.taggedHandlers)+len(m.untaggedHandler))
+	for _, handler := range m.taggedHandlers {
+		handlers = append(handlers, handler)
+	}
+	handlers = append(handlers, m.untaggedHandler...)
+	return handlers
+}
+
 // RemoveHandler implements inbound.Manager.
 func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
 	if tag == "" {
@@ -86,6 +100,89 @@ func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
 	return common.ErrNoClue
 }
 
+func drainHandler(handler inbound.Handler, timeout time.Duration) error {
+	if d, ok := handler.(inbound.Drainer); ok {
+		return d.Drain(timeout)
+	}
+	return handler.Close()
+}
+
+// DrainHandler implements inbound.GracefulManager.
+func (m *Manager) DrainHandler(ctx context.Context, tag string, timeout time.Duration) error {
+	if tag == "" {
+		return common.ErrNoClue
+	}
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	handler, found := m.taggedHandlers[tag]
+	if !found {
+		return common.ErrNoClue
+	}
+	delete(m.taggedHandlers, tag)
+	if err := drainHandler(handler, timeout); err != nil {
+		return newError("failed to drain handler ", tag).Base(err)
+	}
+	return nil
+}
+
+// listenPauser is implemented by handlers that can stop listening and listen again, so that a new handler on
+// the same ports can be started before the old one is removed.
+type listenPauser interface {
+	pauseListening() error
+	resumeListening() error
+}
+
+// ReplaceHandler implements inbound.GracefulManager.
+// The new handler is started before the old one is drained. If the new handler fails to start, the old one is kept.
+func (m *Manager) ReplaceHandler(ctx context.Context, handler inbound.Handler, timeout time.Duration) error {
+	tag := handler.Tag()
+	if tag == "" {
+		return newError("unable to replace handler without tag")
+	}
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	old, found := m.taggedHandlers[tag]
+	if !found {
+		if err := handler.Close(); err != nil {
+			newError("failed to close handler ", tag).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+		return newError("handler not found: ", tag)
+	}
+	if m.running {
+		// The old handler stops listening first, as the new one may listen on the same ports.
+		pauser, _ := old.(listenPauser)
+		if pauser != nil {
+			if err := pauser.pauseListening(); err != nil {
+				if err := pauser.resumeListening(); err != nil {
+					newError("failed to resume handler ", tag).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+				}
+				return newError("failed to stop listening of handler ", tag).Base(err)
+			}
+		}
+		if err := handler.Start(); err != nil {
+			if err := handler.Close(); err != nil {
+				newError("failed to close handler ", tag).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			}
+			if pauser != nil {
+				if err := pauser.resumeListening(); err != nil {
+					newError("failed to resume handler ", tag).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+				}
+			}
+			return newError("failed to start handler ", tag).Base(err)
+		}
+	}
+	m.taggedHandlers[tag] = handler
+
+	if err := drainHandler(old, timeout); err != nil {
+		return newError("handler ", tag, " is replaced, but failed to drain the old one").Base(err)
+	}
+	return nil
+}
+
 // Start implements common.Runnable.
 func (m *Manager) Start() error {
 	m.access.Lock()
@@ -152,11 +249,21 @@ func NewHandler(ctx context.Context, config *core.InboundHandlerConfig) (inbound
 
 	allocStrategy := receiverSettings.AllocationStrategy
 	if allocStrategy == nil || allocStrategy.Type == proxyman.AllocationStrategy_Always {
-		return NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
+		h, err := NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
+		if err != nil {
+			return nil, err
+		}
+		h.config = config
+		return h, nil
 	}
 
-	if allocStrategy.Type == proxyman.AllocationStrategy_Random {
-		return NewDynamicInboundHandler(ctx, tag, receiverSettings, proxySettings)
+	if allocStrategy.Type == proxyman.AllocationStrategy_Random || allocStrategy.Type == proxyman.AllocationStrategy_External {
+		h, err := NewDynamicInboundHandler(ctx, tag, receiverSettings, proxySettings)
+		if err != nil {
+			return nil, err
+		}
+		h.config = config
+		return h, nil
 	}
 	return nil, newError("unknown allocation strategy: ", receiverSettings.AllocationStrategy.Type).AtError()
 }
@@ -28,6 +28,13 @@ type worker interface {
 	Close() error
 	Port() net.Port
 	Proxy() proxy.Inbound
+
+	// StopListening stops accepting new connections, while existing connections are kept if possible.
+	StopListening() error
+	// ActiveConnections returns the number of connections in progress.
+	ActiveConnections() int
+	// InterruptConnections closes all connections in progress.
+	InterruptConnections()
 }
 
 type tcpWorker struct {
@@ -42,7 +49,10 @@ type tcpWorker struct {
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
 
-	hub internet.Listener
+	access    sync.Mutex
+	hub       internet.Listener
+	hubClosed bool
+	conns     map[internet.Connection]struct{}
 }
 
 func getTProxyType(s *internet.MemoryStreamConfig) internet.SocketConfig_TProxyMode {
@@ -52,7 +62,25 @@ func getTProxyType(s *internet.MemoryStreamConfig) internet.SocketConfig_TProxyM
 	return s.SocketSettings.Tproxy
 }
 
+func (w *tcpWorker) addConn(conn internet.Connection) {
+	w.access.Lock()
+	if w.conns == nil {
+		w.conns = make(map[internet.Connection]struct{})
+	}
+	w.conns[conn] = struct{}{}
+	w.access.Unlock()
+}
+
+func (w *tcpWorker) removeConn(conn internet.Connection) {
+	w.access.Lock()
+	delete(w.conns, conn)
+	w.access.Unlock()
+}
+
 func (w *tcpWorker) callback(conn internet.Connection) {
+	w.addConn(conn)
+	defer w.removeConn(conn)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	sid := session.NewID()
 	ctx = session.ContextWithID(ctx, sid)
@@ -115,14 +143,51 @@ func (w *tcpWorker) Start() error {
 	if err != nil {
 		return newError("failed to listen TCP on ", w.port).AtWarning().Base(err)
 	}
+	w.access.Lock()
 	w.hub = hub
+	w.hubClosed = false
+	w.access.Unlock()
 	return nil
 }
 
+func (w *tcpWorker) closeHub() error {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	if w.hub == nil || w.hubClosed {
+		return nil
+	}
+	w.hubClosed = true
+	return common.Close(w.hub)
+}
+
+// StopListening implements worker.
+func (w *tcpWorker) StopListening() error {
+	return w.closeHub()
+}
+
+// ActiveConnections implements worker.
+func (w *tcpWorker) ActiveConnections() int {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	return len(w.conns)
+}
+
+// InterruptConnections implements worker.
+func (w *tcpWorker) InterruptConnections() {
+	w.access.Lock()
+	defer w.access.Unlock()
+
+	for conn := range w.conns {
+		conn.Close() // nolint: errcheck
+	}
+}
+
 func (w *tcpWorker) Close() error {
 	var errors []interface{}
 	if w.hub != nil {
-		if err := common.Close(w.hub); err != nil {
+		if err := w.closeHub(); err != nil {
 			errors = append(errors, err)
 		}
 		if err := common.Close(w.proxy); err != nil {
@@ -313,8 +378,8 @@ func (w *udpWorker) removeConn(id connID) {
 	w.Unlock()
 }
 
-func (w *udpWorker) handlePackets() {
-	receive := w.hub.Receive()
+func (w *udpWorker) handlePackets(hub *udp.Hub) {
+	receive := hub.Receive()
 	for payload := range receive {
 		w.callback(payload.Payload, payload.Source, payload.Target)
 	}
@@ -344,27 +409,26 @@ func (w *udpWorker) clean() error {
 }
 
 func (w *udpWorker) Start() error {
-	w.activeConn = make(map[connID]*udpConn, 16)
 	ctx := context.Background()
 	h, err := udp.ListenUDP(ctx, w.address, w.port, w.stream, udp.HubCapacity(256))
 	if err != nil {
 		return err
 	}
 
+	w.Lock()
+	w.activeConn = make(map[connID]*udpConn, 16)
 	w.checker = &task.Periodic{
 		Interval: time.Second * 16,
 		Execute:  w.clean,
 	}
-
 	w.hub = h
-	go w.handlePackets()
+	w.Unlock()
+
+	go w.handlePackets(h)
 	return nil
 }
 
-func (w *udpWorker) Close() error {
-	w.Lock()
-	defer w.Unlock()
-
+func (w *udpWorker) stopListening() []interface{} {
 	var errors []interface{}
 
 	if w.hub != nil {
@@ -379,6 +443,15 @@ func (w *udpWorker) Close() error {
 		}
 	}
 
+	return errors
+}
+
+func (w *udpWorker) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	errors := w.stopListening()
+
 	if err := common.Close(w.proxy); err != nil {
 		errors = append(errors, err)
 	}
@@ -389,6 +462,25 @@ func (w *udpWorker) Close() error {
 	return nil
 }
 
+// StopListening implements worker. UDP sessions share the listening socket, so they are closed as well.
+func (w *udpWorker) StopListening() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if errors := w.stopListening(); len(errors) > 0 {
+		return newError("failed to stop listening").Base(newError(serial.Concat(errors...)))
+	}
+	return nil
+}
+
+// ActiveConnections implements worker.
+func (w *udpWorker) ActiveConnections() int {
+	return 0
+}
+
+// InterruptConnections implements worker.
+func (w *udpWorker) InterruptConnections() {}
+
 func (w *udpWorker) Port() net.Port {
 	return w.port
 }
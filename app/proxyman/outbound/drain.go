@@ -0,0 +1,94 @@
+package outbound
+
+import (
+	"sync"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/transport"
+)
+
+// linkWriter notifies the Handler when a link ends, i.e., its writer is closed or interrupted.
+type linkWriter struct {
+	buf.Writer
+	onEnd func()
+}
+
+// Close implements common.Closable.
+func (w *linkWriter) Close() error {
+	w.onEnd()
+	return common.Close(w.Writer)
+}
+
+// Interrupt implements common.Interruptible.
+func (w *linkWriter) Interrupt() {
+	w.onEnd()
+	common.Interrupt(w.Writer)
+}
+
+// trackLink registers the link as active until its writer is closed or interrupted, or the returned function is called.
+func (h *Handler) trackLink(link *transport.Link) (*transport.Link, func()) {
+	h.linkAccess.Lock()
+	defer h.linkAccess.Unlock()
+
+	if h.links == nil {
+		h.links = make(map[*transport.Link]struct{})
+	}
+	tracked := &transport.Link{
+		Reader: link.Reader,
+	}
+	var once sync.Once
+	untrack := func() {
+		once.Do(func() {
+			h.linkAccess.Lock()
+			delete(h.links, tracked)
+			h.linkAccess.Unlock()
+		})
+	}
+	tracked.Writer = &linkWriter{
+		Writer: link.Writer,
+		onEnd:  untrack,
+	}
+	h.links[tracked] = struct{}{}
+	return tracked, untrack
+}
+
+func (h *Handler) activeLinks() []*transport.Link {
+	h.linkAccess.Lock()
+	defer h.linkAccess.Unlock()
+
+	links := make([]*transport.Link, 0, len(h.links))
+	for link := range h.links {
+		links = append(links, link)
+	}
+	return links
+}
+
+// Drain implements outbound.Drainer.
+func (h *Handler) Drain(timeout time.Duration) error {
+	go func() {
+		deadline := time.NewTimer(timeout)
+		defer deadline.Stop()
+		ticker := time.NewTicker(time.Millisecond * 500)
+		defer ticker.Stop()
+
+	wait:
+		for len(h.activeLinks()) > 0 {
+			select {
+			case <-ticker.C:
+			case <-deadline.C:
+				break wait
+			}
+		}
+
+		for _, link := range h.activeLinks() {
+			common.Interrupt(link.Writer)
+			common.Interrupt(link.Reader)
+		}
+		if err := h.Close(); err != nil {
+			newError("failed to close drained handler ", h.tag).Base(err).AtWarning().WriteToLog()
+		}
+	}()
+	return nil
+}
@@ -2,6 +2,9 @@ package outbound
 
 import (
 	"context"
+	"strconv"
+	"sync"
+	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
@@ -9,7 +12,10 @@ import (
 	"v2ray.com/core/common/mux"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/dns"
 	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/internet"
@@ -25,6 +31,16 @@ type Handler struct {
 	proxy           proxy.Outbound
 	outboundManager outbound.Manager
 	mux             *mux.ClientManager
+	config          *core.OutboundHandlerConfig
+	dns             dns.Client
+
+	linkAccess sync.Mutex
+	links      map[*transport.Link]struct{}
+
+	poolAccess  sync.Mutex
+	pools       map[net.Destination]*connPool
+	poolCleanup *task.Periodic
+	poolsClosed bool
 }
 
 // NewHandler create a new Handler based on the given configuration.
@@ -32,6 +48,7 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 	v := core.MustFromContext(ctx)
 	h := &Handler{
 		tag:             config.Tag,
+		config:          config,
 		outboundManager: v.GetFeature(outbound.ManagerType()).(outbound.Manager),
 	}
 
@@ -47,6 +64,22 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 			if err != nil {
 				return nil, newError("failed to parse stream settings").Base(err).AtWarning()
 			}
+			if prefix := s.ViaPrefix; prefix != nil {
+				if bits := len(prefix.Ip) * 8; (bits != 32 && bits != 128) || int(prefix.Prefix) > bits {
+					return nil, newError("invalid prefix to send through: ", prefix.Ip, "/", prefix.Prefix)
+				}
+				if mss.SocketSettings == nil {
+					mss.SocketSettings = &internet.SocketConfig{}
+				}
+				mss.SocketSettings.Freebind = true
+			}
+			if mss.SocketSettings.GetHappyEyeballs() != nil {
+				if err := core.RequireFeatures(ctx, func(d dns.Client) {
+					h.dns = d
+				}); err != nil {
+					return nil, err
+				}
+			}
 			h.streamSettings = mss
 		default:
 			return nil, newError("settings is not SenderConfig")
@@ -68,23 +101,49 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 		return nil, newError("not an outbound handler")
 	}
 
+	if h.senderSettings.GetConnectionPool().GetSize() > 0 {
+		if d, ok := proxyHandler.(proxy.DirectOutbound); ok && d.IsDirect() {
+			return nil, newError("connection pool is not available for outbounds without a fixed server")
+		}
+		h.poolCleanup = &task.Periodic{
+			Interval: poolCleanupInterval,
+			Execute:  h.removeEmptyPools,
+		}
+	}
+
 	if h.senderSettings != nil && h.senderSettings.MultiplexSettings != nil {
 		config := h.senderSettings.MultiplexSettings
 		if config.Concurrency < 1 || config.Concurrency > 1024 {
 			return nil, newError("invalid mux concurrency: ", config.Concurrency).AtWarning()
 		}
-		h.mux = &mux.ClientManager{
-			Enabled: h.senderSettings.MultiplexSettings.Enabled,
-			Picker: &mux.IncrementalWorkerPicker{
-				Factory: &mux.DialingWorkerFactory{
-					Proxy:  proxyHandler,
-					Dialer: h,
-					Strategy: mux.ClientStrategy{
-						MaxConcurrency: config.Concurrency,
-						MaxConnection:  128,
-					},
+		maxStreams := config.MaxStreams
+		if maxStreams == 0 {
+			maxStreams = 128
+		}
+		picker := &mux.IncrementalWorkerPicker{
+			Factory: &mux.DialingWorkerFactory{
+				Proxy:  proxyHandler,
+				Dialer: h,
+				Strategy: mux.ClientStrategy{
+					MaxConcurrency:    config.Concurrency,
+					MaxConnection:     maxStreams,
+					IdleTimeout:       time.Second * time.Duration(config.IdleTimeout),
+					KeepAliveInterval: time.Second * time.Duration(config.KeepAliveInterval),
 				},
 			},
+			MaxWorkers: config.MaxConnections,
+		}
+		if len(h.tag) > 0 {
+			statsManager := v.GetFeature(stats.ManagerType()).(stats.Manager)
+			picker.Counter = func(slot int) stats.Counter {
+				name := "outbound>>>" + h.tag + ">>>mux>>>" + strconv.Itoa(slot) + ">>>active"
+				c, _ := stats.GetOrRegisterCounter(statsManager, name)
+				return c
+			}
+		}
+		h.mux = &mux.ClientManager{
+			Enabled: h.senderSettings.MultiplexSettings.Enabled,
+			Picker:  picker,
 		}
 	}
 
@@ -97,14 +156,23 @@ func (h *Handler) Tag() string {
 	return h.tag
 }
 
+// GetConfig returns the config this handler was created from.
+func (h *Handler) GetConfig() *core.OutboundHandlerConfig {
+	return h.config
+}
+
 // Dispatch implements proxy.Outbound.Dispatch.
 func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
-	if h.mux != nil && (h.mux.Enabled || session.MuxPreferedFromContext(ctx)) {
+	link, untrack := h.trackLink(link)
+	if h.mux != nil && (h.mux.Enabled || session.MuxPreferedFromContext(ctx)) && h.muxNetwork(ctx) {
+		// Mux sessions outlive this call, and are untracked when their writers are closed or interrupted.
 		if err := h.mux.Dispatch(ctx, link); err != nil {
+			untrack()
 			newError("failed to process mux outbound traffic").Base(err).WriteToLog(session.ExportIDToError(ctx))
 			common.Interrupt(link.Writer)
 		}
 	} else {
+		defer untrack()
 		if err := h.proxy.Process(ctx, link, h); err != nil {
 			// Ensure outbound ray is properly closed.
 			newError("failed to process outbound traffic").Base(err).WriteToLog(session.ExportIDToError(ctx))
@@ -116,6 +184,16 @@ func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
 	}
 }
 
+// muxNetwork returns true if the network of the destination is allowed to go through Mux.
+func (h *Handler) muxNetwork(ctx context.Context) bool {
+	networks := h.senderSettings.MultiplexSettings.Networks
+	if len(networks) == 0 {
+		return true
+	}
+	outbound := session.OutboundFromContext(ctx)
+	return outbound != nil && net.HasNetwork(networks, outbound.Target.Network)
+}
+
 // Address implements internet.Dialer.
 func (h *Handler) Address() net.Address {
 	if h.senderSettings == nil || h.senderSettings.Via == nil {
@@ -154,16 +232,34 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 			newError("failed to get outbound handler with tag: ", tag).AtWarning().WriteToLog(session.ExportIDToError(ctx))
 		}
 
-		if h.senderSettings.Via != nil {
+		if h.senderSettings.ViaPrefix != nil || h.senderSettings.Via != nil {
 			outbound := session.OutboundFromContext(ctx)
 			if outbound == nil {
 				outbound = new(session.Outbound)
 				ctx = session.ContextWithOutbound(ctx, outbound)
 			}
-			outbound.Gateway = h.senderSettings.Via.AsAddress()
+			if prefix := h.senderSettings.ViaPrefix; prefix != nil {
+				var email string
+				if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.User != nil {
+					email = inbound.User.Email
+				}
+				outbound.Gateway = prefix.PickAddress(email)
+				newError("sending through ", outbound.Gateway, " for dest ", dest).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+			} else {
+				outbound.Gateway = h.senderSettings.Via.AsAddress()
+			}
 		}
 	}
 
+	if conn := h.pooledConn(dest); conn != nil {
+		newError("using pre-established connection to ", dest).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+		return conn, nil
+	}
+
+	if h.dns != nil {
+		ctx = internet.ContextWithDNSClient(ctx, h.dns)
+	}
+
 	return internet.Dial(ctx, dest, h.streamSettings)
 }
 
@@ -174,11 +270,24 @@ func (h *Handler) GetOutbound() proxy.Outbound {
 
 // Start implements common.Runnable.
 func (h *Handler) Start() error {
+	if h.poolCleanup != nil {
+		if err := h.poolCleanup.Start(); err != nil {
+			return err
+		}
+	}
+	if r, ok := h.proxy.(common.Runnable); ok {
+		return r.Start()
+	}
 	return nil
 }
 
 // Close implements common.Closable.
 func (h *Handler) Close() error {
 	common.Close(h.mux)
+	if h.poolCleanup != nil {
+		h.poolCleanup.Close() // nolint: errcheck
+	}
+	h.closePools()
+	common.Close(h.proxy)
 	return nil
 }
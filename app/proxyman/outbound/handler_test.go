@@ -1,13 +1,212 @@
 package outbound_test
 
 import (
+	"context"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
 	. "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
 	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/proxy/socks"
 )
 
 func TestInterfaces(t *testing.T) {
 	_ = (outbound.Handler)(new(Handler))
 	_ = (outbound.Manager)(new(Manager))
+	_ = (outbound.Drainer)(new(Handler))
+	_ = (outbound.GracefulManager)(new(Manager))
+	_ = (outbound.HandlerLister)(new(Manager))
+}
+
+func TestConnectionPool(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+
+	var accepted int32
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&accepted, 1)
+			conns = append(conns, conn)
+		}
+	}()
+
+	dest := net.DestinationFromAddr(listener.Addr())
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ConnectionPool: &proxyman.ConnectionPoolConfig{
+						Size:        2,
+						MaxIdleTime: 1,
+						IdleTimeout: 2,
+					},
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ClientConfig{
+					Server: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(dest.Address),
+							Port:    uint32(dest.Port),
+						},
+					},
+				}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	h := v.GetFeature(outbound.ManagerType()).(outbound.Manager).GetDefaultHandler().(*Handler)
+
+	waitAccepted := func(expected int32) {
+		for i := 0; i < 100 && atomic.LoadInt32(&accepted) < expected; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := atomic.LoadInt32(&accepted); n != expected {
+			t.Fatal("expected ", expected, " connections, but got ", n)
+		}
+	}
+
+	conn, err := h.Dial(context.Background(), dest)
+	common.Must(err)
+	defer conn.Close()
+	// One direct connection and two idle connections.
+	waitAccepted(3)
+
+	conn, err = h.Dial(context.Background(), dest)
+	common.Must(err)
+	defer conn.Close()
+	// The taken connection is replaced.
+	waitAccepted(4)
+
+	// Idle connections that expire are replaced while the pool is in use.
+	time.Sleep(1500 * time.Millisecond)
+	waitAccepted(6)
+
+	// The pool is no longer refilled after it is not used for the idle timeout.
+	time.Sleep(2500 * time.Millisecond)
+	n := atomic.LoadInt32(&accepted)
+	time.Sleep(1500 * time.Millisecond)
+	waitAccepted(n)
+
+	conn, err = h.Dial(context.Background(), dest)
+	common.Must(err)
+	defer conn.Close()
+	// The pool is refilled once it is used again.
+	waitAccepted(n + 3)
+}
+
+func TestConnectionPoolFreedom(t *testing.T) {
+	_, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ConnectionPool: &proxyman.ConnectionPoolConfig{
+						Size: 2,
+					},
+				}),
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	if err == nil {
+		t.Error("expected error for connection pool of freedom outbound, but nil")
+	}
+
+	// Freedom outbound redirecting all traffic to one server has a fixed server.
+	_, err = core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					ConnectionPool: &proxyman.ConnectionPoolConfig{
+						Size: 2,
+					},
+				}),
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{
+					DestinationOverride: &freedom.DestinationOverride{
+						Server: &protocol.ServerEndpoint{
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    80,
+						},
+					},
+				}),
+			},
+		},
+	})
+	if err != nil {
+		t.Error("unexpected error for freedom outbound with redirect: ", err)
+	}
+}
+
+func TestReplaceHandler(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "a",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+
+	ohm := v.GetFeature(outbound.ManagerType()).(outbound.Manager)
+	replace := func(tag string) error {
+		handler, err := core.CreateObject(v, &core.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		})
+		common.Must(err)
+		return ohm.(outbound.GracefulManager).ReplaceHandler(context.Background(), handler.(outbound.Handler), 0)
+	}
+
+	if err := replace("b"); err == nil {
+		t.Error("expect error for replacing a handler that doesn't exist")
+	}
+	if h := ohm.GetHandler("b"); h != nil {
+		t.Error("expect handler b not to be added, but got ", h)
+	}
+	if err := replace("a"); err != nil {
+		t.Error("unexpected error: ", err)
+	}
 }
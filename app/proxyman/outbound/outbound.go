@@ -6,11 +6,13 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/session"
 	"v2ray.com/core/features/outbound"
 )
 
@@ -121,6 +123,19 @@ func (m *Manager) AddHandler(ctx context.Context, handler outbound.Handler) erro
 	return nil
 }
 
+// ListHandlers implements outbound.HandlerLister.
+func (m *Manager) ListHandlers(ctx context.Context) []outbound.Handler {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	handlers := make([]outbound.Handler, 0, len(m.taggedHandler)+len(m.untaggedHandlers))
+	for _, handler := range m.taggedHandler {
+		handlers = append(handlers, handler)
+	}
+	handlers = append(handlers, m.untaggedHandlers...)
+	return handlers
+}
+
 // RemoveHandler implements outbound.Manager.
 func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
 	if tag == "" {
@@ -137,6 +152,63 @@ func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
 	return nil
 }
 
+func drainHandler(handler outbound.Handler, timeout time.Duration) error {
+	if d, ok := handler.(outbound.Drainer); ok {
+		return d.Drain(timeout)
+	}
+	return handler.Close()
+}
+
+// DrainHandler implements outbound.GracefulManager.
+func (m *Manager) DrainHandler(ctx context.Context, tag string, timeout time.Duration) error {
+	if tag == "" {
+		return common.ErrNoClue
+	}
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	handler, found := m.taggedHandler[tag]
+	if !found {
+		return common.ErrNoClue
+	}
+	delete(m.taggedHandler, tag)
+	if m.defaultHandler == handler {
+		m.defaultHandler = nil
+	}
+
+	return drainHandler(handler, timeout)
+}
+
+// ReplaceHandler implements outbound.GracefulManager.
+func (m *Manager) ReplaceHandler(ctx context.Context, handler outbound.Handler, timeout time.Duration) error {
+	tag := handler.Tag()
+	if tag == "" {
+		return newError("unable to replace handler without tag")
+	}
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	old, found := m.taggedHandler[tag]
+	if !found {
+		if err := handler.Close(); err != nil {
+			newError("failed to close handler ", tag).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+		return newError("handler not found: ", tag)
+	}
+	if m.running {
+		if err := handler.Start(); err != nil {
+			return newError("failed to start handler ", tag).Base(err)
+		}
+	}
+
+	m.taggedHandler[tag] = handler
+	if m.defaultHandler == old {
+		m.defaultHandler = handler
+	}
+
+	return drainHandler(old, timeout)
+}
+
 // Select implements outbound.HandlerSelector.
 func (m *Manager) Select(selectors []string) []string {
 	m.access.RLock()
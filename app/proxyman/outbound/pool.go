@@ -0,0 +1,249 @@
+package outbound
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/transport/internet"
+)
+
+const (
+	defaultPoolMaxIdleTime = time.Second * 3
+	defaultPoolIdleTimeout = time.Minute
+	poolRetryInterval      = time.Second * 2
+	poolHandshakeTimeout   = time.Second * 4
+	poolCleanupInterval    = time.Minute
+)
+
+type idleConn struct {
+	conn  internet.Connection
+	timer *time.Timer
+}
+
+// connPool keeps a number of idle connections to one destination. It refills itself in the background when a
+// connection is taken, expires, or fails to be established, until the pool is not used for idleTimeout. A pool
+// that is no longer used becomes empty. Connections closed by the server are not detected, but they are replaced
+// when they expire.
+type connPool struct {
+	size        int
+	maxIdle     time.Duration
+	idleTimeout time.Duration
+	dial        func() (internet.Connection, error)
+
+	access   sync.Mutex
+	idle     []*idleConn
+	dialing  int
+	lastUsed time.Time
+	failedAt time.Time
+	retry    *time.Timer
+	closed   bool
+}
+
+func newConnPool(size int, maxIdle time.Duration, idleTimeout time.Duration, dial func() (internet.Connection, error)) *connPool {
+	if maxIdle == 0 {
+		maxIdle = defaultPoolMaxIdleTime
+	}
+	if idleTimeout == 0 {
+		idleTimeout = defaultPoolIdleTimeout
+	}
+	return &connPool{
+		size:        size,
+		maxIdle:     maxIdle,
+		idleTimeout: idleTimeout,
+		dial:        dial,
+	}
+}
+
+// get returns an idle connection, or nil if there is none. The pool is refilled in either case.
+func (p *connPool) get() internet.Connection {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	p.lastUsed = time.Now()
+	var conn internet.Connection
+	for len(p.idle) > 0 && conn == nil {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if c.timer.Stop() {
+			conn = c.conn
+		}
+	}
+	p.refill()
+	return conn
+}
+
+// empty returns true if the pool has no idle connection and is not dialing.
+func (p *connPool) empty() bool {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	return len(p.idle) == 0 && p.dialing == 0 && p.retry == nil
+}
+
+// refill starts dialing until the pool is full, or schedules a retry if the last dial failed recently. Must be
+// called with access locked.
+func (p *connPool) refill() {
+	if p.closed || p.retry != nil || time.Since(p.lastUsed) >= p.idleTimeout {
+		return
+	}
+	if wait := poolRetryInterval - time.Since(p.failedAt); wait > 0 {
+		p.retry = time.AfterFunc(wait, func() {
+			p.access.Lock()
+			defer p.access.Unlock()
+
+			p.retry = nil
+			p.refill()
+		})
+		return
+	}
+	for ; len(p.idle)+p.dialing < p.size; p.dialing++ {
+		go p.fill()
+	}
+}
+
+func (p *connPool) fill() {
+	conn, err := p.dial()
+	if err == nil {
+		err = handshake(conn)
+	}
+
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	p.dialing--
+	if err != nil {
+		newError("failed to pre-establish connection").Base(err).AtDebug().WriteToLog()
+		p.failedAt = time.Now()
+		p.refill()
+		return
+	}
+	if p.closed {
+		conn.Close()
+		return
+	}
+
+	c := &idleConn{conn: conn}
+	c.timer = time.AfterFunc(p.maxIdle, func() {
+		p.expire(c)
+	})
+	p.idle = append(p.idle, c)
+}
+
+func (p *connPool) expire(c *idleConn) {
+	c.conn.Close()
+
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	for i, ic := range p.idle {
+		if ic == c {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			break
+		}
+	}
+	p.refill()
+}
+
+// handshake completes the handshake of TLS connections, so that pooled connections are ready to send data.
+func handshake(conn internet.Connection) error {
+	hc, ok := conn.(interface{ Handshake() error })
+	if !ok {
+		return nil
+	}
+	if err := conn.SetDeadline(time.Now().Add(poolHandshakeTimeout)); err != nil {
+		newError("failed to set deadline").Base(err).AtDebug().WriteToLog()
+	}
+	if err := hc.Handshake(); err != nil {
+		conn.Close()
+		return newError("failed to handshake").Base(err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		newError("failed to clear deadline").Base(err).AtDebug().WriteToLog()
+	}
+	return nil
+}
+
+// Close closes all idle connections and stops refilling.
+func (p *connPool) Close() error {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	p.closed = true
+	if p.retry != nil {
+		p.retry.Stop()
+		p.retry = nil
+	}
+	for _, c := range p.idle {
+		if c.timer.Stop() {
+			c.conn.Close()
+		}
+	}
+	p.idle = nil
+	return nil
+}
+
+// pooledConn returns a pre-established connection to dest if the connection pool is enabled, or nil otherwise.
+func (h *Handler) pooledConn(dest net.Destination) internet.Connection {
+	config := h.senderSettings.GetConnectionPool()
+	if config == nil || config.Size == 0 || dest.Network != net.Network_TCP || h.senderSettings.ViaPrefix != nil {
+		return nil
+	}
+
+	h.poolAccess.Lock()
+	if h.poolsClosed {
+		h.poolAccess.Unlock()
+		return nil
+	}
+	if h.pools == nil {
+		h.pools = make(map[net.Destination]*connPool)
+	}
+	pool, found := h.pools[dest]
+	if !found {
+		maxIdle := time.Duration(config.MaxIdleTime) * time.Second
+		idleTimeout := time.Duration(config.IdleTimeout) * time.Second
+		pool = newConnPool(int(config.Size), maxIdle, idleTimeout, func() (internet.Connection, error) {
+			ctx := context.Background()
+			if h.senderSettings.Via != nil {
+				ctx = session.ContextWithOutbound(ctx, &session.Outbound{
+					Gateway: h.senderSettings.Via.AsAddress(),
+				})
+			}
+			if h.dns != nil {
+				ctx = internet.ContextWithDNSClient(ctx, h.dns)
+			}
+			return internet.Dial(ctx, dest, h.streamSettings)
+		})
+		h.pools[dest] = pool
+	}
+	h.poolAccess.Unlock()
+
+	return pool.get()
+}
+
+// removeEmptyPools closes and removes the pools of destinations that are no longer used.
+func (h *Handler) removeEmptyPools() error {
+	h.poolAccess.Lock()
+	defer h.poolAccess.Unlock()
+
+	for dest, pool := range h.pools {
+		if pool.empty() {
+			pool.Close() // nolint: errcheck
+			delete(h.pools, dest)
+		}
+	}
+	return nil
+}
+
+func (h *Handler) closePools() {
+	h.poolAccess.Lock()
+	defer h.poolAccess.Unlock()
+
+	for _, pool := range h.pools {
+		pool.Close() // nolint: errcheck
+	}
+	h.pools = nil
+	h.poolsClosed = true
+}
@@ -0,0 +1,162 @@
+package outbound
+
+import (
+	gotls "crypto/tls"
+	gonet "net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/tls/cert"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/tls"
+)
+
+func waitIdle(p *connPool) {
+	for i := 0; i < 100 && p.empty(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConnPoolTLSHandshake(t *testing.T) {
+	serverConfig := (&tls.Config{
+		Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil))},
+	}).GetTLSConfig()
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	common.Must(err)
+	defer listener.Close()
+
+	handshaked := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			handshaked <- err
+			return
+		}
+		defer conn.Close()
+		handshaked <- conn.(*gotls.Conn).Handshake()
+		time.Sleep(time.Second)
+	}()
+
+	p := newConnPool(1, time.Second*2, 0, func() (internet.Connection, error) {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			return nil, err
+		}
+		return tls.Client(conn, &gotls.Config{InsecureSkipVerify: true}), nil
+	})
+	defer p.Close()
+
+	if conn := p.get(); conn != nil {
+		t.Fatal("expected no idle connection, but got one")
+	}
+
+	// The server finishes the handshake before the client sends any data.
+	select {
+	case err := <-handshaked:
+		common.Must(err)
+	case <-time.After(time.Second * 2):
+		t.Fatal("pooled connection is not handshaked")
+	}
+
+	waitIdle(p)
+	conn := p.get()
+	if conn == nil {
+		t.Fatal("expected an idle connection, but nil")
+	}
+	defer conn.Close()
+	if !conn.(interface{ ConnectionState() gotls.ConnectionState }).ConnectionState().HandshakeComplete {
+		t.Error("handshake of pooled connection is not complete")
+	}
+}
+
+func TestRemoveEmptyPools(t *testing.T) {
+	dial := func() (internet.Connection, error) {
+		conn, _ := gonet.Pipe()
+		return conn, nil
+	}
+	used := newConnPool(1, time.Second*2, 0, dial)
+	unused := newConnPool(1, time.Second*2, 0, dial)
+	h := &Handler{
+		pools: map[net.Destination]*connPool{
+			net.TCPDestination(net.LocalHostIP, 80): used,
+			net.TCPDestination(net.LocalHostIP, 81): unused,
+		},
+	}
+	defer h.closePools()
+
+	used.get()
+	waitIdle(used)
+	common.Must(h.removeEmptyPools())
+
+	if len(h.pools) != 1 || h.pools[net.TCPDestination(net.LocalHostIP, 80)] != used {
+		t.Error("unexpected pools: ", h.pools)
+	}
+	if !unused.closed {
+		t.Error("removed pool is not closed")
+	}
+}
+
+func TestConnPoolRefillOnExpire(t *testing.T) {
+	var dials int32
+	p := newConnPool(1, time.Millisecond*100, time.Millisecond*500, func() (internet.Connection, error) {
+		atomic.AddInt32(&dials, 1)
+		conn, _ := gonet.Pipe()
+		return conn, nil
+	})
+	defer p.Close()
+
+	if conn := p.get(); conn != nil {
+		t.Fatal("expected no idle connection, but got one")
+	}
+
+	// Expired connections are replaced while the pool is in use.
+	time.Sleep(time.Millisecond * 350)
+	if n := atomic.LoadInt32(&dials); n < 3 {
+		t.Error("expected expired connections to be replaced, but only dialed ", n, " times")
+	}
+	if p.empty() {
+		t.Error("expected an idle connection, but the pool is empty")
+	}
+
+	// The pool is no longer refilled after it is not used for the idle timeout.
+	time.Sleep(time.Millisecond * 500)
+	n := atomic.LoadInt32(&dials)
+	time.Sleep(time.Millisecond * 300)
+	if m := atomic.LoadInt32(&dials); m != n {
+		t.Error("expected no more dials after idle timeout, but dialed ", m-n, " times")
+	}
+	if !p.empty() {
+		t.Error("expected the pool to be empty after idle timeout")
+	}
+}
+
+func TestConnPoolRetry(t *testing.T) {
+	var dials int32
+	p := newConnPool(1, time.Second*5, 0, func() (internet.Connection, error) {
+		if atomic.AddInt32(&dials, 1) == 1 {
+			return nil, newError("failed to dial")
+		}
+		conn, _ := gonet.Pipe()
+		return conn, nil
+	})
+	defer p.Close()
+
+	if conn := p.get(); conn != nil {
+		t.Fatal("expected no idle connection, but got one")
+	}
+
+	// The failed dial is retried without another get().
+	deadline := time.Now().Add(poolRetryInterval * 2)
+	for atomic.LoadInt32(&dials) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	waitIdle(p)
+	if conn := p.get(); conn == nil {
+		t.Error("expected an idle connection after retry, but nil")
+	} else {
+		conn.Close()
+	}
+}
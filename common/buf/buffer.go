@@ -2,6 +2,7 @@ package buf
 
 import (
 	"io"
+	"net"
 
 	"v2ray.com/core/common/bytespool"
 )
@@ -18,6 +19,10 @@ type Buffer struct {
 	v     []byte
 	start int32
 	end   int32
+
+	// UDP is the remote address of the packet in this Buffer, if the packet is
+	// sent to or received from a different address than the one of its link.
+	UDP *net.UDPAddr
 }
 
 // Release recycles the buffer into an internal buffer pool.
@@ -28,6 +33,7 @@ func (b *Buffer) Release() {
 
 	p := b.v
 	b.v = nil
+	b.UDP = nil
 	b.Clear()
 	pool.Put(p)
 }
@@ -14,6 +14,7 @@ import (
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal/done"
 	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/internet"
@@ -27,7 +28,7 @@ type ClientManager struct {
 
 func (m *ClientManager) Dispatch(ctx context.Context, link *transport.Link) error {
 	for i := 0; i < 16; i++ {
-		worker, err := m.Picker.PickAvailable()
+		worker, err := m.pick(ctx)
 		if err != nil {
 			return err
 		}
@@ -39,16 +40,33 @@ func (m *ClientManager) Dispatch(ctx context.Context, link *transport.Link) erro
 	return newError("unable to find an available mux client").AtWarning()
 }
 
+func (m *ClientManager) pick(ctx context.Context) (*ClientWorker, error) {
+	if w, ok := m.Picker.(availableWaiter); ok {
+		return w.WaitAvailable(ctx)
+	}
+	return m.Picker.PickAvailable()
+}
+
 type WorkerPicker interface {
 	PickAvailable() (*ClientWorker, error)
 }
 
+// availableWaiter is a WorkerPicker that is able to wait for a worker to become available.
+type availableWaiter interface {
+	WaitAvailable(ctx context.Context) (*ClientWorker, error)
+}
+
 type IncrementalWorkerPicker struct {
 	Factory ClientWorkerFactory
+	// MaxWorkers is the max number of workers. 0 for unlimited.
+	MaxWorkers uint32
+	// Counter returns the stats counter of active connections for the worker in the given slot. Optional.
+	Counter func(slot int) stats.Counter
 
 	access      sync.Mutex
 	workers     []*ClientWorker
 	cleanupTask *task.Periodic
+	released    chan struct{}
 }
 
 func (p *IncrementalWorkerPicker) cleanupFunc() error {
@@ -83,7 +101,20 @@ func (p *IncrementalWorkerPicker) findAvailable() int {
 	return -1
 }
 
-func (p *IncrementalWorkerPicker) pickInternal() (*ClientWorker, error, bool) {
+// release wakes up the callers of WaitAvailable, as a worker may become available.
+func (p *IncrementalWorkerPicker) release() {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.released != nil {
+		close(p.released)
+		p.released = nil
+	}
+}
+
+// pickInternal returns an available worker, and whether the worker is newly created. If all workers are full,
+// it also returns a channel that is closed when a worker may become available.
+func (p *IncrementalWorkerPicker) pickInternal() (*ClientWorker, bool, <-chan struct{}, error) {
 	p.access.Lock()
 	defer p.access.Unlock()
 
@@ -93,14 +124,26 @@ func (p *IncrementalWorkerPicker) pickInternal() (*ClientWorker, error, bool) {
 		if n > 1 && idx != n-1 {
 			p.workers[n-1], p.workers[idx] = p.workers[idx], p.workers[n-1]
 		}
-		return p.workers[idx], nil, false
+		return p.workers[idx], false, nil, nil
 	}
 
 	p.cleanup()
 
+	if p.MaxWorkers > 0 && len(p.workers) >= int(p.MaxWorkers) {
+		if p.released == nil {
+			p.released = make(chan struct{})
+		}
+		return nil, false, p.released, newError("all ", len(p.workers), " mux connections are full").AtWarning()
+	}
+
 	worker, err := p.Factory.Create()
 	if err != nil {
-		return nil, err, false
+		return nil, false, nil, err
+	}
+	worker.onRelease = p.release
+	if p.Counter != nil {
+		worker.slot = p.freeSlot()
+		worker.counter = p.Counter(worker.slot)
 	}
 	p.workers = append(p.workers, worker)
 
@@ -111,11 +154,24 @@ func (p *IncrementalWorkerPicker) pickInternal() (*ClientWorker, error, bool) {
 		}
 	}
 
-	return worker, nil, true
+	return worker, true, nil, nil
+}
+
+// freeSlot returns the smallest slot that is not used by any worker.
+func (p *IncrementalWorkerPicker) freeSlot() int {
+	used := make(map[int]bool, len(p.workers))
+	for _, w := range p.workers {
+		used[w.slot] = true
+	}
+	slot := 0
+	for used[slot] {
+		slot++
+	}
+	return slot
 }
 
 func (p *IncrementalWorkerPicker) PickAvailable() (*ClientWorker, error) {
-	worker, err, start := p.pickInternal()
+	worker, start, _, err := p.pickInternal()
 	if start {
 		common.Must(p.cleanupTask.Start())
 	}
@@ -123,6 +179,26 @@ func (p *IncrementalWorkerPicker) PickAvailable() (*ClientWorker, error) {
 	return worker, err
 }
 
+// WaitAvailable is like PickAvailable, but if all MaxWorkers workers are full, it waits for one of them to become
+// available, until ctx is done.
+func (p *IncrementalWorkerPicker) WaitAvailable(ctx context.Context) (*ClientWorker, error) {
+	for {
+		worker, start, released, err := p.pickInternal()
+		if start {
+			common.Must(p.cleanupTask.Start())
+		}
+		if released == nil {
+			return worker, err
+		}
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
+
 type ClientWorkerFactory interface {
 	Create() (*ClientWorker, error)
 }
@@ -166,6 +242,10 @@ func (f *DialingWorkerFactory) Create() (*ClientWorker, error) {
 type ClientStrategy struct {
 	MaxConcurrency uint32
 	MaxConnection  uint32
+	// IdleTimeout is the time before an idle worker is closed. 0 for the default of 16 seconds.
+	IdleTimeout time.Duration
+	// KeepAliveInterval is the interval between keep-alive frames. 0 for no keep-alive.
+	KeepAliveInterval time.Duration
 }
 
 type ClientWorker struct {
@@ -173,6 +253,16 @@ type ClientWorker struct {
 	link           transport.Link
 	done           *done.Instance
 	strategy       ClientStrategy
+
+	packetAccess     sync.Mutex
+	packetSessions   map[net.Destination]*packetSession
+	packetAddressing bool
+
+	slot    int
+	counter stats.Counter
+	// onRelease is called when a session of this worker ends, or the worker is closed. It must be set before
+	// the worker is used.
+	onRelease func()
 }
 
 var muxCoolAddress = net.DomainAddress("v1.mux.cool")
@@ -185,7 +275,9 @@ func NewClientWorker(stream transport.Link, s ClientStrategy) (*ClientWorker, er
 		link:           stream,
 		done:           done.New(),
 		strategy:       s,
+		packetSessions: make(map[net.Destination]*packetSession),
 	}
+	c.sessionManager.onRemove = c.release
 
 	go c.fetchOutput()
 	go c.monitor()
@@ -207,25 +299,58 @@ func (m *ClientWorker) Closed() bool {
 }
 
 func (m *ClientWorker) monitor() {
-	timer := time.NewTicker(time.Second * 16)
+	idleTimeout := m.strategy.IdleTimeout
+	if idleTimeout == 0 {
+		idleTimeout = time.Second * 16
+	}
+	timer := time.NewTicker(idleTimeout)
 	defer timer.Stop()
 
+	var keepAlive <-chan time.Time
+	if m.strategy.KeepAliveInterval > 0 {
+		ticker := time.NewTicker(m.strategy.KeepAliveInterval)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
 	for {
 		select {
 		case <-m.done.Wait():
 			m.sessionManager.Close()
 			common.Close(m.link.Writer)     // nolint: errcheck
 			common.Interrupt(m.link.Reader) // nolint: errcheck
+			m.release()
 			return
 		case <-timer.C:
 			size := m.sessionManager.Size()
 			if size == 0 && m.sessionManager.CloseIfNoSession() {
 				common.Must(m.done.Close())
 			}
+		case <-keepAlive:
+			frame := buf.New()
+			common.Must(FrameMetadata{SessionStatus: SessionStatusKeepAlive}.WriteTo(frame))
+			if err := m.link.Writer.WriteMultiBuffer(buf.MultiBuffer{frame}); err != nil {
+				newError("failed to send keep-alive").Base(err).AtDebug().WriteToLog()
+			}
 		}
 	}
 }
 
+// release updates the counter and notifies that the worker may have become available.
+func (m *ClientWorker) release() {
+	m.updateCounter()
+	if m.onRelease != nil {
+		m.onRelease()
+	}
+}
+
+// updateCounter sets the stats counter to the number of active connections.
+func (m *ClientWorker) updateCounter() {
+	if m.counter != nil {
+		m.counter.Set(int64(m.ActiveConnections()))
+	}
+}
+
 func writeFirstPayload(reader buf.Reader, writer *Writer) error {
 	err := buf.CopyOnceTimeout(reader, writer, time.Millisecond*100)
 	if err == buf.ErrNotTimeoutReader || err == buf.ErrReadTimeout {
@@ -287,7 +412,16 @@ func (m *ClientWorker) IsFull() bool {
 }
 
 func (m *ClientWorker) Dispatch(ctx context.Context, link *transport.Link) bool {
-	if m.IsFull() || m.Closed() {
+	if m.Closed() {
+		return false
+	}
+
+	source, dest, ok := packetEndpoints(ctx)
+	if ok && m.attachPacketLink(source, dest, link) {
+		return true
+	}
+
+	if m.IsFull() {
 		return false
 	}
 
@@ -296,12 +430,62 @@ func (m *ClientWorker) Dispatch(ctx context.Context, link *transport.Link) bool
 	if s == nil {
 		return false
 	}
-	s.input = link.Reader
-	s.output = link.Writer
+	if ok {
+		m.newPacketSession(s, source, dest, link)
+	} else {
+		s.input = link.Reader
+		s.output = link.Writer
+	}
+	m.updateCounter()
 	go fetchInput(ctx, s, m.link.Writer)
 	return true
 }
 
+// packetEndpoints returns the source and destination of a UDP request, if packets of the request
+// can share a session with other requests from the same source.
+func packetEndpoints(ctx context.Context) (net.Destination, net.Destination, bool) {
+	outbound := session.OutboundFromContext(ctx)
+	inbound := session.InboundFromContext(ctx)
+	if outbound == nil || inbound == nil || !inbound.Source.IsValid() {
+		return net.Destination{}, net.Destination{}, false
+	}
+	dest := outbound.Target
+	if dest.Network != net.Network_UDP || !dest.Address.Family().IsIP() {
+		return net.Destination{}, net.Destination{}, false
+	}
+	return inbound.Source, dest, true
+}
+
+// attachPacketLink adds the link into the existing UDP session of the source, once the server
+// is known to support packet addresses.
+func (m *ClientWorker) attachPacketLink(source net.Destination, dest net.Destination, link *transport.Link) bool {
+	m.packetAccess.Lock()
+	defer m.packetAccess.Unlock()
+
+	if !m.packetAddressing {
+		return false
+	}
+	p, found := m.packetSessions[source]
+	return found && p.attach(dest, link)
+}
+
+func (m *ClientWorker) newPacketSession(s *Session, source net.Destination, dest net.Destination, link *transport.Link) {
+	var p *packetSession
+	p, s.input = newPacketSession(func() {
+		m.packetAccess.Lock()
+		if m.packetSessions[source] == p {
+			delete(m.packetSessions, source)
+		}
+		m.packetAccess.Unlock()
+	})
+	s.output = p
+	p.attach(dest, link)
+
+	m.packetAccess.Lock()
+	m.packetSessions[source] = p
+	m.packetAccess.Unlock()
+}
+
 func (m *ClientWorker) handleStatueKeepAlive(meta *FrameMetadata, reader *buf.BufferedReader) error {
 	if meta.Option.Has(OptionData) {
 		return buf.Copy(NewStreamReader(reader), buf.Discard)
@@ -321,6 +505,12 @@ func (m *ClientWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.Buffere
 		return nil
 	}
 
+	if meta.Target.Network == net.Network_UDP {
+		m.packetAccess.Lock()
+		m.packetAddressing = true
+		m.packetAccess.Unlock()
+	}
+
 	s, found := m.sessionManager.Get(meta.SessionID)
 	if !found {
 		// Notify remote peer to close this session.
@@ -330,7 +520,7 @@ func (m *ClientWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.Buffere
 		return buf.Copy(NewStreamReader(reader), buf.Discard)
 	}
 
-	rr := s.NewReader(reader)
+	rr := newPacketAddressReader(s.NewReader(reader), meta.Target)
 	err := buf.Copy(rr, s.output)
 	if err != nil && buf.IsWriteError(err) {
 		newError("failed to write to downstream. closing session ", s.ID).Base(err).WriteToLog()
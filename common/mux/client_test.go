@@ -2,15 +2,18 @@ package mux_test
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/mux"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/testing/mocks"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/pipe"
@@ -114,3 +117,120 @@ func TestClientWorkerClose(t *testing.T) {
 
 	common.Must(w2.Close())
 }
+
+type testCounter struct {
+	value int64
+}
+
+func (c *testCounter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+func (c *testCounter) Set(v int64) int64 {
+	return atomic.SwapInt64(&c.value, v)
+}
+
+func (c *testCounter) Add(d int64) int64 {
+	return atomic.AddInt64(&c.value, d) - d
+}
+
+func TestIncrementalPickerMaxWorkers(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r, w := pipe.New(pipe.WithoutSizeLimit())
+	defer w.Close()
+	worker, err := mux.NewClientWorker(transport.Link{
+		Reader: r,
+		Writer: w,
+	}, mux.ClientStrategy{
+		MaxConcurrency: 1,
+	})
+	common.Must(err)
+
+	factory := mocks.NewMuxClientWorkerFactory(mockCtl)
+	factory.EXPECT().Create().Return(worker, nil)
+
+	counters := make(map[int]*testCounter)
+	manager := &mux.ClientManager{
+		Picker: &mux.IncrementalWorkerPicker{
+			Factory:    factory,
+			MaxWorkers: 1,
+			Counter: func(slot int) stats.Counter {
+				counters[slot] = new(testCounter)
+				return counters[slot]
+			},
+		},
+	}
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.DomainAddress("www.v2ray.com"), 80),
+	})
+	tr1, tw1 := pipe.New(pipe.WithoutSizeLimit())
+	defer tw1.Close()
+	common.Must(manager.Dispatch(ctx, &transport.Link{
+		Reader: tr1,
+		Writer: tw1,
+	}))
+	if c := counters[0]; c == nil || c.Value() != 1 {
+		t.Error("unexpected active counter: ", counters)
+	}
+
+	tr2, tw2 := pipe.New(pipe.WithoutSizeLimit())
+	defer tw2.Close()
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond*100)
+	defer cancel()
+	if err := manager.Dispatch(timeoutCtx, &transport.Link{
+		Reader: tr2,
+		Writer: tw2,
+	}); err == nil {
+		t.Error("expected error when all mux connections are full, but nil")
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- manager.Dispatch(ctx, &transport.Link{
+			Reader: tr2,
+			Writer: tw2,
+		})
+	}()
+
+	time.Sleep(time.Millisecond * 100)
+	select {
+	case err := <-errCh:
+		t.Fatal("expected dispatch to wait for a free connection, but got: ", err)
+	default:
+	}
+
+	// Close the first session, which frees the connection for the waiting one.
+	common.Must(tw1.Close())
+	select {
+	case err := <-errCh:
+		common.Must(err)
+	case <-time.After(time.Second * 2):
+		t.Fatal("dispatch is not woken up after the session ends")
+	}
+	if c := counters[0]; c.Value() != 1 {
+		t.Error("unexpected active counter: ", c.Value())
+	}
+}
+
+func TestClientWorkerKeepAlive(t *testing.T) {
+	downlinkReader, downlinkWriter := pipe.New(pipe.WithoutSizeLimit())
+	defer downlinkWriter.Close()
+	uplinkReader, uplinkWriter := pipe.New(pipe.WithoutSizeLimit())
+
+	_, err := mux.NewClientWorker(transport.Link{
+		Reader: downlinkReader,
+		Writer: uplinkWriter,
+	}, mux.ClientStrategy{
+		KeepAliveInterval: time.Millisecond * 100,
+	})
+	common.Must(err)
+
+	var meta mux.FrameMetadata
+	common.Must(meta.Unmarshal(&buf.BufferedReader{Reader: uplinkReader}))
+	if meta.SessionStatus != mux.SessionStatusKeepAlive {
+		t.Error("unexpected session status: ", meta.SessionStatus)
+	}
+}
@@ -51,6 +51,11 @@ Frame format
 2 bytes - port
 n bytes - address
 
+Network, port and address are present in frames of status New. Frames of status
+Keep in UDP sessions may also carry them, as the remote address of the packet
+in the frame. Peers that don't support this ignore the extra metadata, and
+send all packets of the session to its initial target.
+
 */
 
 type FrameMetadata struct {
@@ -70,7 +75,7 @@ func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
 	common.Must(b.WriteByte(byte(f.SessionStatus)))
 	common.Must(b.WriteByte(byte(f.Option)))
 
-	if f.SessionStatus == SessionStatusNew {
+	if f.SessionStatus == SessionStatusNew || f.hasPacketAddress() {
 		switch f.Target.Network {
 		case net.Network_TCP:
 			common.Must(b.WriteByte(byte(TargetNetworkTCP)))
@@ -88,6 +93,10 @@ func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
 	return nil
 }
 
+func (f FrameMetadata) hasPacketAddress() bool {
+	return f.SessionStatus == SessionStatusKeep && f.Target.Network == net.Network_UDP && f.Target.Address != nil
+}
+
 // Unmarshal reads FrameMetadata from the given reader.
 func (f *FrameMetadata) Unmarshal(reader io.Reader) error {
 	metaLen, err := serial.ReadUint16(reader)
@@ -119,7 +128,7 @@ func (f *FrameMetadata) UnmarshalFromBuffer(b *buf.Buffer) error {
 	f.Option = bitmask.Byte(b.Byte(3))
 	f.Target.Network = net.Network_Unknown
 
-	if f.SessionStatus == SessionStatusNew {
+	if f.SessionStatus == SessionStatusNew || (f.SessionStatus == SessionStatusKeep && b.Len() > 4) {
 		if b.Len() < 8 {
 			return newError("insufficient buffer: ", b.Len())
 		}
@@ -23,3 +23,31 @@ func BenchmarkFrameWrite(b *testing.B) {
 		writer.Clear()
 	}
 }
+
+func TestFrameKeepWithPacketAddress(t *testing.T) {
+	frame := mux.FrameMetadata{
+		Target:        net.UDPDestination(net.IPAddress([]byte{1, 2, 3, 4}), net.Port(53)),
+		SessionID:     1,
+		SessionStatus: mux.SessionStatusKeep,
+	}
+	b := buf.New()
+	defer b.Release()
+	common.Must(frame.WriteTo(b))
+
+	var actual mux.FrameMetadata
+	common.Must(actual.Unmarshal(b))
+	if actual != frame {
+		t.Error("unexpected frame: ", actual)
+	}
+
+	tcpFrame := mux.FrameMetadata{
+		Target:        net.TCPDestination(net.IPAddress([]byte{1, 2, 3, 4}), net.Port(80)),
+		SessionID:     2,
+		SessionStatus: mux.SessionStatusKeep,
+	}
+	b.Clear()
+	common.Must(tcpFrame.WriteTo(b))
+	if b.Len() != 6 {
+		t.Error("unexpected address in Keep frame of TCP session, len: ", b.Len())
+	}
+}
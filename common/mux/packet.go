@@ -0,0 +1,225 @@
+package mux
+
+import (
+	"context"
+	"sync"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/pipe"
+)
+
+// packetAddressReader sets the remote address of packets read from its underlying reader, unless they have one.
+type packetAddressReader struct {
+	reader buf.Reader
+	addr   *net.UDPAddr
+}
+
+func newPacketAddressReader(reader buf.Reader, dest net.Destination) buf.Reader {
+	if dest.Network != net.Network_UDP || dest.Address == nil || !dest.Address.Family().IsIP() {
+		return reader
+	}
+	return &packetAddressReader{
+		reader: reader,
+		addr: &net.UDPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		},
+	}
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *packetAddressReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.reader.ReadMultiBuffer()
+	for _, b := range mb {
+		if b.UDP == nil {
+			b.UDP = r.addr
+		}
+	}
+	return mb, err
+}
+
+// packetSession merges the UDP links of one source endpoint into one session, so that the
+// server sends all packets of the source from the same endpoint, and packets from any remote
+// address flow back to the source.
+type packetSession struct {
+	access  sync.Mutex
+	input   *pipe.Writer
+	links   map[net.Destination]*transport.Link
+	last    *transport.Link
+	closed  bool
+	onClose func()
+}
+
+func newPacketSession(onClose func()) (*packetSession, buf.Reader) {
+	reader, writer := pipe.New(pipe.WithSizeLimit(64 * 1024))
+	return &packetSession{
+		input:   writer,
+		links:   make(map[net.Destination]*transport.Link),
+		onClose: onClose,
+	}, reader
+}
+
+func (p *packetSession) hasLink(dest net.Destination) bool {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	_, found := p.links[dest]
+	return found
+}
+
+// attach adds a link of the given destination into this session. It returns false if the session is closed.
+func (p *packetSession) attach(dest net.Destination, link *transport.Link) bool {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.closed {
+		return false
+	}
+
+	p.links[dest] = link
+	p.last = link
+	go p.fetchInput(dest, link)
+	return true
+}
+
+func (p *packetSession) detach(dest net.Destination, link *transport.Link) {
+	p.access.Lock()
+	if p.links[dest] == link {
+		delete(p.links, dest)
+	}
+	if p.last == link {
+		p.last = nil
+		for _, l := range p.links {
+			p.last = l
+			break
+		}
+	}
+	empty := len(p.links) == 0
+	p.access.Unlock()
+
+	if empty {
+		p.Close() // nolint: errcheck
+	}
+}
+
+func (p *packetSession) fetchInput(dest net.Destination, link *transport.Link) {
+	defer p.detach(dest, link)
+
+	if err := buf.Copy(newPacketAddressReader(link.Reader, dest), p.input); err != nil {
+		common.Interrupt(link.Reader)
+	}
+}
+
+// WriteMultiBuffer implements buf.Writer. Packets go to the link of their remote address, or to
+// the latest link if there is no such link.
+func (p *packetSession) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	for i, b := range mb {
+		p.access.Lock()
+		link := p.last
+		if b.UDP != nil {
+			if l, found := p.links[net.DestinationFromAddr(b.UDP)]; found {
+				link = l
+			}
+		}
+		p.access.Unlock()
+
+		if link == nil {
+			buf.ReleaseMulti(mb[i:])
+			return newError("no link for UDP session")
+		}
+		if err := link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}); err != nil {
+			newError("failed to write packet from ", b.UDP).Base(err).AtDebug().WriteToLog()
+		}
+	}
+	return nil
+}
+
+// Close implements common.Closable.
+func (p *packetSession) Close() error {
+	p.access.Lock()
+	if p.closed {
+		p.access.Unlock()
+		return nil
+	}
+	p.closed = true
+	links := p.links
+	p.links = make(map[net.Destination]*transport.Link)
+	p.last = nil
+	p.access.Unlock()
+
+	common.Close(p.input) // nolint: errcheck
+	for _, link := range links {
+		common.Close(link.Writer)     // nolint: errcheck
+		common.Interrupt(link.Reader) // nolint: errcheck
+	}
+	if p.onClose != nil {
+		p.onClose()
+	}
+	return nil
+}
+
+// packetDispatcher is the output of a UDP session on the server. Packets to a new remote address are dispatched
+// in a new link, so that each destination goes through routing and policy on its own.
+type packetDispatcher struct {
+	ctx        context.Context
+	dispatcher routing.Dispatcher
+	session    *packetSession
+	access     sync.Mutex
+}
+
+func (d *packetDispatcher) dispatch(dest net.Destination) error {
+	d.access.Lock()
+	defer d.access.Unlock()
+
+	if d.session.hasLink(dest) {
+		return nil
+	}
+
+	ctx := d.ctx
+	if msg := log.AccessMessageFromContext(ctx); msg != nil {
+		m := *msg
+		m.To = dest
+		ctx = log.ContextWithAccessMessage(ctx, &m)
+	}
+	newError("received request for ", dest).WriteToLog(session.ExportIDToError(ctx))
+	link, err := d.dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		return newError("failed to dispatch request to ", dest).Base(err)
+	}
+	if !d.session.attach(dest, link) {
+		common.Close(link.Writer)     // nolint: errcheck
+		common.Interrupt(link.Reader) // nolint: errcheck
+		return newError("UDP session closed")
+	}
+	return nil
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (d *packetDispatcher) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	packets := mb[:0]
+	for _, b := range mb {
+		if b.UDP != nil {
+			if err := d.dispatch(net.DestinationFromAddr(b.UDP)); err != nil {
+				newError("dropping packet").Base(err).WriteToLog(session.ExportIDToError(d.ctx))
+				b.Release()
+				continue
+			}
+		}
+		packets = append(packets, b)
+	}
+	if len(packets) == 0 {
+		return nil
+	}
+	return d.session.WriteMultiBuffer(packets)
+}
+
+// Close implements common.Closable.
+func (d *packetDispatcher) Close() error {
+	return d.session.Close()
+}
@@ -82,8 +82,13 @@ func NewServerWorker(ctx context.Context, d routing.Dispatcher, link *transport.
 	return worker, nil
 }
 
-func handle(ctx context.Context, s *Session, output buf.Writer) {
+func handle(ctx context.Context, s *Session, output buf.Writer, dest net.Destination) {
 	writer := NewResponseWriter(s.ID, output, s.transferType)
+	if dest.Network == net.Network_UDP {
+		// Responses of UDP sessions carry their remote addresses, which also tells the client
+		// that packet addresses are supported.
+		writer.dest = dest
+	}
 	if err := buf.Copy(s.input, writer); err != nil {
 		newError("session ", s.ID, " ends.").Base(err).WriteToLog(session.ExportIDToError(ctx))
 		writer.hasError = true
@@ -138,9 +143,17 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 	}
 	if meta.Target.Network == net.Network_UDP {
 		s.transferType = protocol.TransferTypePacket
+		p, reader := newPacketSession(nil)
+		p.attach(meta.Target, link)
+		s.input = reader
+		s.output = &packetDispatcher{
+			ctx:        ctx,
+			dispatcher: w.dispatcher,
+			session:    p,
+		}
 	}
 	w.sessionManager.Add(s)
-	go handle(ctx, s, w.link.Writer)
+	go handle(ctx, s, w.link.Writer, meta.Target)
 	if !meta.Option.Has(OptionData) {
 		return nil
 	}
@@ -168,7 +181,7 @@ func (w *ServerWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.Buffere
 		return buf.Copy(NewStreamReader(reader), buf.Discard)
 	}
 
-	rr := s.NewReader(reader)
+	rr := newPacketAddressReader(s.NewReader(reader), meta.Target)
 	err := buf.Copy(rr, s.output)
 
 	if err != nil && buf.IsWriteError(err) {
@@ -13,6 +13,8 @@ type SessionManager struct {
 	sessions map[uint16]*Session
 	count    uint16
 	closed   bool
+	// onRemove is called after a session is removed, if not nil.
+	onRemove func()
 }
 
 func NewSessionManager() *SessionManager {
@@ -74,17 +76,23 @@ func (m *SessionManager) Add(s *Session) {
 
 func (m *SessionManager) Remove(id uint16) {
 	m.Lock()
-	defer m.Unlock()
-
 	if m.closed {
+		m.Unlock()
 		return
 	}
 
+	_, found := m.sessions[id]
 	delete(m.sessions, id)
 
 	if len(m.sessions) == 0 {
 		m.sessions = make(map[uint16]*Session, 16)
 	}
+	onRemove := m.onRemove
+	m.Unlock()
+
+	if found && onRemove != nil {
+		onRemove()
+	}
 }
 
 func (m *SessionManager) Get(id uint16) (*Session, bool) {
@@ -79,6 +79,9 @@ func writeMetaWithFrame(writer buf.Writer, meta FrameMetadata, data buf.MultiBuf
 func (w *Writer) writeData(mb buf.MultiBuffer) error {
 	meta := w.getNextFrameMeta()
 	meta.Option.Set(OptionData)
+	if w.transferType == protocol.TransferTypePacket && len(mb) > 0 && mb[0].UDP != nil {
+		meta.Target = net.DestinationFromAddr(mb[0].UDP)
+	}
 
 	return writeMetaWithFrame(w.writer, meta, mb)
 }
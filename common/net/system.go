@@ -26,6 +26,8 @@ var SplitHostPort = net.SplitHostPort
 
 var CIDRMask = net.CIDRMask
 
+var ParseCIDR = net.ParseCIDR
+
 type Addr = net.Addr
 type Conn = net.Conn
 type PacketConn = net.PacketConn
@@ -0,0 +1,66 @@
+package proxyproto
+
+import (
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+)
+
+// Conn is a net.Conn that starts with a PROXY protocol header. The header is read on the first call of Read or RemoteAddr,
+// so that accepting connections is not blocked by slow clients. RemoteAddr returns the original source in the header.
+type Conn struct {
+	net.Conn
+
+	timeout time.Duration
+	once    sync.Once
+	header  *Header
+	err     error
+}
+
+// NewConn creates a new Conn. The header must be received within the timeout, or the connection fails.
+func NewConn(conn net.Conn, timeout time.Duration) *Conn {
+	return &Conn{
+		Conn:    conn,
+		timeout: timeout,
+	}
+}
+
+func (c *Conn) readHeader() {
+	c.once.Do(func() {
+		if c.timeout > 0 {
+			c.Conn.SetReadDeadline(time.Now().Add(c.timeout)) // nolint: errcheck
+		}
+		c.header, c.err = ReadHeader(c.Conn)
+		if c.timeout > 0 {
+			c.Conn.SetReadDeadline(time.Time{}) // nolint: errcheck
+		}
+	})
+}
+
+// Header returns the PROXY protocol header of this connection.
+func (c *Conn) Header() (*Header, error) {
+	c.readHeader()
+	return c.header, c.err
+}
+
+// Read implements net.Conn.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.readHeader()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.Conn.Read(b)
+}
+
+// RemoteAddr implements net.Conn. It returns the source address in the PROXY protocol header, if any.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.readHeader()
+	if c.err != nil || !c.header.HasAddress() {
+		return c.Conn.RemoteAddr()
+	}
+	return &net.TCPAddr{
+		IP:   c.header.Source.Address.IP(),
+		Port: int(c.header.Source.Port),
+	}
+}
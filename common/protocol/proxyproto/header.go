@@ -0,0 +1,230 @@
+// Package proxyproto implements the PROXY protocol of HAProxy, version 1 and 2.
+package proxyproto
+
+//go:generate errorgen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+
+	"v2ray.com/core/common/net"
+)
+
+var (
+	signatureV1 = []byte("PROXY ")
+	signatureV2 = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+)
+
+const (
+	maxLengthV1 = 107
+
+	commandLocal = 0x00
+	commandProxy = 0x01
+
+	familyUnspec = 0x00
+	familyInet   = 0x10
+	familyInet6  = 0x20
+
+	transportStream = 0x01
+	transportDgram  = 0x02
+)
+
+// Header is a PROXY protocol header.
+type Header struct {
+	// Version is either 1 or 2.
+	Version byte
+	// Source and Destination are the original endpoints of the connection.
+	// They are invalid if the header doesn't carry address information, e.g., health checks of the load balancer.
+	Source      net.Destination
+	Destination net.Destination
+}
+
+// HasAddress returns true if the header carries the original endpoints of the connection.
+func (h *Header) HasAddress() bool {
+	return h.Source.IsValid() && h.Destination.IsValid()
+}
+
+// ReadHeader reads a PROXY protocol header of either version from the reader.
+// It never reads beyond the end of the header.
+func ReadHeader(reader io.Reader) (*Header, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(reader, b[:12]); err != nil {
+		return nil, newError("failed to read PROXY protocol header").Base(err)
+	}
+	switch {
+	case bytes.Equal(b[:12], signatureV2):
+		if _, err := io.ReadFull(reader, b[12:16]); err != nil {
+			return nil, newError("failed to read PROXY protocol header").Base(err)
+		}
+		return readHeaderV2(reader, b[12:16])
+	case bytes.HasPrefix(b[:12], signatureV1):
+		return readHeaderV1(reader, b[:12])
+	default:
+		return nil, newError("invalid PROXY protocol signature")
+	}
+}
+
+func readHeaderV1(reader io.Reader, prefix []byte) (*Header, error) {
+	line := make([]byte, len(prefix), maxLengthV1)
+	copy(line, prefix)
+	var c [1]byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= maxLengthV1 {
+			return nil, newError("PROXY protocol v1 header too long")
+		}
+		if _, err := io.ReadFull(reader, c[:]); err != nil {
+			return nil, newError("failed to read PROXY protocol header").Base(err)
+		}
+		line = append(line, c[0])
+	}
+
+	fields := strings.Split(string(line[len(signatureV1):len(line)-2]), " ")
+	header := &Header{Version: 1}
+	if fields[0] == "UNKNOWN" {
+		return header, nil
+	}
+	if len(fields) != 5 || (fields[0] != "TCP4" && fields[0] != "TCP6") {
+		return nil, newError("invalid PROXY protocol v1 header: ", string(line))
+	}
+
+	var err error
+	if header.Source, err = parseDestinationV1(fields[1], fields[3]); err != nil {
+		return nil, err
+	}
+	if header.Destination, err = parseDestinationV1(fields[2], fields[4]); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+func parseDestinationV1(ip string, port string) (net.Destination, error) {
+	addr := net.ParseAddress(ip)
+	if !addr.Family().IsIP() {
+		return net.Destination{}, newError("invalid IP address in PROXY protocol header: ", ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return net.Destination{}, newError("invalid port in PROXY protocol header: ", port).Base(err)
+	}
+	return net.TCPDestination(addr, net.Port(p)), nil
+}
+
+func readHeaderV2(reader io.Reader, b []byte) (*Header, error) {
+	if b[0]>>4 != 2 {
+		return nil, newError("unsupported PROXY protocol version: ", b[0]>>4)
+	}
+	command := b[0] & 0x0F
+	family := b[1] & 0xF0
+	transport := b[1] & 0x0F
+	payload := make([]byte, binary.BigEndian.Uint16(b[2:4]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, newError("failed to read PROXY protocol header").Base(err)
+	}
+
+	header := &Header{Version: 2}
+	if command == commandLocal {
+		return header, nil
+	}
+	if command != commandProxy {
+		return nil, newError("unknown PROXY protocol command: ", command)
+	}
+
+	var ipLen int
+	switch family {
+	case familyInet:
+		ipLen = net.IPv4len
+	case familyInet6:
+		ipLen = net.IPv6len
+	default:
+		// Unix sockets and unspecified addresses are not useful for us.
+		return header, nil
+	}
+	if len(payload) < ipLen*2+4 {
+		return nil, newError("PROXY protocol v2 header too short")
+	}
+
+	newDestination := net.TCPDestination
+	if transport == transportDgram {
+		newDestination = net.UDPDestination
+	}
+	header.Source = newDestination(net.IPAddress(payload[:ipLen]), net.PortFromBytes(payload[ipLen*2:ipLen*2+2]))
+	header.Destination = newDestination(net.IPAddress(payload[ipLen:ipLen*2]), net.PortFromBytes(payload[ipLen*2+2:ipLen*2+4]))
+	return header, nil
+}
+
+// ipPair returns the IP addresses of source and destination, in the same length.
+func ipPair(src net.Destination, dest net.Destination) (net.IP, net.IP, bool) {
+	if !src.IsValid() || !dest.IsValid() || !src.Address.Family().IsIP() || !dest.Address.Family().IsIP() {
+		return nil, nil, false
+	}
+	srcIP := src.Address.IP()
+	destIP := dest.Address.IP()
+	if len(srcIP) == len(destIP) {
+		return srcIP, destIP, true
+	}
+	return srcIP.To16(), destIP.To16(), true
+}
+
+func formatIPV1(ip net.IP) string {
+	if len(ip) == net.IPv6len && ip.To4() != nil {
+		// IPv4-mapped addresses in TCP6 lines must still be IPv6 literals.
+		return "::ffff:" + ip.To4().String()
+	}
+	return ip.String()
+}
+
+// Bytes returns the serialized form of this header.
+func (h *Header) Bytes() []byte {
+	srcIP, destIP, ok := ipPair(h.Source, h.Destination)
+
+	if h.Version == 1 {
+		if !ok {
+			return []byte("PROXY UNKNOWN\r\n")
+		}
+		proto := "TCP4"
+		if len(srcIP) == net.IPv6len {
+			proto = "TCP6"
+		}
+		return []byte(strings.Join([]string{"PROXY", proto, formatIPV1(srcIP), formatIPV1(destIP), h.Source.Port.String(), h.Destination.Port.String()}, " ") + "\r\n")
+	}
+
+	b := make([]byte, 0, 16+net.IPv6len*2+4)
+	b = append(b, signatureV2...)
+	if !ok {
+		return append(b, 0x20|commandLocal, familyUnspec, 0, 0)
+	}
+	family := byte(familyInet)
+	if len(srcIP) == net.IPv6len {
+		family = familyInet6
+	}
+	transport := byte(transportStream)
+	if h.Destination.Network == net.Network_UDP {
+		transport = transportDgram
+	}
+	b = append(b, 0x20|commandProxy, family|transport)
+	b = append(b, byte((len(srcIP)*2+4)>>8), byte(len(srcIP)*2+4))
+	b = append(b, srcIP...)
+	b = append(b, destIP...)
+	b = append(b, byte(h.Source.Port>>8), byte(h.Source.Port))
+	b = append(b, byte(h.Destination.Port>>8), byte(h.Destination.Port))
+	return b
+}
+
+// WriteHeader writes a PROXY protocol header of the given version into the writer.
+func WriteHeader(writer io.Writer, version byte, src net.Destination, dest net.Destination) error {
+	if version != 1 && version != 2 {
+		return newError("unsupported PROXY protocol version: ", version)
+	}
+	header := &Header{
+		Version:     version,
+		Source:      src,
+		Destination: dest,
+	}
+	if _, err := writer.Write(header.Bytes()); err != nil {
+		return newError("failed to write PROXY protocol header").Base(err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package proxyproto_test
+
+import (
+	"bytes"
+	"io"
+	gonet "net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	. "v2ray.com/core/common/protocol/proxyproto"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	cases := []struct {
+		version byte
+		src     net.Destination
+		dest    net.Destination
+	}{
+		{
+			version: 1,
+			src:     net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234),
+			dest:    net.TCPDestination(net.ParseAddress("5.6.7.8"), 443),
+		},
+		{
+			version: 1,
+			src:     net.TCPDestination(net.ParseAddress("2001:db8::1"), 1234),
+			dest:    net.TCPDestination(net.ParseAddress("2001:db8::2"), 443),
+		},
+		{
+			version: 2,
+			src:     net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234),
+			dest:    net.TCPDestination(net.ParseAddress("5.6.7.8"), 443),
+		},
+		{
+			version: 2,
+			src:     net.TCPDestination(net.ParseAddress("2001:db8::1"), 65535),
+			dest:    net.TCPDestination(net.ParseAddress("2001:db8::2"), 1),
+		},
+		{
+			version: 2,
+			src:     net.UDPDestination(net.ParseAddress("1.2.3.4"), 53),
+			dest:    net.UDPDestination(net.ParseAddress("5.6.7.8"), 5353),
+		},
+	}
+
+	for _, c := range cases {
+		buffer := new(bytes.Buffer)
+		common.Must(WriteHeader(buffer, c.version, c.src, c.dest))
+		buffer.WriteString("payload")
+
+		header, err := ReadHeader(buffer)
+		common.Must(err)
+		if header.Version != c.version {
+			t.Error("version: ", header.Version)
+		}
+		if r := cmp.Diff(header.Source, c.src); r != "" {
+			t.Error("source: ", r)
+		}
+		if r := cmp.Diff(header.Destination, c.dest); r != "" {
+			t.Error("destination: ", r)
+		}
+		if s := buffer.String(); s != "payload" {
+			t.Error("unexpected remaining data: ", s)
+		}
+	}
+}
+
+func TestHeaderMixedFamily(t *testing.T) {
+	src := net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234)
+	dest := net.TCPDestination(net.ParseAddress("2001:db8::2"), 443)
+
+	h := &Header{Version: 1, Source: src, Destination: dest}
+	if s := string(h.Bytes()); s != "PROXY TCP6 ::ffff:1.2.3.4 2001:db8::2 1234 443\r\n" {
+		t.Error("unexpected v1 header: ", s)
+	}
+
+	for _, version := range []byte{1, 2} {
+		h := &Header{Version: version, Source: src, Destination: dest}
+		header, err := ReadHeader(bytes.NewReader(h.Bytes()))
+		common.Must(err)
+		if r := cmp.Diff(header.Source, src); r != "" {
+			t.Error("source: ", r)
+		}
+	}
+}
+
+func TestHeaderWithoutAddress(t *testing.T) {
+	for _, version := range []byte{1, 2} {
+		buffer := new(bytes.Buffer)
+		common.Must(WriteHeader(buffer, version, net.Destination{}, net.TCPDestination(net.LocalHostIP, 80)))
+
+		header, err := ReadHeader(buffer)
+		common.Must(err)
+		if header.HasAddress() {
+			t.Error("expected no address, but got ", header.Source)
+		}
+		if buffer.Len() != 0 {
+			t.Error("unexpected remaining data: ", buffer.Len())
+		}
+	}
+}
+
+func TestInvalidHeader(t *testing.T) {
+	cases := []string{
+		"GET / HTTP/1.1\r\n\r\n",
+		"PROXY TCP4 1.2.3.4 5.6.7.8 1234\r\n",
+		"PROXY TCP4 1.2.3.4 5.6.7.8 1234 99999\r\n",
+		"PROXY TCP4 1.2.3.4",
+	}
+	for _, c := range cases {
+		if _, err := ReadHeader(bytes.NewReader([]byte(c))); err == nil {
+			t.Error("expected error for ", c)
+		}
+	}
+}
+
+func TestConnRemoteAddr(t *testing.T) {
+	client, server := gonet.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go func() {
+		common.Must(WriteHeader(client, 2, net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234), net.TCPDestination(net.ParseAddress("5.6.7.8"), 443)))
+		common.Must2(client.Write([]byte("test")))
+	}()
+
+	conn := NewConn(server, 0)
+	if addr := conn.RemoteAddr().String(); addr != "1.2.3.4:1234" {
+		t.Error("unexpected remote address: ", addr)
+	}
+	b := make([]byte, 4)
+	common.Must2(io.ReadFull(conn, b))
+	if string(b) != "test" {
+		t.Error("unexpected payload: ", string(b))
+	}
+}
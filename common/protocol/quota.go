@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"sync"
+	"time"
+)
+
+// QuotaCounter is a counter of traffic in bytes, usually a stats.Counter.
+type QuotaCounter interface {
+	Value() int64
+	Set(int64) int64
+}
+
+// Quota is the traffic quota and expire time of a user. It is shared by all copies of a MemoryUser, and may be changed at runtime.
+type Quota struct {
+	sync.RWMutex
+	traffic  uint64
+	expire   time.Time
+	counters []QuotaCounter
+}
+
+// NewQuota creates a new Quota. Zero traffic means unlimited traffic, and zero expire means the user never expires.
+func NewQuota(traffic uint64, expire time.Time) *Quota {
+	return &Quota{
+		traffic: traffic,
+		expire:  expire,
+	}
+}
+
+// Set changes the traffic quota and expire time.
+func (q *Quota) Set(traffic uint64, expire time.Time) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.traffic = traffic
+	q.expire = expire
+}
+
+// Traffic returns the maximum traffic in bytes, or 0 if unlimited.
+func (q *Quota) Traffic() uint64 {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.traffic
+}
+
+// ExpireTime returns the time after which the user is rejected, or zero time if the user never expires.
+func (q *Quota) ExpireTime() time.Time {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.expire
+}
+
+// Bind sets the counters that measure traffic of the user.
+func (q *Quota) Bind(counters ...QuotaCounter) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.counters = counters
+}
+
+// Used returns the traffic in bytes used by the user, as measured by the bound counters.
+func (q *Quota) Used() uint64 {
+	q.RLock()
+	defer q.RUnlock()
+
+	var used int64
+	for _, c := range q.counters {
+		used += c.Value()
+	}
+	if used < 0 {
+		return 0
+	}
+	return uint64(used)
+}
+
+// Reset sets the bound counters to zero, so that the user may use the full traffic quota again.
+func (q *Quota) Reset() {
+	q.RLock()
+	defer q.RUnlock()
+
+	for _, c := range q.counters {
+		c.Set(0)
+	}
+}
+
+// Check returns an error if the user has expired or used up the traffic quota.
+func (q *Quota) Check() error {
+	if expire := q.ExpireTime(); !expire.IsZero() && time.Now().After(expire) {
+		return newError("user expired at ", expire.Format(time.RFC3339))
+	}
+	if traffic := q.Traffic(); traffic > 0 && q.Used() >= traffic {
+		return newError("user exceeded traffic quota of ", traffic, " bytes")
+	}
+	return nil
+}
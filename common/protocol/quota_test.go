@@ -0,0 +1,85 @@
+package protocol_test
+
+import (
+	"testing"
+	"time"
+
+	. "v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/trojan"
+)
+
+type testCounter int64
+
+func (c *testCounter) Value() int64 {
+	return int64(*c)
+}
+
+func (c *testCounter) Set(v int64) int64 {
+	prev := int64(*c)
+	*c = testCounter(v)
+	return prev
+}
+
+func TestQuotaTraffic(t *testing.T) {
+	uplink := testCounter(400)
+	downlink := testCounter(500)
+
+	quota := NewQuota(1000, time.Time{})
+	if err := quota.Check(); err != nil {
+		t.Error("expect no error without counters, but got ", err)
+	}
+
+	quota.Bind(&uplink, &downlink)
+	if err := quota.Check(); err != nil {
+		t.Error("expect no error with 900 bytes used, but got ", err)
+	}
+
+	downlink = 600
+	if err := quota.Check(); err == nil {
+		t.Error("expect error with 1000 bytes used")
+	}
+
+	quota.Reset()
+	if used := quota.Used(); used != 0 {
+		t.Error("expect 0 bytes used after reset, but got ", used)
+	}
+	if err := quota.Check(); err != nil {
+		t.Error("expect no error after reset, but got ", err)
+	}
+}
+
+func TestQuotaExpire(t *testing.T) {
+	quota := NewQuota(0, time.Now().Add(-time.Minute))
+	if err := quota.Check(); err == nil {
+		t.Error("expect error for expired user")
+	}
+
+	quota.Set(0, time.Now().Add(time.Hour))
+	if err := quota.Check(); err != nil {
+		t.Error("expect no error after extending expire time, but got ", err)
+	}
+}
+
+func TestUserQuota(t *testing.T) {
+	user := &User{
+		Email:   "love@v2ray.com",
+		Account: serial.ToTypedMessage(&trojan.Account{Password: "password"}),
+	}
+	mUser, err := user.ToMemoryUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mUser.Quota != nil {
+		t.Error("expect no quota for unlimited user")
+	}
+
+	user.TrafficQuota = 1024
+	mUser, err = user.ToMemoryUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mUser.Quota == nil || mUser.Quota.Traffic() != 1024 {
+		t.Error("expect quota of 1024 bytes, but got ", mUser.Quota)
+	}
+}
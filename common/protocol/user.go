@@ -1,5 +1,11 @@
 package protocol
 
+import (
+	"time"
+
+	"v2ray.com/core/common/serial"
+)
+
 func (u *User) GetTypedAccount() (Account, error) {
 	if u.GetAccount() == nil {
 		return nil, newError("Account missing").AtWarning()
@@ -23,11 +29,19 @@ func (u *User) ToMemoryUser() (*MemoryUser, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &MemoryUser{
+	user := &MemoryUser{
 		Account: account,
 		Email:   u.Email,
 		Level:   u.Level,
-	}, nil
+	}
+	if u.TrafficQuota > 0 || u.ExpireTime > 0 {
+		var expire time.Time
+		if u.ExpireTime > 0 {
+			expire = time.Unix(u.ExpireTime, 0)
+		}
+		user.Quota = NewQuota(u.TrafficQuota, expire)
+	}
+	return user, nil
 }
 
 // MemoryUser is a parsed form of User, to reduce number of parsing of Account proto.
@@ -36,4 +50,24 @@ type MemoryUser struct {
 	Account Account
 	Email   string
 	Level   uint32
+	// Quota is the traffic quota and expire time of the user. Nil for unlimited.
+	Quota *Quota
+}
+
+// ToProto converts this MemoryUser back into User. The account is omitted if it is not a ProtoAccount.
+func (u *MemoryUser) ToProto() *User {
+	user := &User{
+		Email: u.Email,
+		Level: u.Level,
+	}
+	if account, ok := u.Account.(ProtoAccount); ok {
+		user.Account = serial.ToTypedMessage(account.ToProto())
+	}
+	if u.Quota != nil {
+		user.TrafficQuota = u.Quota.Traffic()
+		if expire := u.Quota.ExpireTime(); !expire.IsZero() {
+			user.ExpireTime = expire.Unix()
+		}
+	}
+	return user
 }
@@ -23,10 +23,14 @@ type User struct {
 	Level uint32 `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
 	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	// Protocol specific account information. Must be the account proto in one of the proxies.
-	Account              *serial.TypedMessage `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Account *serial.TypedMessage `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	// Maximum traffic of this user in bytes, uplink and downlink combined. 0 for unlimited.
+	TrafficQuota uint64 `protobuf:"varint,4,opt,name=traffic_quota,json=trafficQuota,proto3" json:"traffic_quota,omitempty"`
+	// Unix timestamp in seconds, after which this user is rejected. 0 for never.
+	ExpireTime           int64    `protobuf:"varint,5,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *User) Reset()         { *m = User{} }
@@ -75,6 +79,20 @@ func (m *User) GetAccount() *serial.TypedMessage {
 	return nil
 }
 
+func (m *User) GetTrafficQuota() uint64 {
+	if m != nil {
+		return m.TrafficQuota
+	}
+	return 0
+}
+
+func (m *User) GetExpireTime() int64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*User)(nil), "v2ray.core.common.protocol.User")
 }
@@ -84,19 +102,22 @@ func init() {
 }
 
 var fileDescriptor_9da52c16030369bd = []byte{
-	// 218 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2c, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0xce, 0xcf, 0xcd, 0xcd,
-	0xcf, 0xd3, 0x2f, 0x28, 0xca, 0x2f, 0xc9, 0x4f, 0xce, 0xcf, 0xd1, 0x2f, 0x2d, 0x4e, 0x2d, 0xd2,
-	0x03, 0xf3, 0x84, 0xa4, 0x60, 0x4a, 0x8b, 0x52, 0xf5, 0x20, 0xca, 0xf4, 0x60, 0xca, 0xa4, 0x0c,
-	0xb0, 0x1b, 0x53, 0x9c, 0x5a, 0x94, 0x99, 0x98, 0xa3, 0x5f, 0x52, 0x59, 0x90, 0x9a, 0x12, 0x9f,
-	0x9b, 0x5a, 0x5c, 0x9c, 0x98, 0x9e, 0x0a, 0xd1, 0xa4, 0x54, 0xc2, 0xc5, 0x12, 0x5a, 0x9c, 0x5a,
-	0x24, 0x24, 0xc2, 0xc5, 0x9a, 0x93, 0x5a, 0x96, 0x9a, 0x23, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x1b,
-	0x04, 0xe1, 0x80, 0x44, 0x53, 0x73, 0x13, 0x33, 0x73, 0x24, 0x98, 0x14, 0x18, 0x35, 0x38, 0x83,
-	0x20, 0x1c, 0x21, 0x07, 0x2e, 0xf6, 0xc4, 0xe4, 0xe4, 0xfc, 0xd2, 0xbc, 0x12, 0x09, 0x66, 0x05,
-	0x46, 0x0d, 0x6e, 0x23, 0x35, 0x3d, 0x4c, 0x37, 0x41, 0xec, 0xd4, 0x0b, 0x01, 0xd9, 0xe9, 0x0b,
-	0xb1, 0x32, 0x08, 0xa6, 0xcd, 0xc9, 0x93, 0x4b, 0x2e, 0x39, 0x3f, 0x57, 0x0f, 0xb7, 0x4f, 0x02,
-	0x18, 0xa3, 0x38, 0x60, 0xec, 0x55, 0x4c, 0x52, 0x61, 0x46, 0x41, 0x89, 0x95, 0x7a, 0xce, 0x20,
-	0x85, 0xce, 0x10, 0x85, 0x01, 0x50, 0xc9, 0x24, 0x36, 0xb0, 0x32, 0x63, 0x40, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x02, 0xf5, 0x15, 0xcc, 0x42, 0x01, 0x00, 0x00,
+	// 265 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0xbd, 0x4e, 0xc3, 0x30,
+	0x14, 0x85, 0xe5, 0x36, 0xe5, 0xc7, 0xa5, 0x4b, 0xc4, 0x10, 0x65, 0x80, 0x08, 0x24, 0x14, 0x16,
+	0x1b, 0x85, 0x17, 0x40, 0x74, 0x62, 0x40, 0x2a, 0x56, 0x61, 0x60, 0x89, 0x8c, 0xb9, 0x45, 0x96,
+	0xec, 0xde, 0x60, 0x3b, 0x15, 0x79, 0x25, 0x1e, 0x81, 0xa7, 0x43, 0xa9, 0x9b, 0x09, 0xba, 0xf9,
+	0x7c, 0xfe, 0xae, 0x8f, 0x75, 0xe9, 0xf5, 0xa6, 0x72, 0xb2, 0x63, 0x0a, 0x2d, 0x57, 0xe8, 0x80,
+	0x2b, 0xb4, 0x16, 0xd7, 0xbc, 0x71, 0x18, 0x50, 0xa1, 0xe1, 0xad, 0x07, 0xc7, 0xb6, 0x29, 0xcd,
+	0x07, 0xd5, 0x01, 0x8b, 0x1a, 0x1b, 0xb4, 0xfc, 0xe6, 0xff, 0x67, 0x3c, 0x38, 0x2d, 0x0d, 0x0f,
+	0x5d, 0x03, 0xef, 0xb5, 0x05, 0xef, 0xe5, 0x07, 0xc4, 0xa1, 0x8b, 0x1f, 0x42, 0x93, 0x67, 0x0f,
+	0x2e, 0x3d, 0xa5, 0x13, 0x03, 0x1b, 0x30, 0x19, 0x29, 0x48, 0x39, 0x13, 0x31, 0xf4, 0x14, 0xac,
+	0xd4, 0x26, 0x1b, 0x15, 0xa4, 0x3c, 0x16, 0x31, 0xa4, 0x77, 0xf4, 0x50, 0x2a, 0x85, 0xed, 0x3a,
+	0x64, 0xe3, 0x82, 0x94, 0xd3, 0xea, 0x8a, 0xfd, 0xfd, 0x54, 0x2c, 0x65, 0xcb, 0xbe, 0xf4, 0x31,
+	0x76, 0x8a, 0x61, 0x2c, 0xbd, 0xa4, 0xb3, 0xe0, 0xe4, 0x6a, 0xa5, 0x55, 0xfd, 0xd9, 0x62, 0x90,
+	0x59, 0x52, 0x90, 0x32, 0x11, 0x27, 0x3b, 0xf8, 0xd4, 0xb3, 0xf4, 0x9c, 0x4e, 0xe1, 0xab, 0xd1,
+	0x0e, 0xea, 0xa0, 0x2d, 0x64, 0x93, 0x82, 0x94, 0x63, 0x41, 0x23, 0x5a, 0x6a, 0x0b, 0xf7, 0x0f,
+	0xf4, 0x4c, 0xa1, 0x65, 0xfb, 0x17, 0xb2, 0x20, 0xaf, 0x47, 0xc3, 0xf9, 0x7b, 0x94, 0xbf, 0x54,
+	0x42, 0x76, 0x6c, 0xde, 0x8b, 0xf3, 0x28, 0x2e, 0x76, 0x97, 0x6f, 0x07, 0x5b, 0xed, 0xf6, 0x77,
+	0x00, 0xae, 0x1f, 0x74, 0x8c, 0x89, 0x01, 0x00, 0x00,
 }
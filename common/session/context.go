@@ -10,6 +10,7 @@ const (
 	outboundSessionKey
 	contentSessionKey
 	muxPreferedSessionKey
+	loopbackSessionKey
 )
 
 // ContextWithID returns a new context with the given ID.
@@ -70,3 +71,17 @@ func MuxPreferedFromContext(ctx context.Context) bool {
 	}
 	return false
 }
+
+// ContextWithLoopbackTags returns a new context with the given inbound tags, which the connection has been
+// dispatched under before being looped back.
+func ContextWithLoopbackTags(ctx context.Context, tags []string) context.Context {
+	return context.WithValue(ctx, loopbackSessionKey, tags)
+}
+
+// LoopbackTagsFromContext returns the inbound tags in this context, or nil if the connection has not been looped back.
+func LoopbackTagsFromContext(ctx context.Context) []string {
+	if tags, ok := ctx.Value(loopbackSessionKey).([]string); ok {
+		return tags
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package outbound
 
 import (
 	"context"
+	"time"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/features"
@@ -21,6 +22,27 @@ type HandlerSelector interface {
 	Select([]string) []string
 }
 
+// Drainer is an optional interface of Handler, for handlers that can be closed without cutting existing connections.
+type Drainer interface {
+	// Drain closes the handler after all existing connections end, or the timeout expires.
+	// It returns immediately, and the handler should no longer receive new connections.
+	Drain(timeout time.Duration) error
+}
+
+// GracefulManager is an optional interface of Manager, for removing and replacing handlers gracefully.
+type GracefulManager interface {
+	// DrainHandler removes the handler with the given tag from Manager, and drains it in background.
+	DrainHandler(ctx context.Context, tag string, timeout time.Duration) error
+	// ReplaceHandler replaces the handler with the same tag by the given one, and drains the old handler in background.
+	// It returns an error if there is no handler with the same tag.
+	ReplaceHandler(ctx context.Context, handler Handler, timeout time.Duration) error
+}
+
+// HandlerLister is an optional interface of Manager, for listing all outbound.Handlers.
+type HandlerLister interface {
+	ListHandlers(ctx context.Context) []Handler
+}
+
 // Manager is a feature that manages outbound.Handlers.
 //
 // v2ray:api:stable
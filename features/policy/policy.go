@@ -35,6 +35,48 @@ type Buffer struct {
 	PerConnection int32
 }
 
+// RateLimit contains settings for a token bucket limiting the throughput of traffic in one direction.
+type RateLimit struct {
+	// Sustained rate in bytes per second. 0 for unlimited.
+	BytesPerSecond int64
+	// Maximum number of bytes that can be sent at once after an idle period.
+	Burst int64
+}
+
+// Enabled returns true if the rate is limited.
+func (r RateLimit) Enabled() bool {
+	return r.BytesPerSecond > 0
+}
+
+// Bandwidth contains rate limit settings for traffic of a user.
+type Bandwidth struct {
+	// Limit for traffic from the user to the target.
+	Uplink RateLimit
+	// Limit for traffic from the target to the user.
+	Downlink RateLimit
+}
+
+// Quota contains settings for users with traffic quota or expire time.
+type Quota struct {
+	// Whether or not to close existing connections of a user once the user runs out of quota or expires.
+	CloseConnection bool
+}
+
+// Limit contains limits on connections of a user.
+type Limit struct {
+	// Maximum number of concurrent connections. 0 for unlimited.
+	Connections uint32
+	// Maximum number of distinct source IPs. 0 for unlimited.
+	SourceIPs uint32
+	// Duration for which a source IP still counts after its last connection is closed.
+	SourceIPWindow time.Duration
+}
+
+// Enabled returns true if there is any limit.
+func (l Limit) Enabled() bool {
+	return l.Connections > 0 || l.SourceIPs > 0
+}
+
 // SystemStats contains stat policy settings on system level.
 type SystemStats struct {
 	// Whether or not to enable stat counter for uplink traffic in inbound handlers.
@@ -51,9 +93,12 @@ type System struct {
 
 // Session is session based settings for controlling V2Ray requests. It contains various settings (or limits) that may differ for different users in the context.
 type Session struct {
-	Timeouts Timeout // Timeout settings
-	Stats    Stats
-	Buffer   Buffer
+	Timeouts  Timeout // Timeout settings
+	Stats     Stats
+	Buffer    Buffer
+	Bandwidth Bandwidth
+	Quota     Quota
+	Limit     Limit
 }
 
 // Manager is a feature that provides Policy for the given user by its id or level.
@@ -5,6 +5,7 @@ require (
 	github.com/golang/protobuf v1.3.2
 	github.com/google/go-cmp v0.2.0
 	github.com/gorilla/websocket v1.4.1
+	github.com/klauspost/cpuid/v2 v2.0.11 // indirect
 	github.com/miekg/dns v1.1.4
 	github.com/refraction-networking/utls v0.0.0-20190909200633-43c36d3c1f57
 	go.starlark.net v0.0.0-20190919145610-979af19b165c
@@ -15,6 +16,7 @@ require (
 	google.golang.org/genproto v0.0.0-20180831171423-11092d34479b // indirect
 	google.golang.org/grpc v1.24.0
 	h12.io/socks v1.0.0
+	lukechampine.com/blake3 v1.1.7
 )
 
 go 1.13
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"strings"
+	"time"
 
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
@@ -54,6 +55,25 @@ func (v *Address) Build() *net.IPOrDomain {
 	return net.NewIPOrDomain(v.Address)
 }
 
+// AddressList is a list of addresses. It can be parsed from either a single address or an array of addresses.
+type AddressList []*Address
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (v *AddressList) UnmarshalJSON(data []byte) error {
+	var address Address
+	if err := json.Unmarshal(data, &address); err == nil {
+		*v = AddressList{&address}
+		return nil
+	}
+
+	var list []*Address
+	if err := json.Unmarshal(data, &list); err != nil {
+		return newError("invalid address list: ", string(data)).Base(err)
+	}
+	*v = AddressList(list)
+	return nil
+}
+
 type Network string
 
 func (v Network) Build() net.Network {
@@ -201,6 +221,9 @@ func (list *PortList) UnmarshalJSON(data []byte) error {
 			return newError("invalid port: ", string(data)).Base(err2)
 		}
 	}
+	if strings.HasPrefix(listStr, "env:") {
+		listStr = os.Getenv(listStr[4:])
+	}
 	rangelist := strings.Split(listStr, ",")
 	for _, rangeStr := range rangelist {
 		trimmed := strings.TrimSpace(rangeStr)
@@ -210,6 +233,9 @@ func (list *PortList) UnmarshalJSON(data []byte) error {
 				if err != nil {
 					return newError("invalid port range: ", trimmed).Base(err)
 				}
+				if from > to {
+					return newError("invalid port range ", from, " -> ", to)
+				}
 				list.Range = append(list.Range, PortRange{From: uint32(from), To: uint32(to)})
 			} else {
 				port, err := parseIntPort([]byte(trimmed))
@@ -237,3 +263,24 @@ func (v *User) Build() *protocol.User {
 		Level: uint32(v.LevelByte),
 	}
 }
+
+// UserQuota is the traffic quota and expire time of a user.
+type UserQuota struct {
+	// Maximum traffic in MB.
+	TrafficQuota uint64 `json:"trafficQuota"`
+	// Expire time in RFC 3339 format, e.g., "2020-01-01T00:00:00Z".
+	ExpireTime string `json:"expireTime"`
+}
+
+// Apply sets the quota to the given user.
+func (q *UserQuota) Apply(user *protocol.User) error {
+	user.TrafficQuota = q.TrafficQuota * 1024 * 1024
+	if len(q.ExpireTime) > 0 {
+		t, err := time.Parse(time.RFC3339, q.ExpireTime)
+		if err != nil {
+			return newError("invalid expire time: ", q.ExpireTime).Base(err)
+		}
+		user.ExpireTime = t.Unix()
+	}
+	return nil
+}
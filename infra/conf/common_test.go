@@ -203,6 +203,26 @@ func TestOverRangeStringPort(t *testing.T) {
 	}
 }
 
+func TestEnvPortList(t *testing.T) {
+	common.Must(os.Setenv("PORT_LIST", "1234,2000-2010"))
+
+	var portList PortList
+	common.Must(json.Unmarshal([]byte("\"env:PORT_LIST\""), &portList))
+
+	if r := cmp.Diff(portList, PortList{
+		Range: []PortRange{{From: 1234, To: 1234}, {From: 2000, To: 2010}},
+	}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestReversedPortList(t *testing.T) {
+	var portList PortList
+	if err := json.Unmarshal([]byte("\"443,8010-8000\""), &portList); err == nil {
+		t.Error("nil error")
+	}
+}
+
 func TestUserParsing(t *testing.T) {
 	user := new(User)
 	common.Must(json.Unmarshal([]byte(`{
@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/proxy/fallback"
+)
+
+// FallbackConfig is the JSON config of a fallback destination of inbounds.
+type FallbackConfig struct {
+	Alpn string          `json:"alpn"`
+	Name string          `json:"name"`
+	Path string          `json:"path"`
+	Type string          `json:"type"`
+	Dest json.RawMessage `json:"dest"`
+	Xver uint32          `json:"xver"`
+}
+
+// Build implements Buildable.
+func (c *FallbackConfig) Build() (*fallback.Fallback, error) {
+	config := &fallback.Fallback{
+		Alpn: c.Alpn,
+		Name: c.Name,
+		Path: c.Path,
+		Type: strings.ToLower(c.Type),
+		Xver: c.Xver,
+	}
+	if config.Xver > 2 {
+		return nil, newError("unsupported PROXY protocol version in fallback: ", c.Xver)
+	}
+	if len(config.Path) > 0 && config.Path[0] != '/' {
+		return nil, newError("fallback path must start with '/': ", c.Path)
+	}
+
+	var port uint16
+	if err := json.Unmarshal(c.Dest, &port); err == nil {
+		c.Dest, _ = json.Marshal(strconv.Itoa(int(port)))
+	}
+	var dest string
+	if err := json.Unmarshal(c.Dest, &dest); err != nil {
+		return nil, newError("invalid fallback dest: ", string(c.Dest)).Base(err)
+	}
+
+	switch {
+	case config.Type == "unix" || strings.HasPrefix(dest, "/") || strings.HasPrefix(dest, "@"):
+		config.Type = "unix"
+	case config.Type == "" || config.Type == "tcp":
+		config.Type = "tcp"
+		if _, err := strconv.ParseUint(dest, 10, 16); err == nil {
+			dest = "127.0.0.1:" + dest
+		}
+		if _, _, err := net.SplitHostPort(dest); err != nil {
+			return nil, newError("invalid fallback dest: ", dest).Base(err)
+		}
+	default:
+		return nil, newError("unknown fallback type: ", c.Type)
+	}
+	config.Dest = dest
+
+	return config, nil
+}
+
+func buildFallbacks(configs []*FallbackConfig) ([]*fallback.Fallback, error) {
+	var fallbacks []*fallback.Fallback
+	for _, c := range configs {
+		f, err := c.Build()
+		if err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, f)
+	}
+	return fallbacks, nil
+}
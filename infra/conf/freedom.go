@@ -15,6 +15,7 @@ type FreedomConfig struct {
 	Timeout        *uint32 `json:"timeout"`
 	Redirect       string  `json:"redirect"`
 	UserLevel      uint32  `json:"userLevel"`
+	ProxyProtocol  uint32  `json:"proxyProtocol"`
 }
 
 // Build implements Buildable
@@ -34,6 +35,10 @@ func (c *FreedomConfig) Build() (proto.Message, error) {
 		config.Timeout = *c.Timeout
 	}
 	config.UserLevel = c.UserLevel
+	if c.ProxyProtocol > 2 {
+		return nil, newError("unsupported PROXY protocol version: ", c.ProxyProtocol)
+	}
+	config.ProxyProtocol = c.ProxyProtocol
 	if len(c.Redirect) > 0 {
 		host, portStr, err := net.SplitHostPort(c.Redirect)
 		if err != nil {
@@ -51,11 +51,15 @@ type HttpRemoteConfig struct {
 	Users   []json.RawMessage `json:"users"`
 }
 type HttpClientConfig struct {
-	Servers []*HttpRemoteConfig `json:"servers"`
+	Servers         []*HttpRemoteConfig `json:"servers"`
+	Headers         map[string]string   `json:"headers"`
+	ConnectProtocol string              `json:"connectProtocol"`
 }
 
 func (v *HttpClientConfig) Build() (proto.Message, error) {
 	config := new(http.ClientConfig)
+	config.Header = v.Headers
+	config.ConnectProtocol = v.ConnectProtocol
 	config.Server = make([]*protocol.ServerEndpoint, len(v.Servers))
 	for idx, serverConfig := range v.Servers {
 		server := &protocol.ServerEndpoint{
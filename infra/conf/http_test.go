@@ -3,6 +3,9 @@ package conf_test
 import (
 	"testing"
 
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
 	. "v2ray.com/core/infra/conf"
 	"v2ray.com/core/proxy/http"
 )
@@ -37,3 +40,53 @@ func TestHttpServerConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestHttpClientConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(HttpClientConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"servers": [{
+					"address": "127.0.0.1",
+					"port": 443,
+					"users": [
+						{
+							"user": "my-username",
+							"pass": "my-password",
+							"level": 1
+						}
+					]
+				}],
+				"headers": {
+					"User-Agent": "v2ray"
+				},
+				"connectProtocol": "websocket"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &http.ClientConfig{
+				Server: []*protocol.ServerEndpoint{
+					{
+						Address: net.NewIPOrDomain(net.LocalHostIP),
+						Port:    443,
+						User: []*protocol.User{
+							{
+								Level: 1,
+								Account: serial.ToTypedMessage(&http.Account{
+									Username: "my-username",
+									Password: "my-password",
+								}),
+							},
+						},
+					},
+				},
+				Header: map[string]string{
+					"User-Agent": "v2ray",
+				},
+				ConnectProtocol: "websocket",
+			},
+		},
+	})
+}
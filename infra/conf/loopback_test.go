@@ -0,0 +1,30 @@
+package conf_test
+
+import (
+	"testing"
+
+	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/loopback"
+)
+
+func TestLoopbackConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(LoopbackConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"inboundTag": "dns-in"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &loopback.Config{
+				InboundTag: "dns-in",
+			},
+		},
+	})
+
+	if _, err := loadJSON(creator)(`{}`); err == nil {
+		t.Error("nil error without inbound tag")
+	}
+}
@@ -15,26 +15,31 @@ type MTProtoAccount struct {
 	Secret string `json:"secret"`
 }
 
-// Build implements Buildable
+// Build implements Buildable. The secret is 32 hex chars, optionally prefixed by "dd" for random padding, or
+// prefixed by "ee" and followed by the hex-encoded domain for fake TLS.
 func (a *MTProtoAccount) Build() (*mtproto.Account, error) {
-	if len(a.Secret) != 32 {
-		return nil, newError("MTProto secret must have 32 chars")
-	}
 	secret, err := hex.DecodeString(a.Secret)
 	if err != nil {
 		return nil, newError("failed to decode secret: ", a.Secret).Base(err)
 	}
-	return &mtproto.Account{
+	account := &mtproto.Account{
 		Secret: secret,
-	}, nil
+	}
+	if err := account.Validate(); err != nil {
+		return nil, newError("MTProto secret must have 32 chars, optionally prefixed by dd, or prefixed by ee and followed by domain").Base(err)
+	}
+	return account, nil
 }
 
 type MTProtoServerConfig struct {
-	Users []json.RawMessage `json:"users"`
+	Users      []json.RawMessage `json:"users"`
+	ForwardTLS bool              `json:"forwardTLS"`
 }
 
 func (c *MTProtoServerConfig) Build() (proto.Message, error) {
-	config := &mtproto.ServerConfig{}
+	config := &mtproto.ServerConfig{
+		ForwardTls: c.ForwardTLS,
+	}
 
 	if len(c.Users) == 0 {
 		return nil, newError("zero MTProto users configured.")
@@ -36,5 +36,52 @@ func TestMTProtoServerConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"users": [{
+					"secret": "ddb0cbcef5a486d9636472ac27f8e11a9d"
+				}]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &mtproto.ServerConfig{
+				User: []*protocol.User{
+					{
+						Account: serial.ToTypedMessage(&mtproto.Account{
+							Secret: []byte{0xdd, 176, 203, 206, 245, 164, 134, 217, 99, 100, 114, 172, 39, 248, 225, 26, 157},
+						}),
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"users": [{
+					"secret": "eeb0cbcef5a486d9636472ac27f8e11a9d76327261792e636f6d"
+				}],
+				"forwardTLS": true
+			}`,
+			Parser: loadJSON(creator),
+			Output: &mtproto.ServerConfig{
+				User: []*protocol.User{
+					{
+						Account: serial.ToTypedMessage(&mtproto.Account{
+							Secret: append([]byte{0xee, 176, 203, 206, 245, 164, 134, 217, 99, 100, 114, 172, 39, 248, 225, 26, 157}, "v2ray.com"...),
+						}),
+					},
+				},
+				ForwardTls: true,
+			},
+		},
 	})
+
+	for _, secret := range []string{
+		"b0cbcef5a486d9636472ac27f8e11a",
+		"ddb0cbcef5a486d9636472ac27f8e11a9d00",
+		"eeb0cbcef5a486d9636472ac27f8e11a9d",
+		"efb0cbcef5a486d9636472ac27f8e11a9d7632",
+	} {
+		if _, err := loadJSON(creator)(`{"users": [{"secret": "` + secret + `"}]}`); err == nil {
+			t.Error("nil error for secret ", secret)
+		}
+	}
 }
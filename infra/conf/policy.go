@@ -12,6 +12,25 @@ type Policy struct {
 	StatsUserUplink   bool    `json:"statsUserUplink"`
 	StatsUserDownlink bool    `json:"statsUserDownlink"`
 	BufferSize        *int32  `json:"bufferSize"`
+	UplinkRate        uint64  `json:"uplinkRate"`
+	UplinkBurst       uint64  `json:"uplinkBurst"`
+	DownlinkRate      uint64  `json:"downlinkRate"`
+	DownlinkBurst     uint64  `json:"downlinkBurst"`
+	QuotaCloseConn    bool    `json:"quotaCloseConnection"`
+	ConnectionLimit   uint32  `json:"connectionLimit"`
+	SourceIPLimit     uint32  `json:"sourceIpLimit"`
+	SourceIPWindow    uint32  `json:"sourceIpWindow"`
+}
+
+// buildRateLimit converts rate in KB/s and burst in KB into policy.Policy_RateLimit.
+func buildRateLimit(rate uint64, burst uint64) *policy.Policy_RateLimit {
+	if rate == 0 {
+		return nil
+	}
+	return &policy.Policy_RateLimit{
+		Rate:  rate * 1024,
+		Burst: burst * 1024,
+	}
 }
 
 func (t *Policy) Build() (*policy.Policy, error) {
@@ -47,6 +66,27 @@ func (t *Policy) Build() (*policy.Policy, error) {
 		}
 	}
 
+	if t.UplinkRate > 0 || t.DownlinkRate > 0 {
+		p.Bandwidth = &policy.Policy_Bandwidth{
+			Uplink:   buildRateLimit(t.UplinkRate, t.UplinkBurst),
+			Downlink: buildRateLimit(t.DownlinkRate, t.DownlinkBurst),
+		}
+	}
+
+	if t.QuotaCloseConn {
+		p.Quota = &policy.Policy_Quota{
+			CloseConnection: true,
+		}
+	}
+
+	if t.ConnectionLimit > 0 || t.SourceIPLimit > 0 {
+		p.Limit = &policy.Policy_Limit{
+			Connection:     t.ConnectionLimit,
+			SourceIp:       t.SourceIPLimit,
+			SourceIpWindow: &policy.Second{Value: t.SourceIPWindow},
+		}
+	}
+
 	return p, nil
 }
 
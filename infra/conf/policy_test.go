@@ -38,3 +38,19 @@ func TestBufferSize(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimit(t *testing.T) {
+	pConf := Policy{
+		UplinkRate:    100,
+		DownlinkRate:  200,
+		DownlinkBurst: 400,
+	}
+	p, err := pConf.Build()
+	common.Must(err)
+	if p.Bandwidth.Uplink.Rate != 100*1024 || p.Bandwidth.Uplink.Burst != 0 {
+		t.Error("unexpected uplink rate limit: ", p.Bandwidth.Uplink)
+	}
+	if p.Bandwidth.Downlink.Rate != 200*1024 || p.Bandwidth.Downlink.Burst != 400*1024 {
+		t.Error("unexpected downlink rate limit: ", p.Bandwidth.Downlink)
+	}
+}
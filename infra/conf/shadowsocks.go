@@ -26,19 +26,65 @@ func cipherFromString(c string) shadowsocks.CipherType {
 		return shadowsocks.CipherType_AES_256_GCM
 	case "chacha20-poly1305", "aead_chacha20_poly1305", "chacha20-ietf-poly1305":
 		return shadowsocks.CipherType_CHACHA20_POLY1305
+	case "2022-blake3-aes-128-gcm":
+		return shadowsocks.CipherType_BLAKE3_AES_128_GCM
+	case "2022-blake3-aes-256-gcm":
+		return shadowsocks.CipherType_BLAKE3_AES_256_GCM
+	case "2022-blake3-chacha20-poly1305":
+		return shadowsocks.CipherType_BLAKE3_CHACHA20_POLY1305
 	default:
 		return shadowsocks.CipherType_UNKNOWN
 	}
 }
 
+type ShadowsocksUserConfig struct {
+	Cipher   string `json:"method"`
+	Password string `json:"password"`
+	Level    byte   `json:"level"`
+	Email    string `json:"email"`
+	UserQuota
+}
+
+// Build implements Buildable.
+func (v *ShadowsocksUserConfig) Build() (*protocol.User, error) {
+	if v.Password == "" {
+		return nil, newError("Shadowsocks password is not specified.")
+	}
+	account := &shadowsocks.Account{
+		Password:   v.Password,
+		CipherType: cipherFromString(v.Cipher),
+	}
+	if account.CipherType == shadowsocks.CipherType_UNKNOWN {
+		return nil, newError("unknown cipher method: ", v.Cipher)
+	}
+	user := &protocol.User{
+		Email:   v.Email,
+		Level:   uint32(v.Level),
+		Account: serial.ToTypedMessage(account),
+	}
+	if err := v.UserQuota.Apply(user); err != nil {
+		return nil, newError("invalid Shadowsocks user").Base(err)
+	}
+	return user, nil
+}
+
 type ShadowsocksServerConfig struct {
-	Cipher      string       `json:"method"`
-	Password    string       `json:"password"`
-	UDP         bool         `json:"udp"`
-	Level       byte         `json:"level"`
-	Email       string       `json:"email"`
-	OTA         *bool        `json:"ota"`
-	NetworkList *NetworkList `json:"network"`
+	Cipher      string                   `json:"method"`
+	Password    string                   `json:"password"`
+	UDP         bool                     `json:"udp"`
+	Level       byte                     `json:"level"`
+	Email       string                   `json:"email"`
+	OTA         *bool                    `json:"ota"`
+	NetworkList *NetworkList             `json:"network"`
+	Fallbacks   []*FallbackConfig        `json:"fallbacks"`
+	Users       []*ShadowsocksUserConfig `json:"clients"`
+	UserQuota
+
+	Plugin       string   `json:"plugin"`
+	PluginOpts   string   `json:"plugin_opts"`
+	PluginArgs   []string `json:"plugin_args"`
+	PluginListen *Address `json:"plugin_listen"`
+	PluginPort   uint16   `json:"plugin_port"`
 }
 
 func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
@@ -46,7 +92,39 @@ func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
 	config.UdpEnabled = v.UDP
 	config.Network = v.NetworkList.Build()
 
+	if v.Plugin != "" {
+		if v.PluginPort == 0 {
+			return nil, newError("Shadowsocks plugin port is not specified.")
+		}
+		config.Plugin = &shadowsocks.PluginConfig{
+			Command: v.Plugin,
+			Options: v.PluginOpts,
+			Args:    v.PluginArgs,
+		}
+		if v.PluginListen != nil {
+			config.PluginAddress = v.PluginListen.Build()
+		}
+		config.PluginPort = uint32(v.PluginPort)
+	}
+
+	fallbacks, err := buildFallbacks(v.Fallbacks)
+	if err != nil {
+		return nil, err
+	}
+	config.Fallbacks = fallbacks
+
+	for _, user := range v.Users {
+		u, err := user.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Users = append(config.Users, u)
+	}
+
 	if v.Password == "" {
+		if len(config.Users) > 0 {
+			return config, nil
+		}
 		return nil, newError("Shadowsocks password is not specified.")
 	}
 	account := &shadowsocks.Account{
@@ -70,6 +148,9 @@ func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
 		Level:   uint32(v.Level),
 		Account: serial.ToTypedMessage(account),
 	}
+	if err := v.UserQuota.Apply(config.User); err != nil {
+		return nil, newError("invalid Shadowsocks user").Base(err)
+	}
 
 	return config, nil
 }
@@ -85,12 +166,23 @@ type ShadowsocksServerTarget struct {
 }
 
 type ShadowsocksClientConfig struct {
-	Servers []*ShadowsocksServerTarget `json:"servers"`
+	Servers    []*ShadowsocksServerTarget `json:"servers"`
+	Plugin     string                     `json:"plugin"`
+	PluginOpts string                     `json:"plugin_opts"`
+	PluginArgs []string                   `json:"plugin_args"`
 }
 
 func (v *ShadowsocksClientConfig) Build() (proto.Message, error) {
 	config := new(shadowsocks.ClientConfig)
 
+	if v.Plugin != "" {
+		config.Plugin = &shadowsocks.PluginConfig{
+			Command: v.Plugin,
+			Options: v.PluginOpts,
+			Args:    v.PluginArgs,
+		}
+	}
+
 	if len(v.Servers) == 0 {
 		return nil, newError("0 Shadowsocks server configured.")
 	}
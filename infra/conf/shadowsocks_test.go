@@ -1,8 +1,12 @@
 package conf_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
@@ -32,5 +36,139 @@ func TestShadowsocksServerConfigParsing(t *testing.T) {
 				Network: []net.Network{net.Network_TCP},
 			},
 		},
+		{
+			Input: `{
+				"clients": [
+					{
+						"method": "aes-128-gcm",
+						"password": "password-1",
+						"email": "love@v2ray.com",
+						"level": 1,
+						"trafficQuota": 1024,
+						"expireTime": "2020-01-01T00:00:00Z"
+					},
+					{
+						"method": "chacha20-poly1305",
+						"password": "password-2"
+					}
+				],
+				"network": "tcp,udp"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &shadowsocks.ServerConfig{
+				Users: []*protocol.User{
+					{
+						Email: "love@v2ray.com",
+						Level: 1,
+						Account: serial.ToTypedMessage(&shadowsocks.Account{
+							CipherType: shadowsocks.CipherType_AES_128_GCM,
+							Password:   "password-1",
+						}),
+						TrafficQuota: 1024 * 1024 * 1024,
+						ExpireTime:   1577836800,
+					},
+					{
+						Account: serial.ToTypedMessage(&shadowsocks.Account{
+							CipherType: shadowsocks.CipherType_CHACHA20_POLY1305,
+							Password:   "password-2",
+						}),
+					},
+				},
+				Network: []net.Network{net.Network_TCP, net.Network_UDP},
+			},
+		},
+		{
+			Input: `{
+				"method": "2022-blake3-aes-128-gcm",
+				"password": "AAECAwQFBgcICQoLDA0ODw=="
+			}`,
+			Parser: loadJSON(creator),
+			Output: &shadowsocks.ServerConfig{
+				User: &protocol.User{
+					Account: serial.ToTypedMessage(&shadowsocks.Account{
+						CipherType: shadowsocks.CipherType_BLAKE3_AES_128_GCM,
+						Password:   "AAECAwQFBgcICQoLDA0ODw==",
+					}),
+				},
+				Network: []net.Network{net.Network_TCP},
+			},
+		},
+	})
+}
+
+func TestShadowsocksPluginConfigParsing(t *testing.T) {
+	inbound := &InboundDetourConfig{}
+	common.Must(json.Unmarshal([]byte(`{
+		"protocol": "shadowsocks",
+		"listen": "127.0.0.1",
+		"port": 8000,
+		"settings": {
+			"method": "aes-128-gcm",
+			"password": "v2ray-password",
+			"plugin": "obfs-server",
+			"plugin_opts": "obfs=http",
+			"plugin_port": 443
+		}
+	}`), inbound))
+	config, err := inbound.Build()
+	common.Must(err)
+	serverConfig, err := config.ProxySettings.GetInstance()
+	common.Must(err)
+	if !proto.Equal(serverConfig, &shadowsocks.ServerConfig{
+		User: &protocol.User{
+			Account: serial.ToTypedMessage(&shadowsocks.Account{
+				CipherType: shadowsocks.CipherType_AES_128_GCM,
+				Password:   "v2ray-password",
+			}),
+		},
+		Network: []net.Network{net.Network_TCP},
+		Plugin: &shadowsocks.PluginConfig{
+			Command: "obfs-server",
+			Options: "obfs=http",
+		},
+		PluginPort: 443,
+	}) {
+		t.Error("unexpected server config: ", serverConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"servers": [{
+					"address": "127.0.0.1",
+					"port": 443,
+					"method": "aes-128-gcm",
+					"password": "v2ray-password"
+				}],
+				"plugin": "obfs-local",
+				"plugin_opts": "obfs=http;obfs-host=www.bing.com",
+				"plugin_args": ["-v"]
+			}`,
+			Parser: loadJSON(func() Buildable {
+				return new(ShadowsocksClientConfig)
+			}),
+			Output: &shadowsocks.ClientConfig{
+				Server: []*protocol.ServerEndpoint{
+					{
+						Address: net.NewIPOrDomain(net.LocalHostIP),
+						Port:    443,
+						User: []*protocol.User{
+							{
+								Account: serial.ToTypedMessage(&shadowsocks.Account{
+									CipherType: shadowsocks.CipherType_AES_128_GCM,
+									Password:   "v2ray-password",
+									Ota:        shadowsocks.Account_Disabled,
+								}),
+							},
+						},
+					},
+				},
+				Plugin: &shadowsocks.PluginConfig{
+					Command: "obfs-local",
+					Options: "obfs=http;obfs-host=www.bing.com",
+					Args:    []string{"-v"},
+				},
+			},
+		},
 	})
 }
@@ -0,0 +1,98 @@
+package conf
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/ssh"
+)
+
+type SSHAccount struct {
+	Username       string   `json:"user"`
+	Password       string   `json:"pass"`
+	PrivateKeyFile string   `json:"privateKeyFile"`
+	PrivateKey     []string `json:"privateKey"`
+}
+
+func (v *SSHAccount) Build() (*ssh.Account, error) {
+	account := &ssh.Account{
+		Username: v.Username,
+		Password: v.Password,
+	}
+	if len(v.PrivateKeyFile) > 0 || len(v.PrivateKey) > 0 {
+		key, err := readFileOrString(v.PrivateKeyFile, v.PrivateKey)
+		if err != nil {
+			return nil, newError("failed to read private key").Base(err)
+		}
+		account.PrivateKey = string(key)
+	}
+	if _, err := account.AsAccount(); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+type SSHRemoteConfig struct {
+	Address *Address          `json:"address"`
+	Port    uint16            `json:"port"`
+	Users   []json.RawMessage `json:"users"`
+}
+
+type SSHClientConfig struct {
+	Servers        []*SSHRemoteConfig `json:"servers"`
+	KnownHostsFile string             `json:"knownHostsFile"`
+	AllowInsecure  bool               `json:"allowInsecure"`
+	MaxConnections uint32             `json:"maxConnections"`
+}
+
+func (v *SSHClientConfig) Build() (proto.Message, error) {
+	if len(v.KnownHostsFile) == 0 && !v.AllowInsecure {
+		return nil, newError("SSH known hosts file is not specified")
+	}
+	config := &ssh.ClientConfig{
+		KnownHostsFile: v.KnownHostsFile,
+		AllowInsecure:  v.AllowInsecure,
+		MaxConnections: v.MaxConnections,
+	}
+	if len(v.Servers) == 0 {
+		return nil, newError("0 SSH server configured")
+	}
+	config.Server = make([]*protocol.ServerEndpoint, len(v.Servers))
+	for idx, serverConfig := range v.Servers {
+		if serverConfig.Address == nil {
+			return nil, newError("SSH server address is not set")
+		}
+		if len(serverConfig.Users) == 0 {
+			return nil, newError("0 user configured for SSH server")
+		}
+		port := serverConfig.Port
+		if port == 0 {
+			port = 22
+		}
+		server := &protocol.ServerEndpoint{
+			Address: serverConfig.Address.Build(),
+			Port:    uint32(port),
+		}
+		for _, rawUser := range serverConfig.Users {
+			user := new(protocol.User)
+			if err := json.Unmarshal(rawUser, user); err != nil {
+				return nil, newError("failed to parse SSH user").Base(err).AtError()
+			}
+			account := new(SSHAccount)
+			if err := json.Unmarshal(rawUser, account); err != nil {
+				return nil, newError("failed to parse SSH account").Base(err).AtError()
+			}
+			accountProto, err := account.Build()
+			if err != nil {
+				return nil, newError("invalid SSH account").Base(err).AtError()
+			}
+			user.Account = serial.ToTypedMessage(accountProto)
+			server.User = append(server.User, user)
+		}
+		config.Server[idx] = server
+	}
+	return config, nil
+}
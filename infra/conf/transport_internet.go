@@ -104,7 +104,8 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 }
 
 type TCPConfig struct {
-	HeaderConfig json.RawMessage `json:"header"`
+	HeaderConfig        json.RawMessage `json:"header"`
+	AcceptProxyProtocol bool            `json:"acceptProxyProtocol"`
 }
 
 // Build implements Buildable.
@@ -121,6 +122,7 @@ func (c *TCPConfig) Build() (proto.Message, error) {
 		}
 		config.HeaderSettings = serial.ToTypedMessage(ts)
 	}
+	config.AcceptProxyProtocol = c.AcceptProxyProtocol
 
 	return config, nil
 }
@@ -323,10 +325,24 @@ func (p TransportProtocol) Build() (string, error) {
 	}
 }
 
+type HappyEyeballsConfig struct {
+	PreferIPv4 bool   `json:"preferIPv4"`
+	TryDelay   uint32 `json:"tryDelayMs"`
+}
+
+// Build implements Buildable.
+func (c *HappyEyeballsConfig) Build() (*internet.HappyEyeballsConfig, error) {
+	return &internet.HappyEyeballsConfig{
+		PreferIpv4: c.PreferIPv4,
+		TryDelayMs: c.TryDelay,
+	}, nil
+}
+
 type SocketConfig struct {
-	Mark   int32  `json:"mark"`
-	TFO    *bool  `json:"tcpFastOpen"`
-	TProxy string `json:"tproxy"`
+	Mark          int32                `json:"mark"`
+	TFO           *bool                `json:"tcpFastOpen"`
+	TProxy        string               `json:"tproxy"`
+	HappyEyeballs *HappyEyeballsConfig `json:"happyEyeballs"`
 }
 
 func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
@@ -348,11 +364,19 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		tproxy = internet.SocketConfig_Off
 	}
 
-	return &internet.SocketConfig{
+	config := &internet.SocketConfig{
 		Mark:   c.Mark,
 		Tfo:    tfoSettings,
 		Tproxy: tproxy,
-	}, nil
+	}
+	if c.HappyEyeballs != nil {
+		he, err := c.HappyEyeballs.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.HappyEyeballs = he
+	}
+	return config, nil
 }
 
 type StreamConfig struct {
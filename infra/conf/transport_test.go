@@ -42,6 +42,21 @@ func TestSocketConfig(t *testing.T) {
 				Tfo:  internet.SocketConfig_Enable,
 			},
 		},
+		{
+			Input: `{
+				"happyEyeballs": {
+					"preferIPv4": true,
+					"tryDelayMs": 100
+				}
+			}`,
+			Parser: createParser(),
+			Output: &internet.SocketConfig{
+				HappyEyeballs: &internet.HappyEyeballsConfig{
+					PreferIpv4: true,
+					TryDelayMs: 100,
+				},
+			},
+		},
 	})
 }
 
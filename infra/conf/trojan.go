@@ -0,0 +1,104 @@
+package conf
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/trojan"
+)
+
+type TrojanUserConfig struct {
+	Password string `json:"password"`
+	Level    byte   `json:"level"`
+	Email    string `json:"email"`
+	UserQuota
+}
+
+// Build implements Buildable.
+func (c *TrojanUserConfig) Build() (*protocol.User, error) {
+	if c.Password == "" {
+		return nil, newError("Trojan password is not specified.")
+	}
+	user := &protocol.User{
+		Email: c.Email,
+		Level: uint32(c.Level),
+		Account: serial.ToTypedMessage(&trojan.Account{
+			Password: c.Password,
+		}),
+	}
+	if err := c.UserQuota.Apply(user); err != nil {
+		return nil, newError("invalid Trojan user").Base(err)
+	}
+	return user, nil
+}
+
+type TrojanServerConfig struct {
+	Users     []*TrojanUserConfig `json:"clients"`
+	Fallbacks []*FallbackConfig   `json:"fallbacks"`
+}
+
+// Build implements Buildable.
+func (c *TrojanServerConfig) Build() (proto.Message, error) {
+	config := new(trojan.ServerConfig)
+
+	for _, user := range c.Users {
+		u, err := user.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Users = append(config.Users, u)
+	}
+
+	fallbacks, err := buildFallbacks(c.Fallbacks)
+	if err != nil {
+		return nil, err
+	}
+	config.Fallbacks = fallbacks
+
+	return config, nil
+}
+
+type TrojanServerTarget struct {
+	Address  *Address `json:"address"`
+	Port     uint16   `json:"port"`
+	Password string   `json:"password"`
+	Email    string   `json:"email"`
+	Level    byte     `json:"level"`
+}
+
+type TrojanClientConfig struct {
+	Servers []*TrojanServerTarget `json:"servers"`
+}
+
+// Build implements Buildable.
+func (c *TrojanClientConfig) Build() (proto.Message, error) {
+	if len(c.Servers) == 0 {
+		return nil, newError("0 Trojan server configured.")
+	}
+
+	config := new(trojan.ClientConfig)
+	for _, server := range c.Servers {
+		if server.Address == nil {
+			return nil, newError("Trojan server address is not set.")
+		}
+		if server.Port == 0 {
+			return nil, newError("Invalid Trojan port.")
+		}
+		user, err := (&TrojanUserConfig{
+			Password: server.Password,
+			Level:    server.Level,
+			Email:    server.Email,
+		}).Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Server = append(config.Server, &protocol.ServerEndpoint{
+			Address: server.Address.Build(),
+			Port:    uint32(server.Port),
+			User:    []*protocol.User{user},
+		})
+	}
+
+	return config, nil
+}
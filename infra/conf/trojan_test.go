@@ -0,0 +1,109 @@
+package conf_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/fallback"
+	"v2ray.com/core/proxy/trojan"
+)
+
+func TestTrojanServerConfigParsing(t *testing.T) {
+	creator := func() Buildable {
+		return new(TrojanServerConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"clients": [
+					{
+						"password": "password-1",
+						"email": "love@v2ray.com",
+						"level": 1,
+						"trafficQuota": 1024,
+						"expireTime": "2020-01-01T00:00:00Z"
+					},
+					{
+						"password": "password-2"
+					}
+				],
+				"fallbacks": [
+					{
+						"dest": 80
+					}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &trojan.ServerConfig{
+				Users: []*protocol.User{
+					{
+						Email: "love@v2ray.com",
+						Level: 1,
+						Account: serial.ToTypedMessage(&trojan.Account{
+							Password: "password-1",
+						}),
+						TrafficQuota: 1024 * 1024 * 1024,
+						ExpireTime:   1577836800,
+					},
+					{
+						Account: serial.ToTypedMessage(&trojan.Account{
+							Password: "password-2",
+						}),
+					},
+				},
+				Fallbacks: []*fallback.Fallback{
+					{
+						Type: "tcp",
+						Dest: "127.0.0.1:80",
+					},
+				},
+			},
+		},
+	})
+
+	if _, err := loadJSON(creator)(`{"clients": [{"email": "love@v2ray.com"}]}`); err == nil {
+		t.Error("expected error for client without password")
+	}
+}
+
+func TestTrojanClientConfigParsing(t *testing.T) {
+	creator := func() Buildable {
+		return new(TrojanClientConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"servers": [
+					{
+						"address": "127.0.0.1",
+						"port": 443,
+						"password": "password",
+						"level": 1
+					}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &trojan.ClientConfig{
+				Server: []*protocol.ServerEndpoint{
+					{
+						Address: net.NewIPOrDomain(net.LocalHostIP),
+						Port:    443,
+						User: []*protocol.User{
+							{
+								Level: 1,
+								Account: serial.ToTypedMessage(&trojan.Account{
+									Password: "password",
+								}),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
@@ -10,7 +10,10 @@ import (
 	"v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
+	"v2ray.com/core/transport/internet"
 )
 
 var (
@@ -19,6 +22,8 @@ var (
 		"http":          func() interface{} { return new(HttpServerConfig) },
 		"shadowsocks":   func() interface{} { return new(ShadowsocksServerConfig) },
 		"socks":         func() interface{} { return new(SocksServerConfig) },
+		"trojan":        func() interface{} { return new(TrojanServerConfig) },
+		"vless":         func() interface{} { return new(VLessInboundConfig) },
 		"vmess":         func() interface{} { return new(VMessInboundConfig) },
 		"mtproto":       func() interface{} { return new(MTProtoServerConfig) },
 	}, "protocol", "settings")
@@ -27,7 +32,11 @@ var (
 		"blackhole":   func() interface{} { return new(BlackholeConfig) },
 		"freedom":     func() interface{} { return new(FreedomConfig) },
 		"http":        func() interface{} { return new(HttpClientConfig) },
+		"loopback":    func() interface{} { return new(LoopbackConfig) },
 		"shadowsocks": func() interface{} { return new(ShadowsocksClientConfig) },
+		"ssh":         func() interface{} { return new(SSHClientConfig) },
+		"trojan":      func() interface{} { return new(TrojanClientConfig) },
+		"vless":       func() interface{} { return new(VLessOutboundConfig) },
 		"vmess":       func() interface{} { return new(VMessOutboundConfig) },
 		"socks":       func() interface{} { return new(SocksClientConfig) },
 		"mtproto":     func() interface{} { return new(MTProtoClientConfig) },
@@ -79,8 +88,13 @@ func (c *SniffingConfig) Build() (*proxyman.SniffingConfig, error) {
 }
 
 type MuxConfig struct {
-	Enabled     bool  `json:"enabled"`
-	Concurrency int16 `json:"concurrency"`
+	Enabled           bool         `json:"enabled"`
+	Concurrency       int16        `json:"concurrency"`
+	MaxConnections    uint32       `json:"maxConnections"`
+	MaxStreams        uint32       `json:"maxStreams"`
+	IdleTimeout       uint32       `json:"idleTimeout"`
+	KeepAliveInterval uint32       `json:"keepAliveInterval"`
+	Network           *NetworkList `json:"network"`
 }
 
 // Build creates MultiplexingConfig, Concurrency < 0 completely disables mux.
@@ -94,9 +108,32 @@ func (m *MuxConfig) Build() *proxyman.MultiplexingConfig {
 		con = uint32(m.Concurrency)
 	}
 
-	return &proxyman.MultiplexingConfig{
-		Enabled:     m.Enabled,
-		Concurrency: con,
+	config := &proxyman.MultiplexingConfig{
+		Enabled:           m.Enabled,
+		Concurrency:       con,
+		MaxConnections:    m.MaxConnections,
+		MaxStreams:        m.MaxStreams,
+		IdleTimeout:       m.IdleTimeout,
+		KeepAliveInterval: m.KeepAliveInterval,
+	}
+	if m.Network != nil {
+		config.Networks = m.Network.Build()
+	}
+	return config
+}
+
+type ConnectionPoolConfig struct {
+	Size        uint32 `json:"size"`
+	MaxIdleTime uint32 `json:"maxIdleTime"`
+	IdleTimeout uint32 `json:"idleTimeout"`
+}
+
+// Build implements Buildable.
+func (c *ConnectionPoolConfig) Build() *proxyman.ConnectionPoolConfig {
+	return &proxyman.ConnectionPoolConfig{
+		Size:        c.Size,
+		MaxIdleTime: c.MaxIdleTime,
+		IdleTimeout: c.IdleTimeout,
 	}
 }
 
@@ -136,8 +173,8 @@ func (c *InboundDetourAllocationConfig) Build() (*proxyman.AllocationStrategy, e
 
 type InboundDetourConfig struct {
 	Protocol       string                         `json:"protocol"`
-	PortRange      *PortRange                     `json:"port"`
-	ListenOn       *Address                       `json:"listen"`
+	PortList       *PortList                      `json:"port"`
+	ListenOn       *AddressList                   `json:"listen"`
 	Settings       *json.RawMessage               `json:"settings"`
 	Tag            string                         `json:"tag"`
 	Allocation     *InboundDetourAllocationConfig `json:"allocate"`
@@ -150,31 +187,56 @@ type InboundDetourConfig struct {
 func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 	receiverSettings := &proxyman.ReceiverConfig{}
 
-	if c.PortRange == nil {
-		return nil, newError("port range not specified in InboundDetour.")
+	unixOnly := c.ListenOn != nil && len(*c.ListenOn) > 0
+	if c.ListenOn != nil {
+		for _, address := range *c.ListenOn {
+			if !internet.IsUnixSocketAddress(address.Address) {
+				unixOnly = false
+				if address.Family().IsDomain() {
+					return nil, newError("unable to listen on domain address: ", address.Domain())
+				}
+			}
+			if receiverSettings.Listen == nil {
+				receiverSettings.Listen = address.Build()
+			} else {
+				receiverSettings.ListenList = append(receiverSettings.ListenList, address.Build())
+			}
+		}
 	}
-	receiverSettings.PortRange = c.PortRange.Build()
 
-	if c.ListenOn != nil {
-		if c.ListenOn.Family().IsDomain() {
-			return nil, newError("unable to listen on domain address: ", c.ListenOn.Domain())
+	if c.PortList == nil || len(c.PortList.Range) == 0 {
+		if !unixOnly {
+			return nil, newError("port range not specified in InboundDetour.")
+		}
+	} else {
+		receiverSettings.PortRange = c.PortList.Range[0].Build()
+		if len(c.PortList.Range) > 1 {
+			receiverSettings.PortList = (&PortList{Range: c.PortList.Range[1:]}).Build()
 		}
-		receiverSettings.Listen = c.ListenOn.Build()
 	}
+
 	if c.Allocation != nil {
+		as, err := c.Allocation.Build()
+		if err != nil {
+			return nil, err
+		}
+		if as.Type != proxyman.AllocationStrategy_Always {
+			if receiverSettings.PortRange == nil || receiverSettings.PortList != nil || len(receiverSettings.ListenList) > 0 {
+				return nil, newError("allocation strategy ", c.Allocation.Strategy, " requires a single port range and a single listen address")
+			}
+		}
+
 		concurrency := -1
 		if c.Allocation.Concurrency != nil && c.Allocation.Strategy == "random" {
 			concurrency = int(*c.Allocation.Concurrency)
 		}
-		portRange := int(c.PortRange.To - c.PortRange.From + 1)
-		if concurrency >= 0 && concurrency >= portRange {
-			return nil, newError("not enough ports. concurrency = ", concurrency, " ports: ", c.PortRange.From, " - ", c.PortRange.To)
+		if pr := receiverSettings.PortRange; pr != nil {
+			portRange := int(pr.To - pr.From + 1)
+			if concurrency >= 0 && concurrency >= portRange {
+				return nil, newError("not enough ports. concurrency = ", concurrency, " ports: ", pr.From, " - ", pr.To)
+			}
 		}
 
-		as, err := c.Allocation.Build()
-		if err != nil {
-			return nil, err
-		}
 		receiverSettings.AllocationStrategy = as
 	}
 	if c.StreamSetting != nil {
@@ -210,6 +272,12 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 	if dokodemoConfig, ok := rawConfig.(*DokodemoConfig); ok {
 		receiverSettings.ReceiveOriginalDestination = dokodemoConfig.Redirect
 	}
+	if ssConfig, ok := rawConfig.(*ShadowsocksServerConfig); ok && ssConfig.Plugin != "" {
+		pr := receiverSettings.PortRange
+		if pr == nil || pr.From != pr.To || receiverSettings.PortList != nil || len(receiverSettings.ListenList) > 0 {
+			return nil, newError("Shadowsocks plugin requires a single port and a single listen address")
+		}
+	}
 	ts, err := rawConfig.(Buildable).Build()
 	if err != nil {
 		return nil, err
@@ -223,13 +291,40 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 }
 
 type OutboundDetourConfig struct {
-	Protocol      string           `json:"protocol"`
-	SendThrough   *Address         `json:"sendThrough"`
-	Tag           string           `json:"tag"`
-	Settings      *json.RawMessage `json:"settings"`
-	StreamSetting *StreamConfig    `json:"streamSettings"`
-	ProxySettings *ProxyConfig     `json:"proxySettings"`
-	MuxSettings   *MuxConfig       `json:"mux"`
+	Protocol        string                `json:"protocol"`
+	SendThrough     *string               `json:"sendThrough"`
+	SendThroughMode string                `json:"sendThroughMode"`
+	Tag             string                `json:"tag"`
+	Settings        *json.RawMessage      `json:"settings"`
+	StreamSetting   *StreamConfig         `json:"streamSettings"`
+	ProxySettings   *ProxyConfig          `json:"proxySettings"`
+	MuxSettings     *MuxConfig            `json:"mux"`
+	ConnectionPool  *ConnectionPoolConfig `json:"connectionPool"`
+}
+
+func parseViaPrefix(cidr string, mode string) (*proxyman.ViaPrefix, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, newError("invalid prefix to send through: ", cidr).Base(err)
+	}
+	ip := ipNet.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	ones, _ := ipNet.Mask.Size()
+	prefix := &proxyman.ViaPrefix{
+		Ip:     []byte(ip),
+		Prefix: uint32(ones),
+	}
+	switch strings.ToLower(mode) {
+	case "", "random":
+		prefix.Mode = proxyman.ViaPrefix_Random
+	case "user":
+		prefix.Mode = proxyman.ViaPrefix_User
+	default:
+		return nil, newError("unknown send through mode: ", mode)
+	}
+	return prefix, nil
 }
 
 // Build implements Buildable.
@@ -237,11 +332,19 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 	senderSettings := &proxyman.SenderConfig{}
 
 	if c.SendThrough != nil {
-		address := c.SendThrough
-		if address.Family().IsDomain() {
-			return nil, newError("unable to send through: " + address.String())
+		if strings.Contains(*c.SendThrough, "/") {
+			prefix, err := parseViaPrefix(*c.SendThrough, c.SendThroughMode)
+			if err != nil {
+				return nil, err
+			}
+			senderSettings.ViaPrefix = prefix
+		} else {
+			address := net.ParseAddress(*c.SendThrough)
+			if address.Family().IsDomain() {
+				return nil, newError("unable to send through: " + address.String())
+			}
+			senderSettings.Via = net.NewIPOrDomain(address)
 		}
-		senderSettings.Via = address.Build()
 	}
 
 	if c.StreamSetting != nil {
@@ -264,6 +367,10 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 		senderSettings.MultiplexSettings = c.MuxSettings.Build()
 	}
 
+	if c.ConnectionPool != nil {
+		senderSettings.ConnectionPool = c.ConnectionPool.Build()
+	}
+
 	settings := []byte("{}")
 	if c.Settings != nil {
 		settings = ([]byte)(*c.Settings)
@@ -497,10 +604,12 @@ func (c *Config) Build() (*core.Config, error) {
 	}
 
 	// Backward compatibility.
-	if len(inbounds) > 0 && inbounds[0].PortRange == nil && c.Port > 0 {
-		inbounds[0].PortRange = &PortRange{
-			From: uint32(c.Port),
-			To:   uint32(c.Port),
+	if len(inbounds) > 0 && inbounds[0].PortList == nil && c.Port > 0 {
+		inbounds[0].PortList = &PortList{
+			Range: []PortRange{{
+				From: uint32(c.Port),
+				To:   uint32(c.Port),
+			}},
 		}
 	}
 
@@ -515,6 +624,9 @@ func (c *Config) Build() (*core.Config, error) {
 		if err != nil {
 			return nil, err
 		}
+		if c.Stats == nil && hasTrafficQuota(ic) {
+			return nil, newError("traffic quota of users in inbound ", ic.Tag, " requires stats")
+		}
 		config.Inbound = append(config.Inbound, ic)
 	}
 
@@ -548,3 +660,30 @@ func (c *Config) Build() (*core.Config, error) {
 
 	return config, nil
 }
+
+// hasTrafficQuota returns whether any user of the inbound has a traffic quota, which is measured by stats counters.
+func hasTrafficQuota(config *core.InboundHandlerConfig) bool {
+	settings, err := config.ProxySettings.GetInstance()
+	if err != nil {
+		return false
+	}
+	var users []*protocol.User
+	if s, ok := settings.(interface{ GetUser() []*protocol.User }); ok {
+		users = append(users, s.GetUser()...)
+	}
+	if s, ok := settings.(interface{ GetUser() *protocol.User }); ok && s.GetUser() != nil {
+		users = append(users, s.GetUser())
+	}
+	if s, ok := settings.(interface{ GetUsers() []*protocol.User }); ok {
+		users = append(users, s.GetUsers()...)
+	}
+	if s, ok := settings.(interface{ GetClients() []*protocol.User }); ok {
+		users = append(users, s.GetClients()...)
+	}
+	for _, user := range users {
+		if user.TrafficQuota > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package conf_test
 
 import (
 	"encoding/json"
+	"os"
 	"reflect"
 	"testing"
 
@@ -359,6 +360,15 @@ func TestMuxConfig_Build(t *testing.T) {
 			Concurrency: 4,
 		}},
 		{"forbidden", `{"enabled": false, "concurrency": -1}`, nil},
+		{"limits", `{"enabled": true, "maxConnections": 2, "maxStreams": 64, "idleTimeout": 30, "keepAliveInterval": 10, "network": "tcp"}`, &proxyman.MultiplexingConfig{
+			Enabled:           true,
+			Concurrency:       8,
+			MaxConnections:    2,
+			MaxStreams:        64,
+			IdleTimeout:       30,
+			KeepAliveInterval: 10,
+			Networks:          []net.Network{net.Network_TCP},
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -371,6 +381,116 @@ func TestMuxConfig_Build(t *testing.T) {
 	}
 }
 
+func TestInboundDetourConfig_Build(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   *proxyman.ReceiverConfig
+	}{
+		{"listen list", `{"protocol": "dokodemo-door", "listen": ["127.0.0.1", "::1"], "port": "443,8000-8010"}`, &proxyman.ReceiverConfig{
+			Listen: net.NewIPOrDomain(net.LocalHostIP),
+			ListenList: []*net.IPOrDomain{
+				net.NewIPOrDomain(net.LocalHostIPv6),
+			},
+			PortRange: net.SinglePortRange(443),
+			PortList: &net.PortList{
+				Range: []*net.PortRange{{From: 8000, To: 8010}},
+			},
+		}},
+		{"unix socket", `{"protocol": "dokodemo-door", "listen": ["/tmp/v2ray.sock", "@v2ray"]}`, &proxyman.ReceiverConfig{
+			Listen: net.NewIPOrDomain(net.DomainAddress("/tmp/v2ray.sock")),
+			ListenList: []*net.IPOrDomain{
+				net.NewIPOrDomain(net.DomainAddress("@v2ray")),
+			},
+		}},
+		{"env port", `{"protocol": "dokodemo-door", "listen": "127.0.0.1", "port": "env:V2RAY_TEST_PORT"}`, &proxyman.ReceiverConfig{
+			Listen:    net.NewIPOrDomain(net.LocalHostIP),
+			PortRange: net.SinglePortRange(8443),
+		}},
+		{"reversed range", `{"protocol": "dokodemo-door", "port": "8010-8000"}`, nil},
+		{"domain", `{"protocol": "dokodemo-door", "listen": "example.com", "port": 443}`, nil},
+		{"no port", `{"protocol": "dokodemo-door", "listen": ["/tmp/v2ray.sock", "127.0.0.1"]}`, nil},
+		{"random with list", `{"protocol": "dokodemo-door", "port": "443,444", "allocate": {"strategy": "random"}}`, nil},
+		{"shadowsocks plugin with port list", `{"protocol": "shadowsocks", "port": "443,444", "settings": {"method": "aes-128-gcm", "password": "password", "plugin": "obfs-server", "plugin_port": 8388}}`, nil},
+	}
+	common.Must(os.Setenv("V2RAY_TEST_PORT", "8443"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &InboundDetourConfig{}
+			err := json.Unmarshal([]byte(tt.fields), c)
+			var config *core.InboundHandlerConfig
+			if err == nil {
+				config, err = c.Build()
+			}
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("expected error, but actually nil")
+				}
+				return
+			}
+			common.Must(err)
+			got, err := config.ReceiverSettings.GetInstance()
+			common.Must(err)
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("InboundDetourConfig.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboundDetourConfig_SendThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   *proxyman.SenderConfig
+	}{
+		{"address", `{"protocol": "freedom", "sendThrough": "192.0.2.1"}`, &proxyman.SenderConfig{
+			Via: net.NewIPOrDomain(net.IPAddress([]byte{192, 0, 2, 1})),
+		}},
+		{"prefix", `{"protocol": "freedom", "sendThrough": "2001:db8::/64", "sendThroughMode": "user"}`, &proxyman.SenderConfig{
+			ViaPrefix: &proxyman.ViaPrefix{
+				Ip:     net.ParseIP("2001:db8::"),
+				Prefix: 64,
+				Mode:   proxyman.ViaPrefix_User,
+			},
+		}},
+		{"ipv4 prefix", `{"protocol": "freedom", "sendThrough": "192.0.2.1/24"}`, &proxyman.SenderConfig{
+			ViaPrefix: &proxyman.ViaPrefix{
+				Ip:     []byte{192, 0, 2, 0},
+				Prefix: 24,
+			},
+		}},
+		{"connection pool", `{"protocol": "socks", "settings": {"servers": [{"address": "127.0.0.1", "port": 1080}]}, "connectionPool": {"size": 4, "maxIdleTime": 10, "idleTimeout": 120}}`, &proxyman.SenderConfig{
+			ConnectionPool: &proxyman.ConnectionPoolConfig{
+				Size:        4,
+				MaxIdleTime: 10,
+				IdleTimeout: 120,
+			},
+		}},
+		{"domain", `{"protocol": "freedom", "sendThrough": "example.com"}`, nil},
+		{"mode", `{"protocol": "freedom", "sendThrough": "2001:db8::/64", "sendThroughMode": "unknown"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &OutboundDetourConfig{}
+			common.Must(json.Unmarshal([]byte(tt.fields), c))
+			config, err := c.Build()
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("expected error, but actually nil")
+				}
+				return
+			}
+			common.Must(err)
+			got, err := config.SenderSettings.GetInstance()
+			common.Must(err)
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("OutboundDetourConfig.Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_Override(t *testing.T) {
 	tests := []struct {
 		name string
@@ -447,3 +567,65 @@ func TestConfig_Override(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_TrafficQuotaRequiresStats(t *testing.T) {
+	inbounds := []string{
+		`"inbounds": [{
+			"port": 443,
+			"protocol": "vmess",
+			"settings": {
+				"clients": [{
+					"id": "0cdf8a45-303d-4fed-9780-29aa7f54175e",
+					"email": "love@v2ray.com",
+					"trafficQuota": 1024
+				}]
+			}
+		}]`,
+		`"inbounds": [{
+			"port": 443,
+			"protocol": "shadowsocks",
+			"settings": {
+				"method": "aes-128-gcm",
+				"password": "password",
+				"email": "love@v2ray.com",
+				"trafficQuota": 1024
+			}
+		}]`,
+		`"inbounds": [{
+			"port": 443,
+			"protocol": "trojan",
+			"settings": {
+				"clients": [{
+					"password": "password",
+					"email": "love@v2ray.com",
+					"trafficQuota": 1024
+				}]
+			}
+		}]`,
+		`"inbounds": [{
+			"port": 443,
+			"protocol": "vless",
+			"settings": {
+				"clients": [{
+					"id": "0cdf8a45-303d-4fed-9780-29aa7f54175e",
+					"email": "love@v2ray.com",
+					"trafficQuota": 1024
+				}]
+			}
+		}]`,
+	}
+
+	for _, inbound := range inbounds {
+		config := new(Config)
+		common.Must(json.Unmarshal([]byte(`{`+inbound+`}`), config))
+		if _, err := config.Build(); err == nil {
+			t.Error("expected error for traffic quota without stats, but actually nil: ", inbound)
+		}
+
+		config = new(Config)
+		common.Must(json.Unmarshal([]byte(`{"stats": {}, `+inbound+`}`), config))
+		if _, err := config.Build(); err != nil {
+			t.Error("unexpected error: ", err)
+		}
+	}
+}
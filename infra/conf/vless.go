@@ -0,0 +1,118 @@
+package conf
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/uuid"
+	"v2ray.com/core/proxy/vless"
+	"v2ray.com/core/proxy/vless/inbound"
+	"v2ray.com/core/proxy/vless/outbound"
+)
+
+type VLessAccount struct {
+	ID string `json:"id"`
+}
+
+// Build implements Buildable
+func (a *VLessAccount) Build() (*vless.Account, error) {
+	if _, err := uuid.ParseString(a.ID); err != nil {
+		return nil, newError("invalid VLESS ID: ", a.ID).Base(err)
+	}
+	return &vless.Account{
+		Id: a.ID,
+	}, nil
+}
+
+func buildVLessUser(rawData json.RawMessage) (*protocol.User, error) {
+	user := new(protocol.User)
+	if err := json.Unmarshal(rawData, user); err != nil {
+		return nil, newError("invalid VLESS user").Base(err)
+	}
+	account := new(VLessAccount)
+	if err := json.Unmarshal(rawData, account); err != nil {
+		return nil, newError("invalid VLESS user").Base(err)
+	}
+	a, err := account.Build()
+	if err != nil {
+		return nil, err
+	}
+	user.Account = serial.ToTypedMessage(a)
+	return user, nil
+}
+
+type VLessInboundConfig struct {
+	Users     []json.RawMessage `json:"clients"`
+	Fallbacks []*FallbackConfig `json:"fallbacks"`
+}
+
+// Build implements Buildable
+func (c *VLessInboundConfig) Build() (proto.Message, error) {
+	config := new(inbound.Config)
+
+	for _, rawData := range c.Users {
+		user, err := buildVLessUser(rawData)
+		if err != nil {
+			return nil, err
+		}
+		quota := new(UserQuota)
+		if err := json.Unmarshal(rawData, quota); err != nil {
+			return nil, newError("invalid VLESS user").Base(err)
+		}
+		if err := quota.Apply(user); err != nil {
+			return nil, newError("invalid VLESS user").Base(err)
+		}
+		config.Clients = append(config.Clients, user)
+	}
+
+	fallbacks, err := buildFallbacks(c.Fallbacks)
+	if err != nil {
+		return nil, err
+	}
+	config.Fallbacks = fallbacks
+
+	return config, nil
+}
+
+type VLessOutboundTarget struct {
+	Address *Address          `json:"address"`
+	Port    uint16            `json:"port"`
+	Users   []json.RawMessage `json:"users"`
+}
+
+type VLessOutboundConfig struct {
+	Receivers []*VLessOutboundTarget `json:"vnext"`
+}
+
+// Build implements Buildable
+func (c *VLessOutboundConfig) Build() (proto.Message, error) {
+	config := new(outbound.Config)
+
+	if len(c.Receivers) == 0 {
+		return nil, newError("0 VLESS receiver configured")
+	}
+	for _, rec := range c.Receivers {
+		if len(rec.Users) == 0 {
+			return nil, newError("0 user configured for VLESS outbound")
+		}
+		if rec.Address == nil {
+			return nil, newError("address is not set in VLESS outbound config")
+		}
+		spec := &protocol.ServerEndpoint{
+			Address: rec.Address.Build(),
+			Port:    uint32(rec.Port),
+		}
+		for _, rawUser := range rec.Users {
+			user, err := buildVLessUser(rawUser)
+			if err != nil {
+				return nil, err
+			}
+			spec.User = append(spec.User, user)
+		}
+		config.Vnext = append(config.Vnext, spec)
+	}
+	return config, nil
+}
@@ -0,0 +1,105 @@
+package conf_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/fallback"
+	"v2ray.com/core/proxy/vless"
+	"v2ray.com/core/proxy/vless/inbound"
+	"v2ray.com/core/proxy/vless/outbound"
+)
+
+func TestVLessInboundConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(VLessInboundConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"clients": [
+					{
+						"id": "27848739-7e62-4138-9fd3-098a63964b6b",
+						"level": 1,
+						"email": "love@v2ray.com",
+						"trafficQuota": 1024,
+						"expireTime": "2020-01-01T00:00:00Z"
+					}
+				],
+				"fallbacks": [
+					{
+						"dest": 80
+					}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &inbound.Config{
+				Clients: []*protocol.User{
+					{
+						Level: 1,
+						Email: "love@v2ray.com",
+						Account: serial.ToTypedMessage(&vless.Account{
+							Id: "27848739-7e62-4138-9fd3-098a63964b6b",
+						}),
+						TrafficQuota: 1024 * 1024 * 1024,
+						ExpireTime:   1577836800,
+					},
+				},
+				Fallbacks: []*fallback.Fallback{
+					{
+						Type: "tcp",
+						Dest: "127.0.0.1:80",
+					},
+				},
+			},
+		},
+	})
+
+	if _, err := loadJSON(creator)(`{"clients": [{"id": "not-a-uuid"}]}`); err == nil {
+		t.Error("expected error for invalid ID")
+	}
+}
+
+func TestVLessOutboundConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(VLessOutboundConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"vnext": [{
+					"address": "127.0.0.1",
+					"port": 443,
+					"users": [
+						{
+							"id": "27848739-7e62-4138-9fd3-098a63964b6b",
+							"level": 1
+						}
+					]
+				}]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &outbound.Config{
+				Vnext: []*protocol.ServerEndpoint{
+					{
+						Address: net.NewIPOrDomain(net.LocalHostIP),
+						Port:    443,
+						User: []*protocol.User{
+							{
+								Level: 1,
+								Account: serial.ToTypedMessage(&vless.Account{
+									Id: "27848739-7e62-4138-9fd3-098a63964b6b",
+								}),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
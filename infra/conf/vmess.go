@@ -14,9 +14,10 @@ import (
 )
 
 type VMessAccount struct {
-	ID       string `json:"id"`
-	AlterIds uint16 `json:"alterId"`
-	Security string `json:"security"`
+	ID         string `json:"id"`
+	AlterIds   uint16 `json:"alterId"`
+	Security   string `json:"security"`
+	AEADHeader bool   `json:"aeadHeader"`
 }
 
 // Build implements Buildable
@@ -40,6 +41,7 @@ func (a *VMessAccount) Build() *vmess.Account {
 		SecuritySettings: &protocol.SecurityConfig{
 			Type: st,
 		},
+		AeadHeader: a.AEADHeader,
 	}
 }
 
@@ -80,18 +82,27 @@ type VMessInboundConfig struct {
 	Defaults     *VMessDefaultConfig `json:"default"`
 	DetourConfig *VMessDetourConfig  `json:"detour"`
 	SecureOnly   bool                `json:"disableInsecureEncryption"`
+	AEADOnly     bool                `json:"disableLegacyHeader"`
+	Fallbacks    []*FallbackConfig   `json:"fallbacks"`
 }
 
 // Build implements Buildable
 func (c *VMessInboundConfig) Build() (proto.Message, error) {
 	config := &inbound.Config{
 		SecureEncryptionOnly: c.SecureOnly,
+		DisableLegacyHeader:  c.AEADOnly,
 	}
 
 	if c.Defaults != nil {
 		config.Default = c.Defaults.Build()
 	}
 
+	fallbacks, err := buildFallbacks(c.Fallbacks)
+	if err != nil {
+		return nil, err
+	}
+	config.Fallbacks = fallbacks
+
 	if c.DetourConfig != nil {
 		config.Detour = c.DetourConfig.Build()
 	} else if c.Features != nil && c.Features.Detour != nil {
@@ -109,6 +120,13 @@ func (c *VMessInboundConfig) Build() (proto.Message, error) {
 			return nil, newError("invalid VMess user").Base(err)
 		}
 		user.Account = serial.ToTypedMessage(account.Build())
+		quota := new(UserQuota)
+		if err := json.Unmarshal(rawData, quota); err != nil {
+			return nil, newError("invalid VMess user").Base(err)
+		}
+		if err := quota.Apply(user); err != nil {
+			return nil, newError("invalid VMess user").Base(err)
+		}
 		config.User[idx] = user
 	}
 
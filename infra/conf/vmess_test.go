@@ -7,6 +7,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	. "v2ray.com/core/infra/conf"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/proxy/vmess"
 	"v2ray.com/core/proxy/vmess/inbound"
 	"v2ray.com/core/proxy/vmess/outbound"
@@ -27,7 +28,8 @@ func TestVMessOutbound(t *testing.T) {
 						{
 							"id": "e641f5ad-9397-41e3-bf1a-e8740dfed019",
 							"email": "love@v2ray.com",
-							"level": 255
+							"level": 255,
+							"aeadHeader": true
 						}
 					]
 				}]
@@ -52,6 +54,7 @@ func TestVMessOutbound(t *testing.T) {
 									SecuritySettings: &protocol.SecurityConfig{
 										Type: protocol.SecurityType_AUTO,
 									},
+									AeadHeader: true,
 								}),
 							},
 						},
@@ -76,7 +79,9 @@ func TestVMessInbound(t *testing.T) {
 						"level": 0,
 						"alterId": 16,
 						"email": "love@v2ray.com",
-						"security": "aes-128-gcm"
+						"security": "aes-128-gcm",
+						"trafficQuota": 1024,
+						"expireTime": "2020-01-01T00:00:00Z"
 					}
 				],
 				"default": {
@@ -86,7 +91,22 @@ func TestVMessInbound(t *testing.T) {
 				"detour": {
 					"to": "tag_to_detour"
 				},
-				"disableInsecureEncryption": true
+				"disableInsecureEncryption": true,
+				"disableLegacyHeader": true,
+				"fallbacks": [
+					{
+						"alpn": "h2",
+						"dest": "/dev/shm/h2.sock",
+						"xver": 2
+					},
+					{
+						"path": "/ws",
+						"dest": 8080
+					},
+					{
+						"dest": "example.com:80"
+					}
+				]
 			}`,
 			Parser: loadJSON(creator),
 			Output: &inbound.Config{
@@ -101,6 +121,8 @@ func TestVMessInbound(t *testing.T) {
 								Type: protocol.SecurityType_AES128_GCM,
 							},
 						}),
+						TrafficQuota: 1024 * 1024 * 1024,
+						ExpireTime:   1577836800,
 					},
 				},
 				Default: &inbound.DefaultConfig{
@@ -111,6 +133,24 @@ func TestVMessInbound(t *testing.T) {
 					To: "tag_to_detour",
 				},
 				SecureEncryptionOnly: true,
+				DisableLegacyHeader:  true,
+				Fallbacks: []*fallback.Fallback{
+					{
+						Alpn: "h2",
+						Type: "unix",
+						Dest: "/dev/shm/h2.sock",
+						Xver: 2,
+					},
+					{
+						Path: "/ws",
+						Type: "tcp",
+						Dest: "127.0.0.1:8080",
+					},
+					{
+						Type: "tcp",
+						Dest: "example.com:80",
+					},
+				},
 			},
 		},
 	})
@@ -0,0 +1,131 @@
+package fallback
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Fallback is a destination for inbound connections that fail authentication.
+// The first fallback that matches all of its non-empty conditions is used.
+type Fallback struct {
+	// ALPN negotiated by the TLS layer of the inbound.
+	Alpn string `protobuf:"bytes,1,opt,name=alpn,proto3" json:"alpn,omitempty"`
+	// Server name (SNI) sent by the client in TLS handshake.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Prefix of the path in HTTP request line.
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// Network of the destination, either "tcp" or "unix". Default to "tcp".
+	Type string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// Address of the destination. "host:port" for TCP, or the path of the
+	// socket for Unix domain sockets. A leading "@" denotes an abstract socket.
+	Dest string `protobuf:"bytes,5,opt,name=dest,proto3" json:"dest,omitempty"`
+	// Version of PROXY protocol header to send to the destination. 0 for none.
+	Xver                 uint32   `protobuf:"varint,6,opt,name=xver,proto3" json:"xver,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Fallback) Reset()         { *m = Fallback{} }
+func (m *Fallback) String() string { return proto.CompactTextString(m) }
+func (*Fallback) ProtoMessage()    {}
+func (*Fallback) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f605f2dae04fa234, []int{0}
+}
+
+func (m *Fallback) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Fallback.Unmarshal(m, b)
+}
+func (m *Fallback) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Fallback.Marshal(b, m, deterministic)
+}
+func (m *Fallback) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Fallback.Merge(m, src)
+}
+func (m *Fallback) XXX_Size() int {
+	return xxx_messageInfo_Fallback.Size(m)
+}
+func (m *Fallback) XXX_DiscardUnknown() {
+	xxx_messageInfo_Fallback.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Fallback proto.InternalMessageInfo
+
+func (m *Fallback) GetAlpn() string {
+	if m != nil {
+		return m.Alpn
+	}
+	return ""
+}
+
+func (m *Fallback) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Fallback) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Fallback) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Fallback) GetDest() string {
+	if m != nil {
+		return m.Dest
+	}
+	return ""
+}
+
+func (m *Fallback) GetXver() uint32 {
+	if m != nil {
+		return m.Xver
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Fallback)(nil), "v2ray.core.proxy.fallback.Fallback")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/fallback/config.proto", fileDescriptor_f605f2dae04fa234)
+}
+
+var fileDescriptor_f605f2dae04fa234 = []byte{
+	// 201 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2a, 0x33, 0x2a, 0x4a,
+	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x28, 0xca, 0xaf, 0xa8,
+	0xd4, 0x4f, 0x4b, 0xcc, 0xc9, 0x49, 0x4a, 0x4c, 0xce, 0xd6, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c,
+	0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0x84, 0xa9, 0x2d, 0x4a, 0xd5, 0x03, 0xab, 0xd3,
+	0x83, 0xa9, 0x53, 0x6a, 0x62, 0xe4, 0xe2, 0x70, 0x83, 0x72, 0x84, 0x84, 0xb8, 0x58, 0x12, 0x73,
+	0x0a, 0xf2, 0x24, 0x18, 0x15, 0x18, 0x35, 0x38, 0x83, 0xc0, 0x6c, 0x90, 0x58, 0x5e, 0x62, 0x6e,
+	0xaa, 0x04, 0x13, 0x44, 0x0c, 0xc4, 0x06, 0x89, 0x15, 0x24, 0x96, 0x64, 0x48, 0x30, 0x43, 0xc4,
+	0x40, 0x6c, 0x90, 0x58, 0x49, 0x65, 0x41, 0xaa, 0x04, 0x0b, 0x44, 0x0c, 0xc4, 0x06, 0x89, 0xa5,
+	0xa4, 0x16, 0x97, 0x48, 0xb0, 0x42, 0xc4, 0x40, 0x6c, 0x90, 0x58, 0x45, 0x59, 0x6a, 0x91, 0x04,
+	0x9b, 0x02, 0xa3, 0x06, 0x6f, 0x10, 0x98, 0xed, 0xe4, 0xce, 0x25, 0x9b, 0x9c, 0x9f, 0xab, 0x87,
+	0xd3, 0x95, 0x01, 0x8c, 0x51, 0x1c, 0x30, 0xf6, 0x2a, 0x26, 0xc9, 0x30, 0xa3, 0xa0, 0xc4, 0x4a,
+	0x3d, 0x67, 0x90, 0xba, 0x00, 0xb0, 0x3a, 0x98, 0x07, 0x92, 0xd8, 0xc0, 0xfe, 0x35, 0x06, 0x0c,
+	0x00, 0x72, 0x83, 0xcc, 0x69, 0x1d, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,227 @@
+// +build !confonly
+
+// Package fallback forwards inbound connections that fail authentication to other servers, such as a web server,
+// so that the inbound is indistinguishable from those servers for unauthenticated clients.
+package fallback
+
+//go:generate errorgen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport/internet"
+)
+
+// maxRequestLineLength is the maximum number of bytes to read for finding the HTTP request path.
+const maxRequestLineLength = 4096
+
+// Recorder is a buf.Reader that keeps a copy of all data read through it, until Stop is called.
+type Recorder struct {
+	// Reader is the underlying reader, usually reading from the connection directly.
+	Reader buf.Reader
+
+	recorded buf.MultiBuffer
+	stopped  bool
+}
+
+// NewRecorder creates a new Recorder.
+func NewRecorder(reader buf.Reader) *Recorder {
+	return &Recorder{
+		Reader: reader,
+	}
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *Recorder) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	if !r.stopped {
+		for _, b := range mb {
+			c := buf.New()
+			c.Write(b.Bytes()) // nolint: errcheck
+			r.recorded = append(r.recorded, c)
+		}
+	}
+	return mb, err
+}
+
+// Stop stops recording, and releases all recorded data. It should be called once the connection is authenticated.
+func (r *Recorder) Stop() {
+	r.stopped = true
+	buf.ReleaseMulti(r.recorded)
+	r.recorded = nil
+}
+
+type tlsConnection interface {
+	NegotiatedProtocol() (string, error)
+	HandshakeAddress() net.Address
+}
+
+func getTLSConnection(conn net.Conn) tlsConnection {
+	for {
+		switch c := conn.(type) {
+		case tlsConnection:
+			return c
+		case *internet.StatCouterConnection:
+			conn = c.Connection
+		default:
+			return nil
+		}
+	}
+}
+
+// requestPath returns the path in the HTTP request line at the beginning of the data, or empty if not found.
+func requestPath(data []byte) string {
+	end := bytes.Index(data, []byte("\r\n"))
+	if end < 0 {
+		return ""
+	}
+	fields := strings.Fields(string(data[:end]))
+	if len(fields) != 3 || !strings.HasPrefix(fields[2], "HTTP/") {
+		return ""
+	}
+	return fields[1]
+}
+
+// readRequestLine reads from the Recorder until the HTTP request line is complete, or the limit is reached.
+func readRequestLine(conn internet.Connection, recorder *Recorder, timeout time.Duration) {
+	if timeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(timeout)) // nolint: errcheck
+		defer conn.SetReadDeadline(time.Time{})       // nolint: errcheck
+	}
+
+	for {
+		data := make([]byte, recorder.recorded.Len())
+		recorder.recorded.Copy(data)
+		if bytes.Contains(data, []byte("\r\n")) || len(data) >= maxRequestLineLength {
+			return
+		}
+		mb, err := recorder.ReadMultiBuffer()
+		buf.ReleaseMulti(mb)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func matches(f *Fallback, alpn string, name string, path string) bool {
+	if len(f.Alpn) > 0 && f.Alpn != alpn {
+		return false
+	}
+	if len(f.Name) > 0 && !strings.EqualFold(f.Name, name) {
+		return false
+	}
+	if len(f.Path) > 0 && !strings.HasPrefix(path, f.Path) {
+		return false
+	}
+	return true
+}
+
+func dial(ctx context.Context, f *Fallback) (net.Conn, error) {
+	switch strings.ToLower(f.Type) {
+	case "unix":
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", f.Dest)
+	case "", "tcp":
+		dest, err := net.ParseDestination("tcp:" + f.Dest)
+		if err != nil {
+			return nil, newError("invalid fallback destination: ", f.Dest).Base(err)
+		}
+		return internet.DialSystem(ctx, dest, nil)
+	default:
+		return nil, newError("unknown fallback network: ", f.Type)
+	}
+}
+
+// Serve forwards the connection to the first matching fallback. All data read through the Recorder is sent first,
+// followed by the rest of the connection.
+func Serve(ctx context.Context, conn internet.Connection, recorder *Recorder, fallbacks []*Fallback, sessionPolicy policy.Session) error {
+	defer recorder.Stop()
+
+	var alpn, name, path string
+	if tlsConn := getTLSConnection(conn); tlsConn != nil {
+		alpn, _ = tlsConn.NegotiatedProtocol()
+		if addr := tlsConn.HandshakeAddress(); addr != nil {
+			name = addr.String()
+		}
+	}
+	for _, f := range fallbacks {
+		if len(f.Path) > 0 {
+			readRequestLine(conn, recorder, sessionPolicy.Timeouts.Handshake)
+			data := make([]byte, recorder.recorded.Len())
+			recorder.recorded.Copy(data)
+			path = requestPath(data)
+			break
+		}
+	}
+
+	var fallback *Fallback
+	for _, f := range fallbacks {
+		if matches(f, alpn, name, path) {
+			fallback = f
+			break
+		}
+	}
+	if fallback == nil {
+		return newError("no fallback for alpn: ", alpn, ", name: ", name, ", path: ", path)
+	}
+
+	newError("falling back to ", fallback.Dest).WriteToLog(session.ExportIDToError(ctx))
+	target, err := dial(ctx, fallback)
+	if err != nil {
+		return newError("failed to dial fallback ", fallback.Dest).Base(err)
+	}
+	defer target.Close() // nolint: errcheck
+
+	if fallback.Xver > 0 {
+		src := net.DestinationFromAddr(conn.RemoteAddr())
+		dest := net.DestinationFromAddr(conn.LocalAddr())
+		if err := proxyproto.WriteHeader(target, byte(fallback.Xver), src, dest); err != nil {
+			return err
+		}
+	}
+
+	targetWriter := buf.NewWriter(target)
+	recorded := recorder.recorded
+	recorder.recorded = nil
+	recorder.stopped = true
+	if err := targetWriter.WriteMultiBuffer(recorded); err != nil {
+		return newError("failed to write to fallback").Base(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+		if err := buf.Copy(recorder.Reader, targetWriter, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to forward request to fallback").Base(err)
+		}
+		if cw, ok := target.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite() // nolint: errcheck
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+		if err := buf.Copy(buf.NewReader(target), buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to forward response from fallback").Base(err)
+		}
+		return nil
+	}
+
+	if err := task.Run(ctx, requestDone, responseDone); err != nil {
+		return newError("fallback connection ends").Base(err)
+	}
+	return nil
+}
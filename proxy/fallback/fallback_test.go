@@ -0,0 +1,83 @@
+package fallback_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/features/policy"
+	. "v2ray.com/core/proxy/fallback"
+	"v2ray.com/core/testing/servers/tcp"
+)
+
+func xor(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i, v := range b {
+		r[i] = v ^ 'c'
+	}
+	return r
+}
+
+func TestServeByPath(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+
+	fallbacks := []*Fallback{
+		{
+			Path: "/other",
+			Dest: "127.0.0.1:1",
+		},
+		{
+			Path: "/v2",
+			Dest: dest.NetAddr(),
+		},
+	}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Simulates a proxy that reads a few bytes and fails to authenticate the client.
+		recorder := NewRecorder(buf.NewReader(conn))
+		mb, err := recorder.ReadMultiBuffer()
+		buf.ReleaseMulti(mb)
+		if err != nil {
+			return
+		}
+		Serve(context.Background(), conn, recorder, fallbacks, policy.SessionDefault()) // nolint: errcheck
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer conn.Close()
+
+	// The request line arrives in two parts, so that the path is only known after reading more.
+	request := []byte("GET /v2ray HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	common.Must2(conn.Write(request[:6]))
+	time.Sleep(time.Millisecond * 100)
+	common.Must2(conn.Write(request[6:]))
+
+	response := make([]byte, len(request))
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	common.Must2(io.ReadFull(conn, response))
+	if r := cmp.Diff(response, xor(request)); r != "" {
+		t.Error(r)
+	}
+}
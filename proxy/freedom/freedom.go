@@ -13,6 +13,7 @@ import (
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
 	"v2ray.com/core/common/retry"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal"
@@ -51,6 +52,13 @@ func (h *Handler) Init(config *Config, pm policy.Manager, d dns.Client) error {
 	return nil
 }
 
+// IsDirect implements proxy.DirectOutbound. The Handler has a fixed server only if the destination is overridden
+// entirely.
+func (h *Handler) IsDirect() bool {
+	override := h.config.DestinationOverride
+	return override == nil || !isValidAddress(override.Server.Address) || override.Server.Port == 0
+}
+
 func (h *Handler) policy() policy.Session {
 	p := h.policyManager.ForLevel(h.config.UserLevel)
 	if h.config.Timeout > 0 && h.config.UserLevel == 0 {
@@ -139,6 +147,17 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	}
 	defer conn.Close() // nolint: errcheck
 
+	if h.config.ProxyProtocol > 0 && destination.Network == net.Network_TCP {
+		var src net.Destination
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			src = inbound.Source
+		}
+		dest := net.DestinationFromAddr(conn.RemoteAddr())
+		if err := proxyproto.WriteHeader(conn, byte(h.config.ProxyProtocol), src, dest); err != nil {
+			return newError("failed to send PROXY protocol header").Base(err)
+		}
+	}
+
 	plcy := h.policy()
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
@@ -149,6 +168,8 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		var writer buf.Writer
 		if destination.Network == net.Network_TCP {
 			writer = buf.NewWriter(conn)
+		} else if _, ok := conn.(net.PacketConn); ok && h.config.DestinationOverride == nil {
+			writer = &packetWriter{conn: conn}
 		} else {
 			writer = &buf.SequentialWriter{Writer: conn}
 		}
@@ -166,6 +187,8 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		var reader buf.Reader
 		if destination.Network == net.Network_TCP {
 			reader = buf.NewReader(conn)
+		} else if pc, ok := conn.(net.PacketConn); ok && h.config.DestinationOverride == nil {
+			reader = &packetReader{pc: pc}
 		} else {
 			reader = buf.NewPacketReader(conn)
 		}
@@ -182,3 +205,48 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 
 	return nil
 }
+
+// packetWriter sends packets to the remote address of the connection. Packets to other addresses are dropped, as they
+// are not routed to this connection.
+type packetWriter struct {
+	conn net.Conn
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *packetWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+
+	for _, b := range mb {
+		if b.IsEmpty() {
+			continue
+		}
+		if b.UDP != nil && b.UDP.String() != w.conn.RemoteAddr().String() {
+			newError("dropping packet to ", b.UDP, ", which is not routed to ", w.conn.RemoteAddr()).AtDebug().WriteToLog()
+			continue
+		}
+		if _, err := w.conn.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packetReader reads packets along with their remote addresses.
+type packetReader struct {
+	pc net.PacketConn
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *packetReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	b := buf.New()
+	n, addr, err := r.pc.ReadFrom(b.Extend(buf.Size))
+	if err != nil {
+		b.Release()
+		return nil, err
+	}
+	b.Resize(0, int32(n))
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		b.UDP = udpAddr
+	}
+	return buf.MultiBuffer{b}, nil
+}
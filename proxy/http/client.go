@@ -6,12 +6,16 @@ import (
 	"bufio"
 	"context"
 	"encoding/base64"
-	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+
 	"v2ray.com/core"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
@@ -30,6 +34,14 @@ import (
 type Client struct {
 	serverPicker  protocol.ServerPicker
 	policyManager policy.Manager
+	header        map[string]string
+	protocol      string
+
+	access sync.Mutex
+	// h2Conns are HTTP/2 connections to servers, shared by all tunnels to the same server.
+	h2Conns map[net.Destination]*h2Conn
+	// digests are the latest Digest challenges of servers.
+	digests map[net.Destination]*digestChallenge
 }
 
 // NewClient create a new http client based on the given config.
@@ -50,6 +62,10 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 	return &Client{
 		serverPicker:  protocol.NewRoundRobinServerPicker(serverList),
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		header:        config.Header,
+		protocol:      config.ConnectProtocol,
+		h2Conns:       make(map[net.Destination]*h2Conn),
+		digests:       make(map[net.Destination]*digestChallenge),
 	}, nil
 }
 
@@ -65,17 +81,28 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		return newError("UDP is not supported by HTTP outbound")
 	}
 
+	// Send the first payload along with the CONNECT request if it is available soon.
+	var firstPayload []byte
+	if reader, ok := link.Reader.(buf.TimeoutReader); ok {
+		mb, err := reader.ReadMultiBufferTimeout(time.Millisecond * 100)
+		if err != nil && err != buf.ErrReadTimeout {
+			return newError("failed to read first payload").Base(err)
+		}
+		firstPayload = make([]byte, mb.Len())
+		mb, _ = buf.SplitBytes(mb, firstPayload)
+		buf.ReleaseMulti(mb)
+	}
+
 	var server *protocol.ServerSpec
-	var conn internet.Connection
+	var conn net.Conn
 
 	if err := retry.ExponentialBackoff(5, 100).On(func() error {
 		server = c.serverPicker.PickServer()
-		dest := server.Destination()
-		rawConn, err := dialer.Dial(ctx, dest)
+		tunnel, err := c.setUpHTTPTunnel(ctx, dialer, server, destination, firstPayload)
 		if err != nil {
 			return err
 		}
-		conn = rawConn
+		conn = tunnel
 
 		return nil
 	}); err != nil {
@@ -95,8 +122,6 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		p = c.policyManager.ForLevel(user.Level)
 	}
 
-	conn = setUpHTTPTunnel(conn, &destination, user)
-
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, p.Timeouts.ConnectionIdle)
 
@@ -106,15 +131,7 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 	}
 	responseFunc := func() error {
 		defer timer.SetTimeout(p.Timeouts.UplinkOnly)
-		bc := bufio.NewReader(conn)
-		resp, err := http.ReadResponse(bc, nil)
-		if err != nil {
-			return err
-		}
-		if resp.StatusCode != http.StatusOK {
-			return newError(resp.Status)
-		}
-		return buf.Copy(buf.NewReader(bc), link.Writer, buf.UpdateActivity(timer))
+		return buf.Copy(buf.NewReader(conn), link.Writer, buf.UpdateActivity(timer))
 	}
 
 	var responseDonePost = task.OnSuccess(responseFunc, task.Close(link.Writer))
@@ -125,77 +142,220 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 	return nil
 }
 
-// setUpHTTPTunnel will create a socket tunnel via HTTP CONNECT method
-func setUpHTTPTunnel(conn internet.Connection, destination *net.Destination, user *protocol.MemoryUser) *tunConn {
-	var headers []string
-	destNetAddr := destination.NetAddr()
-	headers = append(headers, "CONNECT "+destNetAddr+" HTTP/1.1")
-	headers = append(headers, "Host: "+destNetAddr)
-	if user != nil && user.Account != nil {
-		account := user.Account.(*Account)
+// setUpHTTPTunnel creates a tunnel to the target via HTTP CONNECT method. HTTP/2 is used if the server negotiates
+// it in TLS handshake, and the HTTP/2 connection is reused for later tunnels to the same server.
+func (c *Client) setUpHTTPTunnel(ctx context.Context, dialer internet.Dialer, server *protocol.ServerSpec, target net.Destination, firstPayload []byte) (net.Conn, error) {
+	dest := server.Destination()
+
+	p := c.policyManager.ForLevel(0)
+	if user := server.PickUser(); user != nil {
+		p = c.policyManager.ForLevel(user.Level)
+	}
+
+	c.access.Lock()
+	cachedConn := c.h2Conns[dest]
+	c.access.Unlock()
+	if cachedConn != nil && cachedConn.canTakeNewStream() {
+		return c.connectHTTP2(cachedConn, server, target, firstPayload, p.Timeouts.Handshake)
+	}
+
+	rawConn, err := dialer.Dial(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConn, ok := rawConn.(interface{ NegotiatedProtocol() (string, error) }); ok {
+		proto, err := tlsConn.NegotiatedProtocol()
+		if err != nil {
+			rawConn.Close()
+			return nil, newError("failed to finish TLS handshake").Base(err)
+		}
+		if proto == http2.NextProtoTLS {
+			conn, err := newH2ClientConn(rawConn, p.Timeouts.Handshake, p.Timeouts.ConnectionIdle)
+			if err != nil {
+				return nil, newError("failed to create HTTP/2 connection").Base(err)
+			}
+			c.access.Lock()
+			c.h2Conns[dest] = conn
+			c.access.Unlock()
+			return c.connectHTTP2(conn, server, target, firstPayload, p.Timeouts.Handshake)
+		}
+	}
+
+	if c.protocol != "" {
+		rawConn.Close()
+		return nil, newError("extended CONNECT of ", c.protocol, " requires HTTP/2, but it is not negotiated with ", dest)
+	}
+
+	conn, err := c.connectHTTP1(rawConn, server, target, firstPayload)
+	if err != nil {
+		rawConn.Close()
+		if err != errDigestChallenged {
+			return nil, err
+		}
+		// Authorize with the new challenge on a new connection, as the server may close this one.
+		if rawConn, err = dialer.Dial(ctx, dest); err != nil {
+			return nil, err
+		}
+		if conn, err = c.connectHTTP1(rawConn, server, target, firstPayload); err != nil {
+			rawConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// errDigestChallenged means that the request is rejected with a new Digest challenge, and should be retried.
+var errDigestChallenged = newError("digest challenged")
+
+func (c *Client) newConnectRequest(server *protocol.ServerSpec, target net.Destination) (*http.Request, error) {
+	targetAddr := target.NetAddr()
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: targetAddr},
+		Header: make(http.Header),
+		Host:   targetAddr,
+	}
+	for key, value := range c.header {
+		req.Header.Set(key, value)
+	}
+
+	user := server.PickUser()
+	if user == nil || user.Account == nil {
+		return req, nil
+	}
+	account := user.Account.(*Account)
+
+	c.access.Lock()
+	challenge := c.digests[server.Destination()]
+	c.access.Unlock()
+	if challenge == nil {
 		auth := account.GetUsername() + ":" + account.GetPassword()
-		headers = append(headers, "Proxy-Authorization: Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+		return req, nil
 	}
-	headers = append(headers, "Proxy-Connection: Keep-Alive")
 
-	b := buf.New()
-	b.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
-	return newTunConn(conn, b, 5 * time.Millisecond)
+	auth, err := challenge.authorize(account.GetUsername(), account.GetPassword(), req.Method, targetAddr)
+	if err != nil {
+		return nil, newError("failed to authorize with digest").Base(err)
+	}
+	req.Header.Set("Proxy-Authorization", auth)
+	return req, nil
 }
 
-// tunConn is a connection that writes header before content,
-// the header will be written during the next Write call or after
-// specified delay.
-type tunConn struct {
-	internet.Connection
-	header *buf.Buffer
-	once   sync.Once
-	timer  *time.Timer
+// checkResponse returns nil if the tunnel is established, or errDigestChallenged if the request should be retried
+// with a new Digest challenge.
+func (c *Client) checkResponse(server *protocol.ServerSpec, req *http.Request, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusProxyAuthRequired:
+		challenge := parseDigestChallenge(resp.Header["Proxy-Authenticate"])
+		if challenge == nil {
+			break
+		}
+		// Retry if the request is not authorized with Digest, or only the nonce is stale.
+		if auth := req.Header.Get("Proxy-Authorization"); auth != "" && !strings.HasPrefix(auth, "Digest ") || challenge.stale {
+			c.access.Lock()
+			c.digests[server.Destination()] = challenge
+			c.access.Unlock()
+			return errDigestChallenged
+		}
+	}
+	return newError("proxy responded with: ", resp.Status)
 }
 
-func newTunConn(conn internet.Connection, header *buf.Buffer, delay time.Duration) *tunConn {
-	tc := &tunConn{
-		Connection: conn,
-		header:     header,
+func (c *Client) connectHTTP1(rawConn net.Conn, server *protocol.ServerSpec, target net.Destination, firstPayload []byte) (net.Conn, error) {
+	req, err := c.newConnectRequest(server, target)
+	if err != nil {
+		return nil, err
 	}
-	if delay > 0 {
-		tc.timer = time.AfterFunc(delay, func() {
-			tc.Write([]byte{})
-		})
+	req.Header.Set("Proxy-Connection", "Keep-Alive")
+
+	b := buf.New()
+	defer b.Release()
+	if err := req.Write(b); err != nil {
+		return nil, newError("failed to write CONNECT request").Base(err)
+	}
+	if _, err := rawConn.Write(append(b.Bytes(), firstPayload...)); err != nil {
+		return nil, newError("failed to send CONNECT request").Base(err)
+	}
+
+	reader := bufio.NewReader(rawConn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, newError("failed to read CONNECT response").Base(err)
 	}
-	return tc
+	if err := c.checkResponse(server, req, resp); err != nil {
+		return nil, err
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: rawConn, reader: reader}, nil
+	}
+	return rawConn, nil
+}
+
+func (c *Client) connectHTTP2(conn *h2Conn, server *protocol.ServerSpec, target net.Destination, firstPayload []byte, timeout time.Duration) (net.Conn, error) {
+	if c.protocol != "" && !conn.supportsExtendedConnect() {
+		return nil, newError("extended CONNECT is not supported by ", server.Destination())
+	}
+
+	for i := 0; ; i++ {
+		req, err := c.newConnectRequest(server, target)
+		if err != nil {
+			return nil, err
+		}
+
+		fields := []hpack.HeaderField{{Name: ":method", Value: req.Method}}
+		if c.protocol != "" {
+			fields = append(fields,
+				hpack.HeaderField{Name: ":protocol", Value: c.protocol},
+				hpack.HeaderField{Name: ":scheme", Value: "https"},
+				hpack.HeaderField{Name: ":path", Value: "/"})
+		}
+		fields = append(fields, hpack.HeaderField{Name: ":authority", Value: req.Host})
+		for key, values := range req.Header {
+			for _, value := range values {
+				fields = append(fields, hpack.HeaderField{Name: strings.ToLower(key), Value: value})
+			}
+		}
+
+		stream, err := conn.openStream(fields, timeout)
+		if err != nil {
+			return nil, newError("failed to send CONNECT request").Base(err)
+		}
+		status, _ := strconv.Atoi(stream.pseudoHeader(":status"))
+		resp := &http.Response{
+			Status:     strconv.Itoa(status) + " " + http.StatusText(status),
+			StatusCode: status,
+			Header:     stream.httpHeader(),
+		}
+		err = c.checkResponse(server, req, resp)
+		if err == nil {
+			if len(firstPayload) > 0 {
+				if _, err := stream.Write(firstPayload); err != nil {
+					stream.Close()
+					return nil, newError("failed to write first payload").Base(err)
+				}
+			}
+			return stream, nil
+		}
+		stream.Close()
+		if err != errDigestChallenged || i > 0 {
+			return nil, err
+		}
+	}
+}
+
+// bufferedConn is a connection with some data already read into a buffer.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
 }
 
-func (c *tunConn) Write(b []byte) (n int, err error) {
-	// fallback to normal write if header is sent
-	if c.header == nil {
-		return c.Connection.Write(b)
-	}
-	// Prevent timer and writer race condition
-	c.once.Do(func() {
-		if c.timer != nil {
-			c.timer.Stop()
-			c.timer = nil
-		}
-		lenheader := c.header.Len()
-		// Concate header and b
-		common.Must2(c.header.Write(b))
-		// Write buffer
-		var nc int64
-		nc, err = io.Copy(c.Connection, c.header)
-		c.header.Release()
-		c.header = nil
-		n = int(nc) - int(lenheader)
-		if n < 0 { n = 0 }
-		b = b[n:]
-	})
-	// Write Trailing bytes
-	if len(b) > 0 && err == nil {
-		var nw int
-		nw, err = c.Connection.Write(b)
-		n += nw
-	}
-	return n, err
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
 }
 
 func init() {
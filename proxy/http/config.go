@@ -1,6 +1,8 @@
 package http
 
 import (
+	"github.com/golang/protobuf/proto"
+
 	"v2ray.com/core/common/protocol"
 )
 
@@ -11,6 +13,11 @@ func (a *Account) Equals(another protocol.Account) bool {
 	return false
 }
 
+// ToProto implements protocol.ProtoAccount.
+func (a *Account) ToProto() proto.Message {
+	return a
+}
+
 func (a *Account) AsAccount() (protocol.Account, error) {
 	return a, nil
 }
@@ -133,10 +133,15 @@ func (m *ServerConfig) GetUserLevel() uint32 {
 // ClientConfig is the protobuf config for HTTP proxy client.
 type ClientConfig struct {
 	// Sever is a list of HTTP server addresses.
-	Server               []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
-	XXX_unrecognized     []byte                     `json:"-"`
-	XXX_sizecache        int32                      `json:"-"`
+	Server []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
+	// Additional headers in CONNECT requests.
+	Header map[string]string `protobuf:"bytes,2,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Protocol of extended CONNECT in RFC 8441, sent as the :protocol pseudo-header with :scheme "https" and :path
+	// "/". It requires HTTP/2 negotiated in TLS handshake. Empty for plain CONNECT.
+	ConnectProtocol      string   `protobuf:"bytes,3,opt,name=connect_protocol,json=connectProtocol,proto3" json:"connect_protocol,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ClientConfig) Reset()         { *m = ClientConfig{} }
@@ -171,11 +176,26 @@ func (m *ClientConfig) GetServer() []*protocol.ServerEndpoint {
 	return nil
 }
 
+func (m *ClientConfig) GetHeader() map[string]string {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ClientConfig) GetConnectProtocol() string {
+	if m != nil {
+		return m.ConnectProtocol
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.http.Account")
 	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.http.ServerConfig")
 	proto.RegisterMapType((map[string]string)(nil), "v2ray.core.proxy.http.ServerConfig.AccountsEntry")
 	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.http.ClientConfig")
+	proto.RegisterMapType((map[string]string)(nil), "v2ray.core.proxy.http.ClientConfig.HeaderEntry")
 }
 
 func init() {
@@ -183,29 +203,32 @@ func init() {
 }
 
 var fileDescriptor_e66c3db3a635d8e4 = []byte{
-	// 375 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0x4d, 0x6b, 0xe3, 0x30,
-	0x10, 0xc5, 0x4e, 0x36, 0x1f, 0xda, 0x04, 0xb2, 0x62, 0x03, 0xde, 0xb0, 0x0b, 0x21, 0x87, 0x25,
-	0xb4, 0x20, 0xb7, 0xe9, 0xa5, 0x34, 0xa7, 0x24, 0x04, 0x7a, 0x68, 0x21, 0xa8, 0xa5, 0x87, 0x5e,
-	0x82, 0xaa, 0xa8, 0xad, 0xa9, 0x2d, 0x09, 0x49, 0x76, 0xea, 0x7b, 0x7f, 0x4d, 0x7f, 0x65, 0x91,
-	0x6c, 0xa7, 0x69, 0xc9, 0xc9, 0x9e, 0xf7, 0x66, 0x9e, 0xe6, 0xbd, 0x01, 0xff, 0xb3, 0x89, 0x22,
-	0x39, 0xa2, 0x22, 0x09, 0xa9, 0x50, 0x2c, 0x94, 0x4a, 0xbc, 0xe6, 0xe1, 0xb3, 0x31, 0x32, 0xa4,
-	0x82, 0x3f, 0x46, 0x4f, 0x48, 0x2a, 0x61, 0x04, 0xec, 0x57, 0x7d, 0x8a, 0x21, 0xd7, 0x83, 0x6c,
-	0xcf, 0xe0, 0xe4, 0xdb, 0x38, 0x15, 0x49, 0x22, 0x78, 0xe8, 0x66, 0xa8, 0x88, 0x43, 0xcd, 0x54,
-	0xc6, 0xd4, 0x5a, 0x4b, 0x46, 0x0b, 0xa1, 0xd1, 0x0c, 0x34, 0x67, 0x94, 0x8a, 0x94, 0x1b, 0x38,
-	0x00, 0xad, 0x54, 0x33, 0xc5, 0x49, 0xc2, 0x02, 0x6f, 0xe8, 0x8d, 0xdb, 0x78, 0x57, 0x5b, 0x4e,
-	0x12, 0xad, 0xb7, 0x42, 0x6d, 0x02, 0xbf, 0xe0, 0xaa, 0x7a, 0xf4, 0xe6, 0x83, 0xce, 0x8d, 0x13,
-	0x5e, 0xb8, 0x15, 0xe1, 0x5f, 0xd0, 0x34, 0x51, 0xc2, 0x44, 0x6a, 0x9c, 0x4e, 0x77, 0xee, 0x07,
-	0x1e, 0xae, 0x20, 0x78, 0x0d, 0x5a, 0xa4, 0x78, 0x51, 0x07, 0xfe, 0xb0, 0x36, 0xfe, 0x39, 0x39,
-	0x45, 0x07, 0xdd, 0xa0, 0x7d, 0x51, 0x54, 0x6e, 0xa9, 0x97, 0xdc, 0xa8, 0x1c, 0xef, 0x24, 0xe0,
-	0x31, 0xf8, 0x45, 0xe2, 0x58, 0x6c, 0xd7, 0x46, 0x11, 0xae, 0x25, 0x51, 0x8c, 0x9b, 0xa0, 0x36,
-	0xf4, 0xc6, 0x2d, 0xdc, 0x73, 0xc4, 0xed, 0x27, 0x0e, 0xff, 0x01, 0x60, 0x2d, 0xad, 0x63, 0x96,
-	0xb1, 0x38, 0xa8, 0xdb, 0xe5, 0x70, 0xdb, 0x22, 0x57, 0x16, 0x18, 0x4c, 0x41, 0xf7, 0xcb, 0x33,
-	0xb0, 0x07, 0x6a, 0x2f, 0x2c, 0x2f, 0xd3, 0xb0, 0xbf, 0xf0, 0x37, 0xf8, 0x91, 0x91, 0x38, 0x65,
-	0x65, 0x0a, 0x45, 0x71, 0xe1, 0x9f, 0x7b, 0x23, 0x0c, 0x3a, 0x8b, 0x38, 0x62, 0xdc, 0x94, 0x29,
-	0xcc, 0x41, 0xa3, 0x88, 0x3b, 0xf0, 0x9c, 0xcb, 0xa3, 0x7d, 0x97, 0xc5, 0x61, 0x50, 0x75, 0x98,
-	0xd2, 0xea, 0x92, 0x6f, 0xa4, 0x88, 0xb8, 0xc1, 0xe5, 0xe4, 0x7c, 0x0a, 0xfe, 0x50, 0x91, 0x1c,
-	0x8e, 0x67, 0xe5, 0xdd, 0xd7, 0xed, 0xf7, 0xdd, 0xef, 0xdf, 0x4d, 0x30, 0xc9, 0xd1, 0xc2, 0xf2,
-	0x2b, 0xc7, 0x5f, 0x1a, 0x23, 0x1f, 0x1a, 0x4e, 0xfd, 0xec, 0x23, 0x00, 0x00, 0xff, 0xff, 0xac,
-	0x7a, 0x67, 0x04, 0x54, 0x02, 0x00, 0x00,
+	// 425 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x51, 0xc1, 0x6e, 0x13, 0x31,
+	0x10, 0xd5, 0x6e, 0x4a, 0x9a, 0x4c, 0x5b, 0x11, 0x2c, 0x2a, 0x2d, 0x11, 0x48, 0x51, 0x0e, 0x28,
+	0x80, 0xe4, 0x85, 0x70, 0x01, 0x7a, 0x6a, 0xa2, 0x8a, 0x1e, 0x40, 0x8a, 0x16, 0xc4, 0x81, 0x4b,
+	0x64, 0x9c, 0x81, 0x46, 0xec, 0x7a, 0x2c, 0xdb, 0x49, 0xd9, 0x23, 0x12, 0x5f, 0xc3, 0x57, 0x22,
+	0x7b, 0xbd, 0x25, 0xa0, 0x70, 0xe0, 0xb4, 0x3b, 0x6f, 0x66, 0xde, 0xbc, 0xf7, 0x0c, 0x0f, 0xb7,
+	0x53, 0x23, 0x6a, 0x2e, 0xa9, 0xca, 0x25, 0x19, 0xcc, 0xb5, 0xa1, 0x6f, 0x75, 0x7e, 0xe5, 0x9c,
+	0xce, 0x25, 0xa9, 0xcf, 0xeb, 0x2f, 0x5c, 0x1b, 0x72, 0xc4, 0x4e, 0xdb, 0x39, 0x83, 0x3c, 0xcc,
+	0x70, 0x3f, 0x33, 0x7c, 0xfa, 0xd7, 0xba, 0xa4, 0xaa, 0x22, 0x95, 0x87, 0x1d, 0x49, 0x65, 0x6e,
+	0xd1, 0x6c, 0xd1, 0x2c, 0xad, 0x46, 0xd9, 0x10, 0x8d, 0xcf, 0xe1, 0xf0, 0x5c, 0x4a, 0xda, 0x28,
+	0xc7, 0x86, 0xd0, 0xdb, 0x58, 0x34, 0x4a, 0x54, 0x98, 0x25, 0xa3, 0x64, 0xd2, 0x2f, 0x6e, 0x6a,
+	0xdf, 0xd3, 0xc2, 0xda, 0x6b, 0x32, 0xab, 0x2c, 0x6d, 0x7a, 0x6d, 0x3d, 0xfe, 0x91, 0xc2, 0xf1,
+	0xbb, 0x40, 0x3c, 0x0f, 0x12, 0xd9, 0x7d, 0x38, 0x74, 0xeb, 0x0a, 0x69, 0xe3, 0x02, 0xcf, 0xc9,
+	0x2c, 0xcd, 0x92, 0xa2, 0x85, 0xd8, 0x5b, 0xe8, 0x89, 0xe6, 0xa2, 0xcd, 0xd2, 0x51, 0x67, 0x72,
+	0x34, 0x7d, 0xc6, 0xf7, 0xba, 0xe1, 0xbb, 0xa4, 0x3c, 0xaa, 0xb4, 0x17, 0xca, 0x99, 0xba, 0xb8,
+	0xa1, 0x60, 0x4f, 0xe0, 0x8e, 0x28, 0x4b, 0xba, 0x5e, 0x3a, 0x23, 0x94, 0xd5, 0xc2, 0xa0, 0x72,
+	0x59, 0x67, 0x94, 0x4c, 0x7a, 0xc5, 0x20, 0x34, 0xde, 0xff, 0xc6, 0xd9, 0x03, 0x00, 0x6f, 0x69,
+	0x59, 0xe2, 0x16, 0xcb, 0xec, 0xc0, 0x8b, 0x2b, 0xfa, 0x1e, 0x79, 0xe3, 0x81, 0xe1, 0x19, 0x9c,
+	0xfc, 0x71, 0x86, 0x0d, 0xa0, 0xf3, 0x15, 0xeb, 0x98, 0x86, 0xff, 0x65, 0x77, 0xe1, 0xd6, 0x56,
+	0x94, 0x1b, 0x8c, 0x29, 0x34, 0xc5, 0xab, 0xf4, 0x45, 0x32, 0xfe, 0x9e, 0xc2, 0xf1, 0xbc, 0x5c,
+	0xa3, 0x72, 0x31, 0x86, 0x19, 0x74, 0x9b, 0xbc, 0xb3, 0x24, 0xd8, 0x7c, 0xbc, 0x6b, 0xb3, 0x79,
+	0x19, 0xde, 0xbe, 0x4c, 0xf4, 0x7a, 0xa1, 0x56, 0x9a, 0xd6, 0xca, 0x15, 0x71, 0x93, 0xbd, 0x86,
+	0xee, 0x15, 0x8a, 0x15, 0x9a, 0x18, 0x55, 0xfe, 0x8f, 0xa8, 0x76, 0x0f, 0xf3, 0xcb, 0xb0, 0xd1,
+	0x04, 0x15, 0xd7, 0xd9, 0x23, 0x18, 0x48, 0x52, 0x0a, 0xa5, 0x5b, 0xb6, 0x37, 0x43, 0x4a, 0xfd,
+	0xe2, 0x76, 0xc4, 0x17, 0x11, 0x1e, 0xbe, 0x84, 0xa3, 0x1d, 0x86, 0xff, 0xc9, 0x60, 0x76, 0x06,
+	0xf7, 0x24, 0x55, 0xfb, 0x35, 0x2e, 0x92, 0x8f, 0x07, 0xfe, 0xfb, 0x33, 0x3d, 0xfd, 0x30, 0x2d,
+	0x44, 0xcd, 0xe7, 0xbe, 0xbf, 0x08, 0xfd, 0x4b, 0xe7, 0xf4, 0xa7, 0x6e, 0x10, 0xf6, 0xfc, 0xd7,
+	0x00, 0xfa, 0x78, 0xf8, 0xa7, 0x04, 0x03, 0x00, 0x00,
 }
@@ -0,0 +1,152 @@
+// +build !confonly
+
+package http
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+	"sync/atomic"
+)
+
+// digestChallenge is a Digest challenge of a proxy server, as in RFC 7616. It is kept for authorizing later
+// requests without another round trip.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	stale     bool
+
+	nc uint32
+}
+
+// parseDigestChallenge returns the first Digest challenge in the values of Proxy-Authenticate headers, or nil if
+// there is none.
+func parseDigestChallenge(headers []string) *digestChallenge {
+	const prefix = "digest "
+	for _, header := range headers {
+		if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+			continue
+		}
+		params := parseAuthParams(header[len(prefix):])
+		return &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+			qop:       params["qop"],
+			stale:     strings.EqualFold(params["stale"], "true"),
+		}
+	}
+	return nil
+}
+
+// parseAuthParams parses a comma separated list of auth-params, whose values may be quoted strings.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ,")
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			return params
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = strings.TrimLeft(s[i+1:], " ")
+
+		var value strings.Builder
+		if strings.HasPrefix(s, "\"") {
+			j := 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				value.WriteByte(s[j])
+			}
+			if j < len(s) {
+				j++
+			}
+			s = s[j:]
+		} else {
+			j := strings.IndexByte(s, ',')
+			if j < 0 {
+				j = len(s)
+			}
+			value.WriteString(strings.TrimSpace(s[:j]))
+			s = s[j:]
+		}
+		params[key] = value.String()
+	}
+}
+
+func (c *digestChallenge) hasQOPAuth() bool {
+	for _, qop := range strings.Split(c.qop, ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize returns the value of Proxy-Authorization header for the given request.
+func (c *digestChallenge) authorize(username, password, method, uri string) (string, error) {
+	var cnonce [8]byte
+	if _, err := rand.Read(cnonce[:]); err != nil {
+		return "", err
+	}
+	return c.authorizeWithCNonce(username, password, method, uri, hex.EncodeToString(cnonce[:]))
+}
+
+func (c *digestChallenge) authorizeWithCNonce(username, password, method, uri, cnonce string) (string, error) {
+	var newHash func() hash.Hash
+	algorithm := strings.ToUpper(c.algorithm)
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", newError("unsupported digest algorithm: ", c.algorithm)
+	}
+	h := func(s string) string {
+		hash := newHash()
+		hash.Write([]byte(s)) // nolint: errcheck
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+
+	ha1 := h(username + ":" + c.realm + ":" + password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	fields := []string{
+		fmt.Sprintf("username=%q", username),
+		fmt.Sprintf("realm=%q", c.realm),
+		fmt.Sprintf("nonce=%q", c.nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+	if c.algorithm != "" {
+		fields = append(fields, "algorithm="+c.algorithm)
+	}
+	switch {
+	case c.hasQOPAuth():
+		nc := fmt.Sprintf("%08x", atomic.AddUint32(&c.nc, 1))
+		response := h(ha1 + ":" + c.nonce + ":" + nc + ":" + cnonce + ":auth:" + ha2)
+		fields = append(fields, fmt.Sprintf("response=%q", response), "qop=auth", "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce))
+	case c.qop == "":
+		fields = append(fields, fmt.Sprintf("response=%q", h(ha1+":"+c.nonce+":"+ha2)))
+	default:
+		return "", newError("unsupported digest qop: ", c.qop)
+	}
+	if c.opaque != "" {
+		fields = append(fields, fmt.Sprintf("opaque=%q", c.opaque))
+	}
+
+	return "Digest " + strings.Join(fields, ", "), nil
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport/internet"
+)
+
+func TestDigestAuthorization(t *testing.T) {
+	// Example in RFC 2617 section 3.5.
+	challenge := parseDigestChallenge([]string{
+		`Basic realm="testrealm@host.com"`,
+		`Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+	})
+	if challenge == nil {
+		t.Fatal("digest challenge not found")
+	}
+	auth, err := challenge.authorizeWithCNonce("Mufasa", "Circle Of Life", "GET", "/dir/index.html", "0a4f113b")
+	common.Must(err)
+
+	params := parseAuthParams(strings.TrimPrefix(auth, "Digest "))
+	for key, value := range map[string]string{
+		"username": "Mufasa",
+		"realm":    "testrealm@host.com",
+		"nonce":    "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+		"uri":      "/dir/index.html",
+		"qop":      "auth",
+		"nc":       "00000001",
+		"cnonce":   "0a4f113b",
+		"response": "6629fae49393a05397450978507c4ef1",
+		"opaque":   "5ccc069c403ebaf9f0171e9517f40e41",
+	} {
+		if params[key] != value {
+			t.Error("unexpected ", key, ": ", params[key])
+		}
+	}
+
+	// Nonce count increases for each request.
+	auth, err = challenge.authorize("Mufasa", "Circle Of Life", "GET", "/dir/index.html")
+	common.Must(err)
+	if nc := parseAuthParams(strings.TrimPrefix(auth, "Digest "))["nc"]; nc != "00000002" {
+		t.Error("unexpected nc: ", nc)
+	}
+}
+
+func TestUnsupportedDigestAlgorithm(t *testing.T) {
+	challenge := parseDigestChallenge([]string{`Digest realm="r", nonce="n", algorithm=SHA-512-256`})
+	if _, err := challenge.authorize("user", "pass", "CONNECT", "v2ray.com:443"); err == nil {
+		t.Error("nil error")
+	}
+}
+
+type testDialer struct{}
+
+func (testDialer) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	return net.Dial("tcp", dest.NetAddr())
+}
+
+func (testDialer) Address() net.Address {
+	return nil
+}
+
+// serveDigestProxy serves a CONNECT proxy that requires Digest authentication, and echoes data in tunnels.
+func serveDigestProxy(listener net.Listener, username, password string) {
+	const realm, nonce = "v2ray", "0123456789abcdef"
+	md5Hex := func(s string) string {
+		hash := md5.Sum([]byte(s))
+		return hex.EncodeToString(hash[:])
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+
+			reader := bufio.NewReader(conn)
+			req, err := http.ReadRequest(reader)
+			if err != nil {
+				return
+			}
+			params := parseAuthParams(strings.TrimPrefix(req.Header.Get("Proxy-Authorization"), "Digest "))
+			ha1 := md5Hex(username + ":" + realm + ":" + password)
+			ha2 := md5Hex(req.Method + ":" + params["uri"])
+			expected := md5Hex(ha1 + ":" + nonce + ":" + params["nc"] + ":" + params["cnonce"] + ":auth:" + ha2)
+			if params["response"] != expected || params["uri"] != req.Host {
+				io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: Digest realm=\""+realm+"\", nonce=\""+nonce+"\", qop=\"auth\"\r\nContent-Length: 0\r\n\r\n") // nolint: errcheck
+				return
+			}
+			io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n") // nolint: errcheck
+			io.Copy(conn, reader)                                               // nolint: errcheck
+		}()
+	}
+}
+
+func TestDigestTunnel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	go serveDigestProxy(listener, "user", "pass")
+
+	client := &Client{
+		policyManager: policy.DefaultManager{},
+		digests:       make(map[net.Destination]*digestChallenge),
+		h2Conns:       make(map[net.Destination]*h2Conn),
+	}
+	server := protocol.NewServerSpec(net.DestinationFromAddr(listener.Addr()), protocol.AlwaysValid(), &protocol.MemoryUser{
+		Account: &Account{Username: "user", Password: "pass"},
+	})
+	target := net.TCPDestination(net.DomainAddress("www.v2ray.com"), 443)
+
+	// The first tunnel is challenged, and the second is authorized without challenge.
+	for i := 0; i < 2; i++ {
+		conn, err := client.setUpHTTPTunnel(context.Background(), testDialer{}, server, target, []byte("first payload"))
+		common.Must(err)
+
+		b := make([]byte, len("first payload"))
+		_, err = io.ReadFull(conn, b)
+		common.Must(err)
+		if string(b) != "first payload" {
+			t.Error("unexpected payload: ", string(b))
+		}
+		conn.Close()
+	}
+
+	badServer := protocol.NewServerSpec(net.DestinationFromAddr(listener.Addr()), protocol.AlwaysValid(), &protocol.MemoryUser{
+		Account: &Account{Username: "user", Password: "wrong-pass"},
+	})
+	if _, err := client.setUpHTTPTunnel(context.Background(), testDialer{}, badServer, target, nil); err == nil {
+		t.Error("nil error with wrong password")
+	}
+}
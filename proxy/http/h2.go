@@ -0,0 +1,915 @@
+// +build !confonly
+
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+
+	"v2ray.com/core/common/net"
+)
+
+const (
+	// settingEnableConnectProtocol is SETTINGS_ENABLE_CONNECT_PROTOCOL in RFC 8441.
+	settingEnableConnectProtocol http2.SettingID = 0x8
+
+	h2InitialWindowSize   = 65535
+	h2DefaultMaxFrameSize = 16384
+	// h2ReceiveWindowSize is the flow control window of this side, for the connection and each stream.
+	h2ReceiveWindowSize = 1 << 20
+	h2MaxWindowSize     = 1<<31 - 1
+	h2MaxHeaderSize     = 64 * 1024
+)
+
+var (
+	errH2StreamClosed = newError("HTTP/2 stream closed")
+	errH2ConnClosed   = newError("HTTP/2 connection closed")
+)
+
+// h2Conn is an HTTP/2 connection of tunnels, each of which is a stream opened by a CONNECT request. It implements
+// the parts of RFC 7540 that tunnels need, and the extended CONNECT of RFC 8441, whose :protocol pseudo-header is
+// rejected by golang.org/x/net/http2.
+type h2Conn struct {
+	conn     net.Conn
+	reader   io.Reader
+	framer   *http2.Framer
+	decoder  *hpack.Decoder
+	isServer bool
+	// onStream is called in a new goroutine for each stream opened by the client. Server only.
+	onStream    func(*h2Stream)
+	idleTimeout time.Duration
+	idleTimer   *time.Timer
+
+	// writeAccess serializes frames, and the state of encoder. It is locked before access if both are needed.
+	writeAccess sync.Mutex
+	encoder     *hpack.Encoder
+	headerBuf   bytes.Buffer
+
+	access           sync.Mutex
+	cond             *sync.Cond
+	streams          map[uint32]*h2Stream
+	nextStreamID     uint32
+	lastPeerStreamID uint32
+	sendWindow       int32
+	// recvWindow is the size of data that the peer may still send in the connection, and recvUpdate is the size of
+	// data read but not returned to the peer yet.
+	recvWindow       int32
+	recvUpdate       int32
+	peerWindowSize   int32
+	peerMaxFrameSize uint32
+	extendedConnect  bool
+	goingAway        bool
+	err              error
+
+	// The header block being read from HEADERS and CONTINUATION frames. Only used in readLoop.
+	headerStreamID  uint32
+	headerEndStream bool
+	headerBlock     []byte
+}
+
+func newH2Conn(conn net.Conn, reader io.Reader, idleTimeout time.Duration) *h2Conn {
+	c := &h2Conn{
+		conn:             conn,
+		reader:           reader,
+		framer:           http2.NewFramer(conn, reader),
+		decoder:          hpack.NewDecoder(4096, nil),
+		idleTimeout:      idleTimeout,
+		streams:          make(map[uint32]*h2Stream),
+		sendWindow:       h2InitialWindowSize,
+		recvWindow:       h2ReceiveWindowSize,
+		peerWindowSize:   h2InitialWindowSize,
+		peerMaxFrameSize: h2DefaultMaxFrameSize,
+	}
+	c.decoder.SetMaxStringLength(h2MaxHeaderSize)
+	c.encoder = hpack.NewEncoder(&c.headerBuf)
+	c.cond = sync.NewCond(&c.access)
+	c.idleTimer = time.AfterFunc(idleTimeout, c.closeIfIdle)
+	return c
+}
+
+// newH2ClientConn starts an HTTP/2 connection as the client. It returns after the settings of the server are
+// received.
+func newH2ClientConn(conn net.Conn, handshakeTimeout, idleTimeout time.Duration) (*h2Conn, error) {
+	c := newH2Conn(conn, conn, idleTimeout)
+	c.nextStreamID = 1
+	if err := c.handshake(handshakeTimeout); err != nil {
+		c.close(err)
+		return nil, err
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// serveH2 serves an HTTP/2 connection as the server, and calls onStream for each new stream. It returns when the
+// connection is closed. The client preface is read from reader.
+func serveH2(conn net.Conn, reader io.Reader, handshakeTimeout, idleTimeout time.Duration, onStream func(*h2Stream)) error {
+	c := newH2Conn(conn, reader, idleTimeout)
+	c.isServer = true
+	c.onStream = onStream
+	c.nextStreamID = 2
+	if err := c.handshake(handshakeTimeout); err != nil {
+		c.close(err)
+		return err
+	}
+	return c.readLoop()
+}
+
+// handshake exchanges the connection preface and settings.
+func (c *h2Conn) handshake(timeout time.Duration) error {
+	if err := c.conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		newError("failed to set read deadline").Base(err).AtDebug().WriteToLog()
+	}
+
+	settings := []http2.Setting{
+		{ID: http2.SettingInitialWindowSize, Val: h2ReceiveWindowSize},
+		{ID: http2.SettingMaxHeaderListSize, Val: h2MaxHeaderSize},
+	}
+	if c.isServer {
+		preface := make([]byte, len(http2.ClientPreface))
+		if _, err := io.ReadFull(c.reader, preface); err != nil {
+			return newError("failed to read HTTP/2 preface").Base(err)
+		}
+		if string(preface) != http2.ClientPreface {
+			return newError("invalid HTTP/2 preface")
+		}
+		settings = append(settings, http2.Setting{ID: settingEnableConnectProtocol, Val: 1})
+	} else {
+		if _, err := io.WriteString(c.conn, http2.ClientPreface); err != nil {
+			return newError("failed to write HTTP/2 preface").Base(err)
+		}
+		settings = append(settings, http2.Setting{ID: http2.SettingEnablePush, Val: 0})
+	}
+	if err := c.writeFrame(func() error {
+		if err := c.framer.WriteSettings(settings...); err != nil {
+			return err
+		}
+		return c.framer.WriteWindowUpdate(0, h2ReceiveWindowSize-h2InitialWindowSize)
+	}); err != nil {
+		return newError("failed to write HTTP/2 settings").Base(err)
+	}
+
+	// The first frame from the peer must be its settings.
+	frame, err := c.framer.ReadFrame()
+	if err != nil {
+		return newError("failed to read HTTP/2 settings").Base(err)
+	}
+	settingsFrame, ok := frame.(*http2.SettingsFrame)
+	if !ok || settingsFrame.IsAck() {
+		return newError("expected HTTP/2 settings, but got ", frame.Header().Type)
+	}
+	if err := c.handleSettings(settingsFrame); err != nil {
+		return err
+	}
+
+	if err := c.conn.SetReadDeadline(time.Time{}); err != nil {
+		newError("failed to clear read deadline").Base(err).AtDebug().WriteToLog()
+	}
+	return nil
+}
+
+func (c *h2Conn) writeFrame(write func() error) error {
+	c.writeAccess.Lock()
+	defer c.writeAccess.Unlock()
+
+	return write()
+}
+
+// writeHeaders writes a header block in HEADERS and CONTINUATION frames. Must be called with writeAccess locked.
+func (c *h2Conn) writeHeaders(streamID uint32, fields []hpack.HeaderField, endStream bool) error {
+	c.headerBuf.Reset()
+	for _, field := range fields {
+		if err := c.encoder.WriteField(field); err != nil {
+			return err
+		}
+	}
+	block := c.headerBuf.Bytes()
+	for first := true; first || len(block) > 0; first = false {
+		fragment := block
+		if len(fragment) > h2DefaultMaxFrameSize {
+			fragment = fragment[:h2DefaultMaxFrameSize]
+		}
+		block = block[len(fragment):]
+
+		var err error
+		if first {
+			err = c.framer.WriteHeaders(http2.HeadersFrameParam{
+				StreamID:      streamID,
+				BlockFragment: fragment,
+				EndStream:     endStream,
+				EndHeaders:    len(block) == 0,
+			})
+		} else {
+			err = c.framer.WriteContinuation(streamID, len(block) == 0, fragment)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *h2Conn) readLoop() error {
+	var err error
+	for err == nil {
+		var frame http2.Frame
+		frame, err = c.framer.ReadFrame()
+		switch e := err.(type) {
+		case nil:
+			err = c.handleFrame(frame)
+		case http2.StreamError:
+			err = c.resetStream(e.StreamID, e.Code)
+		}
+	}
+
+	if code, ok := err.(http2.ConnectionError); ok {
+		c.writeFrame(func() error { // nolint: errcheck
+			return c.framer.WriteGoAway(c.lastPeerStreamID, http2.ErrCode(code), nil)
+		})
+	}
+	c.close(err)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func (c *h2Conn) handleFrame(frame http2.Frame) error {
+	switch f := frame.(type) {
+	case *http2.SettingsFrame:
+		return c.handleSettings(f)
+	case *http2.HeadersFrame:
+		c.headerStreamID = f.StreamID
+		c.headerEndStream = f.StreamEnded()
+		c.headerBlock = append(c.headerBlock[:0], f.HeaderBlockFragment()...)
+		if f.HeadersEnded() {
+			return c.handleHeaders()
+		}
+	case *http2.ContinuationFrame:
+		c.headerBlock = append(c.headerBlock, f.HeaderBlockFragment()...)
+		if len(c.headerBlock) > h2MaxHeaderSize {
+			return http2.ConnectionError(http2.ErrCodeEnhanceYourCalm)
+		}
+		if f.HeadersEnded() {
+			return c.handleHeaders()
+		}
+	case *http2.DataFrame:
+		return c.handleData(f)
+	case *http2.WindowUpdateFrame:
+		return c.handleWindowUpdate(f)
+	case *http2.RSTStreamFrame:
+		var returned int32
+		c.access.Lock()
+		if s := c.streams[f.StreamID]; s != nil {
+			returned = c.resetStreamLocked(s, newError("HTTP/2 stream reset by peer: ", f.ErrCode))
+		}
+		c.access.Unlock()
+		return c.returnWindow(returned)
+	case *http2.PingFrame:
+		if !f.IsAck() {
+			return c.writeFrame(func() error {
+				return c.framer.WritePing(true, f.Data)
+			})
+		}
+	case *http2.GoAwayFrame:
+		var returned int32
+		c.access.Lock()
+		c.goingAway = true
+		for id, s := range c.streams {
+			if id > f.LastStreamID && (id%2 == 1) != c.isServer {
+				returned += c.resetStreamLocked(s, newError("HTTP/2 stream refused by GOAWAY: ", f.ErrCode))
+			}
+		}
+		c.access.Unlock()
+		return c.returnWindow(returned)
+	}
+	return nil
+}
+
+func (c *h2Conn) handleWindowUpdate(f *http2.WindowUpdateFrame) error {
+	c.access.Lock()
+	if f.StreamID == 0 {
+		if int64(c.sendWindow)+int64(f.Increment) > h2MaxWindowSize {
+			c.access.Unlock()
+			return http2.ConnectionError(http2.ErrCodeFlowControl)
+		}
+		c.sendWindow += int32(f.Increment)
+		c.cond.Broadcast()
+		c.access.Unlock()
+		return nil
+	}
+
+	s := c.streams[f.StreamID]
+	if s == nil {
+		c.access.Unlock()
+		return nil
+	}
+	if int64(s.sendWindow)+int64(f.Increment) > h2MaxWindowSize {
+		returned := c.resetStreamLocked(s, newError("HTTP/2 stream window overflows"))
+		c.access.Unlock()
+		return c.writeReset(f.StreamID, http2.ErrCodeFlowControl, returned)
+	}
+	s.sendWindow += int32(f.Increment)
+	c.cond.Broadcast()
+	c.access.Unlock()
+	return nil
+}
+
+func (c *h2Conn) handleSettings(f *http2.SettingsFrame) error {
+	if f.IsAck() {
+		return nil
+	}
+
+	var tableSize uint32
+	hasTableSize := false
+	c.access.Lock()
+	err := f.ForeachSetting(func(s http2.Setting) error {
+		if err := s.Valid(); err != nil {
+			return err
+		}
+		switch s.ID {
+		case http2.SettingHeaderTableSize:
+			tableSize, hasTableSize = s.Val, true
+		case http2.SettingInitialWindowSize:
+			delta := int32(s.Val) - c.peerWindowSize
+			for _, stream := range c.streams {
+				if int64(stream.sendWindow)+int64(delta) > h2MaxWindowSize {
+					return http2.ConnectionError(http2.ErrCodeFlowControl)
+				}
+			}
+			c.peerWindowSize = int32(s.Val)
+			for _, stream := range c.streams {
+				stream.sendWindow += delta
+			}
+		case http2.SettingMaxFrameSize:
+			c.peerMaxFrameSize = s.Val
+		case settingEnableConnectProtocol:
+			c.extendedConnect = s.Val == 1
+		}
+		return nil
+	})
+	c.cond.Broadcast()
+	c.access.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.writeFrame(func() error {
+		if hasTableSize {
+			c.encoder.SetMaxDynamicTableSizeLimit(tableSize)
+		}
+		return c.framer.WriteSettingsAck()
+	})
+}
+
+func (c *h2Conn) handleHeaders() error {
+	id, endStream := c.headerStreamID, c.headerEndStream
+	fields, err := c.decoder.DecodeFull(c.headerBlock)
+	if err != nil {
+		return http2.ConnectionError(http2.ErrCodeCompression)
+	}
+
+	c.access.Lock()
+	s := c.streams[id]
+	if s == nil {
+		isNew := c.isServer && id%2 == 1 && id > c.lastPeerStreamID
+		if isNew {
+			c.lastPeerStreamID = id
+		}
+		if !isNew || c.goingAway || c.err != nil {
+			// Headers of a closed stream, such as trailers after the stream is reset, are ignored.
+			c.access.Unlock()
+			return nil
+		}
+		s = c.newStreamLocked(id)
+		s.header = fields
+		s.hasHeader = true
+		s.recvClosed = endStream
+		c.access.Unlock()
+
+		go c.onStream(s)
+		return nil
+	}
+
+	if !s.hasHeader {
+		// Informational responses are skipped.
+		if status := headerValue(fields, ":status"); len(status) == 3 && status[0] == '1' && !endStream {
+			c.access.Unlock()
+			return nil
+		}
+		s.header = fields
+		s.hasHeader = true
+	}
+	if endStream {
+		s.recvClosed = true
+		if s.sendClosed {
+			c.removeStreamLocked(s)
+		}
+	}
+	c.cond.Broadcast()
+	c.access.Unlock()
+	return nil
+}
+
+func (c *h2Conn) handleData(f *http2.DataFrame) error {
+	// Flow control counts the whole payload, including padding.
+	size := int32(f.Header().Length)
+	padding := size - int32(len(f.Data()))
+
+	c.access.Lock()
+	if size > c.recvWindow {
+		c.access.Unlock()
+		return http2.ConnectionError(http2.ErrCodeFlowControl)
+	}
+	c.recvWindow -= size
+
+	s := c.streams[f.StreamID]
+	if s == nil || s.recvClosed {
+		// The data is discarded, so its window of the connection is returned at once.
+		c.recvWindow += size
+		c.access.Unlock()
+		return c.returnWindow(size)
+	}
+	if size > s.recvWindow {
+		c.recvWindow += size
+		returned := c.resetStreamLocked(s, newError("HTTP/2 stream exceeds its flow control window")) + size
+		c.access.Unlock()
+		return c.writeReset(f.StreamID, http2.ErrCodeFlowControl, returned)
+	}
+	s.recvWindow -= size
+	s.recvBuf.Write(f.Data())
+	// Padding is never read, so its window is returned at once.
+	s.recvWindow += padding
+	c.recvWindow += padding
+	if f.StreamEnded() {
+		s.recvClosed = true
+		if s.sendClosed {
+			c.removeStreamLocked(s)
+		}
+	}
+	c.cond.Broadcast()
+	c.access.Unlock()
+
+	if padding > 0 {
+		return c.writeFrame(func() error {
+			if err := c.framer.WriteWindowUpdate(0, uint32(padding)); err != nil {
+				return err
+			}
+			return c.framer.WriteWindowUpdate(f.StreamID, uint32(padding))
+		})
+	}
+	return nil
+}
+
+// resetStream resets a stream on a stream error.
+func (c *h2Conn) resetStream(id uint32, code http2.ErrCode) error {
+	var returned int32
+	c.access.Lock()
+	if s := c.streams[id]; s != nil {
+		returned = c.resetStreamLocked(s, newError("HTTP/2 stream error: ", code))
+	}
+	c.access.Unlock()
+	return c.writeReset(id, code, returned)
+}
+
+// writeReset writes RST_STREAM of a stream, and returns the window of its discarded data to the connection.
+func (c *h2Conn) writeReset(id uint32, code http2.ErrCode, returned int32) error {
+	return c.writeFrame(func() error {
+		if err := c.framer.WriteRSTStream(id, code); err != nil {
+			return err
+		}
+		if returned > 0 {
+			return c.framer.WriteWindowUpdate(0, uint32(returned))
+		}
+		return nil
+	})
+}
+
+// returnWindow returns the window of discarded data to the connection.
+func (c *h2Conn) returnWindow(returned int32) error {
+	if returned <= 0 {
+		return nil
+	}
+	return c.writeFrame(func() error {
+		return c.framer.WriteWindowUpdate(0, uint32(returned))
+	})
+}
+
+// newStreamLocked creates a stream with the given ID. Must be called with access locked.
+func (c *h2Conn) newStreamLocked(id uint32) *h2Stream {
+	s := &h2Stream{
+		conn:       c,
+		id:         id,
+		sendWindow: c.peerWindowSize,
+		recvWindow: h2ReceiveWindowSize,
+	}
+	c.streams[id] = s
+	c.idleTimer.Stop()
+	return s
+}
+
+// removeStreamLocked removes a stream that is closed in both directions, or reset. Must be called with access
+// locked.
+func (c *h2Conn) removeStreamLocked(s *h2Stream) {
+	if c.streams[s.id] == s {
+		delete(c.streams, s.id)
+		if len(c.streams) == 0 {
+			c.idleTimer.Reset(c.idleTimeout)
+		}
+	}
+	c.cond.Broadcast()
+}
+
+// resetStreamLocked ends a stream with err, and removes it. Its unread data is discarded, and the size of the data
+// is returned, which must be given back to the connection window with returnWindow. Must be called with access
+// locked.
+func (c *h2Conn) resetStreamLocked(s *h2Stream, err error) int32 {
+	s.err = err
+	returned := int32(s.recvBuf.Len())
+	s.recvBuf.Reset()
+	c.recvWindow += returned
+	c.removeStreamLocked(s)
+	return returned
+}
+
+func (c *h2Conn) closeIfIdle() {
+	c.access.Lock()
+	idle := len(c.streams) == 0
+	c.access.Unlock()
+
+	if idle {
+		c.close(newError("HTTP/2 connection is idle"))
+	}
+}
+
+// canTakeNewStream returns true if new streams can be opened in this connection.
+func (c *h2Conn) canTakeNewStream() bool {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	return c.err == nil && !c.goingAway && c.nextStreamID < 1<<31
+}
+
+// supportsExtendedConnect returns true if the server allows extended CONNECT.
+func (c *h2Conn) supportsExtendedConnect() bool {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	return c.extendedConnect
+}
+
+// openStream sends a request in a new stream, and waits for the response headers until timeout.
+func (c *h2Conn) openStream(fields []hpack.HeaderField, timeout time.Duration) (*h2Stream, error) {
+	c.writeAccess.Lock()
+	c.access.Lock()
+	if c.err != nil || c.goingAway {
+		c.access.Unlock()
+		c.writeAccess.Unlock()
+		return nil, errH2ConnClosed
+	}
+	s := c.newStreamLocked(c.nextStreamID)
+	c.nextStreamID += 2
+	c.access.Unlock()
+	err := c.writeHeaders(s.id, fields, false)
+	c.writeAccess.Unlock()
+	if err != nil {
+		c.close(err)
+		return nil, newError("failed to write HTTP/2 headers").Base(err)
+	}
+
+	timedOut := false
+	timer := time.AfterFunc(timeout, func() {
+		c.access.Lock()
+		timedOut = true
+		c.cond.Broadcast()
+		c.access.Unlock()
+	})
+	defer timer.Stop()
+
+	c.access.Lock()
+	for !s.hasHeader && s.err == nil && !timedOut {
+		c.cond.Wait()
+	}
+	hasHeader, err := s.hasHeader, s.err
+	c.access.Unlock()
+
+	if !hasHeader {
+		s.Close() // nolint: errcheck
+		if err == nil {
+			err = newError("timeout waiting for HTTP/2 response")
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (c *h2Conn) close(err error) {
+	c.access.Lock()
+	if c.err != nil {
+		c.access.Unlock()
+		return
+	}
+	c.err = newError("HTTP/2 connection closed").Base(err)
+	for _, s := range c.streams {
+		if s.err == nil {
+			s.err = c.err
+		}
+	}
+	c.idleTimer.Stop()
+	c.cond.Broadcast()
+	c.access.Unlock()
+
+	c.conn.Close() // nolint: errcheck
+}
+
+// h2Stream is a tunnel in an HTTP/2 stream.
+type h2Stream struct {
+	conn *h2Conn
+	id   uint32
+
+	// The fields below are guarded by conn.access.
+	header     []hpack.HeaderField
+	hasHeader  bool
+	sendWindow int32
+	recvWindow int32
+	recvUpdate int32
+	recvBuf    bytes.Buffer
+	recvClosed bool
+	sendClosed bool
+	err        error
+
+	readDeadline  time.Time
+	readTimer     *time.Timer
+	writeDeadline time.Time
+	writeTimer    *time.Timer
+}
+
+// Read implements net.Conn.
+func (s *h2Stream) Read(b []byte) (int, error) {
+	c := s.conn
+	c.access.Lock()
+	for s.recvBuf.Len() == 0 && !s.recvClosed && s.err == nil && !deadlineExceeded(s.readDeadline) {
+		c.cond.Wait()
+	}
+	if deadlineExceeded(s.readDeadline) {
+		c.access.Unlock()
+		return 0, h2TimeoutError{}
+	}
+	if s.recvBuf.Len() == 0 {
+		err := s.err
+		if s.recvClosed {
+			err = io.EOF
+		}
+		c.access.Unlock()
+		return 0, err
+	}
+
+	n, _ := s.recvBuf.Read(b)
+	// Windows are returned in batches, so that there is no WINDOW_UPDATE for every read.
+	var streamUpdate, connUpdate int32
+	s.recvUpdate += int32(n)
+	if s.recvUpdate >= h2ReceiveWindowSize/4 && !s.recvClosed {
+		streamUpdate, s.recvUpdate = s.recvUpdate, 0
+		s.recvWindow += streamUpdate
+	}
+	c.recvUpdate += int32(n)
+	if c.recvUpdate >= h2ReceiveWindowSize/4 {
+		connUpdate, c.recvUpdate = c.recvUpdate, 0
+		c.recvWindow += connUpdate
+	}
+	c.access.Unlock()
+
+	if streamUpdate > 0 || connUpdate > 0 {
+		c.writeFrame(func() error { // nolint: errcheck
+			if streamUpdate > 0 {
+				if err := c.framer.WriteWindowUpdate(s.id, uint32(streamUpdate)); err != nil {
+					return err
+				}
+			}
+			if connUpdate > 0 {
+				return c.framer.WriteWindowUpdate(0, uint32(connUpdate))
+			}
+			return nil
+		})
+	}
+	return n, nil
+}
+
+// Write implements net.Conn.
+func (s *h2Stream) Write(b []byte) (int, error) {
+	c := s.conn
+	written := 0
+	for written < len(b) {
+		c.access.Lock()
+		for (s.sendWindow <= 0 || c.sendWindow <= 0) && s.err == nil && !s.sendClosed && !deadlineExceeded(s.writeDeadline) {
+			c.cond.Wait()
+		}
+		if deadlineExceeded(s.writeDeadline) {
+			c.access.Unlock()
+			return written, h2TimeoutError{}
+		}
+		if s.err != nil || s.sendClosed {
+			err := s.err
+			if err == nil {
+				err = errH2StreamClosed
+			}
+			c.access.Unlock()
+			return written, err
+		}
+		n := int32(len(b) - written)
+		if n > s.sendWindow {
+			n = s.sendWindow
+		}
+		if n > c.sendWindow {
+			n = c.sendWindow
+		}
+		if n > int32(c.peerMaxFrameSize) {
+			n = int32(c.peerMaxFrameSize)
+		}
+		s.sendWindow -= n
+		c.sendWindow -= n
+		c.access.Unlock()
+
+		if err := c.writeFrame(func() error {
+			return c.framer.WriteData(s.id, false, b[written:written+int(n)])
+		}); err != nil {
+			return written, err
+		}
+		written += int(n)
+	}
+	return written, nil
+}
+
+// CloseWrite ends the stream in the direction to the peer.
+func (s *h2Stream) CloseWrite() error {
+	c := s.conn
+	c.access.Lock()
+	if s.sendClosed || s.err != nil {
+		c.access.Unlock()
+		return nil
+	}
+	s.sendClosed = true
+	if s.recvClosed {
+		c.removeStreamLocked(s)
+	}
+	c.access.Unlock()
+
+	return c.writeFrame(func() error {
+		return c.framer.WriteData(s.id, true, nil)
+	})
+}
+
+// Close implements net.Conn. The stream is reset if it is not ended by the peer yet, and unread data is discarded.
+// The connection is not closed.
+func (s *h2Stream) Close() error {
+	c := s.conn
+	c.access.Lock()
+	if s.err != nil {
+		c.access.Unlock()
+		return nil
+	}
+	reset := !s.recvClosed
+	endStream := !reset && !s.sendClosed
+	s.sendClosed = true
+	returned := c.resetStreamLocked(s, errH2StreamClosed)
+	c.access.Unlock()
+
+	switch {
+	case reset:
+		return c.writeReset(s.id, http2.ErrCodeCancel, returned)
+	case endStream:
+		return c.writeFrame(func() error {
+			if err := c.framer.WriteData(s.id, true, nil); err != nil {
+				return err
+			}
+			if returned > 0 {
+				return c.framer.WriteWindowUpdate(0, uint32(returned))
+			}
+			return nil
+		})
+	}
+	return c.returnWindow(returned)
+}
+
+// writeResponse writes the response headers of a request.
+func (s *h2Stream) writeResponse(status int, header http.Header, endStream bool) error {
+	fields := []hpack.HeaderField{{Name: ":status", Value: strconv.Itoa(status)}}
+	for key, values := range header {
+		for _, value := range values {
+			fields = append(fields, hpack.HeaderField{Name: strings.ToLower(key), Value: value})
+		}
+	}
+
+	c := s.conn
+	c.writeAccess.Lock()
+	err := c.writeHeaders(s.id, fields, endStream)
+	c.writeAccess.Unlock()
+	if err != nil || !endStream {
+		return err
+	}
+
+	c.access.Lock()
+	s.sendClosed = true
+	if s.recvClosed {
+		c.removeStreamLocked(s)
+	}
+	c.access.Unlock()
+	return nil
+}
+
+// pseudoHeader returns the value of a pseudo-header, such as ":method", in the headers from the peer.
+func (s *h2Stream) pseudoHeader(name string) string {
+	return headerValue(s.header, name)
+}
+
+// httpHeader returns the regular headers from the peer.
+func (s *h2Stream) httpHeader() http.Header {
+	header := make(http.Header)
+	for _, field := range s.header {
+		if !strings.HasPrefix(field.Name, ":") {
+			header.Add(field.Name, field.Value)
+		}
+	}
+	return header
+}
+
+func (s *h2Stream) LocalAddr() net.Addr {
+	return s.conn.conn.LocalAddr()
+}
+
+func (s *h2Stream) RemoteAddr() net.Addr {
+	return s.conn.conn.RemoteAddr()
+}
+
+// SetDeadline implements net.Conn.
+func (s *h2Stream) SetDeadline(t time.Time) error {
+	s.conn.access.Lock()
+	defer s.conn.access.Unlock()
+
+	s.readDeadline, s.readTimer = t, s.wakeUpAtLocked(s.readTimer, t)
+	s.writeDeadline, s.writeTimer = t, s.wakeUpAtLocked(s.writeTimer, t)
+	return nil
+}
+
+// SetReadDeadline implements net.Conn.
+func (s *h2Stream) SetReadDeadline(t time.Time) error {
+	s.conn.access.Lock()
+	defer s.conn.access.Unlock()
+
+	s.readDeadline, s.readTimer = t, s.wakeUpAtLocked(s.readTimer, t)
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.
+func (s *h2Stream) SetWriteDeadline(t time.Time) error {
+	s.conn.access.Lock()
+	defer s.conn.access.Unlock()
+
+	s.writeDeadline, s.writeTimer = t, s.wakeUpAtLocked(s.writeTimer, t)
+	return nil
+}
+
+// wakeUpAtLocked replaces timer with a new one, which wakes up blocked reads and writes at the deadline. Must be
+// called with access locked.
+func (s *h2Stream) wakeUpAtLocked(timer *time.Timer, deadline time.Time) *time.Timer {
+	if timer != nil {
+		timer.Stop()
+	}
+	c := s.conn
+	c.cond.Broadcast()
+	if deadline.IsZero() {
+		return nil
+	}
+	return time.AfterFunc(time.Until(deadline), func() {
+		c.access.Lock()
+		c.cond.Broadcast()
+		c.access.Unlock()
+	})
+}
+
+func deadlineExceeded(deadline time.Time) bool {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
+
+// h2TimeoutError is returned by reads and writes of a stream after the deadline.
+type h2TimeoutError struct{}
+
+func (h2TimeoutError) Error() string   { return "i/o timeout" }
+func (h2TimeoutError) Timeout() bool   { return true }
+func (h2TimeoutError) Temporary() bool { return true }
+
+func headerValue(fields []hpack.HeaderField, name string) string {
+	for _, field := range fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
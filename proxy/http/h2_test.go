@@ -0,0 +1,363 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/features/policy"
+)
+
+// listenH2 accepts one connection, and serves it with serve.
+func listenH2(serve func(net.Conn)) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		serve(conn)
+	}()
+	return listener
+}
+
+func dialH2(listener net.Listener) *h2Conn {
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	client, err := newH2ClientConn(conn, time.Second*4, time.Minute)
+	common.Must(err)
+	return client
+}
+
+// handleTestStream accepts extended CONNECT of websocket. It echoes data for www.v2ray.com:443, sends data without
+// reading for flood.v2ray.com:443, and resets the stream for reset.v2ray.com:443.
+func handleTestStream(stream *h2Stream) {
+	defer stream.Close()
+
+	if stream.pseudoHeader(":method") != "CONNECT" || stream.pseudoHeader(":protocol") != "websocket" {
+		stream.writeResponse(400, nil, true) // nolint: errcheck
+		return
+	}
+	switch stream.pseudoHeader(":authority") {
+	case "www.v2ray.com:443":
+		common.Must(stream.writeResponse(200, nil, false))
+		io.Copy(stream, stream) // nolint: errcheck
+		stream.CloseWrite()     // nolint: errcheck
+	case "flood.v2ray.com:443":
+		common.Must(stream.writeResponse(200, nil, false))
+		b := make([]byte, 16*1024)
+		for {
+			if _, err := stream.Write(b); err != nil {
+				return
+			}
+		}
+	case "reset.v2ray.com:443":
+		common.Must(stream.writeResponse(200, nil, false))
+	default:
+		stream.writeResponse(400, nil, true) // nolint: errcheck
+	}
+}
+
+func extendedConnect(host string) []hpack.HeaderField {
+	return []hpack.HeaderField{
+		{Name: ":method", Value: "CONNECT"},
+		{Name: ":protocol", Value: "websocket"},
+		{Name: ":scheme", Value: "https"},
+		{Name: ":path", Value: "/"},
+		{Name: ":authority", Value: host},
+	}
+}
+
+func listenTestH2() net.Listener {
+	return listenH2(func(conn net.Conn) {
+		serveH2(conn, conn, time.Second*4, time.Minute, handleTestStream) // nolint: errcheck
+	})
+}
+
+// checkH2Echo sends payload in the stream, and checks the echo.
+func checkH2Echo(t *testing.T, stream *h2Stream, size int) {
+	t.Helper()
+
+	payload := make([]byte, size)
+	common.Must2(rand.Read(payload))
+	go func() {
+		common.Must2(stream.Write(payload))
+		common.Must(stream.CloseWrite())
+	}()
+
+	response, err := ioutil.ReadAll(stream)
+	common.Must(err)
+	if !bytes.Equal(response, payload) {
+		t.Error("unexpected response of ", len(response), " bytes")
+	}
+}
+
+func TestH2ExtendedConnect(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialH2(listener)
+	defer client.close(nil)
+
+	if !client.supportsExtendedConnect() {
+		t.Fatal("extended CONNECT is not advertised")
+	}
+
+	stream, err := client.openStream(extendedConnect("www.v2ray.com:443"), time.Second*4)
+	common.Must(err)
+	defer stream.Close()
+	if status := stream.pseudoHeader(":status"); status != "200" {
+		t.Fatal("unexpected status: ", status)
+	}
+
+	// More than the flow control windows.
+	checkH2Echo(t, stream, 3*h2ReceiveWindowSize)
+}
+
+func TestH2ConcurrentStreams(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialH2(listener)
+	defer client.close(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			stream, err := client.openStream(extendedConnect("www.v2ray.com:443"), time.Second*4)
+			common.Must(err)
+			defer stream.Close()
+			checkH2Echo(t, stream, h2ReceiveWindowSize/2)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestH2CloseReturnsConnectionWindow(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialH2(listener)
+	defer client.close(nil)
+
+	// The unread data of the stream fills the window of the connection.
+	flood, err := client.openStream(extendedConnect("flood.v2ray.com:443"), time.Second*4)
+	common.Must(err)
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		client.access.Lock()
+		full := client.recvWindow == 0
+		client.access.Unlock()
+		if full {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("connection window is not used up")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	common.Must(flood.Close())
+
+	stream, err := client.openStream(extendedConnect("www.v2ray.com:443"), time.Second*4)
+	common.Must(err)
+	defer stream.Close()
+	common.Must(stream.SetDeadline(time.Now().Add(time.Second * 5)))
+	checkH2Echo(t, stream, h2ReceiveWindowSize)
+}
+
+func TestH2StreamResetByPeer(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialH2(listener)
+	defer client.close(nil)
+
+	stream, err := client.openStream(extendedConnect("reset.v2ray.com:443"), time.Second*4)
+	common.Must(err)
+	defer stream.Close()
+
+	if _, err := ioutil.ReadAll(stream); err == nil {
+		t.Error("nil error from reset stream")
+	}
+	if _, err := stream.Write([]byte("v2ray")); err == nil {
+		t.Error("nil error from reset stream")
+	}
+}
+
+func TestH2StreamDeadline(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialH2(listener)
+	defer client.close(nil)
+
+	stream, err := client.openStream(extendedConnect("www.v2ray.com:443"), time.Second*4)
+	common.Must(err)
+	defer stream.Close()
+
+	common.Must(stream.SetReadDeadline(time.Now().Add(time.Millisecond * 100)))
+	_, err = stream.Read(make([]byte, 1))
+	if nerr, ok := err.(net.Error); !ok || !nerr.Timeout() {
+		t.Fatal("expected timeout error, but got ", err)
+	}
+
+	// The stream works again without deadline.
+	common.Must(stream.SetReadDeadline(time.Time{}))
+	checkH2Echo(t, stream, 1024)
+}
+
+// rawH2Client writes frames directly, regardless of flow control.
+type rawH2Client struct {
+	conn      net.Conn
+	framer    *http2.Framer
+	headerBuf bytes.Buffer
+}
+
+func dialRawH2(listener net.Listener) *rawH2Client {
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	common.Must(conn.SetDeadline(time.Now().Add(time.Second * 10)))
+	common.Must2(io.WriteString(conn, http2.ClientPreface))
+	c := &rawH2Client{
+		conn:   conn,
+		framer: http2.NewFramer(conn, conn),
+	}
+	common.Must(c.framer.WriteSettings())
+	return c
+}
+
+func (c *rawH2Client) openStream(id uint32, fields []hpack.HeaderField) {
+	c.headerBuf.Reset()
+	encoder := hpack.NewEncoder(&c.headerBuf)
+	for _, field := range fields {
+		common.Must(encoder.WriteField(field))
+	}
+	common.Must(c.framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      id,
+		BlockFragment: c.headerBuf.Bytes(),
+		EndHeaders:    true,
+	}))
+}
+
+// waitFrame reads frames until a frame of the type and stream, and returns its error code.
+func (c *rawH2Client) waitFrame(t *testing.T, frameType http2.FrameType, streamID uint32) http2.ErrCode {
+	t.Helper()
+
+	for {
+		frame, err := c.framer.ReadFrame()
+		if err != nil {
+			t.Fatal("failed to read ", frameType, ": ", err)
+		}
+		if frame.Header().Type != frameType || frame.Header().StreamID != streamID {
+			continue
+		}
+		switch f := frame.(type) {
+		case *http2.RSTStreamFrame:
+			return f.ErrCode
+		case *http2.GoAwayFrame:
+			return f.ErrCode
+		}
+		return http2.ErrCodeNo
+	}
+}
+
+func TestH2ReceiveWindowExceeded(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialRawH2(listener)
+	defer client.conn.Close()
+
+	client.openStream(1, extendedConnect("flood.v2ray.com:443"))
+	client.waitFrame(t, http2.FrameHeaders, 1)
+	b := make([]byte, h2DefaultMaxFrameSize)
+	for i := 0; i <= h2ReceiveWindowSize/len(b); i++ {
+		if err := client.framer.WriteData(1, false, b); err != nil {
+			break
+		}
+	}
+	if code := client.waitFrame(t, http2.FrameGoAway, 0); code != http2.ErrCodeFlowControl {
+		t.Error("unexpected error code: ", code)
+	}
+}
+
+func TestH2SendWindowOverflow(t *testing.T) {
+	listener := listenTestH2()
+	defer listener.Close()
+	client := dialRawH2(listener)
+	defer client.conn.Close()
+
+	client.openStream(1, extendedConnect("www.v2ray.com:443"))
+	client.waitFrame(t, http2.FrameHeaders, 1)
+	common.Must(client.framer.WriteWindowUpdate(1, h2MaxWindowSize))
+	if code := client.waitFrame(t, http2.FrameRSTStream, 1); code != http2.ErrCodeFlowControl {
+		t.Error("unexpected error code of stream: ", code)
+	}
+
+	common.Must(client.framer.WriteWindowUpdate(0, h2MaxWindowSize))
+	if code := client.waitFrame(t, http2.FrameGoAway, 0); code != http2.ErrCodeFlowControl {
+		t.Error("unexpected error code of connection: ", code)
+	}
+}
+
+func TestH2ServerRejectsMalformedConnect(t *testing.T) {
+	s := &Server{
+		config:        &ServerConfig{},
+		policyManager: policy.DefaultManager{},
+	}
+	listener := listenH2(func(conn net.Conn) {
+		s.serveHTTP2(context.Background(), conn, bufio.NewReader(conn), nil) // nolint: errcheck
+	})
+	defer listener.Close()
+	client := dialH2(listener)
+	defer client.close(nil)
+
+	for _, test := range []struct {
+		fields []hpack.HeaderField
+		status string
+	}{
+		{
+			fields: []hpack.HeaderField{
+				{Name: ":method", Value: "GET"},
+				{Name: ":protocol", Value: "websocket"},
+				{Name: ":scheme", Value: "https"},
+				{Name: ":path", Value: "/"},
+				{Name: ":authority", Value: "www.v2ray.com:443"},
+			},
+			status: "400",
+		},
+		{
+			fields: []hpack.HeaderField{
+				{Name: ":method", Value: "CONNECT"},
+				{Name: ":protocol", Value: "websocket"},
+				{Name: ":authority", Value: "www.v2ray.com:443"},
+			},
+			status: "400",
+		},
+		{
+			fields: []hpack.HeaderField{
+				{Name: ":method", Value: "GET"},
+				{Name: ":scheme", Value: "https"},
+				{Name: ":path", Value: "/"},
+				{Name: ":authority", Value: "www.v2ray.com"},
+			},
+			status: "405",
+		},
+	} {
+		stream, err := client.openStream(test.fields, time.Second*4)
+		common.Must(err)
+		if status := stream.pseudoHeader(":status"); status != test.status {
+			t.Error("expected status ", test.status, ", but got ", status)
+		}
+		stream.Close()
+	}
+}
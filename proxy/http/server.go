@@ -99,6 +99,11 @@ Start:
 		newError("failed to set read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 
+	// HTTP/2 connections start with the client preface, "PRI * HTTP/2.0".
+	if prefix, err := reader.Peek(4); err == nil && string(prefix) == "PRI " {
+		return s.serveHTTP2(ctx, conn, reader, dispatcher)
+	}
+
 	request, err := http.ReadRequest(reader)
 	if err != nil {
 		trace := newError("failed to read http request").Base(err)
@@ -213,6 +218,104 @@ func (s *Server) handleConnect(ctx context.Context, request *http.Request, reade
 	return nil
 }
 
+// serveHTTP2 serves CONNECT requests in an HTTP/2 connection. Each request is a tunnel in its own stream. Besides
+// the CONNECT of RFC 7540, the extended CONNECT of RFC 8441 is accepted, with the target in :authority.
+func (s *Server) serveHTTP2(ctx context.Context, conn internet.Connection, reader *bufio.Reader, dispatcher routing.Dispatcher) error {
+	plcy := s.policy()
+	return serveH2(conn, reader, plcy.Timeouts.Handshake, plcy.Timeouts.ConnectionIdle, func(stream *h2Stream) {
+		ctx := session.ContextWithID(ctx, session.NewID())
+		if err := s.handleHTTP2Stream(ctx, stream, dispatcher); err != nil {
+			newError("failed to handle HTTP/2 request").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		}
+	})
+}
+
+func (s *Server) handleHTTP2Stream(ctx context.Context, stream *h2Stream, dispatcher routing.Dispatcher) error {
+	defer stream.Close() // nolint: errcheck
+
+	if len(s.config.Accounts) > 0 {
+		user, pass, ok := parseBasicAuth(stream.httpHeader().Get("Proxy-Authorization"))
+		if !ok || !s.config.HasAccount(user, pass) {
+			return stream.writeResponse(http.StatusProxyAuthRequired, http.Header{"Proxy-Authenticate": {"Basic realm=\"proxy\""}}, true)
+		}
+	}
+
+	method := stream.pseudoHeader(":method")
+	protocol := stream.pseudoHeader(":protocol")
+	if method != http.MethodConnect {
+		if protocol != "" {
+			stream.writeResponse(http.StatusBadRequest, nil, true) // nolint: errcheck
+			return newError("unexpected :protocol in ", method, " request")
+		}
+		stream.writeResponse(http.StatusMethodNotAllowed, nil, true) // nolint: errcheck
+		return newError("unsupported method in HTTP/2: ", method)
+	}
+	// Extended CONNECT carries :scheme and :path, which plain CONNECT must not have.
+	if (protocol != "") != (stream.pseudoHeader(":scheme") != "" && stream.pseudoHeader(":path") != "") {
+		stream.writeResponse(http.StatusBadRequest, nil, true) // nolint: errcheck
+		return newError("malformed CONNECT request")
+	}
+
+	host := stream.pseudoHeader(":authority")
+	dest, err := http_proto.ParseHost(host, net.Port(80))
+	if err != nil {
+		stream.writeResponse(http.StatusBadRequest, nil, true) // nolint: errcheck
+		return newError("malformed proxy host: ", host).AtWarning().Base(err)
+	}
+	if protocol != "" {
+		newError("request to Method [", method, "] Protocol [", protocol, "] Host [", host, "] in HTTP/2").WriteToLog(session.ExportIDToError(ctx))
+	} else {
+		newError("request to Method [", method, "] Host [", host, "] in HTTP/2").WriteToLog(session.ExportIDToError(ctx))
+	}
+	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
+		From:   stream.RemoteAddr(),
+		To:     dest,
+		Status: log.AccessAccepted,
+		Reason: "",
+	})
+
+	plcy := s.policy()
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
+
+	ctx = policy.ContextWithBufferPolicy(ctx, plcy.Buffer)
+	link, err := dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		stream.writeResponse(http.StatusServiceUnavailable, nil, true) // nolint: errcheck
+		return err
+	}
+
+	if err := stream.writeResponse(http.StatusOK, nil, false); err != nil {
+		common.Interrupt(link.Reader)
+		common.Interrupt(link.Writer)
+		return newError("failed to write back OK response").Base(err)
+	}
+
+	requestDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
+
+		return buf.Copy(buf.NewReader(stream), link.Writer, buf.UpdateActivity(timer))
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.UplinkOnly)
+
+		if err := buf.Copy(link.Reader, buf.NewWriter(stream), buf.UpdateActivity(timer)); err != nil {
+			return err
+		}
+		return stream.CloseWrite()
+	}
+
+	var closeWriter = task.OnSuccess(requestDone, task.Close(link.Writer))
+	if err := task.Run(ctx, closeWriter, responseDone); err != nil {
+		common.Interrupt(link.Reader)
+		common.Interrupt(link.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
 var errWaitAnother = newError("keep alive")
 
 func (s *Server) handlePlainHTTP(ctx context.Context, request *http.Request, writer io.Writer, dest net.Destination, dispatcher routing.Dispatcher) error {
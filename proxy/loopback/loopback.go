@@ -0,0 +1,106 @@
+// +build !confonly
+
+// Package loopback is an outbound handler that dispatches connections again under another inbound tag, so that they
+// are routed by rules for that inbound.
+package loopback
+
+//go:generate errorgen
+
+import (
+	"context"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/internet"
+)
+
+// Loopback is an outbound handler that hands connections back to the dispatcher.
+type Loopback struct {
+	inboundTag string
+	dispatcher routing.Dispatcher
+}
+
+// Init initializes the Loopback with the given config and dispatcher.
+func (l *Loopback) Init(config *Config, dispatcher routing.Dispatcher) error {
+	if len(config.InboundTag) == 0 {
+		return newError("inbound tag is not specified")
+	}
+	l.inboundTag = config.InboundTag
+	l.dispatcher = dispatcher
+	return nil
+}
+
+// Process implements proxy.Outbound.
+func (l *Loopback) Process(ctx context.Context, link *transport.Link, _ internet.Dialer) error {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil || !outbound.Target.IsValid() {
+		return newError("target not specified.")
+	}
+	destination := outbound.Target
+
+	// Connections are dispatched under each inbound tag at most once, otherwise they may loop forever.
+	tags := session.LoopbackTagsFromContext(ctx)
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil {
+		inbound = new(session.Inbound)
+	}
+	tags = append(tags[:len(tags):len(tags)], inbound.Tag)
+	for _, tag := range tags {
+		if tag == l.inboundTag {
+			return newError("loop detected for inbound tag ", l.inboundTag, " to ", destination)
+		}
+	}
+
+	newError("looping back to ", destination, " with inbound tag ", l.inboundTag).WriteToLog(session.ExportIDToError(ctx))
+
+	ctx = session.ContextWithLoopbackTags(ctx, tags)
+	ctx = session.ContextWithInbound(ctx, &session.Inbound{
+		Source:  inbound.Source,
+		Gateway: inbound.Gateway,
+		Tag:     l.inboundTag,
+		User:    inbound.User,
+	})
+	newLink, err := l.dispatcher.Dispatch(ctx, destination)
+	if err != nil {
+		return newError("failed to dispatch to ", destination).Base(err)
+	}
+
+	requestDone := func() error {
+		if err := buf.Copy(link.Reader, newLink.Writer); err != nil {
+			return newError("failed to loop back request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		if err := buf.Copy(newLink.Reader, link.Writer); err != nil {
+			return newError("failed to loop back response").Base(err)
+		}
+		return nil
+	}
+
+	if err := task.Run(ctx, task.OnSuccess(requestDone, task.Close(newLink.Writer)), task.OnSuccess(responseDone, task.Close(link.Writer))); err != nil {
+		common.Interrupt(newLink.Reader)
+		common.Interrupt(newLink.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		l := new(Loopback)
+		if err := core.RequireFeatures(ctx, func(dispatcher routing.Dispatcher) error {
+			return l.Init(config.(*Config), dispatcher)
+		}); err != nil {
+			return nil, err
+		}
+		return l, nil
+	}))
+}
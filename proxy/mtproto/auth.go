@@ -79,7 +79,7 @@ func generateRandomBytes(random []byte, connType [4]byte) {
 		}
 
 		val := (uint32(random[3]) << 24) | (uint32(random[2]) << 16) | (uint32(random[1]) << 8) | uint32(random[0])
-		if val == 0x44414548 || val == 0x54534f50 || val == 0x20544547 || val == 0x4954504f || val == 0xeeeeeeee {
+		if val == 0x44414548 || val == 0x54534f50 || val == 0x20544547 || val == 0x4954504f || val == 0x02010316 || val == 0xdddddddd || val == 0xeeeeeeee {
 			continue
 		}
 
@@ -1,6 +1,8 @@
 package mtproto
 
 import (
+	"github.com/golang/protobuf/proto"
+
 	"v2ray.com/core/common/protocol"
 )
 
@@ -22,3 +24,48 @@ func (a *Account) Equals(another protocol.Account) bool {
 
 	return true
 }
+
+const (
+	keySize = 16
+
+	secretPrefixPadded  = 0xdd
+	secretPrefixFakeTLS = 0xee
+)
+
+// Validate returns an error if the secret is not in any of the known formats.
+func (a *Account) Validate() error {
+	switch {
+	case len(a.Secret) == keySize:
+	case len(a.Secret) == keySize+1 && a.Secret[0] == secretPrefixPadded:
+	case len(a.Secret) > keySize+1 && a.Secret[0] == secretPrefixFakeTLS:
+	default:
+		return newError("invalid MTProto secret of ", len(a.Secret), " bytes")
+	}
+	return nil
+}
+
+// key returns the key in the secret.
+func (a *Account) key() []byte {
+	if len(a.Secret) > keySize {
+		return a.Secret[1 : keySize+1]
+	}
+	return a.Secret
+}
+
+// isPadded returns true if the client is required to use random padding.
+func (a *Account) isPadded() bool {
+	return len(a.Secret) == keySize+1 && a.Secret[0] == secretPrefixPadded
+}
+
+// fakeTLSDomain returns the domain in a fake TLS secret, or empty if the secret is not for fake TLS.
+func (a *Account) fakeTLSDomain() string {
+	if len(a.Secret) > keySize+1 && a.Secret[0] == secretPrefixFakeTLS {
+		return string(a.Secret[keySize+1:])
+	}
+	return ""
+}
+
+// ToProto implements protocol.ProtoAccount.
+func (a *Account) ToProto() proto.Message {
+	return a
+}
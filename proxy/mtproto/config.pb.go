@@ -19,6 +19,9 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Account struct {
+	// Secret of the user. It is either a 16-byte key, the key prefixed by 0xdd
+	// for clients using random padding, or the key prefixed by 0xee and
+	// followed by a domain for clients using fake TLS.
 	Secret               []byte   `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -60,10 +63,13 @@ func (m *Account) GetSecret() []byte {
 type ServerConfig struct {
 	// User is a list of users that allowed to connect to this inbound.
 	// Although this is a repeated field, only the first user is effective for now.
-	User                 []*protocol.User `protobuf:"bytes,1,rep,name=user,proto3" json:"user,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	User []*protocol.User `protobuf:"bytes,1,rep,name=user,proto3" json:"user,omitempty"`
+	// Whether to forward connections that fail the fake TLS handshake to port
+	// 443 of the domain in the secret. Only effective for fake TLS secrets.
+	ForwardTls           bool     `protobuf:"varint,2,opt,name=forward_tls,json=forwardTls,proto3" json:"forward_tls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ServerConfig) Reset()         { *m = ServerConfig{} }
@@ -98,6 +104,13 @@ func (m *ServerConfig) GetUser() []*protocol.User {
 	return nil
 }
 
+func (m *ServerConfig) GetForwardTls() bool {
+	if m != nil {
+		return m.ForwardTls
+	}
+	return false
+}
+
 type ClientConfig struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -140,19 +153,21 @@ func init() {
 }
 
 var fileDescriptor_64514e21c693811b = []byte{
-	// 221 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0xc1, 0x4a, 0xc4, 0x30,
-	0x10, 0x86, 0x89, 0xca, 0x2e, 0xc4, 0xe2, 0xa1, 0x07, 0x09, 0xe2, 0xa1, 0xf6, 0xb4, 0x5e, 0x26,
-	0x50, 0x7d, 0x01, 0xed, 0x5e, 0x85, 0xa5, 0xa2, 0x07, 0x6f, 0xeb, 0x30, 0xca, 0xc2, 0x26, 0x53,
-	0xa6, 0x69, 0xb1, 0xaf, 0xe4, 0x53, 0x4a, 0x93, 0x16, 0x44, 0xf0, 0x94, 0xfc, 0xfc, 0x1f, 0xdf,
-	0x9f, 0xe8, 0xdb, 0xa1, 0x92, 0xfd, 0x08, 0xc8, 0xce, 0x22, 0x0b, 0xd9, 0x56, 0xf8, 0x6b, 0xb4,
-	0x2e, 0xb4, 0xc2, 0x81, 0x2d, 0xb2, 0xff, 0x38, 0x7c, 0x42, 0x0c, 0xb9, 0x59, 0x50, 0x21, 0x88,
-	0x18, 0xcc, 0xd8, 0xd5, 0x5f, 0x09, 0xb2, 0x73, 0xec, 0x6d, 0x2c, 0x91, 0x8f, 0xb6, 0xef, 0x48,
-	0x92, 0xa4, 0xbc, 0xd1, 0xeb, 0x07, 0x44, 0xee, 0x7d, 0xc8, 0x2f, 0xf5, 0xaa, 0x23, 0x14, 0x0a,
-	0x46, 0x15, 0x6a, 0x93, 0x35, 0x73, 0x2a, 0xb7, 0x3a, 0x7b, 0x26, 0x19, 0x48, 0xea, 0xb8, 0x9e,
-	0xdf, 0xeb, 0xb3, 0x49, 0x60, 0x54, 0x71, 0xba, 0x39, 0xaf, 0x0a, 0xf8, 0xf5, 0x8c, 0x34, 0x04,
-	0xcb, 0x10, 0xbc, 0x74, 0x24, 0x4d, 0xa4, 0xcb, 0x0b, 0x9d, 0xd5, 0xc7, 0x03, 0xf9, 0x90, 0x2c,
-	0x8f, 0x5b, 0x7d, 0x8d, 0xec, 0xe0, 0xbf, 0x3f, 0xec, 0xd4, 0xdb, 0x7a, 0xbe, 0x7e, 0x9f, 0x98,
-	0xd7, 0xaa, 0xd9, 0x8f, 0x50, 0x4f, 0xd4, 0x2e, 0x52, 0x4f, 0xa9, 0x7a, 0x5f, 0xc5, 0xe3, 0xee,
-	0x27, 0x00, 0x00, 0xff, 0xff, 0x54, 0x23, 0xa0, 0xae, 0x37, 0x01, 0x00, 0x00,
+	// 242 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0xcf, 0x4a, 0xc3, 0x40,
+	0x10, 0xc6, 0xd9, 0x2a, 0xad, 0x6c, 0x83, 0x87, 0x1c, 0x64, 0x11, 0xc1, 0x98, 0x53, 0xbc, 0xcc,
+	0x42, 0xf4, 0x05, 0x34, 0x5e, 0x85, 0x12, 0xff, 0x1c, 0xbc, 0x48, 0x1c, 0xa7, 0x52, 0xc8, 0x66,
+	0xca, 0xec, 0xb6, 0x9a, 0x57, 0xf2, 0x29, 0xa5, 0x9b, 0x14, 0x44, 0xf0, 0xb4, 0x3b, 0xfb, 0xfd,
+	0xf8, 0x7e, 0x3b, 0xfa, 0x72, 0x5b, 0x4a, 0xd3, 0x03, 0xb2, 0xb3, 0xc8, 0x42, 0x76, 0x2d, 0xfc,
+	0xd5, 0x5b, 0x17, 0xd6, 0xc2, 0x81, 0x2d, 0x72, 0xb7, 0x5c, 0x7d, 0x40, 0x1c, 0x52, 0xb3, 0x47,
+	0x85, 0x20, 0x62, 0x30, 0x62, 0xa7, 0x7f, 0x4b, 0x90, 0x9d, 0xe3, 0xce, 0xc6, 0x10, 0xb9, 0xb5,
+	0x1b, 0x4f, 0x32, 0x94, 0xe4, 0x17, 0x7a, 0x76, 0x83, 0xc8, 0x9b, 0x2e, 0xa4, 0x27, 0x7a, 0xea,
+	0x09, 0x85, 0x82, 0x51, 0x99, 0x2a, 0x92, 0x7a, 0x9c, 0x72, 0xd2, 0xc9, 0x03, 0xc9, 0x96, 0xa4,
+	0x8a, 0xf6, 0xf4, 0x5a, 0x1f, 0xee, 0x0a, 0x8c, 0xca, 0x0e, 0x8a, 0x79, 0x99, 0xc1, 0xaf, 0x6f,
+	0x0c, 0x22, 0xd8, 0x8b, 0xe0, 0xc9, 0x93, 0xd4, 0x91, 0x4e, 0xcf, 0xf5, 0x7c, 0xc9, 0xf2, 0xd9,
+	0xc8, 0xfb, 0x6b, 0x68, 0xbd, 0x99, 0x64, 0xaa, 0x38, 0xaa, 0xf5, 0xf8, 0xf4, 0xd8, 0xfa, 0xfc,
+	0x58, 0x27, 0x55, 0xbb, 0xa2, 0x2e, 0x0c, 0x9a, 0xdb, 0x3b, 0x7d, 0x86, 0xec, 0xe0, 0xbf, 0x25,
+	0x17, 0xea, 0x65, 0x36, 0x5e, 0xbf, 0x27, 0xe6, 0xb9, 0xac, 0x9b, 0x1e, 0xaa, 0x1d, 0xb5, 0x88,
+	0xd4, 0xfd, 0x10, 0xbd, 0x4d, 0xe3, 0x71, 0xf5, 0x33, 0x00, 0x3b, 0x28, 0x90, 0xfd, 0x58, 0x01,
+	0x00, 0x00,
 }
@@ -0,0 +1,259 @@
+// +build !confonly
+
+package mtproto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/task"
+)
+
+const (
+	recordTypeChangeCipherSpec = 0x14
+	recordTypeHandshake        = 0x16
+	recordTypeApplicationData  = 0x17
+
+	recordHeaderSize = 5
+	maxRecordPayload = 16384
+
+	// Offset of the random in a ClientHello or ServerHello record.
+	helloRandomOffset = recordHeaderSize + 4 + 2
+	helloRandomSize   = 32
+
+	// maxTimeSkew is the maximum difference between the timestamp in a ClientHello and the local time.
+	maxTimeSkew = time.Minute * 3
+)
+
+// helloHistory keeps track of randoms in recent ClientHellos, to prevent replay attacks.
+type helloHistory struct {
+	sync.Mutex
+	cache map[[helloRandomSize]byte]time.Time
+	task  *task.Periodic
+}
+
+func newHelloHistory() *helloHistory {
+	h := &helloHistory{
+		cache: make(map[[helloRandomSize]byte]time.Time, 128),
+	}
+	h.task = &task.Periodic{
+		Interval: time.Second * 30,
+		Execute:  h.removeExpiredEntries,
+	}
+	return h
+}
+
+// Close implements common.Closable.
+func (h *helloHistory) Close() error {
+	return h.task.Close()
+}
+
+func (h *helloHistory) addIfNotExists(random [helloRandomSize]byte) bool {
+	h.Lock()
+
+	if expire, found := h.cache[random]; found && expire.After(time.Now()) {
+		h.Unlock()
+		return false
+	}
+
+	// A ClientHello older than this fails the timestamp check anyway.
+	h.cache[random] = time.Now().Add(maxTimeSkew * 2)
+	h.Unlock()
+	common.Must(h.task.Start())
+	return true
+}
+
+func (h *helloHistory) removeExpiredEntries() error {
+	now := time.Now()
+
+	h.Lock()
+	defer h.Unlock()
+
+	if len(h.cache) == 0 {
+		return newError("nothing to do")
+	}
+
+	for random, expire := range h.cache {
+		if expire.Before(now) {
+			delete(h.cache, random)
+		}
+	}
+
+	if len(h.cache) == 0 {
+		h.cache = make(map[[helloRandomSize]byte]time.Time, 128)
+	}
+
+	return nil
+}
+
+// clientHello is a ClientHello record sent by a client with fake TLS secret.
+type clientHello struct {
+	Random    [helloRandomSize]byte
+	SessionID []byte
+}
+
+// readClientHello reads a ClientHello record, and verifies that its random is the HMAC of the record signed by
+// the key, XORed with the timestamp of the client.
+func readClientHello(reader io.Reader, key []byte, now time.Time) (*clientHello, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, newError("failed to read record header").Base(err)
+	}
+	if header[0] != recordTypeHandshake || header[1] != 0x03 || header[2] != 0x01 {
+		return nil, newError("not a TLS handshake record")
+	}
+	size := int(binary.BigEndian.Uint16(header[3:]))
+	if size < helloRandomOffset+helloRandomSize+1-recordHeaderSize || size > maxRecordPayload {
+		return nil, newError("invalid ClientHello size: ", size)
+	}
+
+	record := make([]byte, recordHeaderSize+size)
+	copy(record, header[:])
+	if _, err := io.ReadFull(reader, record[recordHeaderSize:]); err != nil {
+		return nil, newError("failed to read ClientHello").Base(err)
+	}
+	if record[recordHeaderSize] != 0x01 {
+		return nil, newError("not a ClientHello")
+	}
+
+	hello := new(clientHello)
+	random := record[helloRandomOffset : helloRandomOffset+helloRandomSize]
+	copy(hello.Random[:], random)
+	for i := range random {
+		random[i] = 0
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(record) // nolint: errcheck
+	digest := mac.Sum(nil)
+	for i := range digest {
+		digest[i] ^= hello.Random[i]
+	}
+	for _, b := range digest[:helloRandomSize-4] {
+		if b != 0 {
+			return nil, newError("invalid ClientHello digest")
+		}
+	}
+
+	timestamp := time.Unix(int64(binary.LittleEndian.Uint32(digest[helloRandomSize-4:])), 0)
+	if skew := now.Sub(timestamp); skew > maxTimeSkew || skew < -maxTimeSkew {
+		return nil, newError("invalid ClientHello timestamp: ", timestamp)
+	}
+
+	sessionIDOffset := helloRandomOffset + helloRandomSize
+	sessionIDSize := int(record[sessionIDOffset])
+	if sessionIDOffset+1+sessionIDSize > len(record) {
+		return nil, newError("invalid session ID size: ", sessionIDSize)
+	}
+	hello.SessionID = record[sessionIDOffset+1 : sessionIDOffset+1+sessionIDSize]
+
+	return hello, nil
+}
+
+// writeServerHello writes a ServerHello record, a ChangeCipherSpec record and an ApplicationData record with random
+// content in place of the encrypted certificate. The random of the ServerHello is the HMAC of the client random and
+// all the records, signed by the key.
+func writeServerHello(writer io.Writer, key []byte, hello *clientHello) error {
+	var serverHello []byte
+	serverHello = append(serverHello, 0x03, 0x03)
+	serverHello = append(serverHello, make([]byte, helloRandomSize)...)
+	serverHello = append(serverHello, byte(len(hello.SessionID)))
+	serverHello = append(serverHello, hello.SessionID...)
+	// Cipher suite TLS_AES_128_GCM_SHA256, and no compression.
+	serverHello = append(serverHello, 0x13, 0x01, 0x00)
+
+	publicKey := make([]byte, 32)
+	common.Must2(rand.Read(publicKey))
+	// Extensions: key_share with X25519, and supported_versions with TLS 1.3.
+	var extensions []byte
+	extensions = append(extensions, 0x00, 0x33, 0x00, 0x24, 0x00, 0x1d, 0x00, 0x20)
+	extensions = append(extensions, publicKey...)
+	extensions = append(extensions, 0x00, 0x2b, 0x00, 0x02, 0x03, 0x04)
+	serverHello = append(serverHello, byte(len(extensions)>>8), byte(len(extensions)))
+	serverHello = append(serverHello, extensions...)
+
+	handshake := append([]byte{0x02, 0x00, byte(len(serverHello) >> 8), byte(len(serverHello))}, serverHello...)
+
+	var response []byte
+	response = appendRecord(response, recordTypeHandshake, handshake)
+	response = appendRecord(response, recordTypeChangeCipherSpec, []byte{0x01})
+	certificate := make([]byte, 1024+dice.Roll(3072))
+	common.Must2(rand.Read(certificate))
+	response = appendRecord(response, recordTypeApplicationData, certificate)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(hello.Random[:]) // nolint: errcheck
+	mac.Write(response)        // nolint: errcheck
+	copy(response[helloRandomOffset:], mac.Sum(nil))
+
+	_, err := writer.Write(response)
+	return err
+}
+
+func appendRecord(b []byte, recordType byte, payload []byte) []byte {
+	b = append(b, recordType, 0x03, 0x03, byte(len(payload)>>8), byte(len(payload)))
+	return append(b, payload...)
+}
+
+// recordReader reads the payload of ApplicationData records, skipping ChangeCipherSpec records.
+type recordReader struct {
+	reader    io.Reader
+	remaining int
+}
+
+func (r *recordReader) Read(b []byte) (int, error) {
+	for r.remaining == 0 {
+		var header [recordHeaderSize]byte
+		if _, err := io.ReadFull(r.reader, header[:]); err != nil {
+			return 0, err
+		}
+		size := int(binary.BigEndian.Uint16(header[3:]))
+		switch header[0] {
+		case recordTypeChangeCipherSpec:
+			if _, err := io.CopyN(ioutil.Discard, r.reader, int64(size)); err != nil {
+				return 0, err
+			}
+		case recordTypeApplicationData:
+			r.remaining = size
+		default:
+			return 0, newError("unexpected record type: ", header[0])
+		}
+	}
+
+	if len(b) > r.remaining {
+		b = b[:r.remaining]
+	}
+	n, err := r.reader.Read(b)
+	r.remaining -= n
+	return n, err
+}
+
+// recordWriter writes data in ApplicationData records.
+type recordWriter struct {
+	writer io.Writer
+}
+
+func (w *recordWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		size := len(b)
+		if size > maxRecordPayload {
+			size = maxRecordPayload
+		}
+		record := make([]byte, 0, recordHeaderSize+size)
+		if _, err := w.writer.Write(appendRecord(record, recordTypeApplicationData, b[:size])); err != nil {
+			return written, err
+		}
+		written += size
+		b = b[size:]
+	}
+	return written, nil
+}
@@ -0,0 +1,178 @@
+package mtproto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+)
+
+// newClientHello returns a ClientHello record signed by the key at the given time, as sent by Telegram clients.
+func newClientHello(key []byte, now time.Time) []byte {
+	var body []byte
+	body = append(body, 0x03, 0x03)
+	body = append(body, make([]byte, helloRandomSize)...)
+	sessionID := make([]byte, 32)
+	common.Must2(rand.Read(sessionID))
+	body = append(body, byte(len(sessionID)))
+	body = append(body, sessionID...)
+	body = append(body, make([]byte, 400)...)
+
+	handshake := append([]byte{0x01, 0x00, byte(len(body) >> 8), byte(len(body))}, body...)
+	record := append([]byte{recordTypeHandshake, 0x03, 0x01, byte(len(handshake) >> 8), byte(len(handshake))}, handshake...)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(record) // nolint: errcheck
+	random := mac.Sum(nil)
+	var timestamp [4]byte
+	binary.LittleEndian.PutUint32(timestamp[:], uint32(now.Unix()))
+	for i := range timestamp {
+		random[helloRandomSize-4+i] ^= timestamp[i]
+	}
+	copy(record[helloRandomOffset:], random)
+	return record
+}
+
+func TestFakeTLSHandshake(t *testing.T) {
+	key := make([]byte, keySize)
+	common.Must2(rand.Read(key))
+	now := time.Now()
+
+	record := newClientHello(key, now)
+	hello, err := readClientHello(bytes.NewReader(record), key, now.Add(time.Minute))
+	common.Must(err)
+	if !bytes.Equal(hello.Random[:], record[helloRandomOffset:helloRandomOffset+helloRandomSize]) {
+		t.Error("unexpected random: ", hello.Random)
+	}
+	if !bytes.Equal(hello.SessionID, record[helloRandomOffset+helloRandomSize+1:helloRandomOffset+helloRandomSize+33]) {
+		t.Error("unexpected session ID: ", hello.SessionID)
+	}
+
+	var response bytes.Buffer
+	common.Must(writeServerHello(&response, key, hello))
+	b := response.Bytes()
+	if b[0] != recordTypeHandshake || b[recordHeaderSize] != 0x02 {
+		t.Fatal("not a ServerHello: ", b[:recordHeaderSize+1])
+	}
+	random := append([]byte(nil), b[helloRandomOffset:helloRandomOffset+helloRandomSize]...)
+	copy(b[helloRandomOffset:], make([]byte, helloRandomSize))
+	mac := hmac.New(sha256.New, key)
+	mac.Write(hello.Random[:]) // nolint: errcheck
+	mac.Write(b)               // nolint: errcheck
+	if !hmac.Equal(random, mac.Sum(nil)) {
+		t.Error("invalid ServerHello digest")
+	}
+
+	// The ServerHello is followed by a ChangeCipherSpec record and an ApplicationData record, and nothing else.
+	reader := bytes.NewReader(b)
+	for _, recordType := range []byte{recordTypeHandshake, recordTypeChangeCipherSpec, recordTypeApplicationData} {
+		var header [recordHeaderSize]byte
+		common.Must2(io.ReadFull(reader, header[:]))
+		if header[0] != recordType {
+			t.Error("unexpected record type: ", header[0])
+		}
+		common.Must2(io.CopyN(ioutil.Discard, reader, int64(binary.BigEndian.Uint16(header[3:]))))
+	}
+	if reader.Len() != 0 {
+		t.Error("unexpected trailing bytes: ", reader.Len())
+	}
+}
+
+func TestInvalidClientHello(t *testing.T) {
+	key := make([]byte, keySize)
+	common.Must2(rand.Read(key))
+	now := time.Now()
+
+	wrongKey := make([]byte, keySize)
+	if _, err := readClientHello(bytes.NewReader(newClientHello(wrongKey, now)), key, now); err == nil {
+		t.Error("nil error with wrong key")
+	}
+
+	if _, err := readClientHello(bytes.NewReader(newClientHello(key, now.Add(-time.Hour))), key, now); err == nil {
+		t.Error("nil error with expired timestamp")
+	}
+
+	record := newClientHello(key, now)
+	record[len(record)-1] ^= 1
+	if _, err := readClientHello(bytes.NewReader(record), key, now); err == nil {
+		t.Error("nil error with modified ClientHello")
+	}
+
+	if _, err := readClientHello(bytes.NewReader([]byte("GET / HTTP/1.1\r\n\r\n")), key, now); err == nil {
+		t.Error("nil error with HTTP request")
+	}
+}
+
+func TestHelloHistory(t *testing.T) {
+	history := newHelloHistory()
+	defer history.Close()
+
+	var random [helloRandomSize]byte
+	common.Must2(rand.Read(random[:]))
+	if !history.addIfNotExists(random) {
+		t.Error("new random rejected")
+	}
+	if history.addIfNotExists(random) {
+		t.Error("duplicated random accepted")
+	}
+}
+
+func TestRecordReaderWriter(t *testing.T) {
+	payload := make([]byte, maxRecordPayload+1000)
+	common.Must2(rand.Read(payload))
+
+	var stream bytes.Buffer
+	stream.Write([]byte{recordTypeChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01})
+	writer := &recordWriter{writer: &stream}
+	common.Must2(writer.Write(payload))
+
+	// ChangeCipherSpec, and two ApplicationData records.
+	if expected := 6 + 2*recordHeaderSize + len(payload); stream.Len() != expected {
+		t.Error("unexpected stream size: ", stream.Len(), " want ", expected)
+	}
+
+	received, err := ioutil.ReadAll(&recordReader{reader: &stream})
+	common.Must(err)
+	if !bytes.Equal(received, payload) {
+		t.Error("payload mismatch")
+	}
+
+	if _, err := (&recordReader{reader: bytes.NewReader([]byte{recordTypeHandshake, 0x03, 0x03, 0x00, 0x00})}).Read(make([]byte, 1)); err == nil {
+		t.Error("nil error with handshake record")
+	}
+}
+
+func TestAccountSecret(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, keySize)
+
+	plain := &Account{Secret: key}
+	common.Must(plain.Validate())
+	if !bytes.Equal(plain.key(), key) || plain.isPadded() || plain.fakeTLSDomain() != "" {
+		t.Error("unexpected plain secret")
+	}
+
+	padded := &Account{Secret: append([]byte{0xdd}, key...)}
+	common.Must(padded.Validate())
+	if !bytes.Equal(padded.key(), key) || !padded.isPadded() || padded.fakeTLSDomain() != "" {
+		t.Error("unexpected padded secret")
+	}
+
+	fakeTLS := &Account{Secret: append(append([]byte{0xee}, key...), "v2ray.com"...)}
+	common.Must(fakeTLS.Validate())
+	if !bytes.Equal(fakeTLS.key(), key) || fakeTLS.isPadded() || fakeTLS.fakeTLSDomain() != "v2ray.com" {
+		t.Error("unexpected fake TLS secret")
+	}
+
+	for _, secret := range [][]byte{key[:15], append([]byte{0xee}, key...), append(append([]byte{0xdd}, key...), 0x00)} {
+		if err := (&Account{Secret: secret}).Validate(); err == nil {
+			t.Error("nil error for secret ", secret)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package mtproto
 import (
 	"bytes"
 	"context"
+	"io"
 	"time"
 
 	"v2ray.com/core"
@@ -18,6 +19,7 @@ import (
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/transport/internet"
 )
 
@@ -32,9 +34,11 @@ var (
 )
 
 type Server struct {
-	user    *protocol.User
-	account *Account
-	policy  policy.Manager
+	user       *protocol.MemoryUser
+	account    *Account
+	forwardTLS bool
+	history    *helloHistory
+	policy     policy.Manager
 }
 
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
@@ -42,31 +46,56 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		return nil, newError("no user configured.")
 	}
 
-	user := config.User[0]
-	rawAccount, err := config.User[0].GetTypedAccount()
+	user, err := config.User[0].ToMemoryUser()
 	if err != nil {
 		return nil, newError("invalid account").Base(err)
 	}
-	account, ok := rawAccount.(*Account)
+	account, ok := user.Account.(*Account)
 	if !ok {
 		return nil, newError("not a MTProto account")
 	}
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
 
 	v := core.MustFromContext(ctx)
 
 	return &Server{
-		user:    user,
-		account: account,
-		policy:  v.GetFeature(policy.ManagerType()).(policy.Manager),
+		user:       user,
+		account:    account,
+		forwardTLS: config.ForwardTls,
+		history:    newHelloHistory(),
+		policy:     v.GetFeature(policy.ManagerType()).(policy.Manager),
 	}, nil
 }
 
+// Close implements common.Closable.
+func (s *Server) Close() error {
+	return s.history.Close()
+}
+
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
+	return newError("MTProto server doesn't support adding users")
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	return newError("MTProto server doesn't support removing users")
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return []*protocol.MemoryUser{s.user}
+}
+
 func (s *Server) Network() []net.Network {
 	return []net.Network{net.Network_TCP}
 }
 
 var ctype1 = []byte{0xef, 0xef, 0xef, 0xef}
 var ctype2 = []byte{0xee, 0xee, 0xee, 0xee}
+var ctypePadded = []byte{0xdd, 0xdd, 0xdd, 0xdd}
 
 func isValidConnectionType(c [4]byte) bool {
 	if bytes.Equal(c[:], ctype1) {
@@ -75,16 +104,55 @@ func isValidConnectionType(c [4]byte) bool {
 	if bytes.Equal(c[:], ctype2) {
 		return true
 	}
+	if bytes.Equal(c[:], ctypePadded) {
+		return true
+	}
 	return false
 }
 
+// handshakeFakeTLS verifies the ClientHello from a client with fake TLS secret, and responds with a ServerHello.
+func (s *Server) handshakeFakeTLS(reader io.Reader, writer io.Writer) error {
+	key := s.account.key()
+	hello, err := readClientHello(reader, key, time.Now())
+	if err != nil {
+		return err
+	}
+	if !s.history.addIfNotExists(hello.Random) {
+		return newError("duplicated ClientHello")
+	}
+	return writeServerHello(writer, key, hello)
+}
+
 func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
 	sPolicy := s.policy.ForLevel(s.user.Level)
 
 	if err := conn.SetDeadline(time.Now().Add(sPolicy.Timeouts.Handshake)); err != nil {
 		newError("failed to set deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
-	auth, err := ReadAuthentication(conn)
+
+	var reader io.Reader = conn
+	var writer io.Writer = conn
+	if domain := s.account.fakeTLSDomain(); len(domain) > 0 {
+		recorder := fallback.NewRecorder(buf.NewReader(conn))
+		bufferedReader := &buf.BufferedReader{Reader: recorder}
+		if err := s.handshakeFakeTLS(bufferedReader, conn); err != nil {
+			if !s.forwardTLS {
+				recorder.Stop()
+				return newError("failed to handshake fake TLS").Base(err)
+			}
+			newError("failed to handshake fake TLS").Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+			if err := conn.SetDeadline(time.Time{}); err != nil {
+				newError("failed to clear deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+			}
+			dest := net.TCPDestination(net.DomainAddress(domain), net.Port(443))
+			return fallback.Serve(ctx, conn, recorder, []*fallback.Fallback{{Dest: dest.NetAddr()}}, sPolicy)
+		}
+		recorder.Stop()
+		reader = &recordReader{reader: bufferedReader}
+		writer = &recordWriter{writer: conn}
+	}
+
+	auth, err := ReadAuthentication(reader)
 	if err != nil {
 		return newError("failed to read authentication header").Base(err)
 	}
@@ -94,7 +162,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		newError("failed to clear deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 
-	auth.ApplySecret(s.account.Secret)
+	auth.ApplySecret(s.account.key())
 
 	decryptor := crypto.NewAesCTRStream(auth.DecodingKey[:], auth.DecodingNonce[:])
 	decryptor.XORKeyStream(auth.Header[:], auth.Header[:])
@@ -103,6 +171,9 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 	if !isValidConnectionType(ct) {
 		return newError("invalid connection type: ", ct)
 	}
+	if s.account.isPadded() && !bytes.Equal(ct[:], ctypePadded) {
+		return newError("connection type ", ct, " without padding")
+	}
 
 	dcID := auth.DataCenterID()
 	if dcID >= uint16(len(dcList)) {
@@ -133,7 +204,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 	request := func() error {
 		defer timer.SetTimeout(sPolicy.Timeouts.DownlinkOnly)
 
-		reader := buf.NewReader(crypto.NewCryptionReader(decryptor, conn))
+		reader := buf.NewReader(crypto.NewCryptionReader(decryptor, reader))
 		return buf.Copy(reader, link.Writer, buf.UpdateActivity(timer))
 	}
 
@@ -141,7 +212,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		defer timer.SetTimeout(sPolicy.Timeouts.UplinkOnly)
 
 		encryptor := crypto.NewAesCTRStream(auth.EncodingKey[:], auth.EncodingNonce[:])
-		writer := buf.NewWriter(crypto.NewCryptionWriter(encryptor, conn))
+		writer := buf.NewWriter(crypto.NewCryptionWriter(encryptor, writer))
 		return buf.Copy(link.Reader, writer, buf.UpdateActivity(timer))
 	}
 
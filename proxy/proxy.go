@@ -30,6 +30,13 @@ type Outbound interface {
 	Process(context.Context, *transport.Link, internet.Dialer) error
 }
 
+// DirectOutbound is the interface for Outbounds that may send traffic to the destinations of requests, instead of
+// to fixed servers.
+type DirectOutbound interface {
+	// IsDirect returns true if the Outbound has no fixed server to connect to.
+	IsDirect() bool
+}
+
 // UserManager is the interface for Inbounds and Outbounds that can manage their users.
 type UserManager interface {
 	// AddUser adds a new user.
@@ -39,6 +46,18 @@ type UserManager interface {
 	RemoveUser(context.Context, string) error
 }
 
+// UserLister is the optional interface for Inbounds and Outbounds that can list their users.
+type UserLister interface {
+	// GetUsers returns all users.
+	GetUsers(context.Context) []*protocol.MemoryUser
+}
+
+// UserQuotaGetter is the interface for Inbounds that can look up the traffic quota of their users.
+type UserQuotaGetter interface {
+	// GetUserQuota returns the quota of the user with the given email, or nil if the user is not found.
+	GetUserQuota(context.Context, string) *protocol.Quota
+}
+
 type GetInbound interface {
 	GetInbound() Inbound
 }
@@ -4,6 +4,7 @@ package shadowsocks
 
 import (
 	"context"
+	"sync"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
@@ -23,6 +24,12 @@ import (
 type Client struct {
 	serverPicker  protocol.ServerPicker
 	policyManager policy.Manager
+	config        *ClientConfig
+
+	access sync.RWMutex
+	// pluginDests maps servers to the local destinations of their plugins.
+	pluginDests map[net.Destination]net.Destination
+	plugins     []*pluginProcess
 }
 
 // NewClient create a new Shadowsocks client.
@@ -43,10 +50,78 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 	client := &Client{
 		serverPicker:  protocol.NewRoundRobinServerPicker(serverList),
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		config:        config,
 	}
 	return client, nil
 }
 
+// Start implements common.Runnable. It starts a plugin for each server if any, unless they are running.
+func (c *Client) Start() error {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.config.Plugin == nil || c.pluginDests != nil {
+		return nil
+	}
+	if err := c.startPlugins(c.config); err != nil {
+		c.closePlugins()
+		return err
+	}
+	return nil
+}
+
+// startPlugins must be called with access locked.
+func (c *Client) startPlugins(config *ClientConfig) error {
+	c.pluginDests = make(map[net.Destination]net.Destination)
+	for _, rec := range config.Server {
+		remote := net.TCPDestination(rec.Address.AsAddress(), net.Port(rec.Port))
+		if _, found := c.pluginDests[remote]; found {
+			continue
+		}
+		port, err := pickLocalPort()
+		if err != nil {
+			return newError("failed to pick a port for plugin").Base(err)
+		}
+		local := net.TCPDestination(net.LocalHostIP, port)
+		plugin, err := startPlugin(config.Plugin, remote, local)
+		if err != nil {
+			return err
+		}
+		c.plugins = append(c.plugins, plugin)
+		c.pluginDests[remote] = local
+	}
+	return nil
+}
+
+// Close implements common.Closable. It stops all plugins.
+func (c *Client) Close() error {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	c.closePlugins()
+	return nil
+}
+
+// closePlugins must be called with access locked.
+func (c *Client) closePlugins() {
+	for _, plugin := range c.plugins {
+		plugin.Close() // nolint: errcheck
+	}
+	c.plugins = nil
+	c.pluginDests = nil
+}
+
+// pluginDest returns the local destination of the plugin for the server, or the server itself if there is no plugin.
+func (c *Client) pluginDest(server net.Destination) net.Destination {
+	c.access.RLock()
+	defer c.access.RUnlock()
+
+	if local, found := c.pluginDests[server]; found {
+		return local
+	}
+	return server
+}
+
 // Process implements OutboundHandler.Process().
 func (c *Client) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
 	outbound := session.OutboundFromContext(ctx)
@@ -63,6 +138,7 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		server = c.serverPicker.PickServer()
 		dest := server.Destination()
 		dest.Network = network
+		dest = c.pluginDest(dest)
 		rawConn, err := dialer.Dial(ctx, dest)
 		if err != nil {
 			return err
@@ -106,7 +182,13 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 
 	if request.Command == protocol.RequestCommandTCP {
 		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
-		bodyWriter, err := WriteTCPRequest(request, bufferedWriter)
+		var bodyWriter buf.Writer
+		var requestSalt []byte
+		if is2022(account) {
+			bodyWriter, requestSalt, err = WriteTCPRequest2022(request, bufferedWriter)
+		} else {
+			bodyWriter, err = WriteTCPRequest(request, bufferedWriter)
+		}
 		if err != nil {
 			return newError("failed to write request").Base(err)
 		}
@@ -123,7 +205,13 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		responseDone := func() error {
 			defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
-			responseReader, err := ReadTCPResponse(user, conn)
+			var responseReader buf.Reader
+			var err error
+			if is2022(account) {
+				responseReader, err = ReadTCPResponse2022(user, requestSalt, &buf.BufferedReader{Reader: buf.NewReader(conn)})
+			} else {
+				responseReader, err = ReadTCPResponse(user, conn)
+			}
 			if err != nil {
 				return err
 			}
@@ -140,10 +228,15 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 	}
 
 	if request.Command == protocol.RequestCommandUDP {
+		var udpSession *UDPSession
+		if is2022(account) {
+			udpSession = NewUDPSession()
+		}
 
 		writer := &buf.SequentialWriter{Writer: &UDPWriter{
 			Writer:  conn,
 			Request: request,
+			Session: udpSession,
 		}}
 
 		requestDone := func() error {
@@ -159,8 +252,9 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 			defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
 			reader := &UDPReader{
-				Reader: conn,
-				User:   user,
+				Reader:  conn,
+				User:    user,
+				Session: udpSession,
 			}
 
 			if err := buf.Copy(reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
@@ -6,13 +6,18 @@ import (
 	"crypto/cipher"
 	"crypto/md5"
 	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
 	"io"
 
+	"github.com/golang/protobuf/proto"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
+	"lukechampine.com/blake3"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/bytespool"
 	"v2ray.com/core/common/crypto"
 	"v2ray.com/core/common/protocol"
 )
@@ -22,6 +27,8 @@ type MemoryAccount struct {
 	Cipher      Cipher
 	Key         []byte
 	OneTimeAuth Account_OneTimeAuth
+	Password    string
+	CipherType  CipherType
 }
 
 // Equals implements protocol.Account.Equals().
@@ -32,6 +39,15 @@ func (a *MemoryAccount) Equals(another protocol.Account) bool {
 	return false
 }
 
+// ToProto implements protocol.ProtoAccount.ToProto().
+func (a *MemoryAccount) ToProto() proto.Message {
+	return &Account{
+		Password:   a.Password,
+		CipherType: a.CipherType,
+		Ota:        a.OneTimeAuth,
+	}
+}
+
 func createAesGcm(key []byte) cipher.AEAD {
 	block, err := aes.NewCipher(key)
 	common.Must(err)
@@ -76,6 +92,23 @@ func (a *Account) getCipher() (Cipher, error) {
 		}, nil
 	case CipherType_NONE:
 		return NoneCipher{}, nil
+	case CipherType_BLAKE3_AES_128_GCM:
+		return &AEAD2022Cipher{
+			KeyBytes:        16,
+			AEADAuthCreator: createAesGcm,
+			SeparateHeader:  true,
+		}, nil
+	case CipherType_BLAKE3_AES_256_GCM:
+		return &AEAD2022Cipher{
+			KeyBytes:        32,
+			AEADAuthCreator: createAesGcm,
+			SeparateHeader:  true,
+		}, nil
+	case CipherType_BLAKE3_CHACHA20_POLY1305:
+		return &AEAD2022Cipher{
+			KeyBytes:        32,
+			AEADAuthCreator: createChacha20Poly1305,
+		}, nil
 	default:
 		return nil, newError("Unsupported cipher.")
 	}
@@ -87,10 +120,24 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	if err != nil {
 		return nil, newError("failed to get cipher").Base(err)
 	}
+	var key []byte
+	if _, ok := cipher.(*AEAD2022Cipher); ok {
+		key, err = base64.StdEncoding.DecodeString(a.Password)
+		if err != nil {
+			return nil, newError("failed to decode key").Base(err)
+		}
+		if int32(len(key)) != cipher.KeySize() {
+			return nil, newError("invalid key size: ", len(key), ", expecting ", cipher.KeySize())
+		}
+	} else {
+		key = passwordToCipherKey([]byte(a.Password), cipher.KeySize())
+	}
 	return &MemoryAccount{
 		Cipher:      cipher,
-		Key:         passwordToCipherKey([]byte(a.Password), cipher.KeySize()),
+		Key:         key,
 		OneTimeAuth: a.Ota,
+		Password:    a.Password,
+		CipherType:  a.CipherType,
 	}, nil
 }
 
@@ -220,6 +267,189 @@ func (c *AEADCipher) DecodePacket(key []byte, b *buf.Buffer) error {
 	return nil
 }
 
+// IsFirstChunk returns true if data starts with an IV, followed by a chunk size sealed with the given key.
+func (c *AEADCipher) IsFirstChunk(key []byte, data []byte) bool {
+	ivLen := c.IVSize()
+	if int32(len(data)) < ivLen {
+		return false
+	}
+	auth := c.createAuthenticator(key, data[:ivLen])
+	end := ivLen + 2 + int32(auth.Overhead())
+	if int32(len(data)) < end {
+		return false
+	}
+	_, err := auth.Open(nil, data[ivLen:end])
+	return err == nil
+}
+
+// AEAD2022Cipher represents the Shadowsocks 2022 ciphers. Session keys are derived from the pre-shared key with
+// BLAKE3, and the request and response headers are bound to each other.
+type AEAD2022Cipher struct {
+	KeyBytes        int32
+	AEADAuthCreator func(key []byte) cipher.AEAD
+	// SeparateHeader is true if the session ID and packet ID of UDP packets are encrypted as a separate block.
+	// Otherwise UDP packets are sealed by XChaCha20-Poly1305 with the pre-shared key and a random nonce.
+	SeparateHeader bool
+}
+
+func (*AEAD2022Cipher) IsAEAD() bool {
+	return true
+}
+
+func (c *AEAD2022Cipher) KeySize() int32 {
+	return c.KeyBytes
+}
+
+// IVSize returns the size of salt, which is the same as key size.
+func (c *AEAD2022Cipher) IVSize() int32 {
+	return c.KeyBytes
+}
+
+func (c *AEAD2022Cipher) createAuthenticator(key []byte, salt []byte) *crypto.AEADAuthenticator {
+	return &crypto.AEADAuthenticator{
+		AEAD:           c.AEADAuthCreator(deriveSessionKey(key, salt)),
+		NonceGenerator: crypto.GenerateInitialAEADNonce(),
+	}
+}
+
+func (c *AEAD2022Cipher) NewEncryptionWriter(key []byte, iv []byte, writer io.Writer) (buf.Writer, error) {
+	auth := c.createAuthenticator(key, iv)
+	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, writer, protocol.TransferTypeStream, nil), nil
+}
+
+func (c *AEAD2022Cipher) NewDecryptionReader(key []byte, iv []byte, reader io.Reader) (buf.Reader, error) {
+	return newChunkReader2022(c.createAuthenticator(key, iv), reader, nil), nil
+}
+
+// udpNonceSize returns the size of the random nonce in front of UDP packets.
+func (c *AEAD2022Cipher) udpNonceSize() int32 {
+	if c.SeparateHeader {
+		return 0
+	}
+	return chacha20poly1305.NonceSizeX
+}
+
+// EncodePacket seals a UDP packet. The packet starts with space for the random nonce, followed by the 16 bytes
+// session ID and packet ID, and then the body.
+func (c *AEAD2022Cipher) EncodePacket(key []byte, b *buf.Buffer) error {
+	nonceLen := c.udpNonceSize()
+	if b.Len() < nonceLen+16 {
+		return newError("insufficient data: ", b.Len())
+	}
+	payloadLen := b.Len()
+
+	if !c.SeparateHeader {
+		aead, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return err
+		}
+		b.Extend(int32(aead.Overhead()))
+		aead.Seal(b.BytesTo(nonceLen), b.BytesTo(nonceLen), b.BytesRange(nonceLen, payloadLen), nil)
+		return nil
+	}
+
+	header := b.BytesTo(16)
+	aead := c.AEADAuthCreator(deriveSessionKey(key, header[:8]))
+	b.Extend(int32(aead.Overhead()))
+	aead.Seal(b.BytesTo(16), header[4:16], b.BytesRange(16, payloadLen), nil)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	block.Encrypt(header, header)
+	return nil
+}
+
+// DecodePacket opens a UDP packet sealed by EncodePacket, and leaves the session ID, packet ID and body in b.
+func (c *AEAD2022Cipher) DecodePacket(key []byte, b *buf.Buffer) error {
+	nonceLen := c.udpNonceSize()
+	if b.Len() <= nonceLen+16 {
+		return newError("insufficient data: ", b.Len())
+	}
+	payloadLen := b.Len()
+
+	if !c.SeparateHeader {
+		aead, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return err
+		}
+		opened, err := aead.Open(b.BytesTo(nonceLen), b.BytesTo(nonceLen), b.BytesRange(nonceLen, payloadLen), nil)
+		if err != nil {
+			return err
+		}
+		b.Resize(nonceLen, int32(len(opened)))
+		return nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	header := b.BytesTo(16)
+	block.Decrypt(header, header)
+	aead := c.AEADAuthCreator(deriveSessionKey(key, header[:8]))
+	opened, err := aead.Open(b.BytesTo(16), header[4:16], b.BytesRange(16, payloadLen), nil)
+	if err != nil {
+		return err
+	}
+	b.Resize(0, int32(len(opened)))
+	return nil
+}
+
+// chunkReader2022 reads the chunks of a Shadowsocks 2022 stream, whose payload may be as large as 0xFFFF bytes.
+type chunkReader2022 struct {
+	auth      *crypto.AEADAuthenticator
+	reader    io.Reader
+	sizeBytes []byte
+	pending   buf.MultiBuffer
+}
+
+func newChunkReader2022(auth *crypto.AEADAuthenticator, reader io.Reader, pending buf.MultiBuffer) *chunkReader2022 {
+	return &chunkReader2022{
+		auth:      auth,
+		reader:    reader,
+		sizeBytes: make([]byte, 2+auth.Overhead()),
+		pending:   pending,
+	}
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *chunkReader2022) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if !r.pending.IsEmpty() {
+		mb := r.pending
+		r.pending = nil
+		return mb, nil
+	}
+
+	if _, err := io.ReadFull(r.reader, r.sizeBytes); err != nil {
+		return nil, err
+	}
+	sizeBytes, err := r.auth.Open(r.sizeBytes[:0], r.sizeBytes)
+	if err != nil {
+		return nil, newError("failed to decrypt chunk size").Base(err)
+	}
+	return readChunk2022(r.auth, r.reader, int32(binary.BigEndian.Uint16(sizeBytes)))
+}
+
+// readChunk2022 reads and opens a sealed chunk of the given payload size.
+func readChunk2022(auth *crypto.AEADAuthenticator, reader io.Reader, size int32) (buf.MultiBuffer, error) {
+	size += int32(auth.Overhead())
+	payload := bytespool.Alloc(size)
+	defer bytespool.Free(payload)
+
+	if _, err := io.ReadFull(reader, payload[:size]); err != nil {
+		return nil, err
+	}
+	plain, err := auth.Open(payload[:0], payload[:size])
+	if err != nil {
+		return nil, newError("failed to decrypt chunk").Base(err)
+	}
+	return buf.MergeBytes(nil, plain), nil
+}
+
 type ChaCha20 struct {
 	IVBytes int32
 }
@@ -305,6 +535,15 @@ func passwordToCipherKey(password []byte, keySize int32) []byte {
 	return key
 }
 
+func deriveSessionKey(key, salt []byte) []byte {
+	material := make([]byte, 0, len(key)+len(salt))
+	material = append(material, key...)
+	material = append(material, salt...)
+	subkey := make([]byte, len(key))
+	blake3.DeriveKey(subkey, "shadowsocks 2022 session subkey", material)
+	return subkey
+}
+
 func hkdfSHA1(secret, salt, outkey []byte) {
 	r := hkdf.New(sha1.New, secret, salt, []byte("ss-subkey"))
 	common.Must2(io.ReadFull(r, outkey))
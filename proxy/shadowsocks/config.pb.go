@@ -6,6 +6,7 @@ import (
 	math "math"
 	net "v2ray.com/core/common/net"
 	protocol "v2ray.com/core/common/protocol"
+	fallback "v2ray.com/core/proxy/fallback"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -31,30 +32,41 @@ const (
 	CipherType_AES_256_GCM       CipherType = 6
 	CipherType_CHACHA20_POLY1305 CipherType = 7
 	CipherType_NONE              CipherType = 8
+	// Shadowsocks 2022 ciphers. The password is the base64 encoded key, whose
+	// size must match the cipher.
+	CipherType_BLAKE3_AES_128_GCM       CipherType = 9
+	CipherType_BLAKE3_AES_256_GCM       CipherType = 10
+	CipherType_BLAKE3_CHACHA20_POLY1305 CipherType = 11
 )
 
 var CipherType_name = map[int32]string{
-	0: "UNKNOWN",
-	1: "AES_128_CFB",
-	2: "AES_256_CFB",
-	3: "CHACHA20",
-	4: "CHACHA20_IETF",
-	5: "AES_128_GCM",
-	6: "AES_256_GCM",
-	7: "CHACHA20_POLY1305",
-	8: "NONE",
+	0:  "UNKNOWN",
+	1:  "AES_128_CFB",
+	2:  "AES_256_CFB",
+	3:  "CHACHA20",
+	4:  "CHACHA20_IETF",
+	5:  "AES_128_GCM",
+	6:  "AES_256_GCM",
+	7:  "CHACHA20_POLY1305",
+	8:  "NONE",
+	9:  "BLAKE3_AES_128_GCM",
+	10: "BLAKE3_AES_256_GCM",
+	11: "BLAKE3_CHACHA20_POLY1305",
 }
 
 var CipherType_value = map[string]int32{
-	"UNKNOWN":           0,
-	"AES_128_CFB":       1,
-	"AES_256_CFB":       2,
-	"CHACHA20":          3,
-	"CHACHA20_IETF":     4,
-	"AES_128_GCM":       5,
-	"AES_256_GCM":       6,
-	"CHACHA20_POLY1305": 7,
-	"NONE":              8,
+	"UNKNOWN":                  0,
+	"AES_128_CFB":              1,
+	"AES_256_CFB":              2,
+	"CHACHA20":                 3,
+	"CHACHA20_IETF":            4,
+	"AES_128_GCM":              5,
+	"AES_256_GCM":              6,
+	"CHACHA20_POLY1305":        7,
+	"NONE":                     8,
+	"BLAKE3_AES_128_GCM":       9,
+	"BLAKE3_AES_256_GCM":       10,
+	"BLAKE3_CHACHA20_POLY1305": 11,
 }
 
 func (x CipherType) String() string {
@@ -151,12 +163,23 @@ func (m *Account) GetOta() Account_OneTimeAuth {
 type ServerConfig struct {
 	// UdpEnabled specified whether or not to enable UDP for Shadowsocks.
 	// Deprecated. Use 'network' field.
-	UdpEnabled           bool           `protobuf:"varint,1,opt,name=udp_enabled,json=udpEnabled,proto3" json:"udp_enabled,omitempty"` // Deprecated: Do not use.
-	User                 *protocol.User `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
-	Network              []net.Network  `protobuf:"varint,3,rep,packed,name=network,proto3,enum=v2ray.core.common.net.Network" json:"network,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+	UdpEnabled bool           `protobuf:"varint,1,opt,name=udp_enabled,json=udpEnabled,proto3" json:"udp_enabled,omitempty"` // Deprecated: Do not use.
+	User       *protocol.User `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Network    []net.Network  `protobuf:"varint,3,rep,packed,name=network,proto3,enum=v2ray.core.common.net.Network" json:"network,omitempty"`
+	// Destinations for TCP connections that fail authentication.
+	Fallbacks []*fallback.Fallback `protobuf:"bytes,4,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	// Additional users. Users are identified by their keys, so all users must
+	// use AEAD ciphers if there is more than one user.
+	Users []*protocol.User `protobuf:"bytes,5,rep,name=users,proto3" json:"users,omitempty"`
+	// SIP003 plugin that accepts client connections on plugin_address and
+	// plugin_port, and forwards them to the listening address and port of the
+	// inbound, which must be a single address and port.
+	Plugin               *PluginConfig   `protobuf:"bytes,6,opt,name=plugin,proto3" json:"plugin,omitempty"`
+	PluginAddress        *net.IPOrDomain `protobuf:"bytes,7,opt,name=plugin_address,json=pluginAddress,proto3" json:"plugin_address,omitempty"`
+	PluginPort           uint32          `protobuf:"varint,8,opt,name=plugin_port,json=pluginPort,proto3" json:"plugin_port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *ServerConfig) Reset()         { *m = ServerConfig{} }
@@ -206,11 +229,49 @@ func (m *ServerConfig) GetNetwork() []net.Network {
 	return nil
 }
 
+func (m *ServerConfig) GetFallbacks() []*fallback.Fallback {
+	if m != nil {
+		return m.Fallbacks
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetUsers() []*protocol.User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetPlugin() *PluginConfig {
+	if m != nil {
+		return m.Plugin
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetPluginAddress() *net.IPOrDomain {
+	if m != nil {
+		return m.PluginAddress
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetPluginPort() uint32 {
+	if m != nil {
+		return m.PluginPort
+	}
+	return 0
+}
+
 type ClientConfig struct {
-	Server               []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
-	XXX_unrecognized     []byte                     `json:"-"`
-	XXX_sizecache        int32                      `json:"-"`
+	Server []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
+	// SIP003 plugin for TCP connections to servers. A plugin process is started
+	// for each server.
+	Plugin               *PluginConfig `protobuf:"bytes,2,opt,name=plugin,proto3" json:"plugin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *ClientConfig) Reset()         { *m = ClientConfig{} }
@@ -245,12 +306,79 @@ func (m *ClientConfig) GetServer() []*protocol.ServerEndpoint {
 	return nil
 }
 
+func (m *ClientConfig) GetPlugin() *PluginConfig {
+	if m != nil {
+		return m.Plugin
+	}
+	return nil
+}
+
+// PluginConfig is the configuration of a SIP003 plugin.
+type PluginConfig struct {
+	// Path of the plugin executable.
+	Command string `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	// Options of the plugin, passed in SS_PLUGIN_OPTIONS.
+	Options string `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// Command line arguments of the plugin.
+	Args                 []string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PluginConfig) Reset()         { *m = PluginConfig{} }
+func (m *PluginConfig) String() string { return proto.CompactTextString(m) }
+func (*PluginConfig) ProtoMessage()    {}
+func (*PluginConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d089a30c2106007, []int{3}
+}
+
+func (m *PluginConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PluginConfig.Unmarshal(m, b)
+}
+func (m *PluginConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PluginConfig.Marshal(b, m, deterministic)
+}
+func (m *PluginConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PluginConfig.Merge(m, src)
+}
+func (m *PluginConfig) XXX_Size() int {
+	return xxx_messageInfo_PluginConfig.Size(m)
+}
+func (m *PluginConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_PluginConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PluginConfig proto.InternalMessageInfo
+
+func (m *PluginConfig) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *PluginConfig) GetOptions() string {
+	if m != nil {
+		return m.Options
+	}
+	return ""
+}
+
+func (m *PluginConfig) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.proxy.shadowsocks.CipherType", CipherType_name, CipherType_value)
 	proto.RegisterEnum("v2ray.core.proxy.shadowsocks.Account_OneTimeAuth", Account_OneTimeAuth_name, Account_OneTimeAuth_value)
 	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.shadowsocks.Account")
 	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.shadowsocks.ServerConfig")
 	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.shadowsocks.ClientConfig")
+	proto.RegisterType((*PluginConfig)(nil), "v2ray.core.proxy.shadowsocks.PluginConfig")
 }
 
 func init() {
@@ -258,38 +386,51 @@ func init() {
 }
 
 var fileDescriptor_8d089a30c2106007 = []byte{
-	// 522 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x92, 0xc1, 0x6e, 0xd3, 0x4e,
-	0x10, 0xc6, 0xbb, 0x71, 0xff, 0x4d, 0xfe, 0xe3, 0x50, 0xdc, 0x95, 0x90, 0xac, 0xa8, 0x42, 0x56,
-	0x38, 0x10, 0x2a, 0xb1, 0x4e, 0x5c, 0x8a, 0x7a, 0x75, 0x4c, 0x4a, 0x2b, 0xc0, 0x89, 0x9c, 0x14,
-	0x04, 0x17, 0xcb, 0x5d, 0x2f, 0xc4, 0x6a, 0xe2, 0xb5, 0xd6, 0x76, 0x43, 0x9e, 0x86, 0x03, 0x37,
-	0xde, 0x8c, 0xb7, 0x40, 0x5e, 0x3b, 0xa9, 0x85, 0xaa, 0x70, 0x88, 0x94, 0x99, 0xfd, 0x7d, 0x9f,
-	0x66, 0xbe, 0x31, 0xbc, 0xbc, 0xb3, 0x44, 0xb0, 0x26, 0x94, 0x2f, 0x4d, 0xca, 0x05, 0x33, 0x13,
-	0xc1, 0xbf, 0xaf, 0xcd, 0x74, 0x1e, 0x84, 0x7c, 0x95, 0x72, 0x7a, 0x9b, 0x9a, 0x94, 0xc7, 0x5f,
-	0xa3, 0x6f, 0x24, 0x11, 0x3c, 0xe3, 0xf8, 0x78, 0x83, 0x0b, 0x46, 0x24, 0x4a, 0x6a, 0x68, 0xe7,
-	0xf9, 0x5f, 0x66, 0x94, 0x2f, 0x97, 0x3c, 0x36, 0x63, 0x96, 0x15, 0xbf, 0x15, 0x17, 0xb7, 0xa5,
-	0x4d, 0xe7, 0xc5, 0xc3, 0xa0, 0x7c, 0xa4, 0x7c, 0x61, 0xe6, 0x29, 0x13, 0x15, 0xda, 0xff, 0x07,
-	0x9a, 0x32, 0x71, 0xc7, 0x84, 0x9f, 0x26, 0x8c, 0x96, 0x8a, 0xee, 0x6f, 0x04, 0x4d, 0x9b, 0x52,
-	0x9e, 0xc7, 0x19, 0xee, 0x40, 0x2b, 0x09, 0xd2, 0x74, 0xc5, 0x45, 0xa8, 0x23, 0x03, 0xf5, 0xfe,
-	0xf7, 0xb6, 0x35, 0xbe, 0x02, 0x95, 0x46, 0xc9, 0x9c, 0x09, 0x3f, 0x5b, 0x27, 0x4c, 0x6f, 0x18,
-	0xa8, 0x77, 0x68, 0xf5, 0xc8, 0xae, 0x0d, 0x89, 0x23, 0x05, 0xb3, 0x75, 0xc2, 0x3c, 0xa0, 0xdb,
-	0xff, 0xd8, 0x01, 0x85, 0x67, 0x81, 0xae, 0x48, 0x8b, 0xc1, 0x6e, 0x8b, 0x6a, 0x34, 0x32, 0x8e,
-	0xd9, 0x2c, 0x5a, 0x32, 0x3b, 0xcf, 0xe6, 0x5e, 0xa1, 0xee, 0x5a, 0xa0, 0xd6, 0x7a, 0xb8, 0x05,
-	0xfb, 0x76, 0x9e, 0x71, 0x6d, 0x0f, 0xb7, 0xa1, 0xf5, 0x26, 0x4a, 0x83, 0x9b, 0x05, 0x0b, 0x35,
-	0x84, 0x55, 0x68, 0x8e, 0xe2, 0xb2, 0x68, 0x74, 0x7f, 0x22, 0x68, 0x4f, 0x65, 0x02, 0x8e, 0x3c,
-	0x13, 0x7e, 0x06, 0x6a, 0x1e, 0x26, 0x3e, 0x2b, 0x09, 0xb9, 0x73, 0x6b, 0xd8, 0xd0, 0x91, 0x07,
-	0x79, 0x98, 0x54, 0x3a, 0xfc, 0x0a, 0xf6, 0x8b, 0x84, 0xe5, 0xca, 0xaa, 0x65, 0xd4, 0xe7, 0x2d,
-	0xe3, 0x25, 0x9b, 0x78, 0xc9, 0x75, 0xca, 0x84, 0x27, 0x69, 0x7c, 0x0e, 0xcd, 0xea, 0x8a, 0xba,
-	0x62, 0x28, 0xbd, 0x43, 0xeb, 0xe9, 0x03, 0xc2, 0x98, 0x65, 0xc4, 0x2d, 0x29, 0x6f, 0x83, 0x77,
-	0x3d, 0x68, 0x3b, 0x8b, 0x88, 0xc5, 0x59, 0x35, 0xe4, 0x10, 0x0e, 0xca, 0xb3, 0xe9, 0xc8, 0x50,
-	0x7a, 0xaa, 0x75, 0xb2, 0x6b, 0x82, 0x72, 0xbd, 0x51, 0x1c, 0x26, 0x3c, 0x8a, 0x33, 0xaf, 0x52,
-	0x9e, 0xfc, 0x40, 0x00, 0xf7, 0xd7, 0x28, 0x52, 0xb9, 0x76, 0xdf, 0xb9, 0xe3, 0x4f, 0xae, 0xb6,
-	0x87, 0x1f, 0x83, 0x6a, 0x8f, 0xa6, 0xfe, 0xc0, 0x3a, 0xf7, 0x9d, 0x8b, 0xa1, 0x86, 0x36, 0x0d,
-	0xeb, 0xec, 0xb5, 0x6c, 0x34, 0x8a, 0x48, 0x9d, 0x4b, 0xdb, 0xb9, 0xb4, 0xad, 0xbe, 0xa6, 0xe0,
-	0x23, 0x78, 0xb4, 0xa9, 0xfc, 0xab, 0xd1, 0xec, 0x42, 0xdb, 0xaf, 0x5b, 0xbc, 0x75, 0x3e, 0x68,
-	0xff, 0xd5, 0x2d, 0x8a, 0xc6, 0x01, 0x7e, 0x02, 0x47, 0x5b, 0xd1, 0x64, 0xfc, 0xfe, 0xf3, 0xe0,
-	0xb4, 0x7f, 0xa6, 0x35, 0x8b, 0xb3, 0xb9, 0x63, 0x77, 0xa4, 0xb5, 0x86, 0x13, 0x30, 0x28, 0x5f,
-	0xee, 0xfc, 0x18, 0x26, 0xe8, 0x8b, 0x5a, 0x2b, 0x7f, 0x35, 0x8e, 0x3f, 0x5a, 0x5e, 0xb0, 0x26,
-	0x4e, 0x41, 0x4f, 0x24, 0x3d, 0xbd, 0x7f, 0xbe, 0x39, 0x90, 0xa1, 0x9c, 0xfe, 0x09, 0x00, 0x00,
-	0xff, 0xff, 0xdc, 0x7e, 0x6b, 0x61, 0xb5, 0x03, 0x00, 0x00,
+	// 730 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0xdd, 0x4e, 0xdb, 0x48,
+	0x14, 0xc6, 0x71, 0x48, 0x9c, 0xe3, 0xc0, 0x9a, 0x91, 0x76, 0x65, 0x21, 0xb4, 0xeb, 0x0d, 0x17,
+	0x9b, 0x45, 0x5a, 0x07, 0xcc, 0x82, 0xb8, 0x75, 0x4c, 0x28, 0x7f, 0x4d, 0x22, 0xf3, 0x53, 0xb5,
+	0x37, 0x96, 0xb1, 0x07, 0xb0, 0x48, 0x66, 0xac, 0x19, 0x07, 0x9a, 0x17, 0xe9, 0x43, 0xf4, 0x8d,
+	0xfa, 0x08, 0xbd, 0xe8, 0x3b, 0x54, 0x1e, 0xdb, 0xe0, 0x06, 0x9a, 0x56, 0xbd, 0x9b, 0x73, 0xe6,
+	0xfb, 0xbe, 0x39, 0xe7, 0x7c, 0x73, 0xe0, 0xbf, 0x7b, 0x8b, 0xf9, 0x53, 0x33, 0xa0, 0xe3, 0x4e,
+	0x40, 0x19, 0xee, 0xc4, 0x8c, 0xbe, 0x9f, 0x76, 0xf8, 0xad, 0x1f, 0xd2, 0x07, 0x4e, 0x83, 0x3b,
+	0xde, 0x09, 0x28, 0xb9, 0x8e, 0x6e, 0xcc, 0x98, 0xd1, 0x84, 0xa2, 0xb5, 0x02, 0xce, 0xb0, 0x29,
+	0xa0, 0x66, 0x09, 0xba, 0xfa, 0xcf, 0x8c, 0x58, 0x40, 0xc7, 0x63, 0x4a, 0x3a, 0x04, 0x27, 0x1d,
+	0x3f, 0x0c, 0x19, 0xe6, 0x3c, 0x93, 0x99, 0x07, 0x24, 0x38, 0x79, 0xa0, 0xec, 0x2e, 0x07, 0xfe,
+	0xfb, 0x32, 0x50, 0x5c, 0x06, 0x74, 0xd4, 0x99, 0x70, 0xcc, 0x72, 0xe8, 0xe6, 0x0f, 0xa0, 0x1c,
+	0xb3, 0x7b, 0xcc, 0x3c, 0x1e, 0xe3, 0x20, 0x67, 0x6c, 0xbc, 0xd8, 0xfb, 0xb5, 0x3f, 0x1a, 0x5d,
+	0xf9, 0xc1, 0xdd, 0x37, 0x8d, 0xb7, 0x3e, 0x4b, 0x50, 0xb7, 0x83, 0x80, 0x4e, 0x48, 0x82, 0x56,
+	0x41, 0x89, 0x7d, 0xce, 0x1f, 0x28, 0x0b, 0x75, 0xc9, 0x90, 0xda, 0x0d, 0xf7, 0x31, 0x46, 0x47,
+	0xa0, 0x06, 0x51, 0x7c, 0x8b, 0x99, 0x97, 0x4c, 0x63, 0xac, 0x57, 0x0c, 0xa9, 0xbd, 0x6c, 0xb5,
+	0xcd, 0x79, 0x63, 0x33, 0x1d, 0x41, 0x38, 0x9f, 0xc6, 0xd8, 0x85, 0xe0, 0xf1, 0x8c, 0x1c, 0x90,
+	0x69, 0xe2, 0xeb, 0xb2, 0x90, 0xd8, 0x9a, 0x2f, 0x91, 0x97, 0x66, 0x0e, 0x08, 0x3e, 0x8f, 0xc6,
+	0xd8, 0x9e, 0x24, 0xb7, 0x6e, 0xca, 0x6e, 0x59, 0xa0, 0x96, 0x72, 0x48, 0x81, 0xaa, 0x3d, 0x49,
+	0xa8, 0xb6, 0x80, 0x9a, 0xa0, 0xec, 0x47, 0xdc, 0xbf, 0x1a, 0xe1, 0x50, 0x93, 0x90, 0x0a, 0xf5,
+	0x1e, 0xc9, 0x82, 0x4a, 0xeb, 0x93, 0x0c, 0xcd, 0x33, 0x31, 0x2d, 0x47, 0x8c, 0x00, 0xad, 0x83,
+	0x3a, 0x09, 0x63, 0x0f, 0x67, 0x08, 0xd1, 0xb3, 0xd2, 0xad, 0xe8, 0x92, 0x0b, 0x93, 0x30, 0xce,
+	0x79, 0xe8, 0x7f, 0xa8, 0xa6, 0x6e, 0x88, 0x96, 0x55, 0xcb, 0x28, 0xd7, 0x9b, 0x59, 0x61, 0x16,
+	0x56, 0x98, 0x17, 0x1c, 0x33, 0x57, 0xa0, 0xd1, 0x1e, 0xd4, 0x73, 0xc7, 0x75, 0xd9, 0x90, 0xdb,
+	0xcb, 0xd6, 0x9f, 0x2f, 0x10, 0x09, 0x4e, 0xcc, 0x7e, 0x86, 0x72, 0x0b, 0x38, 0xb2, 0xa1, 0x51,
+	0x58, 0xc5, 0xf5, 0xaa, 0x21, 0xb7, 0x55, 0x6b, 0xfd, 0xf9, 0x90, 0x0a, 0x88, 0x79, 0x90, 0x1f,
+	0xdc, 0x27, 0x16, 0xda, 0x85, 0xc5, 0xb4, 0x08, 0xae, 0x2f, 0x1a, 0xf2, 0x4f, 0xd5, 0x9c, 0xc1,
+	0x51, 0x17, 0x6a, 0xf1, 0x68, 0x72, 0x13, 0x11, 0xbd, 0x26, 0x9a, 0xdd, 0x98, 0x6f, 0xce, 0x50,
+	0x60, 0xb3, 0x59, 0xba, 0x39, 0x13, 0x1d, 0xc2, 0x72, 0x76, 0xf2, 0xf2, 0xd5, 0xd0, 0xeb, 0x42,
+	0xeb, 0xef, 0xef, 0xf4, 0x7f, 0x34, 0x1c, 0xb0, 0x7d, 0x3a, 0xf6, 0x23, 0xe2, 0x2e, 0x65, 0x44,
+	0x3b, 0xe3, 0xa1, 0xbf, 0x40, 0xcd, 0x95, 0x62, 0xca, 0x12, 0x5d, 0x31, 0xa4, 0xf6, 0x92, 0x0b,
+	0x59, 0x6a, 0x48, 0x59, 0x72, 0x5c, 0x55, 0x1a, 0x1a, 0x1c, 0x57, 0x15, 0xd0, 0xd4, 0xd6, 0x07,
+	0x09, 0x9a, 0xce, 0x28, 0xc2, 0x24, 0xc9, 0xbd, 0xed, 0x42, 0x2d, 0xdb, 0x0c, 0x5d, 0x32, 0xe4,
+	0xd9, 0x5e, 0x66, 0x87, 0x90, 0xfd, 0x8a, 0x1e, 0x09, 0x63, 0x1a, 0x91, 0xc4, 0xcd, 0x99, 0xa5,
+	0x79, 0x54, 0x7e, 0x75, 0x1e, 0xad, 0x4b, 0x68, 0x96, 0xf3, 0x48, 0x87, 0x7a, 0xfa, 0xba, 0x4f,
+	0x8a, 0x1d, 0x2b, 0xc2, 0xf4, 0x86, 0xc6, 0x49, 0x44, 0x09, 0x17, 0xcf, 0x35, 0xdc, 0x22, 0x44,
+	0x08, 0xaa, 0x3e, 0xbb, 0xe1, 0xe2, 0x27, 0x35, 0x5c, 0x71, 0xde, 0xf8, 0x22, 0x01, 0x3c, 0x2d,
+	0x58, 0xfa, 0xd1, 0x2f, 0xfa, 0x27, 0xfd, 0xc1, 0x9b, 0xbe, 0xb6, 0x80, 0x7e, 0x03, 0xd5, 0xee,
+	0x9d, 0x79, 0x5b, 0xd6, 0x9e, 0xe7, 0x1c, 0x74, 0x35, 0xa9, 0x48, 0x58, 0x3b, 0xbb, 0x22, 0x51,
+	0x49, 0xb7, 0xc4, 0x39, 0xb4, 0x9d, 0x43, 0xdb, 0xda, 0xd4, 0x64, 0xb4, 0x02, 0x4b, 0x45, 0xe4,
+	0x1d, 0xf5, 0xce, 0x0f, 0xb4, 0x6a, 0x59, 0xe2, 0x95, 0xf3, 0x5a, 0x5b, 0x2c, 0x4b, 0xa4, 0x89,
+	0x1a, 0xfa, 0x1d, 0x56, 0x1e, 0x49, 0xc3, 0xc1, 0xe9, 0xdb, 0xad, 0xed, 0xcd, 0x1d, 0xad, 0x9e,
+	0x6e, 0x62, 0x7f, 0xd0, 0xef, 0x69, 0x0a, 0xfa, 0x03, 0x50, 0xf7, 0xd4, 0x3e, 0xe9, 0x6d, 0x7b,
+	0x65, 0xa5, 0xc6, 0x4c, 0xbe, 0x10, 0x04, 0xb4, 0x06, 0x7a, 0x9e, 0x7f, 0xae, 0xab, 0x76, 0x87,
+	0x60, 0x04, 0x74, 0x3c, 0xd7, 0x80, 0xa1, 0xf4, 0x4e, 0x2d, 0x85, 0x1f, 0x2b, 0x6b, 0x97, 0x96,
+	0xeb, 0x4f, 0x4d, 0x27, 0x45, 0x0f, 0x05, 0xfa, 0xec, 0xe9, 0xfa, 0xaa, 0x26, 0xec, 0xdf, 0xfe,
+	0x3a, 0x00, 0x5c, 0x74, 0x10, 0x06, 0x2b, 0x06, 0x00, 0x00,
 }
@@ -0,0 +1,135 @@
+// +build !confonly
+
+package shadowsocks
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/signal/done"
+)
+
+const (
+	pluginMinRestartDelay = time.Second
+	pluginMaxRestartDelay = time.Minute
+	// A plugin that runs longer than this is considered healthy, and is restarted without delay growth.
+	pluginStableTime = time.Minute
+)
+
+// pluginProcess runs a SIP003 plugin, and restarts it with exponential backoff when it exits.
+type pluginProcess struct {
+	config *PluginConfig
+	env    []string
+	done   *done.Instance
+
+	access sync.Mutex
+	cmd    *exec.Cmd
+}
+
+// startPlugin starts a plugin that forwards connections between the remote and the local destination. An error
+// is returned if the plugin can't be started at all. Later failures are logged, and the plugin is restarted.
+func startPlugin(config *PluginConfig, remote, local net.Destination) (*pluginProcess, error) {
+	p := &pluginProcess{
+		config: config,
+		env: append(os.Environ(),
+			"SS_REMOTE_HOST="+remote.Address.String(),
+			"SS_REMOTE_PORT="+remote.Port.String(),
+			"SS_LOCAL_HOST="+local.Address.String(),
+			"SS_LOCAL_PORT="+local.Port.String(),
+			"SS_PLUGIN_OPTIONS="+config.Options,
+		),
+		done: done.New(),
+	}
+	if err := p.start(); err != nil {
+		return nil, newError("failed to start plugin ", config.Command).Base(err)
+	}
+	go p.supervise()
+	return p, nil
+}
+
+func (p *pluginProcess) start() error {
+	cmd := exec.Command(p.config.Command, p.config.Args...)
+	cmd.Env = p.env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	p.access.Lock()
+	p.cmd = cmd
+	p.access.Unlock()
+	return nil
+}
+
+func (p *pluginProcess) supervise() {
+	delay := pluginMinRestartDelay
+	for {
+		p.access.Lock()
+		cmd := p.cmd
+		p.access.Unlock()
+
+		startTime := time.Now()
+		err := cmd.Wait()
+		if p.done.Done() {
+			return
+		}
+		newError("plugin ", p.config.Command, " exited").Base(err).AtWarning().WriteToLog()
+
+		if time.Since(startTime) > pluginStableTime {
+			delay = pluginMinRestartDelay
+		}
+		for {
+			select {
+			case <-p.done.Wait():
+				return
+			case <-time.After(delay):
+			}
+			if delay *= 2; delay > pluginMaxRestartDelay {
+				delay = pluginMaxRestartDelay
+			}
+
+			err := p.start()
+			if err == nil {
+				break
+			}
+			newError("failed to restart plugin ", p.config.Command).Base(err).AtWarning().WriteToLog()
+		}
+
+		// Closed while restarting.
+		if p.done.Done() {
+			p.kill()
+			return
+		}
+	}
+}
+
+func (p *pluginProcess) kill() {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.cmd != nil && p.cmd.Process != nil {
+		p.cmd.Process.Kill() // nolint: errcheck
+	}
+}
+
+// Close stops the plugin. It may be called multiple times.
+func (p *pluginProcess) Close() error {
+	p.done.Close() // nolint: errcheck
+	p.kill()
+	return nil
+}
+
+// pickLocalPort returns a free TCP port on the loopback interface.
+func pickLocalPort() (net.Port, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return net.Port(listener.Addr().(*net.TCPAddr).Port), nil
+}
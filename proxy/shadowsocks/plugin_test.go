@@ -0,0 +1,230 @@
+package shadowsocks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/proxyman"
+	_ "v2ray.com/core/app/proxyman/inbound"
+	_ "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/features/inbound"
+	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/testing/servers/tcp"
+)
+
+func buildTestPlugin(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "v2ray")
+	common.Must(err)
+	file := filepath.Join(dir, "sip003")
+	if runtime.GOOS == "windows" {
+		file += ".exe"
+	}
+	cmd := exec.Command("go", "build", "-o", file, "v2ray.com/core/testing/servers/sip003")
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatal("failed to build plugin: ", err)
+	}
+	return file
+}
+
+func testPluginConn(dest net.Destination) error {
+	conn, err := net.Dial("tcp", dest.NetAddr())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	common.Must2(conn.Write([]byte("plugin")))
+	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	response := make([]byte, 6)
+	if _, err := conn.Read(response); err != nil {
+		return err
+	}
+	// The echo server responds with obfuscated bytes, which are restored by the plugin.
+	if string(response) != "plugin" {
+		return newError("unexpected response: ", response)
+	}
+	return nil
+}
+
+func TestPluginRestart(t *testing.T) {
+	pluginPath := buildTestPlugin(t)
+	defer os.RemoveAll(filepath.Dir(pluginPath))
+
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte { return b },
+	}
+	remote, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	port, err := pickLocalPort()
+	common.Must(err)
+	local := net.TCPDestination(net.LocalHostIP, port)
+
+	plugin, err := startPlugin(&PluginConfig{Command: pluginPath}, remote, local)
+	common.Must(err)
+	defer plugin.Close()
+
+	connect := func() error {
+		var err error
+		for i := 0; i < 50; i++ {
+			if err = testPluginConn(local); err == nil {
+				return nil
+			}
+			time.Sleep(time.Millisecond * 100)
+		}
+		return err
+	}
+
+	if err := connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	plugin.access.Lock()
+	killed := plugin.cmd
+	plugin.access.Unlock()
+	common.Must(killed.Process.Kill())
+
+	restarted := false
+	for i := 0; i < 50 && !restarted; i++ {
+		time.Sleep(time.Millisecond * 100)
+		plugin.access.Lock()
+		restarted = plugin.cmd != killed
+		plugin.access.Unlock()
+	}
+	if !restarted {
+		t.Fatal("plugin is not restarted")
+	}
+	if err := connect(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPluginNotFound(t *testing.T) {
+	_, err := startPlugin(&PluginConfig{Command: "/nonexistent/sip003"}, net.TCPDestination(net.LocalHostIP, 1), net.TCPDestination(net.LocalHostIP, 2))
+	if err == nil {
+		t.Error("nil error")
+	}
+}
+
+func TestServerPluginLocal(t *testing.T) {
+	newConfig := func(portRange *net.PortRange) *core.Config {
+		return &core.Config{
+			App: []*serial.TypedMessage{
+				serial.ToTypedMessage(&dispatcher.Config{}),
+				serial.ToTypedMessage(&proxyman.InboundConfig{}),
+				serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			},
+			Inbound: []*core.InboundHandlerConfig{
+				{
+					Tag: "in",
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: portRange,
+					}),
+					ProxySettings: serial.ToTypedMessage(&ServerConfig{
+						User: &protocol.User{
+							Account: serial.ToTypedMessage(&Account{
+								Password:   "password",
+								CipherType: CipherType_AES_128_GCM,
+							}),
+						},
+						Plugin:     &PluginConfig{Command: "sip003"},
+						PluginPort: 8388,
+					}),
+				},
+			},
+		}
+	}
+
+	port := tcp.PickPort()
+	v, err := core.New(newConfig(net.SinglePortRange(port)))
+	common.Must(err)
+
+	handler, err := v.GetFeature(inbound.ManagerType()).(inbound.Manager).GetHandler(context.Background(), "in")
+	common.Must(err)
+	p, _, _ := handler.(interface {
+		GetRandomInboundProxy() (interface{}, net.Port, int)
+	}).GetRandomInboundProxy()
+	server := p.(*Server)
+	if server.local != net.TCPDestination(net.LocalHostIP, port) {
+		t.Error("unexpected local destination of plugin: ", server.local)
+	}
+	// The plugin is started with the inbound.
+	if server.plugin != nil {
+		t.Error("plugin is started before the inbound")
+	}
+
+	if _, err := core.New(newConfig(&net.PortRange{From: uint32(port), To: uint32(port) + 1})); err == nil {
+		t.Error("expected error for plugin of an inbound with multiple ports, but nil")
+	}
+}
+
+func TestClientPluginStart(t *testing.T) {
+	pluginPath := buildTestPlugin(t)
+	defer os.RemoveAll(filepath.Dir(pluginPath))
+
+	remote := net.TCPDestination(net.LocalHostIP, tcp.PickPort())
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag: "out",
+				ProxySettings: serial.ToTypedMessage(&ClientConfig{
+					Server: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(remote.Address),
+							Port:    uint32(remote.Port),
+							User: []*protocol.User{
+								{
+									Account: serial.ToTypedMessage(&Account{
+										Password:   "password",
+										CipherType: CipherType_AES_128_GCM,
+									}),
+								},
+							},
+						},
+					},
+					Plugin: &PluginConfig{Command: pluginPath},
+				}),
+			},
+		},
+	})
+	common.Must(err)
+
+	handler := v.GetFeature(outbound.ManagerType()).(outbound.Manager).GetHandler("out")
+	client := handler.(proxy.GetOutbound).GetOutbound().(*Client)
+
+	// The plugin is started with the outbound.
+	if dest := client.pluginDest(remote); dest != remote {
+		t.Error("plugin is started before the outbound: ", dest)
+	}
+
+	common.Must(v.Start())
+	dest := client.pluginDest(remote)
+	if dest == remote {
+		t.Fatal("plugin is not started with the outbound")
+	}
+
+	common.Must(v.Close())
+	if dest := client.pluginDest(remote); dest != remote {
+		t.Error("plugin is not stopped with the outbound: ", dest)
+	}
+}
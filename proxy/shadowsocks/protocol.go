@@ -289,6 +289,8 @@ func DecodeUDPPacket(user *protocol.MemoryUser, payload *buf.Buffer) (*protocol.
 type UDPReader struct {
 	Reader io.Reader
 	User   *protocol.MemoryUser
+	// Session must be set for Shadowsocks 2022 ciphers.
+	Session *UDPSession
 }
 
 func (v *UDPReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
@@ -298,7 +300,12 @@ func (v *UDPReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
 		buffer.Release()
 		return nil, err
 	}
-	_, payload, err := DecodeUDPPacket(v.User, buffer)
+	var payload *buf.Buffer
+	if v.Session != nil {
+		_, payload, err = v.Session.DecodeUDPPacket(v.User, buffer)
+	} else {
+		_, payload, err = DecodeUDPPacket(v.User, buffer)
+	}
 	if err != nil {
 		buffer.Release()
 		return nil, err
@@ -309,11 +316,19 @@ func (v *UDPReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
 type UDPWriter struct {
 	Writer  io.Writer
 	Request *protocol.RequestHeader
+	// Session must be set for Shadowsocks 2022 ciphers.
+	Session *UDPSession
 }
 
 // Write implements io.Writer.
 func (w *UDPWriter) Write(payload []byte) (int, error) {
-	packet, err := EncodeUDPPacket(w.Request, payload)
+	var packet *buf.Buffer
+	var err error
+	if w.Session != nil {
+		packet, err = w.Session.EncodeUDPPacket(w.Request, payload)
+	} else {
+		packet, err = EncodeUDPPacket(w.Request, payload)
+	}
 	if err != nil {
 		return 0, err
 	}
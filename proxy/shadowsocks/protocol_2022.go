@@ -0,0 +1,520 @@
+// +build !confonly
+
+package shadowsocks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/crypto"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/protocol"
+)
+
+const (
+	headerTypeClient byte = 0
+	headerTypeServer byte = 1
+
+	// maxTimeDifference is the maximum difference between the timestamp in a header and local time.
+	maxTimeDifference = 30 * time.Second
+	maxPaddingLength  = 900
+
+	// fixedHeaderSize is the size of header type, timestamp and length in a request header.
+	fixedHeaderSize = 1 + 8 + 2
+	// udpHeaderSize is the size of session ID and packet ID of a UDP packet.
+	udpHeaderSize = 8 + 8
+)
+
+func checkTimestamp(b []byte) error {
+	t := time.Unix(int64(binary.BigEndian.Uint64(b)), 0)
+	if diff := time.Since(t); diff > maxTimeDifference || diff < -maxTimeDifference {
+		return newError("invalid timestamp: ", t)
+	}
+	return nil
+}
+
+func putTimestamp(b []byte) {
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Unix()))
+}
+
+// WriteTCPRequest2022 writes a Shadowsocks 2022 request header into the given writer, and returns a writer for
+// body, as well as the salt of the request, which the response header refers to.
+func WriteTCPRequest2022(request *protocol.RequestHeader, writer io.Writer) (buf.Writer, []byte, error) {
+	account := request.User.Account.(*MemoryAccount)
+	cipher := account.Cipher.(*AEAD2022Cipher)
+
+	salt := make([]byte, cipher.IVSize())
+	common.Must2(rand.Read(salt))
+	auth := cipher.createAuthenticator(account.Key, salt)
+
+	variableHeader := buf.New()
+	defer variableHeader.Release()
+
+	if err := addrParser.WriteAddressPort(variableHeader, request.Address, request.Port); err != nil {
+		return nil, nil, newError("failed to write address").Base(err)
+	}
+	// Padding is required as there is no initial payload.
+	paddingLen := dice.Roll(maxPaddingLength) + 1
+	binary.BigEndian.PutUint16(variableHeader.Extend(2), uint16(paddingLen))
+	common.Must2(rand.Read(variableHeader.Extend(int32(paddingLen))))
+
+	header := buf.New()
+	defer header.Release()
+
+	common.Must2(header.Write(salt))
+	fixedHeader := header.Extend(fixedHeaderSize + int32(auth.Overhead()))
+	fixedHeader[0] = headerTypeClient
+	putTimestamp(fixedHeader[1:])
+	binary.BigEndian.PutUint16(fixedHeader[9:], uint16(variableHeader.Len()))
+	if _, err := auth.Seal(fixedHeader[:0], fixedHeader[:fixedHeaderSize]); err != nil {
+		return nil, nil, newError("failed to seal header").Base(err)
+	}
+	if _, err := auth.Seal(header.Extend(variableHeader.Len() + int32(auth.Overhead()))[:0], variableHeader.Bytes()); err != nil {
+		return nil, nil, newError("failed to seal header").Base(err)
+	}
+
+	if err := buf.WriteAllBytes(writer, header.Bytes()); err != nil {
+		return nil, nil, newError("failed to write header").Base(err)
+	}
+
+	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, writer, protocol.TransferTypeStream, nil), salt, nil
+}
+
+// ReadTCPSession2022 reads a Shadowsocks 2022 TCP session from the given reader, returns its header, the salt of
+// the request and remaining parts.
+func ReadTCPSession2022(user *protocol.MemoryUser, reader io.Reader) (*protocol.RequestHeader, []byte, buf.Reader, error) {
+	account := user.Account.(*MemoryAccount)
+	cipher := account.Cipher.(*AEAD2022Cipher)
+
+	salt := make([]byte, cipher.IVSize())
+	if _, err := io.ReadFull(reader, salt); err != nil {
+		return nil, nil, nil, newError("failed to read salt").Base(err)
+	}
+	auth := cipher.createAuthenticator(account.Key, salt)
+
+	fixedHeader := make([]byte, fixedHeaderSize+auth.Overhead())
+	if _, err := io.ReadFull(reader, fixedHeader); err != nil {
+		return nil, nil, nil, newError("failed to read header").Base(err)
+	}
+	fixedHeader, err := auth.Open(fixedHeader[:0], fixedHeader)
+	if err != nil {
+		return nil, nil, nil, newError("failed to decrypt header").Base(err)
+	}
+	if fixedHeader[0] != headerTypeClient {
+		return nil, nil, nil, newError("unexpected header type: ", fixedHeader[0])
+	}
+	if err := checkTimestamp(fixedHeader[1:]); err != nil {
+		return nil, nil, nil, err
+	}
+
+	mb, err := readChunk2022(auth, reader, int32(binary.BigEndian.Uint16(fixedHeader[9:])))
+	if err != nil {
+		return nil, nil, nil, newError("failed to read header").Base(err)
+	}
+	variableHeader := &buf.MultiBufferContainer{MultiBuffer: mb}
+
+	addr, port, err := addrParser.ReadAddressPort(nil, variableHeader)
+	if err != nil {
+		buf.ReleaseMulti(variableHeader.MultiBuffer)
+		return nil, nil, nil, newError("failed to read address").Base(err)
+	}
+
+	var paddingLen [2]byte
+	if _, err := io.ReadFull(variableHeader, paddingLen[:]); err != nil {
+		buf.ReleaseMulti(variableHeader.MultiBuffer)
+		return nil, nil, nil, newError("failed to read padding").Base(err)
+	}
+	padding := make([]byte, binary.BigEndian.Uint16(paddingLen[:]))
+	if _, err := io.ReadFull(variableHeader, padding); err != nil {
+		buf.ReleaseMulti(variableHeader.MultiBuffer)
+		return nil, nil, nil, newError("failed to read padding").Base(err)
+	}
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+		Command: protocol.RequestCommandTCP,
+		Address: addr,
+		Port:    port,
+	}
+
+	return request, salt, newChunkReader2022(auth, reader, variableHeader.MultiBuffer), nil
+}
+
+// WriteTCPResponse2022 returns a writer for the response of the request with the given salt. The response header
+// is written along with the first chunk of body, as it contains the size of the chunk.
+func WriteTCPResponse2022(request *protocol.RequestHeader, requestSalt []byte, writer io.Writer) (buf.Writer, error) {
+	account := request.User.Account.(*MemoryAccount)
+	cipher := account.Cipher.(*AEAD2022Cipher)
+
+	salt := make([]byte, cipher.IVSize())
+	common.Must2(rand.Read(salt))
+
+	return &responseWriter2022{
+		auth:        cipher.createAuthenticator(account.Key, salt),
+		salt:        salt,
+		requestSalt: requestSalt,
+		writer:      writer,
+	}, nil
+}
+
+type responseWriter2022 struct {
+	auth        *crypto.AEADAuthenticator
+	salt        []byte
+	requestSalt []byte
+	writer      io.Writer
+	body        buf.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *responseWriter2022) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if w.body != nil {
+		return w.body.WriteMultiBuffer(mb)
+	}
+
+	overhead := int32(w.auth.Overhead())
+	headerSize := 1 + 8 + int32(len(w.requestSalt)) + 2
+
+	header := buf.New()
+	defer header.Release()
+
+	common.Must2(header.Write(w.salt))
+	fixedHeader := header.Extend(headerSize + overhead)
+
+	payload := make([]byte, buf.Size-header.Len()-overhead)
+	mb, n := buf.SplitBytes(mb, payload)
+
+	fixedHeader[0] = headerTypeServer
+	putTimestamp(fixedHeader[1:])
+	copy(fixedHeader[9:], w.requestSalt)
+	binary.BigEndian.PutUint16(fixedHeader[headerSize-2:], uint16(n))
+	if _, err := w.auth.Seal(fixedHeader[:0], fixedHeader[:headerSize]); err != nil {
+		buf.ReleaseMulti(mb)
+		return newError("failed to seal header").Base(err)
+	}
+	if _, err := w.auth.Seal(header.Extend(int32(n) + overhead)[:0], payload[:n]); err != nil {
+		buf.ReleaseMulti(mb)
+		return newError("failed to seal header").Base(err)
+	}
+
+	if err := buf.WriteAllBytes(w.writer, header.Bytes()); err != nil {
+		buf.ReleaseMulti(mb)
+		return newError("failed to write header").Base(err)
+	}
+
+	w.body = crypto.NewAuthenticationWriter(w.auth, &crypto.AEADChunkSizeParser{
+		Auth: w.auth,
+	}, w.writer, protocol.TransferTypeStream, nil)
+	if mb.IsEmpty() {
+		return nil
+	}
+	return w.body.WriteMultiBuffer(mb)
+}
+
+// ReadTCPResponse2022 reads the response of the request with the given salt, and returns a reader for its body.
+func ReadTCPResponse2022(user *protocol.MemoryUser, requestSalt []byte, reader io.Reader) (buf.Reader, error) {
+	account := user.Account.(*MemoryAccount)
+	cipher := account.Cipher.(*AEAD2022Cipher)
+
+	salt := make([]byte, cipher.IVSize())
+	if _, err := io.ReadFull(reader, salt); err != nil {
+		return nil, newError("failed to read salt").Base(err)
+	}
+	auth := cipher.createAuthenticator(account.Key, salt)
+
+	fixedHeader := make([]byte, 1+8+len(requestSalt)+2+auth.Overhead())
+	if _, err := io.ReadFull(reader, fixedHeader); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+	fixedHeader, err := auth.Open(fixedHeader[:0], fixedHeader)
+	if err != nil {
+		return nil, newError("failed to decrypt header").Base(err)
+	}
+	if fixedHeader[0] != headerTypeServer {
+		return nil, newError("unexpected header type: ", fixedHeader[0])
+	}
+	if err := checkTimestamp(fixedHeader[1:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(fixedHeader[9:9+len(requestSalt)], requestSalt) {
+		return nil, newError("response is not for this request")
+	}
+
+	mb, err := readChunk2022(auth, reader, int32(binary.BigEndian.Uint16(fixedHeader[9+len(requestSalt):])))
+	if err != nil {
+		return nil, newError("failed to read first chunk").Base(err)
+	}
+
+	return newChunkReader2022(auth, reader, mb), nil
+}
+
+// saltFilter remembers the salts of recent requests, and rejects requests that reuse them. A salt needs to be
+// remembered only until the timestamp of its request expires.
+type saltFilter struct {
+	sync.Mutex
+	salts       map[string]time.Time
+	lastCleanup time.Time
+}
+
+func newSaltFilter() *saltFilter {
+	return &saltFilter{
+		salts:       make(map[string]time.Time),
+		lastCleanup: time.Now(),
+	}
+}
+
+// Add returns false if the salt has been added before.
+func (f *saltFilter) Add(salt []byte) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	now := time.Now()
+	if now.Sub(f.lastCleanup) > maxTimeDifference {
+		for s, t := range f.salts {
+			if now.Sub(t) > 2*maxTimeDifference {
+				delete(f.salts, s)
+			}
+		}
+		f.lastCleanup = now
+	}
+
+	if _, found := f.salts[string(salt)]; found {
+		return false
+	}
+	f.salts[string(salt)] = now
+	return true
+}
+
+const replayWindowSize = 64
+
+// replayWindow is a sliding window of the received packet IDs of a UDP session.
+type replayWindow struct {
+	last uint64
+	// bitmap has its n-th bit set if packet last-n has been received.
+	bitmap uint64
+}
+
+// Check returns false if the packet has been received, or is too old to tell.
+func (w *replayWindow) Check(id uint64) bool {
+	if id > w.last {
+		if shift := id - w.last; shift < replayWindowSize {
+			w.bitmap <<= shift
+		} else {
+			w.bitmap = 0
+		}
+		w.bitmap |= 1
+		w.last = id
+		return true
+	}
+
+	diff := w.last - id
+	if diff >= replayWindowSize || w.bitmap&(1<<diff) != 0 {
+		return false
+	}
+	w.bitmap |= 1 << diff
+	return true
+}
+
+// UDPSession is one side of a Shadowsocks 2022 UDP session.
+type UDPSession struct {
+	server   bool
+	id       uint64
+	packetID uint64
+	// remoteID is the session ID of the other side. It is fixed on server side.
+	remoteID uint64
+	window   replayWindow
+}
+
+func randomSessionID() uint64 {
+	var b [8]byte
+	common.Must2(rand.Read(b[:]))
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// NewUDPSession creates the client side of a new UDP session.
+func NewUDPSession() *UDPSession {
+	return &UDPSession{
+		id: randomSessionID(),
+	}
+}
+
+// EncodeUDPPacket encodes a packet of the session.
+func (s *UDPSession) EncodeUDPPacket(request *protocol.RequestHeader, payload []byte) (*buf.Buffer, error) {
+	account := request.User.Account.(*MemoryAccount)
+	cipher, ok := account.Cipher.(*AEAD2022Cipher)
+	if !ok {
+		return nil, newError("not a Shadowsocks 2022 cipher")
+	}
+
+	buffer := buf.New()
+	if nonceLen := cipher.udpNonceSize(); nonceLen > 0 {
+		common.Must2(buffer.ReadFullFrom(rand.Reader, nonceLen))
+	}
+
+	header := buffer.Extend(udpHeaderSize)
+	binary.BigEndian.PutUint64(header, s.id)
+	binary.BigEndian.PutUint64(header[8:], atomic.AddUint64(&s.packetID, 1)-1)
+
+	if s.server {
+		header = buffer.Extend(1 + 8 + 8 + 2)
+		header[0] = headerTypeServer
+		putTimestamp(header[1:])
+		binary.BigEndian.PutUint64(header[9:], s.remoteID)
+	} else {
+		header = buffer.Extend(1 + 8 + 2)
+		header[0] = headerTypeClient
+		putTimestamp(header[1:])
+	}
+	// No padding.
+	binary.BigEndian.PutUint16(header[len(header)-2:], 0)
+
+	if err := addrParser.WriteAddressPort(buffer, request.Address, request.Port); err != nil {
+		buffer.Release()
+		return nil, newError("failed to write address").Base(err)
+	}
+
+	buffer.Write(payload)
+
+	if err := cipher.EncodePacket(account.Key, buffer); err != nil {
+		buffer.Release()
+		return nil, newError("failed to encrypt UDP payload").Base(err)
+	}
+
+	return buffer, nil
+}
+
+// DecodeUDPPacket decodes a packet from the server of the session. Packets of other sessions and replayed
+// packets are rejected.
+func (s *UDPSession) DecodeUDPPacket(user *protocol.MemoryUser, payload *buf.Buffer) (*protocol.RequestHeader, *buf.Buffer, error) {
+	packet, err := decodeUDPPacket2022(user, payload, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if packet.clientID != s.id {
+		return nil, nil, newError("unexpected client session ID")
+	}
+	if packet.sessionID != s.remoteID {
+		s.remoteID = packet.sessionID
+		s.window = replayWindow{}
+	}
+	if !s.window.Check(packet.packetID) {
+		return nil, nil, newError("replayed packet: ", packet.packetID)
+	}
+	return packet.request, payload, nil
+}
+
+// serverUDPSessions keeps the server side of the UDP sessions from a client, by their client session IDs.
+type serverUDPSessions map[uint64]*UDPSession
+
+// DecodeUDPPacket decodes a packet from a client, and returns the server side of its session.
+func (m serverUDPSessions) DecodeUDPPacket(user *protocol.MemoryUser, payload *buf.Buffer) (*UDPSession, *protocol.RequestHeader, error) {
+	packet, err := decodeUDPPacket2022(user, payload, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, found := m[packet.sessionID]
+	if !found {
+		session = &UDPSession{
+			server:   true,
+			id:       randomSessionID(),
+			remoteID: packet.sessionID,
+		}
+		m[packet.sessionID] = session
+	}
+	if !session.window.Check(packet.packetID) {
+		return nil, nil, newError("replayed packet: ", packet.packetID)
+	}
+	return session, packet.request, nil
+}
+
+type udpPacket2022 struct {
+	sessionID uint64
+	packetID  uint64
+	// clientID is the client session ID, in packets from server only.
+	clientID uint64
+	request  *protocol.RequestHeader
+}
+
+// decodeUDPPacket2022 decrypts and parses a UDP packet, and leaves only the payload in the buffer.
+func decodeUDPPacket2022(user *protocol.MemoryUser, payload *buf.Buffer, fromServer bool) (*udpPacket2022, error) {
+	account := user.Account.(*MemoryAccount)
+	cipher, ok := account.Cipher.(*AEAD2022Cipher)
+	if !ok {
+		return nil, newError("not a Shadowsocks 2022 cipher")
+	}
+
+	if err := cipher.DecodePacket(account.Key, payload); err != nil {
+		return nil, newError("failed to decrypt UDP payload").Base(err)
+	}
+
+	packet := &udpPacket2022{
+		sessionID: binary.BigEndian.Uint64(payload.BytesTo(8)),
+		packetID:  binary.BigEndian.Uint64(payload.BytesRange(8, 16)),
+	}
+	payload.Advance(udpHeaderSize)
+
+	headerType := headerTypeClient
+	headerSize := int32(1 + 8 + 2)
+	if fromServer {
+		headerType = headerTypeServer
+		headerSize += 8
+	}
+	if payload.Len() < headerSize {
+		return nil, newError("insufficient data: ", payload.Len())
+	}
+	header := payload.BytesTo(headerSize)
+	if header[0] != headerType {
+		return nil, newError("unexpected header type: ", header[0])
+	}
+	if err := checkTimestamp(header[1:]); err != nil {
+		return nil, err
+	}
+	if fromServer {
+		packet.clientID = binary.BigEndian.Uint64(header[9:])
+	}
+	paddingLen := int32(binary.BigEndian.Uint16(header[headerSize-2:]))
+	if payload.Len() < headerSize+paddingLen {
+		return nil, newError("insufficient data: ", payload.Len())
+	}
+	payload.Advance(headerSize + paddingLen)
+
+	addr, port, err := addrParser.ReadAddressPort(nil, payload)
+	if err != nil {
+		return nil, newError("failed to parse address").Base(err)
+	}
+
+	packet.request = &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+		Command: protocol.RequestCommandUDP,
+		Address: addr,
+		Port:    port,
+	}
+	return packet, nil
+}
+
+type udpSessionKey int
+
+const udpSessionContextKey udpSessionKey = 0
+
+func contextWithUDPSession(ctx context.Context, session *UDPSession) context.Context {
+	return context.WithValue(ctx, udpSessionContextKey, session)
+}
+
+func udpSessionFromContext(ctx context.Context) *UDPSession {
+	if session, ok := ctx.Value(udpSessionContextKey).(*UDPSession); ok {
+		return session
+	}
+	return nil
+}
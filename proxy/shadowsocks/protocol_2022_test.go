@@ -0,0 +1,91 @@
+package shadowsocks
+
+import (
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+)
+
+func TestReplayWindow(t *testing.T) {
+	var w replayWindow
+	for _, c := range []struct {
+		id       uint64
+		accepted bool
+	}{
+		{0, true},
+		{0, false},
+		{2, true},
+		{1, true},
+		{1, false},
+		{100, true},
+		{36, false},
+		{37, true},
+		{37, false},
+		{99, true},
+	} {
+		if r := w.Check(c.id); r != c.accepted {
+			t.Error("packet ", c.id, ": expected ", c.accepted, ", but got ", r)
+		}
+	}
+}
+
+func TestUDPSession2022(t *testing.T) {
+	for _, cipherType := range []CipherType{CipherType_BLAKE3_AES_128_GCM, CipherType_BLAKE3_CHACHA20_POLY1305} {
+		password := "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+		if cipherType == CipherType_BLAKE3_AES_128_GCM {
+			password = "AAECAwQFBgcICQoLDA0ODw=="
+		}
+		account, err := (&Account{
+			Password:   password,
+			CipherType: cipherType,
+		}).AsAccount()
+		common.Must(err)
+		user := &protocol.MemoryUser{Account: account}
+
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandUDP,
+			Address: net.LocalHostIP,
+			Port:    53,
+			User:    user,
+		}
+
+		client := NewUDPSession()
+		servers := make(serverUDPSessions)
+
+		packet, err := client.EncodeUDPPacket(request, []byte("request"))
+		common.Must(err)
+		replayed := buf.New()
+		replayed.Write(packet.Bytes())
+
+		server, decodedRequest, err := servers.DecodeUDPPacket(user, packet)
+		common.Must(err)
+		if decodedRequest.Destination() != request.Destination() {
+			t.Error("unexpected destination: ", decodedRequest.Destination())
+		}
+		if packet.String() != "request" {
+			t.Error("unexpected request payload: ", packet.String())
+		}
+
+		if _, _, err := servers.DecodeUDPPacket(user, replayed); err == nil {
+			t.Error("accepted replayed packet")
+		}
+
+		packet, err = server.EncodeUDPPacket(request, []byte("response"))
+		common.Must(err)
+		_, payload, err := client.DecodeUDPPacket(user, packet)
+		common.Must(err)
+		if payload.String() != "response" {
+			t.Error("unexpected response payload: ", payload.String())
+		}
+
+		packet, err = server.EncodeUDPPacket(request, []byte("response"))
+		common.Must(err)
+		if _, _, err := NewUDPSession().DecodeUDPPacket(user, packet); err == nil {
+			t.Error("accepted packet of another session")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package shadowsocks_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -190,3 +191,90 @@ func TestUDPReaderWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestTCPRequest2022(t *testing.T) {
+	accounts := []*Account{
+		{
+			Password:   "AAECAwQFBgcICQoLDA0ODw==",
+			CipherType: CipherType_BLAKE3_AES_128_GCM,
+		},
+		{
+			Password:   "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=",
+			CipherType: CipherType_BLAKE3_AES_256_GCM,
+		},
+		{
+			Password:   "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=",
+			CipherType: CipherType_BLAKE3_CHACHA20_POLY1305,
+		},
+	}
+
+	for _, account := range accounts {
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("v2ray.com"),
+			Port:    1234,
+			User: &protocol.MemoryUser{
+				Email:   "love@v2ray.com",
+				Account: toAccount(account),
+			},
+		}
+
+		cache := buf.New()
+		defer cache.Release()
+
+		writer, requestSalt, err := WriteTCPRequest2022(request, cache)
+		common.Must(err)
+
+		payload := buf.New()
+		common.Must2(payload.WriteString("test request"))
+		common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{payload}))
+
+		decodedRequest, decodedSalt, reader, err := ReadTCPSession2022(request.User, cache)
+		common.Must(err)
+		if decodedRequest.Destination() != request.Destination() {
+			t.Error("unexpected destination: ", decodedRequest.Destination())
+		}
+		if r := cmp.Diff(decodedSalt, requestSalt); r != "" {
+			t.Error("salt: ", r)
+		}
+
+		mb, err := reader.ReadMultiBuffer()
+		common.Must(err)
+		if mb.String() != "test request" {
+			t.Error("unexpected request payload: ", mb.String())
+		}
+
+		cache.Clear()
+		writer, err = WriteTCPResponse2022(decodedRequest, decodedSalt, cache)
+		common.Must(err)
+
+		payload = buf.New()
+		common.Must2(payload.WriteString("test response"))
+		common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{payload}))
+
+		response := append([]byte(nil), cache.Bytes()...)
+		reader, err = ReadTCPResponse2022(request.User, requestSalt, cache)
+		common.Must(err)
+
+		mb, err = reader.ReadMultiBuffer()
+		common.Must(err)
+		if mb.String() != "test response" {
+			t.Error("unexpected response payload: ", mb.String())
+		}
+
+		if _, err := ReadTCPResponse2022(request.User, make([]byte, len(requestSalt)), bytes.NewReader(response)); err == nil {
+			t.Error("accepted response of another request")
+		}
+	}
+}
+
+func TestTCPRequest2022InvalidKey(t *testing.T) {
+	_, err := (&Account{
+		Password:   "AAECAwQFBgcICQoLDA0ODw==",
+		CipherType: CipherType_BLAKE3_AES_256_GCM,
+	}).AsAccount()
+	if err == nil {
+		t.Error("accepted key of wrong size")
+	}
+}
@@ -4,11 +4,14 @@ package shadowsocks
 
 import (
 	"context"
+	"io"
+	"sync"
 	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
@@ -18,37 +21,120 @@ import (
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/udp"
 )
 
 type Server struct {
 	config        ServerConfig
-	user          *protocol.MemoryUser
+	validator     *Validator
+	salts         *saltFilter
 	policyManager policy.Manager
+
+	// local is where the plugin forwards connections to, which is the listening address of the inbound.
+	local  net.Destination
+	access sync.Mutex
+	plugin *pluginProcess
 }
 
 // NewServer create a new Shadowsocks server.
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
-	if config.GetUser() == nil {
+	users := config.Users
+	if config.User != nil {
+		users = append([]*protocol.User{config.User}, users...)
+	}
+	if len(users) == 0 {
 		return nil, newError("user is not specified")
 	}
 
-	mUser, err := config.User.ToMemoryUser()
-	if err != nil {
-		return nil, newError("failed to parse user account").Base(err)
+	validator := NewValidator()
+	for _, user := range users {
+		mUser, err := user.ToMemoryUser()
+		if err != nil {
+			return nil, newError("failed to parse user account").Base(err)
+		}
+		if err := validator.Add(mUser); err != nil {
+			return nil, newError("failed to add user").Base(err)
+		}
 	}
 
 	v := core.MustFromContext(ctx)
 	s := &Server{
 		config:        *config,
-		user:          mUser,
+		validator:     validator,
+		salts:         newSaltFilter(),
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
 	}
 
+	if config.Plugin != nil {
+		if config.PluginPort == 0 {
+			return nil, newError("plugin port must be specified for plugin")
+		}
+		inbound := session.InboundFromContext(ctx)
+		if inbound == nil || !inbound.Gateway.IsValid() {
+			return nil, newError("plugin requires a single listening address and port of the inbound")
+		}
+		s.local = net.TCPDestination(inbound.Gateway.Address, inbound.Gateway.Port)
+		switch s.local.Address {
+		case net.AnyIP:
+			s.local.Address = net.LocalHostIP
+		case net.AnyIPv6:
+			s.local.Address = net.LocalHostIPv6
+		}
+	}
+
 	return s, nil
 }
 
+// Start implements common.Runnable. It starts the plugin if any, unless it is running.
+func (s *Server) Start() error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if s.config.Plugin == nil || s.plugin != nil {
+		return nil
+	}
+	remote := net.TCPDestination(net.AnyIP, net.Port(s.config.PluginPort))
+	if s.config.PluginAddress != nil {
+		remote.Address = s.config.PluginAddress.AsAddress()
+	}
+	plugin, err := startPlugin(s.config.Plugin, remote, s.local)
+	if err != nil {
+		return err
+	}
+	s.plugin = plugin
+	return nil
+}
+
+// Close implements common.Closable. It stops the plugin if any.
+func (s *Server) Close() error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if s.plugin == nil {
+		return nil
+	}
+	err := s.plugin.Close()
+	s.plugin = nil
+	return err
+}
+
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
+	return s.validator.Add(u)
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	return s.validator.Remove(email)
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return s.validator.GetUsers()
+}
+
 func (s *Server) Network() []net.Network {
 	list := s.config.Network
 	if len(list) == 0 {
@@ -77,9 +163,18 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		if request == nil {
 			return
 		}
+		response := *request
+		response.Address = packet.Source.Address
+		response.Port = packet.Source.Port
 
 		payload := packet.Payload
-		data, err := EncodeUDPPacket(request, payload.Bytes())
+		var data *buf.Buffer
+		var err error
+		if udpSession := udpSessionFromContext(ctx); udpSession != nil {
+			data, err = udpSession.EncodeUDPPacket(&response, payload.Bytes())
+		} else {
+			data, err = EncodeUDPPacket(&response, payload.Bytes())
+		}
 		payload.Release()
 		if err != nil {
 			newError("failed to encode UDP packet").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
@@ -90,13 +185,12 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		conn.Write(data.Bytes())
 	})
 
-	account := s.user.Account.(*MemoryAccount)
 	inbound := session.InboundFromContext(ctx)
 	if inbound == nil {
 		panic("no inbound metadata")
 	}
-	inbound.User = s.user
 
+	sessions := make(serverUDPSessions)
 	reader := buf.NewPacketReader(conn)
 	for {
 		mpayload, err := reader.ReadMultiBuffer()
@@ -105,7 +199,18 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		}
 
 		for _, payload := range mpayload {
-			request, data, err := DecodeUDPPacket(s.user, payload)
+			user, err := s.validator.GetUDP(inbound.Source.Address, payload)
+			var request *protocol.RequestHeader
+			var data *buf.Buffer
+			var udpSession *UDPSession
+			if err == nil {
+				if is2022(user.Account.(*MemoryAccount)) {
+					udpSession, request, err = sessions.DecodeUDPPacket(user, payload)
+					data = payload
+				} else {
+					request, data, err = DecodeUDPPacket(user, payload)
+				}
+			}
 			if err != nil {
 				if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
 					newError("dropping invalid UDP packet from: ", inbound.Source).Base(err).WriteToLog(session.ExportIDToError(ctx))
@@ -120,6 +225,8 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 				continue
 			}
 
+			inbound.User = user
+			account := user.Account.(*MemoryAccount)
 			if request.Option.Has(RequestOptionOneTimeAuth) && account.OneTimeAuth == Account_Disabled {
 				newError("client payload enables OTA but server doesn't allow it").WriteToLog(session.ExportIDToError(ctx))
 				payload.Release()
@@ -145,6 +252,9 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 			newError("tunnelling request to ", dest).WriteToLog(session.ExportIDToError(ctx))
 
 			ctx = protocol.ContextWithRequestHeader(ctx, request)
+			if udpSession != nil {
+				ctx = contextWithUDPSession(ctx, udpSession)
+			}
 			udpServer.Dispatch(ctx, dest, data)
 		}
 	}
@@ -153,11 +263,36 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 }
 
 func (s *Server) handleConnection(ctx context.Context, conn internet.Connection, dispatcher routing.Dispatcher) error {
-	sessionPolicy := s.policyManager.ForLevel(s.user.Level)
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil {
+		panic("no inbound metadata")
+	}
+
+	sessionPolicy := s.policyManager.ForLevel(s.validator.HandshakeLevel())
 	conn.SetReadDeadline(time.Now().Add(sessionPolicy.Timeouts.Handshake))
 
-	bufferedReader := buf.BufferedReader{Reader: buf.NewReader(conn)}
-	request, bodyReader, err := ReadTCPSession(s.user, &bufferedReader)
+	var recorder *fallback.Recorder
+	connReader := buf.NewReader(conn)
+	if len(s.config.Fallbacks) > 0 {
+		recorder = fallback.NewRecorder(connReader)
+		connReader = recorder
+	}
+
+	bufferedReader := buf.BufferedReader{Reader: connReader}
+	var request *protocol.RequestHeader
+	var requestSalt []byte
+	var bodyReader buf.Reader
+	user, reader, err := s.validator.GetTCP(inbound.Source.Address, &bufferedReader)
+	if err == nil {
+		if is2022(user.Account.(*MemoryAccount)) {
+			request, requestSalt, bodyReader, err = ReadTCPSession2022(user, reader)
+			if err == nil && !s.salts.Add(requestSalt) {
+				err = newError("replayed request")
+			}
+		} else {
+			request, bodyReader, err = ReadTCPSession(user, reader)
+		}
+	}
 	if err != nil {
 		log.Record(&log.AccessMessage{
 			From:   conn.RemoteAddr(),
@@ -165,15 +300,21 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 			Status: log.AccessRejected,
 			Reason: err,
 		})
-		return newError("failed to create request from: ", conn.RemoteAddr()).Base(err)
+		rejected := newError("failed to create request from: ", conn.RemoteAddr()).Base(err)
+		if recorder == nil || errors.Cause(err) == io.EOF {
+			return rejected
+		}
+		rejected.AtInfo().WriteToLog(session.ExportIDToError(ctx))
+		conn.SetReadDeadline(time.Time{})
+		return fallback.Serve(ctx, conn, recorder, s.config.Fallbacks, sessionPolicy)
 	}
 	conn.SetReadDeadline(time.Time{})
-
-	inbound := session.InboundFromContext(ctx)
-	if inbound == nil {
-		panic("no inbound metadata")
+	if recorder != nil {
+		recorder.Stop()
 	}
-	inbound.User = s.user
+
+	inbound.User = user
+	sessionPolicy = s.policyManager.ForLevel(user.Level)
 
 	dest := request.Destination()
 	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
@@ -198,7 +339,13 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
 		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
-		responseWriter, err := WriteTCPResponse(request, bufferedWriter)
+		var responseWriter buf.Writer
+		var err error
+		if requestSalt != nil {
+			responseWriter, err = WriteTCPResponse2022(request, requestSalt, bufferedWriter)
+		} else {
+			responseWriter, err = WriteTCPResponse(request, bufferedWriter)
+		}
 		if err != nil {
 			return newError("failed to write response").Base(err)
 		}
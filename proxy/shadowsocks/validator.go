@@ -0,0 +1,203 @@
+// +build !confonly
+
+package shadowsocks
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+)
+
+const maxCachedSources = 1024
+
+// Validator stores the users of a Shadowsocks server, and identifies the user of incoming connections and
+// packets by trying the key of each user.
+type Validator struct {
+	sync.RWMutex
+	users []*protocol.MemoryUser
+	// lastUser caches the last identified user of each source IP.
+	lastUser map[string]*protocol.MemoryUser
+}
+
+// NewValidator creates a new Validator.
+func NewValidator() *Validator {
+	return &Validator{
+		lastUser: make(map[string]*protocol.MemoryUser),
+	}
+}
+
+// Add adds a user. If there is more than one user, all users must use AEAD ciphers.
+func (v *Validator) Add(u *protocol.MemoryUser) error {
+	v.Lock()
+	defer v.Unlock()
+
+	account := u.Account.(*MemoryAccount)
+	if len(v.users) > 0 {
+		if is2022(account) || is2022(v.users[0].Account.(*MemoryAccount)) {
+			return newError("Shadowsocks 2022 ciphers don't support multiple users")
+		}
+		if !account.Cipher.IsAEAD() || account.CipherType == CipherType_NONE {
+			return newError("only AEAD ciphers are supported for multiple users")
+		}
+		for _, user := range v.users {
+			a := user.Account.(*MemoryAccount)
+			if !a.Cipher.IsAEAD() || a.CipherType == CipherType_NONE {
+				return newError("only AEAD ciphers are supported for multiple users")
+			}
+			if len(u.Email) > 0 && strings.EqualFold(user.Email, u.Email) {
+				return newError("User ", u.Email, " already exists.")
+			}
+		}
+	}
+
+	v.users = append(v.users, u)
+	return nil
+}
+
+func is2022(account *MemoryAccount) bool {
+	_, ok := account.Cipher.(*AEAD2022Cipher)
+	return ok
+}
+
+// Remove removes the user of the given email.
+func (v *Validator) Remove(email string) error {
+	if email == "" {
+		return newError("Email must not be empty.")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	for i, u := range v.users {
+		if strings.EqualFold(u.Email, email) {
+			v.users = append(v.users[:i], v.users[i+1:]...)
+			for source, user := range v.lastUser {
+				if user == u {
+					delete(v.lastUser, source)
+				}
+			}
+			return nil
+		}
+	}
+	return newError("User ", email, " not found.")
+}
+
+// GetUsers returns all users.
+func (v *Validator) GetUsers() []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	users := make([]*protocol.MemoryUser, len(v.users))
+	copy(users, v.users)
+	return users
+}
+
+// HandshakeLevel returns the level of the only user, or 0 if there are multiple users, whose levels are unknown
+// until the handshake is done.
+func (v *Validator) HandshakeLevel() uint32 {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 1 {
+		return v.users[0].Level
+	}
+	return 0
+}
+
+// candidates returns all users, starting with the last user identified from the source.
+func (v *Validator) candidates(source net.Address) []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	users := make([]*protocol.MemoryUser, 0, len(v.users))
+	var last *protocol.MemoryUser
+	if source != nil {
+		last = v.lastUser[source.String()]
+	}
+	if last != nil {
+		users = append(users, last)
+	}
+	for _, u := range v.users {
+		if u != last {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+func (v *Validator) remember(source net.Address, u *protocol.MemoryUser) {
+	if source == nil {
+		return
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if len(v.lastUser) >= maxCachedSources {
+		v.lastUser = make(map[string]*protocol.MemoryUser)
+	}
+	v.lastUser[source.String()] = u
+}
+
+// GetTCP identifies the user of a TCP connection from the given source, by reading the IV and the first chunk
+// size. It returns the user and a reader that replays the data read.
+func (v *Validator) GetTCP(source net.Address, reader io.Reader) (*protocol.MemoryUser, io.Reader, error) {
+	users := v.candidates(source)
+	switch len(users) {
+	case 0:
+		return nil, nil, newError("no user")
+	case 1:
+		return users[0], reader, nil
+	}
+
+	buffer := buf.New()
+	defer buffer.Release()
+
+	for _, u := range users {
+		account := u.Account.(*MemoryAccount)
+		cipher, ok := account.Cipher.(*AEADCipher)
+		if !ok {
+			continue
+		}
+		if size := cipher.IVSize() + 2 + 16; buffer.Len() < size {
+			if _, err := buffer.ReadFullFrom(reader, size-buffer.Len()); err != nil {
+				return nil, nil, newError("failed to read first chunk").Base(err)
+			}
+		}
+		if cipher.IsFirstChunk(account.Key, buffer.Bytes()) {
+			v.remember(source, u)
+			return u, io.MultiReader(bytes.NewReader(append([]byte(nil), buffer.Bytes()...)), reader), nil
+		}
+	}
+	return nil, nil, newError("invalid user")
+}
+
+// GetUDP identifies the user of a UDP packet from the given source.
+func (v *Validator) GetUDP(source net.Address, payload *buf.Buffer) (*protocol.MemoryUser, error) {
+	users := v.candidates(source)
+	switch len(users) {
+	case 0:
+		return nil, newError("no user")
+	case 1:
+		return users[0], nil
+	}
+
+	packet := buf.New()
+	defer packet.Release()
+
+	for _, u := range users {
+		account := u.Account.(*MemoryAccount)
+		packet.Clear()
+		packet.Write(payload.Bytes())
+		if err := account.Cipher.DecodePacket(account.Key, packet); err == nil {
+			v.remember(source, u)
+			return u, nil
+		}
+	}
+	return nil, newError("invalid user")
+}
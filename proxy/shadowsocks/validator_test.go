@@ -0,0 +1,130 @@
+package shadowsocks_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	. "v2ray.com/core/proxy/shadowsocks"
+)
+
+func TestValidatorMultipleUsers(t *testing.T) {
+	users := []*protocol.MemoryUser{
+		{
+			Email: "user1@v2ray.com",
+			Account: toAccount(&Account{
+				Password:   "password-1",
+				CipherType: CipherType_AES_128_GCM,
+			}),
+		},
+		{
+			Email: "user2@v2ray.com",
+			Account: toAccount(&Account{
+				Password:   "password-2",
+				CipherType: CipherType_AES_256_GCM,
+			}),
+		},
+		{
+			Email: "user3@v2ray.com",
+			Account: toAccount(&Account{
+				Password:   "password-3",
+				CipherType: CipherType_CHACHA20_POLY1305,
+			}),
+		},
+	}
+
+	validator := NewValidator()
+	for _, u := range users {
+		common.Must(validator.Add(u))
+	}
+
+	if err := validator.Add(&protocol.MemoryUser{
+		Account: toAccount(&Account{
+			Password:   "password-4",
+			CipherType: CipherType_AES_128_CFB,
+		}),
+	}); err == nil {
+		t.Error("added a user of stream cipher")
+	}
+	if err := validator.Add(users[0]); err == nil {
+		t.Error("added a duplicated user")
+	}
+
+	source := net.ParseAddress("192.0.2.1")
+	for _, user := range users {
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("v2ray.com"),
+			Port:    443,
+			User:    user,
+		}
+
+		cache := buf.New()
+		writer, err := WriteTCPRequest(request, cache)
+		common.Must(err)
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("payload"))))
+
+		u, reader, err := validator.GetTCP(source, cache)
+		common.Must(err)
+		if u != user {
+			t.Error("unexpected user: ", u.Email, ", expected: ", user.Email)
+		}
+		decodedRequest, _, err := ReadTCPSession(u, reader)
+		common.Must(err)
+		if decodedRequest.Destination() != request.Destination() {
+			t.Error("unexpected destination: ", decodedRequest.Destination())
+		}
+
+		request.Command = protocol.RequestCommandUDP
+		packet, err := EncodeUDPPacket(request, []byte("payload"))
+		common.Must(err)
+		u, err = validator.GetUDP(source, packet)
+		common.Must(err)
+		if u != user {
+			t.Error("unexpected user: ", u.Email, ", expected: ", user.Email)
+		}
+		_, data, err := DecodeUDPPacket(u, packet)
+		common.Must(err)
+		if data.String() != "payload" {
+			t.Error("unexpected payload: ", data.String())
+		}
+	}
+
+	common.Must(validator.Remove("user2@v2ray.com"))
+	if len(validator.GetUsers()) != 2 {
+		t.Error("unexpected number of users: ", len(validator.GetUsers()))
+	}
+	if err := validator.Remove("user2@v2ray.com"); err == nil {
+		t.Error("removed a user twice")
+	}
+}
+
+func TestValidatorHandshakeLevel(t *testing.T) {
+	validator := NewValidator()
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Email: "user1@v2ray.com",
+		Level: 2,
+		Account: toAccount(&Account{
+			Password:   "password-1",
+			CipherType: CipherType_AES_128_GCM,
+		}),
+	}))
+	if level := validator.HandshakeLevel(); level != 2 {
+		t.Error("expected level 2 of the only user, but got ", level)
+	}
+
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Email: "user2@v2ray.com",
+		Level: 1,
+		Account: toAccount(&Account{
+			Password:   "password-2",
+			CipherType: CipherType_AES_256_GCM,
+		}),
+	}))
+	if level := validator.HandshakeLevel(); level != 0 {
+		t.Error("expected level 0 of multiple users, but got ", level)
+	}
+}
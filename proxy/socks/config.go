@@ -2,7 +2,11 @@
 
 package socks
 
-import "v2ray.com/core/common/protocol"
+import (
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+)
 
 func (a *Account) Equals(another protocol.Account) bool {
 	if account, ok := another.(*Account); ok {
@@ -11,6 +15,11 @@ func (a *Account) Equals(another protocol.Account) bool {
 	return false
 }
 
+// ToProto implements protocol.ProtoAccount.
+func (a *Account) ToProto() proto.Message {
+	return a
+}
+
 func (a *Account) AsAccount() (protocol.Account, error) {
 	return a, nil
 }
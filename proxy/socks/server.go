@@ -194,7 +194,10 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 		if request == nil {
 			return
 		}
-		udpMessage, err := EncodeUDPPacket(request, payload.Bytes())
+		response := *request
+		response.Address = packet.Source.Address
+		response.Port = packet.Source.Port
+		udpMessage, err := EncodeUDPPacket(&response, payload.Bytes())
 		payload.Release()
 
 		defer udpMessage.Release()
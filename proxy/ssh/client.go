@@ -0,0 +1,448 @@
+// +build !confonly
+
+// Package ssh is an outbound handler that tunnels TCP connections through SSH servers, in direct-tcpip channels.
+package ssh
+
+//go:generate errorgen
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/retry"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/internet"
+)
+
+const (
+	keepAliveInterval = time.Second * 30
+	keepAliveTimeout  = time.Second * 15
+)
+
+// sshConn is an SSH connection in the pool of a server.
+type sshConn struct {
+	client *ssh.Client
+	// level is the user level of the connection.
+	level uint32
+	// Number of open channels, guarded by Client.access.
+	channels int
+}
+
+// Client is an outbound handler for SSH servers.
+type Client struct {
+	serverPicker    protocol.ServerPicker
+	policyManager   policy.Manager
+	hostKeyCallback ssh.HostKeyCallback
+	maxConnections  int
+
+	access sync.Mutex
+	conns  map[net.Destination][]*sshConn
+	// dialing serializes dialing of new connections to each server, so that the pools don't grow beyond the limit.
+	dialing map[net.Destination]*sync.Mutex
+}
+
+// NewClient creates a new SSH client based on the given config.
+func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	serverList := protocol.NewServerList()
+	for _, rec := range config.Server {
+		s, err := protocol.NewServerSpecFromPB(*rec)
+		if err != nil {
+			return nil, newError("failed to get server spec").Base(err)
+		}
+		serverList.AddServer(s)
+	}
+	if serverList.Size() == 0 {
+		return nil, newError("0 target server")
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case len(config.KnownHostsFile) > 0:
+		callback, err := knownhosts.New(config.KnownHostsFile)
+		if err != nil {
+			return nil, newError("failed to load known hosts file ", config.KnownHostsFile).Base(err)
+		}
+		hostKeyCallback = callback
+	case config.AllowInsecure:
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, newError("known hosts file is not specified")
+	}
+
+	maxConnections := int(config.MaxConnections)
+	if maxConnections == 0 {
+		maxConnections = 1
+	}
+
+	v := core.MustFromContext(ctx)
+	return &Client{
+		serverPicker:    protocol.NewRoundRobinServerPicker(serverList),
+		policyManager:   v.GetFeature(policy.ManagerType()).(policy.Manager),
+		hostKeyCallback: hostKeyCallback,
+		maxConnections:  maxConnections,
+		conns:           make(map[net.Destination][]*sshConn),
+		dialing:         make(map[net.Destination]*sync.Mutex),
+	}, nil
+}
+
+// Process implements proxy.Outbound.Process. It opens a direct-tcpip channel to the target, in a pooled SSH
+// connection to the server.
+func (c *Client) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil || !outbound.Target.IsValid() {
+		return newError("target not specified.")
+	}
+	destination := outbound.Target
+
+	if destination.Network == net.Network_UDP {
+		return newError("UDP is not supported by SSH outbound")
+	}
+
+	var conn *channelConn
+
+	if err := retry.ExponentialBackoff(5, 100).On(func() error {
+		server := c.serverPicker.PickServer()
+		channel, err := c.openChannel(ctx, dialer, server, destination)
+		if err != nil {
+			return err
+		}
+		conn = channel
+
+		return nil
+	}); err != nil {
+		return newError("failed to find an available destination").Base(err)
+	}
+
+	defer func() {
+		if err := conn.Close(); err != nil {
+			newError("failed to closed connection").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+
+	p := c.policyManager.ForLevel(conn.level)
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, p.Timeouts.ConnectionIdle)
+
+	requestFunc := func() error {
+		defer timer.SetTimeout(p.Timeouts.DownlinkOnly)
+		if err := buf.Copy(link.Reader, buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return err
+		}
+		conn.CloseWrite() // nolint: errcheck
+		return nil
+	}
+	responseFunc := func() error {
+		defer timer.SetTimeout(p.Timeouts.UplinkOnly)
+		return buf.Copy(buf.NewReader(conn), link.Writer, buf.UpdateActivity(timer))
+	}
+
+	var responseDonePost = task.OnSuccess(responseFunc, task.Close(link.Writer))
+	if err := task.Run(ctx, requestFunc, responseDonePost); err != nil {
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+// openChannel opens a direct-tcpip channel to the target, within the handshake timeout of the connection's user
+// level. A broken SSH connection is removed from the pool, so that a new one is dialed on retry.
+func (c *Client) openChannel(ctx context.Context, dialer internet.Dialer, server *protocol.ServerSpec, target net.Destination) (*channelConn, error) {
+	dest := server.Destination()
+	sc, err := c.getConn(ctx, dialer, server)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	// ssh.Client.Dial takes no context, so the channel is opened in background, and closed if it is too late.
+	done := make(chan result, 1)
+	go func() {
+		conn, err := sc.client.Dial("tcp", target.NetAddr())
+		done <- result{conn: conn, err: err}
+	}()
+
+	timer := time.NewTimer(c.policyManager.ForLevel(sc.level).Timeouts.Handshake)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			c.release(sc)
+			if _, ok := r.err.(*ssh.OpenChannelError); !ok {
+				c.remove(dest, sc)
+				sc.client.Close() // nolint: errcheck
+			}
+			return nil, newError("failed to open channel to ", target, " via ", dest).Base(r.err)
+		}
+		return &channelConn{
+			Conn:  r.conn,
+			level: sc.level,
+			release: func() {
+				c.release(sc)
+			},
+		}, nil
+	case <-timer.C:
+		err = newError("timeout")
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// The reservation is kept until the channel is opened or failed, as the connection is still busy with it.
+	go func() {
+		if r := <-done; r.conn != nil {
+			r.conn.Close() // nolint: errcheck
+		}
+		c.release(sc)
+	}()
+	return nil, newError("failed to open channel to ", target, " via ", dest).Base(err)
+}
+
+// getConn returns an SSH connection to the server for a new channel. A new connection is dialed if all pooled
+// connections have open channels and the pool is not full.
+func (c *Client) getConn(ctx context.Context, dialer internet.Dialer, server *protocol.ServerSpec) (*sshConn, error) {
+	dest := server.Destination()
+	if sc := c.pick(dest, false); sc != nil {
+		return sc, nil
+	}
+
+	dialing := c.dialLock(dest)
+	dialing.Lock()
+	defer dialing.Unlock()
+
+	if sc := c.pick(dest, false); sc != nil {
+		return sc, nil
+	}
+
+	sc, err := c.dial(ctx, dialer, server)
+	if err != nil {
+		// Share a pooled connection, if any.
+		if sc := c.pick(dest, true); sc != nil {
+			newError("failed to dial SSH connection to ", dest).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			return sc, nil
+		}
+		return nil, err
+	}
+
+	c.access.Lock()
+	sc.channels++
+	c.conns[dest] = append(c.conns[dest], sc)
+	c.access.Unlock()
+
+	go c.monitor(dest, sc)
+	return sc, nil
+}
+
+// dialLock returns the lock that serializes dialing to the server.
+func (c *Client) dialLock(dest net.Destination) *sync.Mutex {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	lock, found := c.dialing[dest]
+	if !found {
+		lock = new(sync.Mutex)
+		c.dialing[dest] = lock
+	}
+	return lock
+}
+
+// pick returns the pooled connection with the least open channels, and reserves a channel on it. Unless forced, it
+// returns nil if the connection has open channels and the pool is not full.
+func (c *Client) pick(dest net.Destination, force bool) *sshConn {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	conns := c.conns[dest]
+	var picked *sshConn
+	for _, sc := range conns {
+		if picked == nil || sc.channels < picked.channels {
+			picked = sc
+		}
+	}
+	if picked == nil {
+		return nil
+	}
+	if !force && picked.channels > 0 && len(conns) < c.maxConnections {
+		return nil
+	}
+	picked.channels++
+	return picked
+}
+
+func (c *Client) release(sc *sshConn) {
+	c.access.Lock()
+	sc.channels--
+	c.access.Unlock()
+}
+
+func (c *Client) remove(dest net.Destination, sc *sshConn) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	conns := c.conns[dest]
+	for i, conn := range conns {
+		if conn == sc {
+			conns = append(conns[:i:i], conns[i+1:]...)
+			break
+		}
+	}
+	if len(conns) == 0 {
+		delete(c.conns, dest)
+	} else {
+		c.conns[dest] = conns
+	}
+}
+
+// dial creates a new SSH connection to the server.
+func (c *Client) dial(ctx context.Context, dialer internet.Dialer, server *protocol.ServerSpec) (*sshConn, error) {
+	dest := server.Destination()
+	user := server.PickUser()
+	if user == nil {
+		return nil, newError("no user for ", dest)
+	}
+	account, ok := user.Account.(*Account)
+	if !ok {
+		return nil, newError("not an SSH account")
+	}
+
+	var auth []ssh.AuthMethod
+	if len(account.PrivateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey([]byte(account.PrivateKey))
+		if err != nil {
+			return nil, newError("failed to parse private key of ", account.Username).Base(err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if len(account.Password) > 0 {
+		auth = append(auth, ssh.Password(account.Password))
+	}
+
+	rawConn, err := dialer.Dial(ctx, dest)
+	if err != nil {
+		return nil, newError("failed to dial to ", dest).Base(err)
+	}
+
+	p := c.policyManager.ForLevel(user.Level)
+	if err := rawConn.SetDeadline(time.Now().Add(p.Timeouts.Handshake)); err != nil {
+		newError("failed to set deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+	conn, chans, reqs, err := ssh.NewClientConn(rawConn, dest.NetAddr(), &ssh.ClientConfig{
+		User:            account.Username,
+		Auth:            auth,
+		HostKeyCallback: c.hostKeyCallback,
+	})
+	if err != nil {
+		rawConn.Close() // nolint: errcheck
+		return nil, newError("failed to handshake with ", dest).Base(err)
+	}
+	if err := rawConn.SetDeadline(time.Time{}); err != nil {
+		newError("failed to clear deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	newError("SSH connection established to ", dest).WriteToLog(session.ExportIDToError(ctx))
+	return &sshConn{
+		client: ssh.NewClient(conn, chans, reqs),
+		level:  user.Level,
+	}, nil
+}
+
+// monitor sends keep-alive requests in the connection, and removes it from the pool when it is closed.
+func (c *Client) monitor(dest net.Destination, sc *sshConn) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			errc := make(chan error, 1)
+			go func() {
+				_, _, err := sc.client.SendRequest("keepalive@openssh.com", true, nil)
+				errc <- err
+			}()
+			select {
+			case err := <-errc:
+				if err == nil {
+					continue
+				}
+				newError("failed to send keep-alive to ", dest).Base(err).AtInfo().WriteToLog()
+			case <-time.After(keepAliveTimeout):
+				newError("keep-alive to ", dest, " timed out").AtInfo().WriteToLog()
+			}
+			sc.client.Close() // nolint: errcheck
+			return
+		}
+	}()
+
+	err := sc.client.Wait()
+	close(done)
+	c.remove(dest, sc)
+	newError("SSH connection to ", dest, " closed").Base(err).AtInfo().WriteToLog()
+}
+
+// Close implements common.Closable. It closes all pooled connections.
+func (c *Client) Close() error {
+	c.access.Lock()
+	var clients []*ssh.Client
+	for _, conns := range c.conns {
+		for _, sc := range conns {
+			clients = append(clients, sc.client)
+		}
+	}
+	c.conns = make(map[net.Destination][]*sshConn)
+	c.access.Unlock()
+
+	for _, client := range clients {
+		client.Close() // nolint: errcheck
+	}
+	return nil
+}
+
+// channelConn is a direct-tcpip channel, which releases its reservation in the pool on close.
+type channelConn struct {
+	net.Conn
+	// level is the user level of the SSH connection.
+	level   uint32
+	once    sync.Once
+	release func()
+}
+
+func (c *channelConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+// CloseWrite sends EOF to the target.
+func (c *channelConn) CloseWrite() error {
+	if cw, ok := c.Conn.(interface{ CloseWrite() error }); ok {
+		return cw.CloseWrite()
+	}
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ClientConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewClient(ctx, config.(*ClientConfig))
+	}))
+}
@@ -0,0 +1,293 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/features/policy"
+	sshtest "v2ray.com/core/testing/servers/ssh"
+	"v2ray.com/core/testing/servers/tcp"
+	"v2ray.com/core/transport/internet"
+)
+
+func newECDSAKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	common.Must(err)
+	return key
+}
+
+type testDialer struct{}
+
+func (testDialer) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	return net.Dial("tcp", dest.NetAddr())
+}
+
+func (testDialer) Address() net.Address {
+	return nil
+}
+
+func startServer(server *sshtest.Server) net.Destination {
+	server.Username = "v2ray"
+	dest, err := server.Start()
+	common.Must(err)
+	return dest
+}
+
+func newTestClient(dest net.Destination, account *Account, hostKeyCallback ssh.HostKeyCallback, maxConnections int) (*Client, *protocol.ServerSpec) {
+	spec := protocol.NewServerSpec(dest, protocol.AlwaysValid(), &protocol.MemoryUser{
+		Account: account,
+	})
+	serverList := protocol.NewServerList()
+	serverList.AddServer(spec)
+	return &Client{
+		serverPicker:    protocol.NewRoundRobinServerPicker(serverList),
+		policyManager:   policy.DefaultManager{},
+		hostKeyCallback: hostKeyCallback,
+		maxConnections:  maxConnections,
+		conns:           make(map[net.Destination][]*sshConn),
+		dialing:         make(map[net.Destination]*sync.Mutex),
+	}, spec
+}
+
+func startEchoServer() (*tcp.Server, net.Destination) {
+	echoServer := &tcp.Server{
+		MsgProcessor: func(b []byte) []byte { return b },
+	}
+	dest, err := echoServer.Start()
+	common.Must(err)
+	return echoServer, dest
+}
+
+func checkEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+	payload := []byte("v2ray over ssh")
+	common.Must2(conn.Write(payload))
+	response := make([]byte, len(payload))
+	common.Must2(io.ReadFull(conn, response))
+	if !bytes.Equal(response, payload) {
+		t.Error("unexpected response: ", string(response))
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	echoServer, target := startEchoServer()
+	defer echoServer.Close()
+
+	clientKey := newECDSAKey()
+	clientPublicKey, err := ssh.NewPublicKey(&clientKey.PublicKey)
+	common.Must(err)
+	keyBytes, err := x509.MarshalECPrivateKey(clientKey)
+	common.Must(err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}))
+
+	server := &sshtest.Server{
+		Password:      "password",
+		AuthorizedKey: clientPublicKey,
+	}
+	dest := startServer(server)
+	defer server.Close()
+
+	for _, account := range []*Account{
+		{Username: "v2ray", Password: "password"},
+		{Username: "v2ray", PrivateKey: privateKey},
+	} {
+		client, spec := newTestClient(dest, account, ssh.InsecureIgnoreHostKey(), 1)
+		conn, err := client.openChannel(context.Background(), testDialer{}, spec, target)
+		common.Must(err)
+		checkEcho(t, conn)
+		conn.Close()
+		client.Close()
+	}
+
+	client, spec := newTestClient(dest, &Account{Username: "v2ray", Password: "wrong"}, ssh.InsecureIgnoreHostKey(), 1)
+	defer client.Close()
+	if _, err := client.openChannel(context.Background(), testDialer{}, spec, target); err == nil {
+		t.Error("nil error with wrong password")
+	}
+}
+
+func TestKnownHosts(t *testing.T) {
+	echoServer, target := startEchoServer()
+	defer echoServer.Close()
+
+	server := &sshtest.Server{
+		Password: "password",
+	}
+	dest := startServer(server)
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "v2ray-ssh")
+	common.Must(err)
+	defer os.RemoveAll(tempDir)
+
+	otherKey, err := ssh.NewPublicKey(&newECDSAKey().PublicKey)
+	common.Must(err)
+	for _, tc := range []struct {
+		key   ssh.PublicKey
+		valid bool
+	}{
+		{key: server.HostKey(), valid: true},
+		{key: otherKey, valid: false},
+	} {
+		file := filepath.Join(tempDir, "known_hosts")
+		line := knownhosts.Line([]string{knownhosts.Normalize(dest.NetAddr())}, tc.key)
+		common.Must(ioutil.WriteFile(file, []byte(line+"\n"), 0600))
+		callback, err := knownhosts.New(file)
+		common.Must(err)
+
+		client, spec := newTestClient(dest, &Account{Username: "v2ray", Password: "password"}, callback, 1)
+		conn, err := client.openChannel(context.Background(), testDialer{}, spec, target)
+		if tc.valid {
+			common.Must(err)
+			checkEcho(t, conn)
+			conn.Close()
+		} else if err == nil {
+			t.Error("nil error with unknown host key")
+		}
+		client.Close()
+	}
+}
+
+func TestConnectionPool(t *testing.T) {
+	echoServer, target := startEchoServer()
+	defer echoServer.Close()
+
+	server := &sshtest.Server{
+		Password: "password",
+	}
+	dest := startServer(server)
+	defer server.Close()
+
+	client, spec := newTestClient(dest, &Account{Username: "v2ray", Password: "password"}, ssh.InsecureIgnoreHostKey(), 2)
+	defer client.Close()
+
+	// Channels are spread over 2 connections at most.
+	var conns []net.Conn
+	for i := 0; i < 4; i++ {
+		conn, err := client.openChannel(context.Background(), testDialer{}, spec, target)
+		common.Must(err)
+		checkEcho(t, conn)
+		conns = append(conns, conn)
+	}
+	if n := server.Accepted(); n != 2 {
+		t.Error("unexpected number of connections: ", n)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+
+	conn, err := client.openChannel(context.Background(), testDialer{}, spec, target)
+	common.Must(err)
+	checkEcho(t, conn)
+	conn.Close()
+	if n := server.Accepted(); n != 2 {
+		t.Error("unexpected number of connections: ", n)
+	}
+
+	// Broken connections are replaced by new ones.
+	server.CloseConnections()
+	deadline := time.Now().Add(time.Second * 5)
+	for {
+		conn, err := client.openChannel(context.Background(), testDialer{}, spec, target)
+		if err == nil {
+			checkEcho(t, conn)
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("failed to reconnect: ", err)
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	if n := server.Accepted(); n != 3 {
+		t.Error("unexpected number of connections: ", n)
+	}
+}
+
+// levelPolicyManager has a short handshake timeout for level 1.
+type levelPolicyManager struct {
+	policy.DefaultManager
+}
+
+func (m levelPolicyManager) ForLevel(level uint32) policy.Session {
+	p := m.DefaultManager.ForLevel(level)
+	if level == 1 {
+		p.Timeouts.Handshake = time.Millisecond * 200
+	}
+	return p
+}
+
+func TestChannelLevel(t *testing.T) {
+	echoServer, target := startEchoServer()
+	defer echoServer.Close()
+
+	server := &sshtest.Server{
+		Password: "password",
+	}
+	dest := startServer(server)
+	defer server.Close()
+
+	client, spec := newTestClient(dest, &Account{Username: "v2ray", Password: "password"}, ssh.InsecureIgnoreHostKey(), 1)
+	defer client.Close()
+	spec.PickUser().Level = 1
+
+	conn, err := client.openChannel(context.Background(), testDialer{}, spec, target)
+	common.Must(err)
+	defer conn.Close()
+	if conn.level != 1 {
+		t.Error("unexpected level: ", conn.level)
+	}
+}
+
+func TestChannelTimeout(t *testing.T) {
+	echoServer, target := startEchoServer()
+	defer echoServer.Close()
+
+	server := &sshtest.Server{
+		Password:     "password",
+		ChannelDelay: time.Second,
+	}
+	dest := startServer(server)
+	defer server.Close()
+
+	client, spec := newTestClient(dest, &Account{Username: "v2ray", Password: "password"}, ssh.InsecureIgnoreHostKey(), 1)
+	defer client.Close()
+	client.policyManager = levelPolicyManager{}
+	spec.PickUser().Level = 1
+
+	start := time.Now()
+	if _, err := client.openChannel(context.Background(), testDialer{}, spec, target); err == nil {
+		t.Fatal("nil error with delayed channel")
+	}
+	if elapsed := time.Since(start); elapsed > time.Millisecond*800 {
+		t.Error("channel timed out after ", elapsed)
+	}
+
+	// The reservation is released when the late channel is closed.
+	time.Sleep(time.Second * 2)
+	client.access.Lock()
+	defer client.access.Unlock()
+	for _, sc := range client.conns[dest] {
+		if sc.channels != 0 {
+			t.Error("unexpected number of channels: ", sc.channels)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package ssh
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+)
+
+func (a *Account) Equals(another protocol.Account) bool {
+	if account, ok := another.(*Account); ok {
+		return a.Username == account.Username
+	}
+	return false
+}
+
+// ToProto implements protocol.ProtoAccount.
+func (a *Account) ToProto() proto.Message {
+	return a
+}
+
+func (a *Account) AsAccount() (protocol.Account, error) {
+	if len(a.Username) == 0 {
+		return nil, newError("username is not specified")
+	}
+	if len(a.Password) == 0 && len(a.PrivateKey) == 0 {
+		return nil, newError("neither password nor private key is specified for ", a.Username)
+	}
+	return a, nil
+}
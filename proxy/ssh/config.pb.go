@@ -0,0 +1,177 @@
+package ssh
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+	protocol "v2ray.com/core/common/protocol"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Account struct {
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// Private key in PEM format. Either password or private key must be set.
+	PrivateKey           string   `protobuf:"bytes,3,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Account) Reset()         { *m = Account{} }
+func (m *Account) String() string { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()    {}
+func (*Account) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fed90457b3394cdd, []int{0}
+}
+
+func (m *Account) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Account.Unmarshal(m, b)
+}
+func (m *Account) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Account.Marshal(b, m, deterministic)
+}
+func (m *Account) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Account.Merge(m, src)
+}
+func (m *Account) XXX_Size() int {
+	return xxx_messageInfo_Account.Size(m)
+}
+func (m *Account) XXX_DiscardUnknown() {
+	xxx_messageInfo_Account.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Account proto.InternalMessageInfo
+
+func (m *Account) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *Account) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *Account) GetPrivateKey() string {
+	if m != nil {
+		return m.PrivateKey
+	}
+	return ""
+}
+
+// ClientConfig is the protobuf config for SSH client.
+type ClientConfig struct {
+	// Server is a list of SSH servers.
+	Server []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
+	// Path of the known_hosts file to verify host keys of the servers.
+	KnownHostsFile string `protobuf:"bytes,2,opt,name=known_hosts_file,json=knownHostsFile,proto3" json:"known_hosts_file,omitempty"`
+	// Whether to accept any host key, when known hosts file is not set.
+	AllowInsecure bool `protobuf:"varint,3,opt,name=allow_insecure,json=allowInsecure,proto3" json:"allow_insecure,omitempty"`
+	// Maximum number of SSH connections to each server. Default to 1.
+	MaxConnections       uint32   `protobuf:"varint,4,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientConfig) Reset()         { *m = ClientConfig{} }
+func (m *ClientConfig) String() string { return proto.CompactTextString(m) }
+func (*ClientConfig) ProtoMessage()    {}
+func (*ClientConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fed90457b3394cdd, []int{1}
+}
+
+func (m *ClientConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientConfig.Unmarshal(m, b)
+}
+func (m *ClientConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientConfig.Marshal(b, m, deterministic)
+}
+func (m *ClientConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientConfig.Merge(m, src)
+}
+func (m *ClientConfig) XXX_Size() int {
+	return xxx_messageInfo_ClientConfig.Size(m)
+}
+func (m *ClientConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientConfig proto.InternalMessageInfo
+
+func (m *ClientConfig) GetServer() []*protocol.ServerEndpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func (m *ClientConfig) GetKnownHostsFile() string {
+	if m != nil {
+		return m.KnownHostsFile
+	}
+	return ""
+}
+
+func (m *ClientConfig) GetAllowInsecure() bool {
+	if m != nil {
+		return m.AllowInsecure
+	}
+	return false
+}
+
+func (m *ClientConfig) GetMaxConnections() uint32 {
+	if m != nil {
+		return m.MaxConnections
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.ssh.Account")
+	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.ssh.ClientConfig")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/ssh/config.proto", fileDescriptor_fed90457b3394cdd)
+}
+
+var fileDescriptor_fed90457b3394cdd = []byte{
+	// 334 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0x4a, 0xf3, 0x40,
+	0x14, 0xc5, 0x49, 0xfb, 0xd1, 0xaf, 0x4e, 0x6d, 0x95, 0xd0, 0x45, 0xe8, 0xc6, 0x52, 0x28, 0x06,
+	0x17, 0x13, 0xa9, 0x1b, 0xb7, 0x36, 0x28, 0x8a, 0x9b, 0x92, 0x82, 0x0b, 0x37, 0x61, 0x3a, 0xbd,
+	0x35, 0x43, 0x93, 0xb9, 0x61, 0x6e, 0xfa, 0x27, 0xaf, 0xe4, 0xb3, 0xf8, 0x50, 0xd2, 0x49, 0xaa,
+	0x22, 0x2e, 0xef, 0xef, 0x9c, 0x33, 0x9c, 0xc3, 0xb0, 0xf1, 0x76, 0x62, 0x44, 0xc9, 0x25, 0x66,
+	0x81, 0x44, 0x03, 0x41, 0x6e, 0x70, 0x5f, 0x06, 0x44, 0x49, 0x20, 0x51, 0xaf, 0xd4, 0x1b, 0xcf,
+	0x0d, 0x16, 0xe8, 0xf6, 0x8f, 0x36, 0x03, 0xdc, 0x5a, 0x38, 0x51, 0x32, 0xb8, 0xfe, 0x15, 0x96,
+	0x98, 0x65, 0xa8, 0x03, 0x1b, 0x91, 0x98, 0x06, 0x04, 0x66, 0x0b, 0x26, 0xa6, 0x1c, 0x64, 0xf5,
+	0xce, 0x68, 0xc1, 0xfe, 0xdf, 0x49, 0x89, 0x1b, 0x5d, 0xb8, 0x03, 0xd6, 0xde, 0x10, 0x18, 0x2d,
+	0x32, 0xf0, 0x9c, 0xa1, 0xe3, 0x9f, 0x44, 0x5f, 0xf7, 0x41, 0xcb, 0x05, 0xd1, 0x0e, 0xcd, 0xd2,
+	0x6b, 0x54, 0xda, 0xf1, 0x76, 0x2f, 0x58, 0x27, 0x37, 0x6a, 0x2b, 0x0a, 0x88, 0xd7, 0x50, 0x7a,
+	0x4d, 0x2b, 0xb3, 0x1a, 0x3d, 0x43, 0x39, 0xfa, 0x70, 0xd8, 0x69, 0x98, 0x2a, 0xd0, 0x45, 0x68,
+	0x27, 0xb8, 0x53, 0xd6, 0xaa, 0x9a, 0x78, 0xce, 0xb0, 0xe9, 0x77, 0x26, 0x57, 0xfc, 0xc7, 0x9a,
+	0xaa, 0x33, 0x3f, 0x76, 0xe6, 0x73, 0xeb, 0xbc, 0xd7, 0xcb, 0x1c, 0x95, 0x2e, 0xa2, 0x3a, 0xe9,
+	0xfa, 0xec, 0x7c, 0xad, 0x71, 0xa7, 0xe3, 0x04, 0xa9, 0xa0, 0x78, 0xa5, 0x52, 0xa8, 0x9b, 0xf5,
+	0x2c, 0x7f, 0x3c, 0xe0, 0x07, 0x95, 0x82, 0x3b, 0x66, 0x3d, 0x91, 0xa6, 0xb8, 0x8b, 0x95, 0x26,
+	0x90, 0x1b, 0x03, 0xb6, 0x62, 0x3b, 0xea, 0x5a, 0xfa, 0x54, 0x43, 0xf7, 0x92, 0x9d, 0x65, 0x62,
+	0x1f, 0x4b, 0xd4, 0x1a, 0x64, 0xa1, 0x50, 0x93, 0xf7, 0x6f, 0xe8, 0xf8, 0xdd, 0xa8, 0x97, 0x89,
+	0x7d, 0xf8, 0x4d, 0xa7, 0xb7, 0xcc, 0x93, 0x98, 0xf1, 0xbf, 0x3e, 0x60, 0xe6, 0xbc, 0x36, 0x89,
+	0x92, 0xf7, 0x46, 0xff, 0x65, 0x12, 0x89, 0x92, 0x87, 0x07, 0x75, 0x66, 0xd5, 0x39, 0x25, 0x8b,
+	0x96, 0x1d, 0x75, 0xf3, 0x39, 0x00, 0xcb, 0x85, 0x31, 0xcd, 0xe4, 0x01, 0x00, 0x00,
+}
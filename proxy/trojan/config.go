@@ -0,0 +1,50 @@
+package trojan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+)
+
+// MemoryAccount is an account type converted from Account.
+type MemoryAccount struct {
+	Password string
+	// Key is the hex encoded SHA-224 hash of the password, which is sent in requests.
+	Key []byte
+}
+
+// AsAccount implements protocol.AsAccount.
+func (a *Account) AsAccount() (protocol.Account, error) {
+	if a.Password == "" {
+		return nil, newError("empty password")
+	}
+	return &MemoryAccount{
+		Password: a.Password,
+		Key:      hexSHA224(a.Password),
+	}, nil
+}
+
+// Equals implements protocol.Account.Equals().
+func (a *MemoryAccount) Equals(another protocol.Account) bool {
+	if account, ok := another.(*MemoryAccount); ok {
+		return a.Password == account.Password
+	}
+	return false
+}
+
+// ToProto implements protocol.ProtoAccount.ToProto().
+func (a *MemoryAccount) ToProto() proto.Message {
+	return &Account{
+		Password: a.Password,
+	}
+}
+
+func hexSHA224(password string) []byte {
+	hash := sha256.Sum224([]byte(password))
+	key := make([]byte, hex.EncodedLen(len(hash)))
+	hex.Encode(key, hash[:])
+	return key
+}
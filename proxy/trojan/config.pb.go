@@ -0,0 +1,179 @@
+package trojan
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+	protocol "v2ray.com/core/common/protocol"
+	fallback "v2ray.com/core/proxy/fallback"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Account struct {
+	Password             string   `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Account) Reset()         { *m = Account{} }
+func (m *Account) String() string { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()    {}
+func (*Account) Descriptor() ([]byte, []int) {
+	return fileDescriptor_27dab8c3a6f61031, []int{0}
+}
+
+func (m *Account) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Account.Unmarshal(m, b)
+}
+func (m *Account) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Account.Marshal(b, m, deterministic)
+}
+func (m *Account) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Account.Merge(m, src)
+}
+func (m *Account) XXX_Size() int {
+	return xxx_messageInfo_Account.Size(m)
+}
+func (m *Account) XXX_DiscardUnknown() {
+	xxx_messageInfo_Account.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Account proto.InternalMessageInfo
+
+func (m *Account) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type ServerConfig struct {
+	Users []*protocol.User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// Destinations for connections that are not valid Trojan requests.
+	Fallbacks            []*fallback.Fallback `protobuf:"bytes,2,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ServerConfig) Reset()         { *m = ServerConfig{} }
+func (m *ServerConfig) String() string { return proto.CompactTextString(m) }
+func (*ServerConfig) ProtoMessage()    {}
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_27dab8c3a6f61031, []int{1}
+}
+
+func (m *ServerConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerConfig.Unmarshal(m, b)
+}
+func (m *ServerConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerConfig.Marshal(b, m, deterministic)
+}
+func (m *ServerConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerConfig.Merge(m, src)
+}
+func (m *ServerConfig) XXX_Size() int {
+	return xxx_messageInfo_ServerConfig.Size(m)
+}
+func (m *ServerConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerConfig proto.InternalMessageInfo
+
+func (m *ServerConfig) GetUsers() []*protocol.User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetFallbacks() []*fallback.Fallback {
+	if m != nil {
+		return m.Fallbacks
+	}
+	return nil
+}
+
+type ClientConfig struct {
+	Server               []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *ClientConfig) Reset()         { *m = ClientConfig{} }
+func (m *ClientConfig) String() string { return proto.CompactTextString(m) }
+func (*ClientConfig) ProtoMessage()    {}
+func (*ClientConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_27dab8c3a6f61031, []int{2}
+}
+
+func (m *ClientConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientConfig.Unmarshal(m, b)
+}
+func (m *ClientConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientConfig.Marshal(b, m, deterministic)
+}
+func (m *ClientConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientConfig.Merge(m, src)
+}
+func (m *ClientConfig) XXX_Size() int {
+	return xxx_messageInfo_ClientConfig.Size(m)
+}
+func (m *ClientConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientConfig proto.InternalMessageInfo
+
+func (m *ClientConfig) GetServer() []*protocol.ServerEndpoint {
+	if m != nil {
+		return m.Server
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.trojan.Account")
+	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.trojan.ServerConfig")
+	proto.RegisterType((*ClientConfig)(nil), "v2ray.core.proxy.trojan.ClientConfig")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/trojan/config.proto", fileDescriptor_27dab8c3a6f61031)
+}
+
+var fileDescriptor_27dab8c3a6f61031 = []byte{
+	// 294 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0xbd, 0x4e, 0xc3, 0x30,
+	0x10, 0xc7, 0x95, 0x22, 0x0a, 0x35, 0x9d, 0xb2, 0xb4, 0x0a, 0x4b, 0x14, 0x84, 0x14, 0x3a, 0xd8,
+	0x28, 0x48, 0xec, 0x69, 0x04, 0x73, 0x65, 0x3e, 0x06, 0x16, 0xe4, 0xba, 0x2e, 0x0a, 0x24, 0xbe,
+	0xe8, 0x9c, 0x16, 0xf2, 0x08, 0xbc, 0x0a, 0x4f, 0x89, 0x6a, 0x27, 0x7c, 0x7f, 0x6c, 0x17, 0xe5,
+	0xf7, 0xbf, 0xfb, 0xdd, 0x99, 0xc4, 0xeb, 0x04, 0x45, 0x43, 0x25, 0x94, 0x4c, 0x02, 0x2a, 0x56,
+	0x21, 0x3c, 0x35, 0xac, 0x46, 0xb8, 0x17, 0x9a, 0x49, 0xd0, 0xcb, 0xfc, 0x8e, 0x56, 0x08, 0x35,
+	0xf8, 0xa3, 0x8e, 0x44, 0x45, 0x2d, 0x45, 0x1d, 0x15, 0x1c, 0x7d, 0x69, 0x21, 0xa1, 0x2c, 0x41,
+	0x33, 0x9b, 0x92, 0x50, 0xb0, 0x95, 0x51, 0xe8, 0x7a, 0x04, 0xc7, 0xff, 0xa0, 0x46, 0xe1, 0x5a,
+	0xe1, 0xad, 0xa9, 0x94, 0x6c, 0x13, 0x93, 0x1f, 0xfd, 0x96, 0xa2, 0x28, 0xe6, 0x42, 0x3e, 0x7c,
+	0x32, 0x8c, 0x0e, 0xc9, 0x4e, 0x2a, 0x25, 0xac, 0x74, 0xed, 0x07, 0x64, 0xb7, 0x12, 0xc6, 0x3c,
+	0x02, 0x2e, 0xc6, 0x5e, 0xe8, 0xc5, 0x03, 0xfe, 0xf6, 0x1d, 0x3d, 0x7b, 0x64, 0x78, 0x61, 0x07,
+	0x65, 0x36, 0xed, 0x9f, 0x92, 0xed, 0x8d, 0xa3, 0x19, 0x7b, 0xe1, 0x56, 0xbc, 0x97, 0x84, 0xf4,
+	0xc3, 0xa6, 0xce, 0x90, 0x76, 0x86, 0xf4, 0xca, 0x28, 0xe4, 0x0e, 0xf7, 0x53, 0x32, 0xe8, 0x44,
+	0xcc, 0xb8, 0x67, 0xb3, 0x07, 0xf4, 0xdb, 0x95, 0x3a, 0x84, 0x9e, 0xb7, 0x05, 0x7f, 0x4f, 0x45,
+	0x9c, 0x0c, 0xb3, 0x22, 0x57, 0xba, 0x6e, 0x55, 0xa6, 0xa4, 0xef, 0x6e, 0xd0, 0xba, 0x4c, 0xfe,
+	0x72, 0x71, 0x4b, 0x9c, 0xe9, 0x45, 0x05, 0xb9, 0xae, 0x79, 0x9b, 0x9c, 0xa6, 0x64, 0x5f, 0x42,
+	0x49, 0x7f, 0x79, 0xae, 0x99, 0x77, 0xd3, 0x77, 0xd5, 0x4b, 0x6f, 0x74, 0x9d, 0x70, 0xd1, 0xd0,
+	0x6c, 0xc3, 0xcc, 0x2c, 0x73, 0x69, 0xff, 0xcc, 0xfb, 0x76, 0xc6, 0xc9, 0xeb, 0x00, 0x7c, 0x2b,
+	0x67, 0x18, 0x1e, 0x02, 0x00, 0x00,
+}
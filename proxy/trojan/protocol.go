@@ -0,0 +1,188 @@
+// +build !confonly
+
+package trojan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+)
+
+const (
+	commandTCP byte = 0x01
+	commandUDP byte = 0x03
+
+	// keySize is the size of hex encoded SHA-224 hash.
+	keySize = 56
+)
+
+var crlf = []byte{'\r', '\n'}
+
+var addrParser = protocol.NewAddressParser(
+	protocol.AddressFamilyByte(0x01, net.AddressFamilyIPv4),
+	protocol.AddressFamilyByte(0x04, net.AddressFamilyIPv6),
+	protocol.AddressFamilyByte(0x03, net.AddressFamilyDomain),
+)
+
+// WriteRequest writes the header of a Trojan request into the given writer.
+func WriteRequest(writer io.Writer, request *protocol.RequestHeader) error {
+	account := request.User.Account.(*MemoryAccount)
+
+	buffer := buf.New()
+	defer buffer.Release()
+
+	buffer.Write(account.Key)
+	buffer.Write(crlf)
+	if request.Command == protocol.RequestCommandUDP {
+		buffer.WriteByte(commandUDP)
+	} else {
+		buffer.WriteByte(commandTCP)
+	}
+	if err := addrParser.WriteAddressPort(buffer, request.Address, request.Port); err != nil {
+		return newError("failed to write address").Base(err)
+	}
+	buffer.Write(crlf)
+
+	return buf.WriteAllBytes(writer, buffer.Bytes())
+}
+
+// ReadRequest reads the header of a Trojan request from the given reader, and finds its user in the validator.
+func ReadRequest(reader io.Reader, validator *Validator) (*protocol.RequestHeader, error) {
+	buffer := buf.New()
+	defer buffer.Release()
+
+	if _, err := buffer.ReadFullFrom(reader, keySize+2); err != nil {
+		return nil, newError("failed to read user key").Base(err)
+	}
+	user := validator.Get(buffer.BytesTo(keySize))
+	if user == nil || !bytes.Equal(buffer.BytesFrom(keySize), crlf) {
+		return nil, newError("invalid user")
+	}
+
+	buffer.Clear()
+	if _, err := buffer.ReadFullFrom(reader, 1); err != nil {
+		return nil, newError("failed to read command").Base(err)
+	}
+
+	request := &protocol.RequestHeader{
+		User: user,
+	}
+	switch buffer.Byte(0) {
+	case commandTCP:
+		request.Command = protocol.RequestCommandTCP
+	case commandUDP:
+		request.Command = protocol.RequestCommandUDP
+	default:
+		return nil, newError("unknown command: ", buffer.Byte(0))
+	}
+
+	buffer.Clear()
+	addr, port, err := addrParser.ReadAddressPort(buffer, reader)
+	if err != nil {
+		return nil, newError("failed to read address").Base(err)
+	}
+	request.Address = addr
+	request.Port = port
+
+	buffer.Clear()
+	if _, err := buffer.ReadFullFrom(reader, 2); err != nil {
+		return nil, newError("failed to read request").Base(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), crlf) {
+		return nil, newError("invalid request")
+	}
+
+	return request, nil
+}
+
+// PacketWriter writes UDP packets into a Trojan connection.
+type PacketWriter struct {
+	Writer io.Writer
+	// Target is the destination of packets that don't specify their own.
+	Target net.Destination
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *PacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+
+	for _, b := range mb {
+		dest := w.Target
+		if b.UDP != nil {
+			dest = net.DestinationFromAddr(b.UDP)
+		}
+		if err := w.WritePacket(dest, b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePacket writes a packet to or from the given destination.
+func (w *PacketWriter) WritePacket(dest net.Destination, payload []byte) error {
+	header := buf.New()
+	defer header.Release()
+
+	if err := addrParser.WriteAddressPort(header, dest.Address, dest.Port); err != nil {
+		return newError("failed to write address").Base(err)
+	}
+	binary.BigEndian.PutUint16(header.Extend(2), uint16(len(payload)))
+	header.Write(crlf)
+
+	packet := make([]byte, 0, header.Len()+int32(len(payload)))
+	packet = append(packet, header.Bytes()...)
+	packet = append(packet, payload...)
+	return buf.WriteAllBytes(w.Writer, packet)
+}
+
+// PacketReader reads UDP packets from a Trojan connection.
+type PacketReader struct {
+	Reader io.Reader
+}
+
+// ReadMultiBuffer implements buf.Reader. The UDP address of the returned buffer is set if the packet comes from
+// an IP address.
+func (r *PacketReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	dest, b, err := r.ReadPacket()
+	if err != nil {
+		return nil, err
+	}
+	if dest.Address.Family().IsIP() {
+		b.UDP = &net.UDPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		}
+	}
+	return buf.MultiBuffer{b}, nil
+}
+
+// ReadPacket reads a packet and the destination or source of it.
+func (r *PacketReader) ReadPacket() (net.Destination, *buf.Buffer, error) {
+	addr, port, err := addrParser.ReadAddressPort(nil, r.Reader)
+	if err != nil {
+		return net.Destination{}, nil, newError("failed to read address").Base(err)
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(r.Reader, header[:]); err != nil {
+		return net.Destination{}, nil, newError("failed to read packet length").Base(err)
+	}
+	if !bytes.Equal(header[2:], crlf) {
+		return net.Destination{}, nil, newError("invalid packet")
+	}
+	length := int32(binary.BigEndian.Uint16(header[:]))
+	if length > buf.Size {
+		return net.Destination{}, nil, newError("packet too large: ", length)
+	}
+
+	b := buf.New()
+	if _, err := b.ReadFullFrom(r.Reader, length); err != nil {
+		b.Release()
+		return net.Destination{}, nil, newError("failed to read packet").Base(err)
+	}
+	return net.UDPDestination(addr, port), b, nil
+}
@@ -0,0 +1,117 @@
+package trojan_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	. "v2ray.com/core/proxy/trojan"
+)
+
+func toAccount(a *Account) protocol.Account {
+	account, err := a.AsAccount()
+	common.Must(err)
+	return account
+}
+
+func TestRequestSerialization(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Email:   "love@v2ray.com",
+		Account: toAccount(&Account{Password: "trojan-password"}),
+	}
+	validator := NewValidator()
+	common.Must(validator.Add(user))
+
+	for _, expected := range []*protocol.RequestHeader{
+		{
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("www.v2ray.com"),
+			Port:    443,
+			User:    user,
+		},
+		{
+			Command: protocol.RequestCommandUDP,
+			Address: net.ParseAddress("2001:4860:4860::8888"),
+			Port:    53,
+			User:    user,
+		},
+	} {
+		buffer := buf.New()
+		common.Must(WriteRequest(buffer, expected))
+
+		actual, err := ReadRequest(buffer, validator)
+		common.Must(err)
+		if r := cmp.Diff(actual, expected); r != "" {
+			t.Error(r)
+		}
+		if !buffer.IsEmpty() {
+			t.Error("unexpected remaining bytes: ", buffer.Len())
+		}
+		buffer.Release()
+	}
+}
+
+func TestInvalidUser(t *testing.T) {
+	request := &protocol.RequestHeader{
+		Command: protocol.RequestCommandTCP,
+		Address: net.LocalHostIP,
+		Port:    80,
+		User: &protocol.MemoryUser{
+			Account: toAccount(&Account{Password: "unknown-password"}),
+		},
+	}
+	validator := NewValidator()
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Account: toAccount(&Account{Password: "trojan-password"}),
+	}))
+
+	buffer := buf.New()
+	defer buffer.Release()
+	common.Must(WriteRequest(buffer, request))
+
+	if _, err := ReadRequest(buffer, validator); err == nil {
+		t.Error("nil error")
+	}
+}
+
+func TestPacketSerialization(t *testing.T) {
+	var stream bytes.Buffer
+	writer := &PacketWriter{
+		Writer: &stream,
+		Target: net.UDPDestination(net.DomainAddress("dns.v2ray.com"), 53),
+	}
+
+	b := buf.New()
+	common.Must2(b.WriteString("first packet"))
+	b2 := buf.New()
+	common.Must2(b2.WriteString("second packet"))
+	b2.UDP = &net.UDPAddr{IP: net.LocalHostIP.IP(), Port: 1234}
+	common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{b, b2}))
+
+	reader := &PacketReader{Reader: &stream}
+
+	dest, payload, err := reader.ReadPacket()
+	common.Must(err)
+	if dest != writer.Target {
+		t.Error("unexpected destination: ", dest)
+	}
+	if payload.String() != "first packet" {
+		t.Error("unexpected payload: ", payload.String())
+	}
+	payload.Release()
+
+	mb, err := reader.ReadMultiBuffer()
+	common.Must(err)
+	if len(mb) != 1 || mb[0].String() != "second packet" {
+		t.Error("unexpected payload: ", mb)
+	}
+	if mb[0].UDP == nil || mb[0].UDP.Port != 1234 || !mb[0].UDP.IP.Equal(net.LocalHostIP.IP()) {
+		t.Error("unexpected source: ", mb[0].UDP)
+	}
+	buf.ReleaseMulti(mb)
+}
@@ -0,0 +1,214 @@
+// +build !confonly
+
+package trojan
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	udp_proto "v2ray.com/core/common/protocol/udp"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy/fallback"
+	"v2ray.com/core/transport/internet"
+	"v2ray.com/core/transport/internet/udp"
+)
+
+// Server is an inbound handler for Trojan protocol.
+type Server struct {
+	validator     *Validator
+	fallbacks     []*fallback.Fallback
+	policyManager policy.Manager
+}
+
+// NewServer creates a new Trojan server.
+func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
+	validator := NewValidator()
+	for _, user := range config.Users {
+		mUser, err := user.ToMemoryUser()
+		if err != nil {
+			return nil, newError("failed to parse user account").Base(err)
+		}
+		if err := validator.Add(mUser); err != nil {
+			return nil, newError("failed to add user").Base(err)
+		}
+	}
+
+	v := core.MustFromContext(ctx)
+	return &Server{
+		validator:     validator,
+		fallbacks:     config.Fallbacks,
+		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+	}, nil
+}
+
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, u *protocol.MemoryUser) error {
+	return s.validator.Add(u)
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	return s.validator.Remove(email)
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return s.validator.GetUsers()
+}
+
+// Network implements proxy.Inbound.Network().
+func (s *Server) Network() []net.Network {
+	return []net.Network{net.Network_TCP}
+}
+
+// Process implements proxy.Inbound.Process().
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil {
+		panic("no inbound metadata")
+	}
+
+	sessionPolicy := s.policyManager.ForLevel(s.validator.HandshakeLevel())
+	conn.SetReadDeadline(time.Now().Add(sessionPolicy.Timeouts.Handshake))
+
+	var recorder *fallback.Recorder
+	connReader := buf.NewReader(conn)
+	if len(s.fallbacks) > 0 {
+		recorder = fallback.NewRecorder(connReader)
+		connReader = recorder
+	}
+
+	bufferedReader := &buf.BufferedReader{Reader: connReader}
+	request, err := ReadRequest(bufferedReader, s.validator)
+	if err != nil {
+		log.Record(&log.AccessMessage{
+			From:   conn.RemoteAddr(),
+			To:     "",
+			Status: log.AccessRejected,
+			Reason: err,
+		})
+		rejected := newError("failed to read request from: ", conn.RemoteAddr()).Base(err)
+		if recorder == nil || errors.Cause(err) == io.EOF {
+			return rejected
+		}
+		rejected.AtInfo().WriteToLog(session.ExportIDToError(ctx))
+		conn.SetReadDeadline(time.Time{})
+		return fallback.Serve(ctx, conn, recorder, s.fallbacks, sessionPolicy)
+	}
+	conn.SetReadDeadline(time.Time{})
+	if recorder != nil {
+		recorder.Stop()
+	}
+
+	inbound.User = request.User
+	sessionPolicy = s.policyManager.ForLevel(request.User.Level)
+
+	if request.Command == protocol.RequestCommandUDP {
+		return s.handleUDPPayload(ctx, bufferedReader, conn, dispatcher)
+	}
+
+	dest := request.Destination()
+	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
+		From:   conn.RemoteAddr(),
+		To:     dest,
+		Status: log.AccessAccepted,
+		Reason: "",
+		Email:  request.User.Email,
+	})
+	newError("tunnelling request to ", dest).WriteToLog(session.ExportIDToError(ctx))
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	ctx = policy.ContextWithBufferPolicy(ctx, sessionPolicy.Buffer)
+	link, err := dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		return err
+	}
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+
+		if err := buf.Copy(bufferedReader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport all TCP request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		if err := buf.Copy(link.Reader, buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport all TCP response").Base(err)
+		}
+		return nil
+	}
+
+	var requestDoneAndCloseWriter = task.OnSuccess(requestDone, task.Close(link.Writer))
+	if err := task.Run(ctx, requestDoneAndCloseWriter, responseDone); err != nil {
+		common.Interrupt(link.Reader)
+		common.Interrupt(link.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+func (s *Server) handleUDPPayload(ctx context.Context, reader io.Reader, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	inbound := session.InboundFromContext(ctx)
+	user := inbound.User
+
+	var writeAccess sync.Mutex
+	writer := &PacketWriter{Writer: conn}
+	udpServer := udp.NewDispatcher(dispatcher, func(ctx context.Context, packet *udp_proto.Packet) {
+		writeAccess.Lock()
+		defer writeAccess.Unlock()
+
+		payload := packet.Payload
+		if err := writer.WritePacket(packet.Source, payload.Bytes()); err != nil {
+			newError("failed to write UDP response").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+		payload.Release()
+	})
+
+	packetReader := &PacketReader{Reader: reader}
+	for {
+		dest, payload, err := packetReader.ReadPacket()
+		if err != nil {
+			if errors.Cause(err) == io.EOF {
+				return nil
+			}
+			return newError("failed to read UDP packet").Base(err)
+		}
+
+		ctx := log.ContextWithAccessMessage(ctx, &log.AccessMessage{
+			From:   conn.RemoteAddr(),
+			To:     dest,
+			Status: log.AccessAccepted,
+			Reason: "",
+			Email:  user.Email,
+		})
+		newError("tunnelling request to ", dest).WriteToLog(session.ExportIDToError(ctx))
+
+		udpServer.Dispatch(ctx, dest, payload)
+	}
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewServer(ctx, config.(*ServerConfig))
+	}))
+}
@@ -0,0 +1,5 @@
+// Package trojan implements the Trojan protocol, which authenticates clients by password and is meant to run
+// over TLS. Connections that fail authentication can be forwarded to a fallback server.
+package trojan
+
+//go:generate errorgen
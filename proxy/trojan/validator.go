@@ -0,0 +1,96 @@
+// +build !confonly
+
+package trojan
+
+import (
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/protocol"
+)
+
+// Validator stores the users of a Trojan server, and finds them by the keys in requests.
+type Validator struct {
+	sync.RWMutex
+	users map[string]*protocol.MemoryUser
+}
+
+// NewValidator creates a new Validator.
+func NewValidator() *Validator {
+	return &Validator{
+		users: make(map[string]*protocol.MemoryUser),
+	}
+}
+
+// Add adds a user. Users must have different passwords.
+func (v *Validator) Add(u *protocol.MemoryUser) error {
+	v.Lock()
+	defer v.Unlock()
+
+	key := string(u.Account.(*MemoryAccount).Key)
+	if _, found := v.users[key]; found {
+		return newError("User with the same password already exists.")
+	}
+	if len(u.Email) > 0 {
+		for _, user := range v.users {
+			if strings.EqualFold(user.Email, u.Email) {
+				return newError("User ", u.Email, " already exists.")
+			}
+		}
+	}
+
+	v.users[key] = u
+	return nil
+}
+
+// Remove removes the user of the given email.
+func (v *Validator) Remove(email string) error {
+	if email == "" {
+		return newError("Email must not be empty.")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	for key, u := range v.users {
+		if strings.EqualFold(u.Email, email) {
+			delete(v.users, key)
+			return nil
+		}
+	}
+	return newError("User ", email, " not found.")
+}
+
+// Get returns the user of the given key, or nil if not found.
+func (v *Validator) Get(key []byte) *protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	return v.users[string(key)]
+}
+
+// GetUsers returns all users.
+func (v *Validator) GetUsers() []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	users := make([]*protocol.MemoryUser, 0, len(v.users))
+	for _, u := range v.users {
+		users = append(users, u)
+	}
+	return users
+}
+
+// HandshakeLevel returns the level of the only user, or 0 if there are multiple users, as the user of a request is
+// unknown until its password is read.
+func (v *Validator) HandshakeLevel() uint32 {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 1 {
+		for _, u := range v.users {
+			return u.Level
+		}
+	}
+	return 0
+}
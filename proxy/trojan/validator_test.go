@@ -0,0 +1,64 @@
+package trojan_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
+	. "v2ray.com/core/proxy/trojan"
+)
+
+func TestValidator(t *testing.T) {
+	validator := NewValidator()
+
+	user := &protocol.MemoryUser{
+		Email:   "love@v2ray.com",
+		Account: toAccount(&Account{Password: "password-1"}),
+	}
+	common.Must(validator.Add(user))
+
+	if err := validator.Add(&protocol.MemoryUser{
+		Account: toAccount(&Account{Password: "password-1"}),
+	}); err == nil {
+		t.Error("added user with duplicated password")
+	}
+	if err := validator.Add(&protocol.MemoryUser{
+		Email:   "LOVE@v2ray.com",
+		Account: toAccount(&Account{Password: "password-2"}),
+	}); err == nil {
+		t.Error("added user with duplicated email")
+	}
+
+	if u := validator.Get(user.Account.(*MemoryAccount).Key); u != user {
+		t.Error("unexpected user: ", u)
+	}
+
+	common.Must(validator.Remove("love@v2ray.com"))
+	if u := validator.Get(user.Account.(*MemoryAccount).Key); u != nil {
+		t.Error("user not removed: ", u)
+	}
+	if err := validator.Remove("love@v2ray.com"); err == nil {
+		t.Error("removed nonexistent user")
+	}
+}
+
+func TestValidatorHandshakeLevel(t *testing.T) {
+	validator := NewValidator()
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Email:   "user1@v2ray.com",
+		Level:   2,
+		Account: toAccount(&Account{Password: "password-1"}),
+	}))
+	if level := validator.HandshakeLevel(); level != 2 {
+		t.Error("expected level 2 of the only user, but got ", level)
+	}
+
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Email:   "user2@v2ray.com",
+		Level:   1,
+		Account: toAccount(&Account{Password: "password-2"}),
+	}))
+	if level := validator.HandshakeLevel(); level != 0 {
+		t.Error("expected level 0 of multiple users, but got ", level)
+	}
+}
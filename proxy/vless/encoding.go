@@ -0,0 +1,197 @@
+// +build !confonly
+
+package vless
+
+import (
+	"encoding/binary"
+	"io"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/uuid"
+)
+
+const (
+	// Version is the current version of VLESS protocol.
+	Version byte = 0
+
+	commandTCP byte = 0x01
+	commandUDP byte = 0x02
+	commandMux byte = 0x03
+)
+
+var addrParser = protocol.NewAddressParser(
+	protocol.AddressFamilyByte(0x01, net.AddressFamilyIPv4),
+	protocol.AddressFamilyByte(0x02, net.AddressFamilyDomain),
+	protocol.AddressFamilyByte(0x03, net.AddressFamilyIPv6),
+	protocol.PortThenAddress(),
+)
+
+var muxCoolAddress = net.DomainAddress("v1.mux.cool")
+
+// EncodeRequestHeader writes the header of a VLESS request into the given writer.
+func EncodeRequestHeader(writer io.Writer, request *protocol.RequestHeader) error {
+	account := request.User.Account.(*MemoryAccount)
+
+	buffer := buf.New()
+	defer buffer.Release()
+
+	buffer.WriteByte(Version)
+	buffer.Write(account.ID.Bytes())
+	// No addons.
+	buffer.WriteByte(0)
+
+	switch request.Command {
+	case protocol.RequestCommandMux:
+		buffer.WriteByte(commandMux)
+	case protocol.RequestCommandUDP:
+		buffer.WriteByte(commandUDP)
+	default:
+		buffer.WriteByte(commandTCP)
+	}
+	if request.Command != protocol.RequestCommandMux {
+		if err := addrParser.WriteAddressPort(buffer, request.Address, request.Port); err != nil {
+			return newError("failed to write address").Base(err)
+		}
+	}
+
+	return buf.WriteAllBytes(writer, buffer.Bytes())
+}
+
+// DecodeRequestHeader reads the header of a VLESS request from the given reader, and finds its user in the
+// validator.
+func DecodeRequestHeader(reader io.Reader, validator *Validator) (*protocol.RequestHeader, error) {
+	buffer := buf.New()
+	defer buffer.Release()
+
+	if _, err := buffer.ReadFullFrom(reader, 1+16); err != nil {
+		return nil, newError("failed to read request version and ID").Base(err)
+	}
+	if buffer.Byte(0) != Version {
+		return nil, newError("invalid request version: ", buffer.Byte(0))
+	}
+	id, err := uuid.ParseBytes(buffer.BytesFrom(1))
+	if err != nil {
+		return nil, newError("invalid ID").Base(err)
+	}
+	user := validator.Get(id)
+	if user == nil {
+		return nil, newError("invalid user")
+	}
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+	}
+
+	if err := skipAddons(reader, buffer); err != nil {
+		return nil, err
+	}
+
+	buffer.Clear()
+	if _, err := buffer.ReadFullFrom(reader, 1); err != nil {
+		return nil, newError("failed to read command").Base(err)
+	}
+	switch buffer.Byte(0) {
+	case commandTCP:
+		request.Command = protocol.RequestCommandTCP
+	case commandUDP:
+		request.Command = protocol.RequestCommandUDP
+	case commandMux:
+		request.Command = protocol.RequestCommandMux
+		request.Address = muxCoolAddress
+		return request, nil
+	default:
+		return nil, newError("unknown command: ", buffer.Byte(0))
+	}
+
+	buffer.Clear()
+	addr, port, err := addrParser.ReadAddressPort(buffer, reader)
+	if err != nil {
+		return nil, newError("failed to read address").Base(err)
+	}
+	request.Address = addr
+	request.Port = port
+
+	return request, nil
+}
+
+// EncodeResponseHeader writes the header of a VLESS response into the given writer.
+func EncodeResponseHeader(writer io.Writer) error {
+	// Version and no addons.
+	return buf.WriteAllBytes(writer, []byte{Version, 0})
+}
+
+// DecodeResponseHeader reads the header of a VLESS response from the given reader.
+func DecodeResponseHeader(reader io.Reader) error {
+	buffer := buf.New()
+	defer buffer.Release()
+
+	if _, err := buffer.ReadFullFrom(reader, 1); err != nil {
+		return newError("failed to read response version").Base(err)
+	}
+	if buffer.Byte(0) != Version {
+		return newError("invalid response version: ", buffer.Byte(0))
+	}
+	return skipAddons(reader, buffer)
+}
+
+// skipAddons reads and discards the addons section. No addons are defined in this version.
+func skipAddons(reader io.Reader, buffer *buf.Buffer) error {
+	buffer.Clear()
+	if _, err := buffer.ReadFullFrom(reader, 1); err != nil {
+		return newError("failed to read addons length").Base(err)
+	}
+	if length := int32(buffer.Byte(0)); length > 0 {
+		buffer.Clear()
+		if _, err := buffer.ReadFullFrom(reader, length); err != nil {
+			return newError("failed to read addons").Base(err)
+		}
+	}
+	return nil
+}
+
+// LengthPacketWriter writes UDP packets into a VLESS connection, each prefixed by its length.
+type LengthPacketWriter struct {
+	Writer io.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *LengthPacketWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+
+	for _, b := range mb {
+		packet := make([]byte, 2+b.Len())
+		binary.BigEndian.PutUint16(packet, uint16(b.Len()))
+		copy(packet[2:], b.Bytes())
+		if err := buf.WriteAllBytes(w.Writer, packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LengthPacketReader reads UDP packets written by LengthPacketWriter.
+type LengthPacketReader struct {
+	Reader io.Reader
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *LengthPacketReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r.Reader, header[:]); err != nil {
+		return nil, err
+	}
+	length := int32(binary.BigEndian.Uint16(header[:]))
+	if length > buf.Size {
+		return nil, newError("packet too large: ", length)
+	}
+
+	b := buf.New()
+	if _, err := b.ReadFullFrom(r.Reader, length); err != nil {
+		b.Release()
+		return nil, newError("failed to read packet").Base(err)
+	}
+	return buf.MultiBuffer{b}, nil
+}
@@ -0,0 +1,134 @@
+package vless_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/uuid"
+	. "v2ray.com/core/proxy/vless"
+)
+
+func newAccount() protocol.Account {
+	id := uuid.New()
+	return toAccount(&Account{Id: id.String()})
+}
+
+func toAccount(a *Account) protocol.Account {
+	account, err := a.AsAccount()
+	common.Must(err)
+	return account
+}
+
+func TestRequestSerialization(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Level:   0,
+		Email:   "test@v2ray.com",
+		Account: newAccount(),
+	}
+	validator := NewValidator()
+	common.Must(validator.Add(user))
+
+	for _, expected := range []*protocol.RequestHeader{
+		{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("www.v2ray.com"),
+			Port:    443,
+			User:    user,
+		},
+		{
+			Version: Version,
+			Command: protocol.RequestCommandUDP,
+			Address: net.ParseAddress("2001:4860:4860::8888"),
+			Port:    53,
+			User:    user,
+		},
+		{
+			Version: Version,
+			Command: protocol.RequestCommandMux,
+			Address: net.DomainAddress("v1.mux.cool"),
+			User:    user,
+		},
+	} {
+		buffer := buf.New()
+		common.Must(EncodeRequestHeader(buffer, expected))
+
+		actual, err := DecodeRequestHeader(buffer, validator)
+		common.Must(err)
+		if actual.User != expected.User {
+			t.Error("unexpected user: ", actual.User)
+		}
+		if r := cmp.Diff(actual.Command, expected.Command); r != "" {
+			t.Error(r)
+		}
+		if actual.Destination() != expected.Destination() {
+			t.Error("unexpected destination: ", actual.Destination())
+		}
+		if !buffer.IsEmpty() {
+			t.Error("unexpected remaining bytes: ", buffer.Len())
+		}
+		buffer.Release()
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Account: newAccount(),
+	}
+	validator := NewValidator()
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Account: newAccount(),
+	}))
+
+	buffer := buf.New()
+	defer buffer.Release()
+	common.Must(EncodeRequestHeader(buffer, &protocol.RequestHeader{
+		Version: Version,
+		Command: protocol.RequestCommandTCP,
+		Address: net.LocalHostIP,
+		Port:    80,
+		User:    user,
+	}))
+
+	if _, err := DecodeRequestHeader(buffer, validator); err == nil {
+		t.Error("nil error")
+	}
+}
+
+func TestResponseWithAddons(t *testing.T) {
+	buffer := buf.New()
+	defer buffer.Release()
+
+	// Unknown addons are skipped.
+	common.Must2(buffer.Write([]byte{Version, 3, 1, 2, 3, 'x'}))
+	common.Must(DecodeResponseHeader(buffer))
+	if buffer.String() != "x" {
+		t.Error("unexpected remaining bytes: ", buffer.String())
+	}
+}
+
+func TestLengthPacket(t *testing.T) {
+	stream := buf.New()
+	defer stream.Release()
+
+	b1 := buf.New()
+	common.Must2(b1.WriteString("first packet"))
+	b2 := buf.New()
+	common.Must2(b2.WriteString("second packet"))
+	common.Must((&LengthPacketWriter{Writer: stream}).WriteMultiBuffer(buf.MultiBuffer{b1, b2}))
+
+	reader := &LengthPacketReader{Reader: stream}
+	for _, expected := range []string{"first packet", "second packet"} {
+		mb, err := reader.ReadMultiBuffer()
+		common.Must(err)
+		if len(mb) != 1 || mb[0].String() != expected {
+			t.Error("unexpected packet: ", mb)
+		}
+		buf.ReleaseMulti(mb)
+	}
+}
@@ -0,0 +1,95 @@
+package inbound
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+	protocol "v2ray.com/core/common/protocol"
+	fallback "v2ray.com/core/proxy/fallback"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Config struct {
+	Clients []*protocol.User `protobuf:"bytes,1,rep,name=clients,proto3" json:"clients,omitempty"`
+	// Destinations for connections that are not valid VLESS requests.
+	Fallbacks            []*fallback.Fallback `protobuf:"bytes,2,rep,name=fallbacks,proto3" json:"fallbacks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b949f4cadf2c2c15, []int{0}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func (m *Config) GetClients() []*protocol.User {
+	if m != nil {
+		return m.Clients
+	}
+	return nil
+}
+
+func (m *Config) GetFallbacks() []*fallback.Fallback {
+	if m != nil {
+		return m.Fallbacks
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.vless.inbound.Config")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/proxy/vless/inbound/config.proto", fileDescriptor_b949f4cadf2c2c15)
+}
+
+var fileDescriptor_b949f4cadf2c2c15 = []byte{
+	// 233 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2f, 0x33, 0x2a, 0x4a,
+	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x2f, 0x28, 0xca, 0xaf, 0xa8,
+	0xd4, 0x2f, 0xcb, 0x49, 0x2d, 0x2e, 0xd6, 0xcf, 0xcc, 0x4b, 0xca, 0x2f, 0xcd, 0x4b, 0xd1, 0x4f,
+	0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0x83, 0x69, 0x28,
+	0x4a, 0xd5, 0x03, 0x2b, 0xd6, 0x03, 0x2b, 0xd6, 0x83, 0x2a, 0x96, 0xd2, 0x44, 0x33, 0x30, 0x39,
+	0x3f, 0x37, 0x37, 0x3f, 0x4f, 0x1f, 0xac, 0x39, 0x39, 0x3f, 0x47, 0xbf, 0xb4, 0x38, 0xb5, 0x08,
+	0x62, 0x94, 0x94, 0x16, 0x56, 0xbb, 0xd3, 0x12, 0x73, 0x72, 0x92, 0x12, 0x93, 0xb3, 0x51, 0xac,
+	0x55, 0x6a, 0x67, 0xe4, 0x62, 0x73, 0x06, 0x0b, 0x08, 0x59, 0x71, 0xb1, 0x27, 0xe7, 0x64, 0xa6,
+	0xe6, 0x95, 0x14, 0x4b, 0x30, 0x2a, 0x30, 0x6b, 0x70, 0x1b, 0x29, 0xe8, 0x21, 0xb9, 0x09, 0x62,
+	0x9f, 0x1e, 0xcc, 0x3e, 0xbd, 0xd0, 0xe2, 0xd4, 0xa2, 0x20, 0x98, 0x06, 0x21, 0x47, 0x2e, 0x4e,
+	0x98, 0xf9, 0xc5, 0x12, 0x4c, 0x60, 0xdd, 0xca, 0x7a, 0x18, 0x3e, 0x82, 0x29, 0xd1, 0x73, 0x83,
+	0x32, 0x82, 0x10, 0xba, 0x9c, 0x02, 0xb8, 0x94, 0x92, 0xf3, 0x73, 0xf5, 0xf0, 0x07, 0x43, 0x00,
+	0x63, 0x14, 0x3b, 0x94, 0xb9, 0x8a, 0x49, 0x2e, 0xcc, 0x28, 0x28, 0xb1, 0x52, 0xcf, 0x19, 0xa4,
+	0x36, 0x00, 0xac, 0x36, 0x0c, 0xac, 0xd6, 0x13, 0xa2, 0x20, 0x89, 0x0d, 0xec, 0x58, 0x63, 0xc0,
+	0x00, 0x19, 0x85, 0xe8, 0x80, 0x8c, 0x01, 0x00, 0x00,
+}
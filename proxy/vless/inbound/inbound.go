@@ -0,0 +1,205 @@
+// +build !confonly
+
+package inbound
+
+//go:generate errorgen
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy/fallback"
+	"v2ray.com/core/proxy/vless"
+	"v2ray.com/core/transport/internet"
+)
+
+// Handler is an inbound connection handler that handles messages in VLESS protocol.
+type Handler struct {
+	validator     *vless.Validator
+	fallbacks     []*fallback.Fallback
+	policyManager policy.Manager
+}
+
+// New creates a new VLESS inbound handler.
+func New(ctx context.Context, config *Config) (*Handler, error) {
+	validator := vless.NewValidator()
+	for _, user := range config.Clients {
+		mUser, err := user.ToMemoryUser()
+		if err != nil {
+			return nil, newError("failed to parse user account").Base(err)
+		}
+		if err := validator.Add(mUser); err != nil {
+			return nil, newError("failed to add user").Base(err)
+		}
+	}
+
+	v := core.MustFromContext(ctx)
+	return &Handler{
+		validator:     validator,
+		fallbacks:     config.Fallbacks,
+		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+	}, nil
+}
+
+// AddUser implements proxy.UserManager.AddUser().
+func (h *Handler) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
+	return h.validator.Add(user)
+}
+
+// RemoveUser implements proxy.UserManager.RemoveUser().
+func (h *Handler) RemoveUser(ctx context.Context, email string) error {
+	return h.validator.Remove(email)
+}
+
+// GetUsers implements proxy.UserLister.GetUsers().
+func (h *Handler) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return h.validator.GetUsers()
+}
+
+// Network implements proxy.Inbound.Network().
+func (*Handler) Network() []net.Network {
+	return []net.Network{net.Network_TCP}
+}
+
+// Process implements proxy.Inbound.Process().
+func (h *Handler) Process(ctx context.Context, network net.Network, connection internet.Connection, dispatcher routing.Dispatcher) error {
+	sessionPolicy := h.policyManager.ForLevel(h.validator.HandshakeLevel())
+	if err := connection.SetReadDeadline(time.Now().Add(sessionPolicy.Timeouts.Handshake)); err != nil {
+		return newError("unable to set read deadline").Base(err).AtWarning()
+	}
+
+	var recorder *fallback.Recorder
+	connReader := buf.NewReader(connection)
+	if len(h.fallbacks) > 0 {
+		recorder = fallback.NewRecorder(connReader)
+		connReader = recorder
+	}
+
+	reader := &buf.BufferedReader{Reader: connReader}
+	request, err := vless.DecodeRequestHeader(reader, h.validator)
+	if err != nil {
+		if errors.Cause(err) != io.EOF {
+			log.Record(&log.AccessMessage{
+				From:   connection.RemoteAddr(),
+				To:     "",
+				Status: log.AccessRejected,
+				Reason: err,
+			})
+			rejected := newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
+			if recorder == nil {
+				return rejected
+			}
+			rejected.WriteToLog(session.ExportIDToError(ctx))
+			if err := connection.SetReadDeadline(time.Time{}); err != nil {
+				newError("unable to set back read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+			}
+			return fallback.Serve(ctx, connection, recorder, h.fallbacks, sessionPolicy)
+		}
+		return err
+	}
+	if recorder != nil {
+		recorder.Stop()
+	}
+
+	if request.Command != protocol.RequestCommandMux {
+		ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
+			From:   connection.RemoteAddr(),
+			To:     request.Destination(),
+			Status: log.AccessAccepted,
+			Reason: "",
+			Email:  request.User.Email,
+		})
+	}
+
+	newError("received request for ", request.Destination()).WriteToLog(session.ExportIDToError(ctx))
+
+	if err := connection.SetReadDeadline(time.Time{}); err != nil {
+		newError("unable to set back read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil {
+		panic("no inbound metadata")
+	}
+	inbound.User = request.User
+
+	sessionPolicy = h.policyManager.ForLevel(request.User.Level)
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	ctx = policy.ContextWithBufferPolicy(ctx, sessionPolicy.Buffer)
+	link, err := dispatcher.Dispatch(ctx, request.Destination())
+	if err != nil {
+		return newError("failed to dispatch request to ", request.Destination()).Base(err)
+	}
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+
+		var bodyReader buf.Reader = reader
+		if request.Command == protocol.RequestCommandUDP {
+			bodyReader = &vless.LengthPacketReader{Reader: reader}
+		}
+		if err := buf.Copy(bodyReader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transfer request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		writer := buf.NewBufferedWriter(buf.NewWriter(connection))
+		if err := vless.EncodeResponseHeader(writer); err != nil {
+			return newError("failed to write response header").Base(err)
+		}
+
+		var bodyWriter buf.Writer = writer
+		if request.Command == protocol.RequestCommandUDP {
+			bodyWriter = &vless.LengthPacketWriter{Writer: writer}
+		}
+
+		// Send the first payload along with the header.
+		if err := buf.CopyOnceTimeout(link.Reader, bodyWriter, time.Millisecond*100); err != nil && err != buf.ErrNotTimeoutReader && err != buf.ErrReadTimeout {
+			return newError("failed to write first payload").Base(err)
+		}
+
+		if err := writer.SetBuffered(false); err != nil {
+			return err
+		}
+
+		if err := buf.Copy(link.Reader, bodyWriter, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transfer response").Base(err)
+		}
+		return nil
+	}
+
+	var requestDonePost = task.OnSuccess(requestDone, task.Close(link.Writer))
+	if err := task.Run(ctx, requestDonePost, responseDone); err != nil {
+		common.Interrupt(link.Reader)
+		common.Interrupt(link.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,97 @@
+// +build !confonly
+
+package vless
+
+import (
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/uuid"
+)
+
+// Validator stores the users of a VLESS server, and finds them by ID.
+type Validator struct {
+	sync.RWMutex
+	users map[uuid.UUID]*protocol.MemoryUser
+}
+
+// NewValidator creates a new Validator.
+func NewValidator() *Validator {
+	return &Validator{
+		users: make(map[uuid.UUID]*protocol.MemoryUser),
+	}
+}
+
+// Add adds a user. Users must have different IDs.
+func (v *Validator) Add(u *protocol.MemoryUser) error {
+	v.Lock()
+	defer v.Unlock()
+
+	id := u.Account.(*MemoryAccount).ID.UUID()
+	if _, found := v.users[id]; found {
+		return newError("User with the same ID already exists.")
+	}
+	if len(u.Email) > 0 {
+		for _, user := range v.users {
+			if strings.EqualFold(user.Email, u.Email) {
+				return newError("User ", u.Email, " already exists.")
+			}
+		}
+	}
+
+	v.users[id] = u
+	return nil
+}
+
+// Remove removes the user of the given email.
+func (v *Validator) Remove(email string) error {
+	if email == "" {
+		return newError("Email must not be empty.")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	for id, u := range v.users {
+		if strings.EqualFold(u.Email, email) {
+			delete(v.users, id)
+			return nil
+		}
+	}
+	return newError("User ", email, " not found.")
+}
+
+// Get returns the user of the given ID, or nil if not found.
+func (v *Validator) Get(id uuid.UUID) *protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	return v.users[id]
+}
+
+// GetUsers returns all users.
+func (v *Validator) GetUsers() []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	users := make([]*protocol.MemoryUser, 0, len(v.users))
+	for _, u := range v.users {
+		users = append(users, u)
+	}
+	return users
+}
+
+// HandshakeLevel returns the level of the only user, or 0 if there are multiple users, since the user is identified
+// by the ID in the request header.
+func (v *Validator) HandshakeLevel() uint32 {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 1 {
+		for _, u := range v.users {
+			return u.Level
+		}
+	}
+	return 0
+}
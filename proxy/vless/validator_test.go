@@ -0,0 +1,35 @@
+package vless_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
+	. "v2ray.com/core/proxy/vless"
+)
+
+func TestValidatorHandshakeLevel(t *testing.T) {
+	validator := NewValidator()
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Email:   "user1@v2ray.com",
+		Level:   2,
+		Account: newAccount(),
+	}))
+	if level := validator.HandshakeLevel(); level != 2 {
+		t.Error("expected level 2 of the only user, but got ", level)
+	}
+
+	common.Must(validator.Add(&protocol.MemoryUser{
+		Email:   "user2@v2ray.com",
+		Level:   1,
+		Account: newAccount(),
+	}))
+	if level := validator.HandshakeLevel(); level != 0 {
+		t.Error("expected level 0 of multiple users, but got ", level)
+	}
+
+	common.Must(validator.Remove("user2@v2ray.com"))
+	if level := validator.HandshakeLevel(); level != 2 {
+		t.Error("expected level 2 of the only user, but got ", level)
+	}
+}
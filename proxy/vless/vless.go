@@ -0,0 +1,7 @@
+// Package vless contains the implementation of VLESS protocol, a lightweight stateless protocol that identifies
+// users by UUID. It has no encryption of its own, and is meant to run over a secure transport such as TLS.
+//
+// Like VMess, VLESS contains both inbound and outbound connections.
+package vless
+
+//go:generate errorgen
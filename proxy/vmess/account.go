@@ -3,6 +3,8 @@
 package vmess
 
 import (
+	"github.com/golang/protobuf/proto"
+
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/uuid"
@@ -16,6 +18,8 @@ type MemoryAccount struct {
 	AlterIDs []*protocol.ID
 	// Security type of the account. Used for client connections.
 	Security protocol.SecurityType
+	// AEADHeader is whether to use the AEAD header for client connections.
+	AEADHeader bool
 }
 
 // AnyValidID returns an ID that is either the main ID or one of the alternative IDs if any.
@@ -36,6 +40,18 @@ func (a *MemoryAccount) Equals(account protocol.Account) bool {
 	return a.ID.Equals(vmessAccount.ID)
 }
 
+// ToProto implements protocol.ProtoAccount.
+func (a *MemoryAccount) ToProto() proto.Message {
+	return &Account{
+		Id:      a.ID.String(),
+		AlterId: uint32(len(a.AlterIDs)),
+		SecuritySettings: &protocol.SecurityConfig{
+			Type: a.Security,
+		},
+		AeadHeader: a.AEADHeader,
+	}
+}
+
 // AsAccount implements protocol.Account.
 func (a *Account) AsAccount() (protocol.Account, error) {
 	id, err := uuid.ParseString(a.Id)
@@ -44,8 +60,9 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	}
 	protoID := protocol.NewID(id)
 	return &MemoryAccount{
-		ID:       protoID,
-		AlterIDs: protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
-		Security: a.SecuritySettings.GetSecurityType(),
+		ID:         protoID,
+		AlterIDs:   protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
+		Security:   a.SecuritySettings.GetSecurityType(),
+		AEADHeader: a.AeadHeader,
 	}, nil
 }
@@ -24,10 +24,13 @@ type Account struct {
 	// Number of alternative IDs. Client and server must share the same number.
 	AlterId uint32 `protobuf:"varint,2,opt,name=alter_id,json=alterId,proto3" json:"alter_id,omitempty"`
 	// Security settings. Only applies to client side.
-	SecuritySettings     *protocol.SecurityConfig `protobuf:"bytes,3,opt,name=security_settings,json=securitySettings,proto3" json:"security_settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+	SecuritySettings *protocol.SecurityConfig `protobuf:"bytes,3,opt,name=security_settings,json=securitySettings,proto3" json:"security_settings,omitempty"`
+	// Whether to send requests with the AEAD header instead of the legacy
+	// header. Only applies to client side. Servers accept both.
+	AeadHeader           bool     `protobuf:"varint,4,opt,name=aead_header,json=aeadHeader,proto3" json:"aead_header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Account) Reset()         { *m = Account{} }
@@ -76,6 +79,13 @@ func (m *Account) GetSecuritySettings() *protocol.SecurityConfig {
 	return nil
 }
 
+func (m *Account) GetAeadHeader() bool {
+	if m != nil {
+		return m.AeadHeader
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.vmess.Account")
 }
@@ -85,21 +95,22 @@ func init() {
 }
 
 var fileDescriptor_d65dee31e5abbda0 = []byte{
-	// 244 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8f, 0xc1, 0x4a, 0x03, 0x31,
-	0x10, 0x86, 0xc9, 0x8a, 0x56, 0x23, 0x8a, 0xee, 0xa1, 0xac, 0x3d, 0x2d, 0x9e, 0x16, 0x91, 0x09,
-	0xac, 0x77, 0x41, 0x7b, 0xf2, 0x56, 0xb6, 0x50, 0xc1, 0x4b, 0x89, 0x49, 0xac, 0x81, 0x66, 0xa7,
-	0x4c, 0xd2, 0x62, 0x1e, 0xc2, 0x17, 0xf1, 0x29, 0xa5, 0xd9, 0x5d, 0x10, 0xe9, 0x2d, 0x61, 0xbe,
-	0xff, 0xfb, 0x67, 0x78, 0xb5, 0xab, 0x49, 0x46, 0x50, 0xe8, 0x84, 0x42, 0x32, 0x62, 0x43, 0xf8,
-	0x15, 0xc5, 0xce, 0x19, 0xef, 0x85, 0x54, 0x0a, 0xb7, 0x6d, 0x80, 0x0d, 0x61, 0xc0, 0x7c, 0x3c,
-	0x90, 0x64, 0x20, 0x51, 0x90, 0xa8, 0xc9, 0xfd, 0x3f, 0x83, 0x42, 0xe7, 0xb0, 0x15, 0x29, 0xa4,
-	0x70, 0x2d, 0x3e, 0x8d, 0xd4, 0x86, 0x7c, 0x67, 0xb9, 0xfd, 0x66, 0x7c, 0xf4, 0xd4, 0x79, 0xf3,
-	0x4b, 0x9e, 0x59, 0x5d, 0xb0, 0x92, 0x55, 0x67, 0x4d, 0x66, 0x75, 0x7e, 0xc3, 0x4f, 0xe5, 0x3a,
-	0x18, 0x5a, 0x5a, 0x5d, 0x64, 0x25, 0xab, 0x2e, 0x9a, 0x51, 0xfa, 0xbf, 0xe8, 0xfc, 0x95, 0x5f,
-	0x7b, 0xa3, 0xb6, 0x64, 0x43, 0x5c, 0x7a, 0x13, 0x82, 0x6d, 0x57, 0xbe, 0x38, 0x2a, 0x59, 0x75,
-	0x5e, 0xdf, 0xc1, 0x9f, 0xc5, 0xba, 0x72, 0x18, 0xca, 0x61, 0xde, 0x87, 0xa6, 0xd8, 0x7e, 0xd8,
-	0x55, 0x73, 0x35, 0x48, 0xe6, 0xbd, 0xe3, 0xf9, 0x91, 0x4f, 0x14, 0x3a, 0x38, 0x7c, 0xdb, 0x8c,
-	0xbd, 0x1d, 0xa7, 0xc7, 0x4f, 0x36, 0x5e, 0xd4, 0x8d, 0x8c, 0x30, 0xdd, 0x13, 0xb3, 0x44, 0x2c,
-	0xf6, 0x83, 0xf7, 0x93, 0x54, 0xf5, 0xf0, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x82, 0xf9, 0x1b, 0xdb,
-	0x48, 0x01, 0x00, 0x00,
+	// 260 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8f, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0x49, 0xfd, 0xb3, 0x99, 0xa1, 0x68, 0x0e, 0xa3, 0xee, 0x62, 0xf1, 0x14, 0x44, 0x12,
+	0xa8, 0x77, 0x41, 0x77, 0xd1, 0xdb, 0xc8, 0x60, 0x82, 0x97, 0x12, 0x93, 0x38, 0x03, 0x4b, 0xdf,
+	0x91, 0x64, 0xc3, 0x7e, 0x25, 0x0f, 0x7e, 0x46, 0x69, 0xda, 0x82, 0x88, 0xb7, 0xe4, 0xcd, 0xef,
+	0xfd, 0x3d, 0x4f, 0x30, 0xdd, 0x97, 0x5e, 0x36, 0x4c, 0x81, 0xe3, 0x0a, 0xbc, 0xe1, 0x5b, 0x0f,
+	0x9f, 0x0d, 0xdf, 0x3b, 0x13, 0x02, 0x97, 0x4a, 0xc1, 0xae, 0x8e, 0x6c, 0xeb, 0x21, 0x02, 0x99,
+	0x0e, 0xa4, 0x37, 0x2c, 0x51, 0x2c, 0x51, 0xb3, 0xdb, 0x3f, 0x06, 0x05, 0xce, 0x41, 0xcd, 0xd3,
+	0x92, 0x82, 0x0d, 0xff, 0x30, 0x52, 0x1b, 0x1f, 0x3a, 0xcb, 0xf5, 0x37, 0xc2, 0xa3, 0x87, 0xce,
+	0x4b, 0xce, 0x70, 0x66, 0x75, 0x8e, 0x0a, 0x44, 0x4f, 0x44, 0x66, 0x35, 0xb9, 0xc4, 0x63, 0xb9,
+	0x89, 0xc6, 0x57, 0x56, 0xe7, 0x59, 0x81, 0xe8, 0xa9, 0x18, 0xa5, 0xfb, 0xb3, 0x26, 0x2f, 0xf8,
+	0x22, 0x18, 0xb5, 0xf3, 0x36, 0x36, 0x55, 0x30, 0x31, 0xda, 0x7a, 0x1d, 0xf2, 0x83, 0x02, 0xd1,
+	0x49, 0x79, 0xc3, 0x7e, 0x15, 0xeb, 0xc2, 0xd9, 0x10, 0xce, 0x96, 0xfd, 0xd2, 0x1c, 0xea, 0x77,
+	0xbb, 0x16, 0xe7, 0x83, 0x64, 0xd9, 0x3b, 0xc8, 0x15, 0x9e, 0x48, 0x23, 0x75, 0xd5, 0xb5, 0xcc,
+	0x0f, 0x0b, 0x44, 0xc7, 0x02, 0xb7, 0xa3, 0xa7, 0x34, 0x79, 0xbc, 0xc7, 0x33, 0x05, 0x8e, 0xfd,
+	0xff, 0xf9, 0x05, 0x7a, 0x3d, 0x4a, 0x87, 0xaf, 0x6c, 0xba, 0x2a, 0x85, 0x6c, 0xd8, 0xbc, 0x25,
+	0x16, 0x89, 0x58, 0xb5, 0x0f, 0x6f, 0xc7, 0xa9, 0xcb, 0xdd, 0xcf, 0x00, 0x1f, 0x73, 0xe3, 0xd4,
+	0x69, 0x01, 0x00, 0x00,
 }
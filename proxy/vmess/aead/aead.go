@@ -0,0 +1,13 @@
+// Package aead implements the AEAD request header of VMess, which replaces the timestamp hash and the
+// AES-CFB encrypted header of the legacy format.
+//
+// A request header in this format consists of:
+//   - 16 bytes auth ID: AES encrypted timestamp, random number and CRC32 checksum, keyed by the user ID.
+//   - 18 bytes length of the header, sealed with AES-GCM.
+//   - 8 bytes connection nonce.
+//   - The header sealed with AES-GCM.
+//
+// All keys and nonces are derived from the cmd key of the user, the auth ID and the connection nonce.
+package aead
+
+//go:generate errorgen
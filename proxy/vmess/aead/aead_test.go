@@ -0,0 +1,67 @@
+package aead_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/proxy/vmess/aead"
+)
+
+func TestAuthID(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	now := time.Now().Unix()
+	authID := CreateAuthID(key, now)
+
+	ts, ok := NewAuthIDDecoder(key).Decode(authID)
+	if !ok {
+		t.Fatal("failed to decode auth ID")
+	}
+	if ts != now {
+		t.Error("unexpected time: ", ts)
+	}
+	if !IsValidTime(ts, time.Now()) || IsValidTime(ts, time.Now().Add(time.Minute*5)) {
+		t.Error("unexpected time validation")
+	}
+
+	if _, ok := NewAuthIDDecoder([]byte("fedcba9876543210")).Decode(authID); ok {
+		t.Error("decoded auth ID with a different key")
+	}
+}
+
+func TestSealHeader(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	header := []byte("request header")
+	authID := CreateAuthID(key, time.Now().Unix())
+
+	sealed := SealHeader(key, authID, header)
+	if !bytes.Equal(sealed[:AuthIDLen], authID[:]) {
+		t.Error("sealed header doesn't start with auth ID")
+	}
+
+	opened, err := OpenHeader(key, authID, bytes.NewReader(sealed[AuthIDLen:]))
+	common.Must(err)
+	if r := cmp.Diff(opened, header); r != "" {
+		t.Error(r)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := OpenHeader(key, authID, bytes.NewReader(sealed[AuthIDLen:])); err == nil {
+		t.Error("opened a tampered header")
+	}
+}
+
+func TestSealResponseHeader(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	iv := []byte("fedcba9876543210")
+	header := []byte("response header")
+
+	opened, err := OpenResponseHeader(key, iv, bytes.NewReader(SealResponseHeader(key, iv, header)))
+	common.Must(err)
+	if r := cmp.Diff(opened, header); r != "" {
+		t.Error(r)
+	}
+}
@@ -0,0 +1,62 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"hash/crc32"
+	"time"
+
+	"v2ray.com/core/common"
+)
+
+const (
+	// AuthIDLen is the length of an auth ID.
+	AuthIDLen = 16
+
+	kdfSaltAuthIDEncryptionKey = "AES Auth ID Encryption"
+)
+
+// CreateAuthID creates an auth ID of the given cmd key at the given time.
+func CreateAuthID(cmdKey []byte, t int64) [AuthIDLen]byte {
+	var authID [AuthIDLen]byte
+	binary.BigEndian.PutUint64(authID[:8], uint64(t))
+	common.Must2(rand.Read(authID[8:12]))
+	binary.BigEndian.PutUint32(authID[12:], crc32.ChecksumIEEE(authID[:12]))
+	newAuthIDBlock(cmdKey).Encrypt(authID[:], authID[:])
+	return authID
+}
+
+func newAuthIDBlock(cmdKey []byte) cipher.Block {
+	block, err := aes.NewCipher(KDF16(cmdKey, kdfSaltAuthIDEncryptionKey))
+	common.Must(err)
+	return block
+}
+
+// AuthIDDecoder decodes auth IDs of one user.
+type AuthIDDecoder struct {
+	block cipher.Block
+}
+
+// NewAuthIDDecoder creates a new AuthIDDecoder for the given cmd key.
+func NewAuthIDDecoder(cmdKey []byte) *AuthIDDecoder {
+	return &AuthIDDecoder{
+		block: newAuthIDBlock(cmdKey),
+	}
+}
+
+// Decode returns the time in the given auth ID, and whether the auth ID belongs to the user of this decoder.
+func (d *AuthIDDecoder) Decode(authID [AuthIDLen]byte) (int64, bool) {
+	d.block.Decrypt(authID[:], authID[:])
+	if crc32.ChecksumIEEE(authID[:12]) != binary.BigEndian.Uint32(authID[12:]) {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(authID[:8])), true
+}
+
+// IsValidTime returns true if the time of an auth ID is within the allowed window around now.
+func IsValidTime(t int64, now time.Time) bool {
+	delta := now.Unix() - t
+	return delta >= -120 && delta <= 120
+}
@@ -0,0 +1,119 @@
+package aead
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/crypto"
+)
+
+const (
+	kdfSaltHeaderLengthKey   = "VMess Header AEAD Key_Length"
+	kdfSaltHeaderLengthNonce = "VMess Header AEAD Nonce_Length"
+	kdfSaltHeaderKey         = "VMess Header AEAD Key"
+	kdfSaltHeaderNonce       = "VMess Header AEAD Nonce"
+
+	kdfSaltResponseLengthKey   = "AEAD Resp Header Len Key"
+	kdfSaltResponseLengthNonce = "AEAD Resp Header Len IV"
+	kdfSaltResponseKey         = "AEAD Resp Header Key"
+	kdfSaltResponseNonce       = "AEAD Resp Header IV"
+
+	nonceLen    = 8
+	overhead    = 16
+	gcmNonceLen = 12
+)
+
+// SealHeader returns the sealed form of the given request header, including the auth ID.
+func SealHeader(cmdKey []byte, authID [AuthIDLen]byte, header []byte) []byte {
+	var nonce [nonceLen]byte
+	common.Must2(rand.Read(nonce[:]))
+
+	var length [2]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(header)))
+
+	lengthAEAD := crypto.NewAesGcm(KDF16(cmdKey, kdfSaltHeaderLengthKey, string(authID[:]), string(nonce[:])))
+	lengthNonce := KDF(cmdKey, kdfSaltHeaderLengthNonce, string(authID[:]), string(nonce[:]))[:gcmNonceLen]
+	headerAEAD := crypto.NewAesGcm(KDF16(cmdKey, kdfSaltHeaderKey, string(authID[:]), string(nonce[:])))
+	headerNonce := KDF(cmdKey, kdfSaltHeaderNonce, string(authID[:]), string(nonce[:]))[:gcmNonceLen]
+
+	output := make([]byte, 0, AuthIDLen+len(length)+overhead+nonceLen+len(header)+overhead)
+	output = append(output, authID[:]...)
+	output = lengthAEAD.Seal(output, lengthNonce, length[:], authID[:])
+	output = append(output, nonce[:]...)
+	output = headerAEAD.Seal(output, headerNonce, header, authID[:])
+	return output
+}
+
+// OpenHeader reads a sealed request header after the given auth ID from the reader, and returns the header.
+func OpenHeader(cmdKey []byte, authID [AuthIDLen]byte, reader io.Reader) ([]byte, error) {
+	var sealedLength [2 + overhead]byte
+	if _, err := io.ReadFull(reader, sealedLength[:]); err != nil {
+		return nil, newError("failed to read header length").Base(err)
+	}
+	var nonce [nonceLen]byte
+	if _, err := io.ReadFull(reader, nonce[:]); err != nil {
+		return nil, newError("failed to read header nonce").Base(err)
+	}
+
+	lengthAEAD := crypto.NewAesGcm(KDF16(cmdKey, kdfSaltHeaderLengthKey, string(authID[:]), string(nonce[:])))
+	lengthNonce := KDF(cmdKey, kdfSaltHeaderLengthNonce, string(authID[:]), string(nonce[:]))[:gcmNonceLen]
+	length, err := lengthAEAD.Open(nil, lengthNonce, sealedLength[:], authID[:])
+	if err != nil {
+		return nil, newError("failed to open header length").Base(err)
+	}
+
+	sealedHeader := make([]byte, int(binary.BigEndian.Uint16(length))+overhead)
+	if _, err := io.ReadFull(reader, sealedHeader); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+
+	headerAEAD := crypto.NewAesGcm(KDF16(cmdKey, kdfSaltHeaderKey, string(authID[:]), string(nonce[:])))
+	headerNonce := KDF(cmdKey, kdfSaltHeaderNonce, string(authID[:]), string(nonce[:]))[:gcmNonceLen]
+	header, err := headerAEAD.Open(sealedHeader[:0], headerNonce, sealedHeader, authID[:])
+	if err != nil {
+		return nil, newError("failed to open header").Base(err)
+	}
+	return header, nil
+}
+
+// SealResponseHeader returns the sealed form of the given response header, keyed by the response body key and IV.
+func SealResponseHeader(key []byte, iv []byte, header []byte) []byte {
+	var length [2]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(header)))
+
+	lengthAEAD := crypto.NewAesGcm(KDF16(key, kdfSaltResponseLengthKey))
+	headerAEAD := crypto.NewAesGcm(KDF16(key, kdfSaltResponseKey))
+
+	output := make([]byte, 0, len(length)+overhead+len(header)+overhead)
+	output = lengthAEAD.Seal(output, KDF(iv, kdfSaltResponseLengthNonce)[:gcmNonceLen], length[:], nil)
+	output = headerAEAD.Seal(output, KDF(iv, kdfSaltResponseNonce)[:gcmNonceLen], header, nil)
+	return output
+}
+
+// OpenResponseHeader reads a sealed response header from the reader, and returns the header.
+func OpenResponseHeader(key []byte, iv []byte, reader io.Reader) ([]byte, error) {
+	var sealedLength [2 + overhead]byte
+	if _, err := io.ReadFull(reader, sealedLength[:]); err != nil {
+		return nil, newError("failed to read response header length").Base(err)
+	}
+
+	lengthAEAD := crypto.NewAesGcm(KDF16(key, kdfSaltResponseLengthKey))
+	length, err := lengthAEAD.Open(nil, KDF(iv, kdfSaltResponseLengthNonce)[:gcmNonceLen], sealedLength[:], nil)
+	if err != nil {
+		return nil, newError("failed to open response header length").Base(err)
+	}
+
+	sealedHeader := make([]byte, int(binary.BigEndian.Uint16(length))+overhead)
+	if _, err := io.ReadFull(reader, sealedHeader); err != nil {
+		return nil, newError("failed to read response header").Base(err)
+	}
+
+	headerAEAD := crypto.NewAesGcm(KDF16(key, kdfSaltResponseKey))
+	header, err := headerAEAD.Open(sealedHeader[:0], KDF(iv, kdfSaltResponseNonce)[:gcmNonceLen], sealedHeader, nil)
+	if err != nil {
+		return nil, newError("failed to open response header").Base(err)
+	}
+	return header, nil
+}
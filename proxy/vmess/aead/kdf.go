@@ -0,0 +1,32 @@
+package aead
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+const kdfSaltConst = "VMess AEAD KDF"
+
+// KDF derives a 32 bytes key from the given key and path. Each element in the path wraps the HMAC of the
+// previous elements, so keys of different paths are independent.
+func KDF(key []byte, path ...string) []byte {
+	hmacCreator := func() hash.Hash {
+		return hmac.New(sha256.New, []byte(kdfSaltConst))
+	}
+	for _, p := range path {
+		parent := hmacCreator
+		salt := []byte(p)
+		hmacCreator = func() hash.Hash {
+			return hmac.New(parent, salt)
+		}
+	}
+	h := hmacCreator()
+	h.Write(key) // nolint: errcheck
+	return h.Sum(nil)
+}
+
+// KDF16 derives a 16 bytes key from the given key and path.
+func KDF16(key []byte, path ...string) []byte {
+	return KDF(key, path...)[:16]
+}
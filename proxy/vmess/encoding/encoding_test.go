@@ -42,7 +42,7 @@ func TestRequestSerialization(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(false, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -92,7 +92,7 @@ func TestInvalidRequest(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(false, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -133,7 +133,7 @@ func TestMuxRequest(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(false, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -154,3 +154,119 @@ func TestMuxRequest(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestAEADRequestSerialization(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Level: 0,
+		Email: "test@v2ray.com",
+	}
+	id := uuid.New()
+	account := &vmess.Account{
+		Id:         id.String(),
+		AlterId:    0,
+		AeadHeader: true,
+	}
+	user.Account = toAccount(account)
+
+	expectedRequest := &protocol.RequestHeader{
+		Version:  1,
+		User:     user,
+		Command:  protocol.RequestCommandTCP,
+		Option:   protocol.RequestOptionChunkStream,
+		Address:  net.DomainAddress("www.v2ray.com"),
+		Port:     net.Port(443),
+		Security: protocol.SecurityType_LEGACY,
+	}
+
+	buffer := buf.New()
+	client := NewClientSession(true, protocol.DefaultIDHash)
+	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
+
+	buffer2 := buf.New()
+	buffer2.Write(buffer.Bytes())
+
+	sessionHistory := NewSessionHistory()
+	defer common.Close(sessionHistory)
+
+	userValidator := vmess.NewTimedUserValidator(protocol.DefaultIDHash)
+	userValidator.Add(user)
+	defer common.Close(userValidator)
+
+	server := NewServerSession(userValidator, sessionHistory)
+	server.DisableLegacyHeader()
+	actualRequest, err := server.DecodeRequestHeader(buffer)
+	common.Must(err)
+
+	if r := cmp.Diff(actualRequest, expectedRequest, cmp.AllowUnexported(protocol.ID{})); r != "" {
+		t.Error(r)
+	}
+
+	_, err = NewServerSession(userValidator, sessionHistory).DecodeRequestHeader(buffer2)
+	// anti replay attack
+	if err == nil {
+		t.Error("nil error")
+	}
+
+	response := buf.New()
+	server.EncodeResponseHeader(&protocol.ResponseHeader{
+		Command: &protocol.CommandSwitchAccount{
+			ID:       id,
+			AlterIds: 8,
+			Level:    1,
+			ValidMin: 60,
+		},
+	}, response)
+	bodyWriter := server.EncodeResponseBody(actualRequest, response)
+	payload := []byte("response payload")
+	common.Must(bodyWriter.WriteMultiBuffer(buf.MergeBytes(nil, payload)))
+
+	header, err := client.DecodeResponseHeader(response)
+	common.Must(err)
+	if cmd, ok := header.Command.(*protocol.CommandSwitchAccount); !ok || cmd.AlterIds != 8 {
+		t.Error("unexpected response command: ", header.Command)
+	}
+	mb, err := client.DecodeResponseBody(expectedRequest, response).ReadMultiBuffer()
+	common.Must(err)
+	if r := cmp.Diff(mb.String(), string(payload)); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestLegacyHeaderDisabled(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Level: 0,
+		Email: "test@v2ray.com",
+	}
+	id := uuid.New()
+	account := &vmess.Account{
+		Id:      id.String(),
+		AlterId: 0,
+	}
+	user.Account = toAccount(account)
+
+	request := &protocol.RequestHeader{
+		Version:  1,
+		User:     user,
+		Command:  protocol.RequestCommandTCP,
+		Address:  net.DomainAddress("www.v2ray.com"),
+		Port:     net.Port(443),
+		Security: protocol.SecurityType_AES128_GCM,
+	}
+
+	buffer := buf.New()
+	client := NewClientSession(false, protocol.DefaultIDHash)
+	common.Must(client.EncodeRequestHeader(request, buffer))
+
+	sessionHistory := NewSessionHistory()
+	defer common.Close(sessionHistory)
+
+	userValidator := vmess.NewTimedUserValidator(protocol.DefaultIDHash)
+	userValidator.Add(user)
+	defer common.Close(userValidator)
+
+	server := NewServerSession(userValidator, sessionHistory)
+	server.DisableLegacyHeader()
+	if _, err := server.DecodeRequestHeader(buffer); err == nil {
+		t.Error("nil error")
+	}
+}
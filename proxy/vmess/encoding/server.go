@@ -1,7 +1,9 @@
 package encoding
 
 import (
+	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
 	"hash/fnv"
 	"io"
@@ -18,6 +20,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/proxy/vmess"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 type sessionId struct {
@@ -97,6 +100,8 @@ type ServerSession struct {
 	responseBodyIV  [16]byte
 	responseWriter  io.Writer
 	responseHeader  byte
+	isAEAD          bool
+	aeadOnly        bool
 }
 
 // NewServerSession creates a new ServerSession, using the given UserValidator.
@@ -108,6 +113,11 @@ func NewServerSession(validator *vmess.TimedUserValidator, sessionHistory *Sessi
 	}
 }
 
+// DisableLegacyHeader makes the ServerSession reject requests with the legacy header.
+func (s *ServerSession) DisableLegacyHeader() {
+	s.aeadOnly = true
+}
+
 func parseSecurityType(b byte) protocol.SecurityType {
 	if _, f := protocol.SecurityType_name[int32(b)]; f {
 		st := protocol.SecurityType(b)
@@ -129,16 +139,35 @@ func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.Request
 		return nil, newError("failed to read request header").Base(err)
 	}
 
-	user, timestamp, valid := s.userValidator.Get(buffer.Bytes())
-	if !valid {
-		return nil, newError("invalid user")
+	var user *protocol.MemoryUser
+	var timestamp protocol.Timestamp
+	valid := false
+	if !s.aeadOnly {
+		user, timestamp, valid = s.userValidator.Get(buffer.Bytes())
 	}
 
-	iv := hashTimestamp(md5.New(), timestamp)
-	vmessAccount := user.Account.(*vmess.MemoryAccount)
+	var decryptor io.Reader
+	if valid {
+		iv := hashTimestamp(md5.New(), timestamp)
+		aesStream := crypto.NewAesDecryptionStream(user.Account.(*vmess.MemoryAccount).ID.CmdKey(), iv[:])
+		decryptor = crypto.NewCryptionReader(aesStream, reader)
+	} else {
+		var err error
+		user, err = s.userValidator.GetAEAD(buffer.Bytes())
+		if err != nil {
+			return nil, newError("invalid user").Base(err)
+		}
 
-	aesStream := crypto.NewAesDecryptionStream(vmessAccount.ID.CmdKey(), iv[:])
-	decryptor := crypto.NewCryptionReader(aesStream, reader)
+		var authID [aead.AuthIDLen]byte
+		copy(authID[:], buffer.Bytes())
+		header, err := aead.OpenHeader(user.Account.(*vmess.MemoryAccount).ID.CmdKey(), authID, reader)
+		if err != nil {
+			return nil, newError("failed to read AEAD request header").Base(err)
+		}
+		decryptor = bytes.NewReader(header)
+		s.isAEAD = true
+	}
+	vmessAccount := user.Account.(*vmess.MemoryAccount)
 
 	buffer.Clear()
 	if _, err := buffer.ReadFullFrom(decryptor, 38); err != nil {
@@ -273,13 +302,32 @@ func (s *ServerSession) DecodeRequestBody(request *protocol.RequestHeader, reade
 
 // EncodeResponseHeader writes encoded response header into the given writer.
 func (s *ServerSession) EncodeResponseHeader(header *protocol.ResponseHeader, writer io.Writer) {
-	s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
-	s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	if s.isAEAD {
+		responseBodyKey := sha256.Sum256(s.requestBodyKey[:])
+		copy(s.responseBodyKey[:], responseBodyKey[:16])
+		responseBodyIV := sha256.Sum256(s.requestBodyIV[:])
+		copy(s.responseBodyIV[:], responseBodyIV[:16])
+	} else {
+		s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
+		s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	}
 
 	aesStream := crypto.NewAesEncryptionStream(s.responseBodyKey[:], s.responseBodyIV[:])
 	encryptionWriter := crypto.NewCryptionWriter(aesStream, writer)
 	s.responseWriter = encryptionWriter
 
+	if s.isAEAD {
+		buffer := buf.New()
+		defer buffer.Release()
+
+		common.Must2(buffer.Write([]byte{s.responseHeader, byte(header.Option)}))
+		if err := MarshalCommand(header.Command, buffer); err != nil {
+			common.Must2(buffer.Write([]byte{0x00, 0x00}))
+		}
+		common.Must2(writer.Write(aead.SealResponseHeader(s.responseBodyKey[:], s.responseBodyIV[:], buffer.Bytes())))
+		return
+	}
+
 	common.Must2(encryptionWriter.Write([]byte{s.responseHeader, byte(header.Option)}))
 	err := MarshalCommand(header.Command, encryptionWriter)
 	if err != nil {
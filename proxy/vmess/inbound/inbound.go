@@ -25,6 +25,7 @@ import (
 	feature_inbound "v2ray.com/core/features/inbound"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy/fallback"
 	"v2ray.com/core/proxy/vmess"
 	"v2ray.com/core/proxy/vmess/encoding"
 	"v2ray.com/core/transport/internet"
@@ -109,6 +110,8 @@ type Handler struct {
 	detours               *DetourConfig
 	sessionHistory        *encoding.SessionHistory
 	secure                bool
+	aeadOnly              bool
+	fallbacks             []*fallback.Fallback
 }
 
 // New creates a new VMess inbound handler.
@@ -122,6 +125,8 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 		usersByEmail:          newUserByEmail(config.GetDefaultValue()),
 		sessionHistory:        encoding.NewSessionHistory(),
 		secure:                config.SecureEncryptionOnly,
+		aeadOnly:              config.DisableLegacyHeader,
+		fallbacks:             config.Fallbacks,
 	}
 
 	for _, user := range config.User {
@@ -177,6 +182,16 @@ func (h *Handler) RemoveUser(ctx context.Context, email string) error {
 	return nil
 }
 
+// GetUsers implements proxy.UserLister.
+func (h *Handler) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return h.clients.GetUsers()
+}
+
+// GetUserQuota implements proxy.UserQuotaGetter.
+func (h *Handler) GetUserQuota(ctx context.Context, email string) *protocol.Quota {
+	return h.clients.GetQuota(email)
+}
+
 func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSession, request *protocol.RequestHeader, response *protocol.ResponseHeader, input buf.Reader, output *buf.BufferedWriter) error {
 	session.EncodeResponseHeader(response, output)
 
@@ -222,8 +237,18 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		return newError("unable to set read deadline").Base(err).AtWarning()
 	}
 
-	reader := &buf.BufferedReader{Reader: buf.NewReader(connection)}
+	var recorder *fallback.Recorder
+	connReader := buf.NewReader(connection)
+	if len(h.fallbacks) > 0 {
+		recorder = fallback.NewRecorder(connReader)
+		connReader = recorder
+	}
+
+	reader := &buf.BufferedReader{Reader: connReader}
 	svrSession := encoding.NewServerSession(h.clients, h.sessionHistory)
+	if h.aeadOnly {
+		svrSession.DisableLegacyHeader()
+	}
 	request, err := svrSession.DecodeRequestHeader(reader)
 	if err != nil {
 		if errors.Cause(err) != io.EOF {
@@ -233,10 +258,21 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 				Status: log.AccessRejected,
 				Reason: err,
 			})
-			err = newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
+			rejected := newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
+			if recorder == nil {
+				return rejected
+			}
+			rejected.WriteToLog(session.ExportIDToError(ctx))
+			if err := connection.SetReadDeadline(time.Time{}); err != nil {
+				newError("unable to set back read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+			}
+			return fallback.Serve(ctx, connection, recorder, h.fallbacks, sessionPolicy)
 		}
 		return err
 	}
+	if recorder != nil {
+		recorder.Stop()
+	}
 
 	if h.secure && isInsecureEncryption(request.Security) {
 		log.Record(&log.AccessMessage{
@@ -11,6 +11,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/common/task"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 const (
@@ -19,8 +20,9 @@ const (
 )
 
 type user struct {
-	user    protocol.MemoryUser
-	lastSec protocol.Timestamp
+	user        protocol.MemoryUser
+	lastSec     protocol.Timestamp
+	authDecoder *aead.AuthIDDecoder
 }
 
 // TimedUserValidator is a user Validator based on time.
@@ -31,6 +33,8 @@ type TimedUserValidator struct {
 	hasher   protocol.IDHash
 	baseTime protocol.Timestamp
 	task     *task.Periodic
+	// authIDs are the AEAD auth IDs seen recently, to prevent replay attacks.
+	authIDs map[[aead.AuthIDLen]byte]time.Time
 }
 
 type indexTimePair struct {
@@ -45,6 +49,7 @@ func NewTimedUserValidator(hasher protocol.IDHash) *TimedUserValidator {
 		userHash: make(map[[16]byte]indexTimePair, 1024),
 		hasher:   hasher,
 		baseTime: protocol.Timestamp(time.Now().Unix() - cacheDurationSec*2),
+		authIDs:  make(map[[aead.AuthIDLen]byte]time.Time),
 	}
 	tuv.task = &task.Periodic{
 		Interval: updateInterval,
@@ -109,6 +114,12 @@ func (v *TimedUserValidator) updateUserHash() {
 	if expire > v.baseTime {
 		v.removeExpiredHashes(uint32(expire - v.baseTime))
 	}
+
+	for authID, t := range v.authIDs {
+		if t.Before(now) {
+			delete(v.authIDs, authID)
+		}
+	}
 }
 
 func (v *TimedUserValidator) Add(u *protocol.MemoryUser) error {
@@ -118,8 +129,9 @@ func (v *TimedUserValidator) Add(u *protocol.MemoryUser) error {
 	nowSec := time.Now().Unix()
 
 	uu := &user{
-		user:    *u,
-		lastSec: protocol.Timestamp(nowSec - cacheDurationSec),
+		user:        *u,
+		lastSec:     protocol.Timestamp(nowSec - cacheDurationSec),
+		authDecoder: aead.NewAuthIDDecoder(u.Account.(*MemoryAccount).ID.CmdKey()),
 	}
 	v.users = append(v.users, uu)
 	v.generateNewHashes(protocol.Timestamp(nowSec), uu)
@@ -142,6 +154,60 @@ func (v *TimedUserValidator) Get(userHash []byte) (*protocol.MemoryUser, protoco
 	return nil, 0, false
 }
 
+// GetAEAD returns the user of the given AEAD auth ID. Each auth ID is accepted only once.
+func (v *TimedUserValidator) GetAEAD(authID []byte) (*protocol.MemoryUser, error) {
+	v.Lock()
+	defer v.Unlock()
+
+	var fixedSizeAuthID [aead.AuthIDLen]byte
+	copy(fixedSizeAuthID[:], authID)
+
+	for _, u := range v.users {
+		t, ok := u.authDecoder.Decode(fixedSizeAuthID)
+		if !ok {
+			continue
+		}
+		now := time.Now()
+		if !aead.IsValidTime(t, now) {
+			return nil, newError("invalid time of auth ID: ", t)
+		}
+		if _, found := v.authIDs[fixedSizeAuthID]; found {
+			return nil, newError("duplicated auth ID, possibly under replay attack")
+		}
+		v.authIDs[fixedSizeAuthID] = now.Add(time.Second * cacheDurationSec * 2)
+
+		user := u.user
+		return &user, nil
+	}
+	return nil, newError("user not found")
+}
+
+// GetUsers returns all users in this validator.
+func (v *TimedUserValidator) GetUsers() []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	users := make([]*protocol.MemoryUser, 0, len(v.users))
+	for _, u := range v.users {
+		user := u.user
+		users = append(users, &user)
+	}
+	return users
+}
+
+// GetQuota returns the quota of the user with the given email, or nil if the user is not found.
+func (v *TimedUserValidator) GetQuota(email string) *protocol.Quota {
+	v.RLock()
+	defer v.RUnlock()
+
+	for _, u := range v.users {
+		if strings.EqualFold(u.user.Email, email) {
+			return u.user.Quota
+		}
+	}
+	return nil
+}
+
 func (v *TimedUserValidator) Remove(email string) bool {
 	v.Lock()
 	defer v.Unlock()
@@ -126,6 +126,276 @@ func TestCommanderRemoveHandler(t *testing.T) {
 	}
 }
 
+func TestCommanderReplaceInbound(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	clientPort := tcp.PickPort()
+	cmdPort := tcp.PickPort()
+	inboundConfig := &core.InboundHandlerConfig{
+		Tag: "d",
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortRange: net.SinglePortRange(clientPort),
+			Listen:    net.NewIPOrDomain(net.LocalHostIP),
+		}),
+		ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+			Address:  net.NewIPOrDomain(dest.Address),
+			Port:     uint32(dest.Port),
+			Networks: []net.Network{net.Network_TCP},
+		}),
+	}
+	clientConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&commander.Config{
+				Tag: "api",
+				Service: []*serial.TypedMessage{
+					serial.ToTypedMessage(&command.Config{}),
+				},
+			}),
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						InboundTag: []string{"api"},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "api",
+						},
+					},
+				},
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			inboundConfig,
+			{
+				Tag: "api",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(cmdPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address:  net.NewIPOrDomain(dest.Address),
+					Port:     uint32(dest.Port),
+					Networks: []net.Network{net.Network_TCP},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(clientConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(clientPort),
+	})
+	common.Must(err)
+	defer conn.Close()
+	if err := testTCPConn2(conn, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+
+	cmdConn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", cmdPort), grpc.WithInsecure(), grpc.WithBlock())
+	common.Must(err)
+	defer cmdConn.Close()
+
+	hsClient := command.NewHandlerServiceClient(cmdConn)
+	_, err = hsClient.ReplaceInbound(context.Background(), &command.ReplaceInboundRequest{
+		Inbound:      inboundConfig,
+		DrainTimeout: 2,
+	})
+	common.Must(err)
+
+	// The existing connection is kept by the old handler, while new connections go to the new handler.
+	if err := testTCPConn2(conn, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testTCPConn(clientPort, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Second * 3)
+	if err := testTCPConn2(conn, 1024, time.Second*5)(); err == nil {
+		t.Error("expected error after drain timeout")
+	}
+	if err := testTCPConn(clientPort, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The handler is kept if the new one fails to start.
+	_, err = hsClient.ReplaceInbound(context.Background(), &command.ReplaceInboundRequest{
+		Inbound: &core.InboundHandlerConfig{
+			Tag: "d",
+			ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+				PortRange: &net.PortRange{From: uint32(clientPort), To: uint32(clientPort)},
+				PortList: &net.PortList{
+					Range: []*net.PortRange{net.SinglePortRange(cmdPort)},
+				},
+				Listen: net.NewIPOrDomain(net.LocalHostIP),
+			}),
+			ProxySettings: inboundConfig.ProxySettings,
+		},
+		DrainTimeout: 1,
+	})
+	if err == nil {
+		t.Error("expected error when the new handler fails to listen")
+	}
+	if err := testTCPConn(clientPort, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Handlers that don't exist can't be replaced.
+	_, err = hsClient.ReplaceInbound(context.Background(), &command.ReplaceInboundRequest{
+		Inbound: &core.InboundHandlerConfig{
+			Tag:              "e",
+			ReceiverSettings: inboundConfig.ReceiverSettings,
+			ProxySettings:    inboundConfig.ProxySettings,
+		},
+	})
+	if err == nil {
+		t.Error("expected error when replacing a handler that doesn't exist")
+	}
+
+	_, err = hsClient.RemoveInbound(context.Background(), &command.RemoveInboundRequest{
+		Tag:          "d",
+		DrainTimeout: 1,
+	})
+	common.Must(err)
+
+	if err := testTCPConn(clientPort, 1024, time.Second*5)(); err == nil {
+		t.Error("expected error after handler is removed")
+	}
+}
+
+func TestCommanderSetPorts(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	port1 := tcp.PickPort()
+	port2 := tcp.PickPort()
+	cmdPort := tcp.PickPort()
+	clientConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&commander.Config{
+				Tag: "api",
+				Service: []*serial.TypedMessage{
+					serial.ToTypedMessage(&command.Config{}),
+				},
+			}),
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						InboundTag: []string{"api"},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "api",
+						},
+					},
+				},
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				Tag: "d",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: &net.PortRange{From: 1, To: 65535},
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					AllocationStrategy: &proxyman.AllocationStrategy{
+						Type: proxyman.AllocationStrategy_External,
+					},
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address:  net.NewIPOrDomain(dest.Address),
+					Port:     uint32(dest.Port),
+					Networks: []net.Network{net.Network_TCP},
+				}),
+			},
+			{
+				Tag: "api",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(cmdPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address:  net.NewIPOrDomain(dest.Address),
+					Port:     uint32(dest.Port),
+					Networks: []net.Network{net.Network_TCP},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(clientConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	if err := testTCPConn(port1, 1024, time.Second*5)(); err == nil {
+		t.Fatal("expected error before ports are set")
+	}
+
+	cmdConn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", cmdPort), grpc.WithInsecure(), grpc.WithBlock())
+	common.Must(err)
+	defer cmdConn.Close()
+
+	hsClient := command.NewHandlerServiceClient(cmdConn)
+	setPorts := func(ports ...net.Port) error {
+		op := &command.SetPortsOperation{}
+		for _, port := range ports {
+			op.Ports = append(op.Ports, uint32(port))
+		}
+		_, err := hsClient.AlterInbound(context.Background(), &command.AlterInboundRequest{
+			Tag:       "d",
+			Operation: serial.ToTypedMessage(op),
+		})
+		return err
+	}
+
+	common.Must(setPorts(port1))
+	if err := testTCPConn(port1, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Duplicated ports are listened once.
+	common.Must(setPorts(port2, port2))
+	if err := testTCPConn(port2, 1024, time.Second*5)(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testTCPConn(port1, 1024, time.Second*5)(); err == nil {
+		t.Error("expected error after port is removed")
+	}
+
+	// The ports are unchanged if any of them fails to listen.
+	if err := setPorts(port1, cmdPort); err == nil {
+		t.Error("expected error when port is in use")
+	}
+	if err := testTCPConn(port2, 1024, time.Second*5)(); err != nil {
+		t.Error(err)
+	}
+	if err := testTCPConn(port1, 1024, time.Second*5)(); err == nil {
+		t.Error("expected error after failed update")
+	}
+	common.Must(setPorts(port1))
+	if err := testTCPConn(port1, 1024, time.Second*5)(); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestCommanderAddRemoveUser(t *testing.T) {
 	tcpServer := tcp.Server{
 		MsgProcessor: xor,
@@ -296,6 +566,30 @@ func TestCommanderAddRemoveUser(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	usersResp, err := hsClient.ListUsers(context.Background(), &command.ListUsersRequest{
+		Tag:   "v",
+		Email: "test@v2ray.com",
+	})
+	common.Must(err)
+	if len(usersResp.Users) != 1 {
+		t.Fatal("expected 1 user, but got ", len(usersResp.Users))
+	}
+	account, err := usersResp.Users[0].Account.GetInstance()
+	common.Must(err)
+	if id := account.(*vmess.Account).Id; id != u2.String() {
+		t.Error("unexpected user id: ", id)
+	}
+
+	inboundsResp, err := hsClient.ListInbounds(context.Background(), &command.ListInboundsRequest{})
+	common.Must(err)
+	tags := make(map[string]bool)
+	for _, config := range inboundsResp.Inbounds {
+		tags[config.Tag] = true
+	}
+	if !tags["v"] || !tags["api"] || len(tags) != 2 {
+		t.Error("unexpected inbounds: ", tags)
+	}
+
 	resp, err = hsClient.AlterInbound(context.Background(), &command.AlterInboundRequest{
 		Tag:       "v",
 		Operation: serial.ToTypedMessage(&command.RemoveUserOperation{Email: "test@v2ray.com"}),
@@ -0,0 +1,118 @@
+package scenarios
+
+import (
+	"testing"
+	"time"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/blackhole"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/proxy/loopback"
+	"v2ray.com/core/testing/servers/tcp"
+)
+
+func TestLoopback(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	dokodemoConfig := serial.ToTypedMessage(&dokodemo.Config{
+		Address: net.NewIPOrDomain(dest.Address),
+		Port:    uint32(dest.Port),
+		NetworkList: &net.NetworkList{
+			Network: []net.Network{net.Network_TCP},
+		},
+	})
+	routeTo := func(outboundTag string, inboundTag string) *router.RoutingRule {
+		return &router.RoutingRule{
+			TargetTag: &router.RoutingRule_Tag{
+				Tag: outboundTag,
+			},
+			InboundTag: []string{inboundTag},
+		}
+	}
+
+	serverPort := tcp.PickPort()
+	loopPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				Tag: "in",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: dokodemoConfig,
+			},
+			{
+				Tag: "loop",
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(loopPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: dokodemoConfig,
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				// Connections not matching any rule are blocked.
+				Tag:           "blocked",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+			{
+				Tag: "to-reentry",
+				ProxySettings: serial.ToTypedMessage(&loopback.Config{
+					InboundTag: "reentry",
+				}),
+			},
+			{
+				Tag: "to-loop",
+				ProxySettings: serial.ToTypedMessage(&loopback.Config{
+					InboundTag: "loop",
+				}),
+			},
+			{
+				Tag: "to-loop-reentry",
+				ProxySettings: serial.ToTypedMessage(&loopback.Config{
+					InboundTag: "loop-reentry",
+				}),
+			},
+		},
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					routeTo("to-reentry", "in"),
+					routeTo("direct", "reentry"),
+					// "loop" and "loop-reentry" loop back to each other.
+					routeTo("to-loop-reentry", "loop"),
+					routeTo("to-loop", "loop-reentry"),
+				},
+			}),
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	if err := testTCPConn(serverPort, 10240*1024, time.Second*20)(); err != nil {
+		t.Error(err)
+	}
+
+	if err := testTCPConn(loopPort, 1024, time.Second*5)(); err == nil {
+		t.Error("nil error with loop")
+	}
+}
@@ -0,0 +1,317 @@
+package scenarios
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/crypto"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/proxy/mtproto"
+	"v2ray.com/core/testing/servers/tcp"
+)
+
+// mtprotoConn is a connection of a Telegram client to an MTProto proxy.
+type mtprotoConn struct {
+	net.Conn
+	reader io.Reader
+	writer io.Writer
+}
+
+func (c *mtprotoConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func (c *mtprotoConn) Write(b []byte) (int, error) {
+	// The writer encrypts data in place.
+	return c.writer.Write(append([]byte(nil), b...))
+}
+
+// fakeTLSRecords reads the payload of ApplicationData records, or writes data in ApplicationData records.
+type fakeTLSRecords struct {
+	conn      net.Conn
+	remaining int
+}
+
+func (r *fakeTLSRecords) Read(b []byte) (int, error) {
+	for r.remaining == 0 {
+		var header [5]byte
+		if _, err := io.ReadFull(r.conn, header[:]); err != nil {
+			return 0, err
+		}
+		if header[0] != 0x17 {
+			return 0, errors.New("unexpected record type: ", header[0])
+		}
+		r.remaining = int(binary.BigEndian.Uint16(header[3:]))
+	}
+	if len(b) > r.remaining {
+		b = b[:r.remaining]
+	}
+	n, err := r.conn.Read(b)
+	r.remaining -= n
+	return n, err
+}
+
+func (r *fakeTLSRecords) Write(b []byte) (int, error) {
+	record := append([]byte{0x17, 0x03, 0x03, byte(len(b) >> 8), byte(len(b))}, b...)
+	if _, err := r.conn.Write(record); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// newFakeTLSClientHello returns a ClientHello record, whose random is the HMAC of the record XORed with the timestamp.
+func newFakeTLSClientHello(key []byte) []byte {
+	body := make([]byte, 2+32+1+32+400)
+	body[0], body[1] = 0x03, 0x03
+	body[34] = 32
+	common.Must2(rand.Read(body[35:67]))
+	record := append([]byte{0x16, 0x03, 0x01, byte((len(body) + 4) >> 8), byte(len(body) + 4), 0x01, 0x00, byte(len(body) >> 8), byte(len(body))}, body...)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(record) // nolint: errcheck
+	random := mac.Sum(nil)
+	var timestamp [4]byte
+	binary.LittleEndian.PutUint32(timestamp[:], uint32(time.Now().Unix()))
+	for i := range timestamp {
+		random[28+i] ^= timestamp[i]
+	}
+	copy(record[11:], random)
+	return record
+}
+
+// handshakeFakeTLS sends the ClientHello, and verifies the response of the proxy.
+func handshakeFakeTLS(conn net.Conn, key []byte, clientHello []byte) error {
+	if _, err := conn.Write(clientHello); err != nil {
+		return err
+	}
+
+	var response []byte
+	for _, recordType := range []byte{0x16, 0x14, 0x17} {
+		var header [5]byte
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			return err
+		}
+		if header[0] != recordType {
+			return errors.New("unexpected record type: ", header[0])
+		}
+		payload := make([]byte, binary.BigEndian.Uint16(header[3:]))
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return err
+		}
+		response = append(append(response, header[:]...), payload...)
+	}
+
+	random := append([]byte(nil), response[11:43]...)
+	copy(response[11:43], make([]byte, 32))
+	mac := hmac.New(sha256.New, key)
+	mac.Write(clientHello[11:43]) // nolint: errcheck
+	mac.Write(response)           // nolint: errcheck
+	if !hmac.Equal(random, mac.Sum(nil)) {
+		return errors.New("invalid ServerHello digest")
+	}
+
+	_, err := conn.Write([]byte{0x14, 0x03, 0x03, 0x00, 0x01, 0x01})
+	return err
+}
+
+// dialMTProto connects to the MTProto proxy at the port as a Telegram client with the secret.
+func dialMTProto(port net.Port, secret []byte, connType byte) (net.Conn, error) {
+	conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(port),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := secret
+	var reader io.Reader = conn
+	var writer io.Writer = conn
+	if len(secret) > 16 {
+		key = secret[1:17]
+	}
+	if len(secret) > 17 {
+		if err := handshakeFakeTLS(conn, key, newFakeTLSClientHello(key)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		records := &fakeTLSRecords{conn: conn}
+		reader = records
+		writer = records
+	}
+
+	auth := mtproto.NewAuthentication(mtproto.SessionContext{
+		ConnectionType: [4]byte{connType, connType, connType, connType},
+	})
+	// Data center 1.
+	auth.Header[60], auth.Header[61] = 1, 0
+	auth.ApplySecret(key)
+
+	encryptor := crypto.NewAesCTRStream(auth.EncodingKey[:], auth.EncodingNonce[:])
+	var header [mtproto.HeaderSize]byte
+	encryptor.XORKeyStream(header[:], auth.Header[:])
+	copy(header[:56], auth.Header[:])
+	if _, err := writer.Write(header[:]); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	decryptor := crypto.NewAesCTRStream(auth.DecodingKey[:], auth.DecodingNonce[:])
+	return &mtprotoConn{
+		Conn:   conn,
+		reader: crypto.NewCryptionReader(decryptor, reader),
+		writer: crypto.NewCryptionWriter(encryptor, writer),
+	}, nil
+}
+
+func TestMTProtoSecrets(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	key, err := hex.DecodeString("b0cbcef5a486d9636472ac27f8e11a9d")
+	common.Must(err)
+
+	for _, tc := range []struct {
+		secret   []byte
+		connType byte
+		valid    bool
+	}{
+		{secret: key, connType: 0xef, valid: true},
+		{secret: key, connType: 0xdd, valid: true},
+		{secret: append([]byte{0xdd}, key...), connType: 0xdd, valid: true},
+		{secret: append([]byte{0xdd}, key...), connType: 0xef, valid: false},
+		{secret: append(append([]byte{0xee}, key...), "v2ray.com"...), connType: 0xdd, valid: true},
+	} {
+		serverPort := tcp.PickPort()
+		serverConfig := &core.Config{
+			Inbound: []*core.InboundHandlerConfig{
+				{
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(serverPort),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					}),
+					ProxySettings: serial.ToTypedMessage(&mtproto.ServerConfig{
+						User: []*protocol.User{
+							{
+								Account: serial.ToTypedMessage(&mtproto.Account{
+									Secret: tc.secret,
+								}),
+							},
+						},
+					}),
+				},
+			},
+			Outbound: []*core.OutboundHandlerConfig{
+				{
+					// Telegram data centers are replaced by the test server.
+					ProxySettings: serial.ToTypedMessage(&freedom.Config{
+						DestinationOverride: &freedom.DestinationOverride{
+							Server: &protocol.ServerEndpoint{
+								Address: net.NewIPOrDomain(net.LocalHostIP),
+								Port:    uint32(dest.Port),
+							},
+						},
+					}),
+				},
+			},
+		}
+
+		servers, err := InitializeServerConfigs(serverConfig)
+		common.Must(err)
+
+		var errg errgroup.Group
+		for i := 0; i < 5; i++ {
+			errg.Go(func() error {
+				conn, err := dialMTProto(serverPort, tc.secret, tc.connType)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				return testTCPConn2(conn, 10240, time.Second*5)()
+			})
+		}
+		err = errg.Wait()
+		if tc.valid && err != nil {
+			t.Error("secret ", hex.EncodeToString(tc.secret), ", connection type ", tc.connType, ": ", err)
+		}
+		if !tc.valid && err == nil {
+			t.Error("secret ", hex.EncodeToString(tc.secret), ", connection type ", tc.connType, ": nil error")
+		}
+
+		CloseAllServers(servers)
+	}
+}
+
+func TestMTProtoFakeTLSReplay(t *testing.T) {
+	key, err := hex.DecodeString("b0cbcef5a486d9636472ac27f8e11a9d")
+	common.Must(err)
+	secret := append(append([]byte{0xee}, key...), "v2ray.com"...)
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&mtproto.ServerConfig{
+					User: []*protocol.User{
+						{
+							Account: serial.ToTypedMessage(&mtproto.Account{
+								Secret: secret,
+							}),
+						},
+					},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	clientHello := newFakeTLSClientHello(key)
+	for i := 0; i < 2; i++ {
+		conn, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+			IP:   []byte{127, 0, 0, 1},
+			Port: int(serverPort),
+		})
+		common.Must(err)
+		common.Must(conn.SetDeadline(time.Now().Add(time.Second * 5)))
+		err = handshakeFakeTLS(conn, key, clientHello)
+		conn.Close()
+
+		if i == 0 && err != nil {
+			t.Error("failed to handshake: ", err)
+		}
+		if i == 1 && err == nil {
+			t.Error("replayed ClientHello accepted")
+		}
+	}
+}
@@ -2,6 +2,11 @@ package scenarios
 
 import (
 	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -826,3 +831,351 @@ func TestShadowsocksNone(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestShadowsocksMultipleUsers(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	tcpDest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	udpServer := udp.Server{
+		MsgProcessor: xor,
+	}
+	udpDest, err := udpServer.Start()
+	common.Must(err)
+	defer udpServer.Close()
+
+	account1 := serial.ToTypedMessage(&shadowsocks.Account{
+		Password:   "shadowsocks-password-1",
+		CipherType: shadowsocks.CipherType_AES_128_GCM,
+	})
+	account2 := serial.ToTypedMessage(&shadowsocks.Account{
+		Password:   "shadowsocks-password-2",
+		CipherType: shadowsocks.CipherType_CHACHA20_POLY1305,
+	})
+
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&log.Config{
+				ErrorLogLevel: clog.Severity_Debug,
+				ErrorLogType:  log.LogType_Console,
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&shadowsocks.ServerConfig{
+					Users: []*protocol.User{
+						{
+							Account: account1,
+							Email:   "user1@v2ray.com",
+						},
+						{
+							Account: account2,
+							Email:   "user2@v2ray.com",
+						},
+					},
+					Network: []net.Network{net.Network_TCP, net.Network_UDP},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	createClientConfig := func(clientPort net.Port, dest net.Destination, account *serial.TypedMessage) *core.Config {
+		return &core.Config{
+			Inbound: []*core.InboundHandlerConfig{
+				{
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(clientPort),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					}),
+					ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+						Address: net.NewIPOrDomain(dest.Address),
+						Port:    uint32(dest.Port),
+						NetworkList: &net.NetworkList{
+							Network: []net.Network{dest.Network},
+						},
+					}),
+				},
+			},
+			Outbound: []*core.OutboundHandlerConfig{
+				{
+					ProxySettings: serial.ToTypedMessage(&shadowsocks.ClientConfig{
+						Server: []*protocol.ServerEndpoint{
+							{
+								Address: net.NewIPOrDomain(net.LocalHostIP),
+								Port:    uint32(serverPort),
+								User: []*protocol.User{
+									{
+										Account: account,
+									},
+								},
+							},
+						},
+					}),
+				},
+			},
+		}
+	}
+
+	tcpPort1 := tcp.PickPort()
+	tcpPort2 := tcp.PickPort()
+	udpPort1 := udp.PickPort()
+	udpPort2 := udp.PickPort()
+	servers, err := InitializeServerConfigs(serverConfig,
+		createClientConfig(tcpPort1, tcpDest, account1),
+		createClientConfig(tcpPort2, tcpDest, account2),
+		createClientConfig(udpPort1, udpDest, account1),
+		createClientConfig(udpPort2, udpDest, account2))
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	var errg errgroup.Group
+	for i := 0; i < 5; i++ {
+		errg.Go(testTCPConn(tcpPort1, 10240, time.Second*20))
+		errg.Go(testTCPConn(tcpPort2, 10240, time.Second*20))
+		errg.Go(testUDPConn(udpPort1, 1024, time.Second*5))
+		errg.Go(testUDPConn(udpPort2, 1024, time.Second*5))
+	}
+	if err := errg.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShadowsocks2022(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	tcpDest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	udpServer := udp.Server{
+		MsgProcessor: xor,
+	}
+	udpDest, err := udpServer.Start()
+	common.Must(err)
+	defer udpServer.Close()
+
+	for _, cipherType := range []shadowsocks.CipherType{shadowsocks.CipherType_BLAKE3_AES_256_GCM, shadowsocks.CipherType_BLAKE3_CHACHA20_POLY1305} {
+		account := serial.ToTypedMessage(&shadowsocks.Account{
+			Password:   "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=",
+			CipherType: cipherType,
+		})
+
+		serverPort := tcp.PickPort()
+		serverConfig := &core.Config{
+			App: []*serial.TypedMessage{
+				serial.ToTypedMessage(&log.Config{
+					ErrorLogLevel: clog.Severity_Debug,
+					ErrorLogType:  log.LogType_Console,
+				}),
+			},
+			Inbound: []*core.InboundHandlerConfig{
+				{
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(serverPort),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					}),
+					ProxySettings: serial.ToTypedMessage(&shadowsocks.ServerConfig{
+						User: &protocol.User{
+							Account: account,
+							Level:   1,
+						},
+						Network: []net.Network{net.Network_TCP, net.Network_UDP},
+					}),
+				},
+			},
+			Outbound: []*core.OutboundHandlerConfig{
+				{
+					ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+				},
+			},
+		}
+
+		clientTCPPort := tcp.PickPort()
+		clientUDPPort := udp.PickPort()
+		clientConfig := &core.Config{
+			App: []*serial.TypedMessage{
+				serial.ToTypedMessage(&log.Config{
+					ErrorLogLevel: clog.Severity_Debug,
+					ErrorLogType:  log.LogType_Console,
+				}),
+			},
+			Inbound: []*core.InboundHandlerConfig{
+				{
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(clientTCPPort),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					}),
+					ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+						Address: net.NewIPOrDomain(tcpDest.Address),
+						Port:    uint32(tcpDest.Port),
+						NetworkList: &net.NetworkList{
+							Network: []net.Network{net.Network_TCP},
+						},
+					}),
+				},
+				{
+					ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+						PortRange: net.SinglePortRange(clientUDPPort),
+						Listen:    net.NewIPOrDomain(net.LocalHostIP),
+					}),
+					ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+						Address: net.NewIPOrDomain(udpDest.Address),
+						Port:    uint32(udpDest.Port),
+						NetworkList: &net.NetworkList{
+							Network: []net.Network{net.Network_UDP},
+						},
+					}),
+				},
+			},
+			Outbound: []*core.OutboundHandlerConfig{
+				{
+					ProxySettings: serial.ToTypedMessage(&shadowsocks.ClientConfig{
+						Server: []*protocol.ServerEndpoint{
+							{
+								Address: net.NewIPOrDomain(net.LocalHostIP),
+								Port:    uint32(serverPort),
+								User: []*protocol.User{
+									{
+										Account: account,
+									},
+								},
+							},
+						},
+					}),
+				},
+			},
+		}
+
+		servers, err := InitializeServerConfigs(serverConfig, clientConfig)
+		common.Must(err)
+
+		var errg errgroup.Group
+		for i := 0; i < 5; i++ {
+			errg.Go(testTCPConn(clientTCPPort, 1024*1024, time.Second*20))
+			errg.Go(testUDPConn(clientUDPPort, 1024, time.Second*5))
+		}
+		if err := errg.Wait(); err != nil {
+			t.Error(cipherType, ": ", err)
+		}
+
+		CloseAllServers(servers)
+	}
+}
+
+func TestShadowsocksPlugin(t *testing.T) {
+	pluginDir, err := ioutil.TempDir("", "v2ray")
+	common.Must(err)
+	defer os.RemoveAll(pluginDir)
+	pluginPath := filepath.Join(pluginDir, "sip003")
+	if runtime.GOOS == "windows" {
+		pluginPath += ".exe"
+	}
+	common.Must(exec.Command("go", "build", "-o", pluginPath, "v2ray.com/core/testing/servers/sip003").Run())
+
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	account := serial.ToTypedMessage(&shadowsocks.Account{
+		Password:   "shadowsocks-password",
+		CipherType: shadowsocks.CipherType_AES_128_GCM,
+	})
+
+	serverPort := tcp.PickPort()
+	pluginPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&shadowsocks.ServerConfig{
+					User: &protocol.User{
+						Account: account,
+					},
+					Network: []net.Network{net.Network_TCP},
+					Plugin: &shadowsocks.PluginConfig{
+						Command: pluginPath,
+						Options: "server",
+					},
+					PluginAddress: net.NewIPOrDomain(net.LocalHostIP),
+					PluginPort:    uint32(pluginPort),
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	clientPort := tcp.PickPort()
+	clientConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(clientPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address: net.NewIPOrDomain(dest.Address),
+					Port:    uint32(dest.Port),
+					NetworkList: &net.NetworkList{
+						Network: []net.Network{net.Network_TCP},
+					},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&shadowsocks.ClientConfig{
+					Server: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(pluginPort),
+							User: []*protocol.User{
+								{
+									Account: account,
+								},
+							},
+						},
+					},
+					Plugin: &shadowsocks.PluginConfig{
+						Command: pluginPath,
+					},
+				}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig, clientConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	var errg errgroup.Group
+	for i := 0; i < 10; i++ {
+		errg.Go(testTCPConn(clientPort, 1024*1024, time.Second*20))
+	}
+	if err := errg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package scenarios
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/sync/errgroup"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/proxy/dokodemo"
+	"v2ray.com/core/proxy/ssh"
+	sshtest "v2ray.com/core/testing/servers/ssh"
+	"v2ray.com/core/testing/servers/tcp"
+)
+
+func TestSSHOutbound(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	sshServer := &sshtest.Server{
+		Username: "v2ray",
+		Password: "password",
+	}
+	sshDest, err := sshServer.Start()
+	common.Must(err)
+	defer sshServer.Close()
+
+	tempDir, err := ioutil.TempDir("", "v2ray-ssh")
+	common.Must(err)
+	defer os.RemoveAll(tempDir)
+	knownHostsFile := filepath.Join(tempDir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(sshDest.NetAddr())}, sshServer.HostKey())
+	common.Must(ioutil.WriteFile(knownHostsFile, []byte(line+"\n"), 0600))
+
+	clientPort := tcp.PickPort()
+	clientConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(clientPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address: net.NewIPOrDomain(dest.Address),
+					Port:    uint32(dest.Port),
+					NetworkList: &net.NetworkList{
+						Network: []net.Network{net.Network_TCP},
+					},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&ssh.ClientConfig{
+					Server: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(sshDest.Address),
+							Port:    uint32(sshDest.Port),
+							User: []*protocol.User{
+								{
+									Account: serial.ToTypedMessage(&ssh.Account{
+										Username: "v2ray",
+										Password: "password",
+									}),
+								},
+							},
+						},
+					},
+					KnownHostsFile: knownHostsFile,
+					MaxConnections: 2,
+				}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(clientConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	var errg errgroup.Group
+	for i := 0; i < 10; i++ {
+		errg.Go(testTCPConn(clientPort, 1024*1024, time.Second*20))
+	}
+	if err := errg.Wait(); err != nil {
+		t.Error(err)
+	}
+
+	if n := sshServer.Accepted(); n < 1 || n > 2 {
+		t.Error("unexpected number of SSH connections: ", n)
+	}
+}
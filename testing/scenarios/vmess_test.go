@@ -1,6 +1,7 @@
 package scenarios
 
 import (
+	"io"
 	"os"
 	"testing"
 	"time"
@@ -9,14 +10,18 @@ import (
 	"v2ray.com/core"
 	"v2ray.com/core/app/log"
 	"v2ray.com/core/app/proxyman"
+	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
 	clog "v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/common/uuid"
+	"v2ray.com/core/proxy/blackhole"
 	"v2ray.com/core/proxy/dokodemo"
 	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/proxy/socks"
 	"v2ray.com/core/proxy/vmess"
 	"v2ray.com/core/proxy/vmess/inbound"
 	"v2ray.com/core/proxy/vmess/outbound"
@@ -251,6 +256,114 @@ func TestVMessGCM(t *testing.T) {
 	}
 }
 
+func TestVMessAEADHeader(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: xor,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	userID := protocol.NewID(uuid.New())
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&log.Config{
+				ErrorLogLevel: clog.Severity_Debug,
+				ErrorLogType:  log.LogType_Console,
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&inbound.Config{
+					User: []*protocol.User{
+						{
+							Account: serial.ToTypedMessage(&vmess.Account{
+								Id:      userID.String(),
+								AlterId: 64,
+							}),
+						},
+					},
+					DisableLegacyHeader: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	clientPort := tcp.PickPort()
+	clientConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&log.Config{
+				ErrorLogLevel: clog.Severity_Debug,
+				ErrorLogType:  log.LogType_Console,
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(clientPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address: net.NewIPOrDomain(dest.Address),
+					Port:    uint32(dest.Port),
+					NetworkList: &net.NetworkList{
+						Network: []net.Network{net.Network_TCP},
+					},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&outbound.Config{
+					Receiver: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(serverPort),
+							User: []*protocol.User{
+								{
+									Account: serial.ToTypedMessage(&vmess.Account{
+										Id:      userID.String(),
+										AlterId: 64,
+										SecuritySettings: &protocol.SecurityConfig{
+											Type: protocol.SecurityType_AES128_GCM,
+										},
+										AeadHeader: true,
+									}),
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig, clientConfig)
+	if err != nil {
+		t.Fatal("Failed to initialize all servers: ", err.Error())
+	}
+	defer CloseAllServers(servers)
+
+	var errg errgroup.Group
+	for i := 0; i < 10; i++ {
+		errg.Go(testTCPConn(clientPort, 1024*1024, time.Second*20))
+	}
+
+	if err := errg.Wait(); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestVMessGCMReadv(t *testing.T) {
 	tcpServer := tcp.Server{
 		MsgProcessor: xor,
@@ -1175,3 +1288,190 @@ func TestVMessGCMMuxUDP(t *testing.T) {
 		time.Sleep(time.Second)
 	}
 }
+
+func TestVMessGCMMuxFullConeUDP(t *testing.T) {
+	var remotes []*net.UDPConn
+	for i := 0; i < 4; i++ {
+		remote, err := net.ListenUDP("udp", &net.UDPAddr{IP: []byte{127, 0, 0, 1}})
+		common.Must(err)
+		defer remote.Close()
+		remotes = append(remotes, remote)
+	}
+	blocked := remotes[3].LocalAddr().(*net.UDPAddr)
+
+	userID := protocol.NewID(uuid.New())
+	serverPort := tcp.PickPort()
+	serverConfig := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						PortRange: net.SinglePortRange(net.Port(blocked.Port)),
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "blocked",
+						},
+					},
+				},
+			}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(serverPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&inbound.Config{
+					User: []*protocol.User{
+						{
+							Account: serial.ToTypedMessage(&vmess.Account{
+								Id:      userID.String(),
+								AlterId: 64,
+							}),
+						},
+					},
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+			{
+				Tag:           "blocked",
+				ProxySettings: serial.ToTypedMessage(&blackhole.Config{}),
+			},
+		},
+	}
+
+	clientPort := tcp.PickPort()
+	clientConfig := &core.Config{
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortRange: net.SinglePortRange(clientPort),
+					Listen:    net.NewIPOrDomain(net.LocalHostIP),
+				}),
+				ProxySettings: serial.ToTypedMessage(&socks.ServerConfig{
+					AuthType:   socks.AuthType_NO_AUTH,
+					Address:    net.NewIPOrDomain(net.LocalHostIP),
+					UdpEnabled: true,
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+					MultiplexSettings: &proxyman.MultiplexingConfig{
+						Enabled:     true,
+						Concurrency: 4,
+					},
+				}),
+				ProxySettings: serial.ToTypedMessage(&outbound.Config{
+					Receiver: []*protocol.ServerEndpoint{
+						{
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(serverPort),
+							User: []*protocol.User{
+								{
+									Account: serial.ToTypedMessage(&vmess.Account{
+										Id:      userID.String(),
+										AlterId: 64,
+										SecuritySettings: &protocol.SecurityConfig{
+											Type: protocol.SecurityType_AES128_GCM,
+										},
+									}),
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+	}
+
+	servers, err := InitializeServerConfigs(serverConfig, clientConfig)
+	common.Must(err)
+	defer CloseAllServers(servers)
+
+	// UDP ASSOCIATE through the SOCKS inbound.
+	control, err := net.DialTCP("tcp", nil, &net.TCPAddr{
+		IP:   []byte{127, 0, 0, 1},
+		Port: int(clientPort),
+	})
+	common.Must(err)
+	defer control.Close()
+
+	common.Must2(control.Write([]byte{0x05, 0x01, 0x00}))
+	reply := make([]byte, 10)
+	common.Must2(io.ReadFull(control, reply[:2]))
+	common.Must2(control.Write([]byte{0x05, 0x03, 0x00, 0x01, 0, 0, 0, 0, 0, 0}))
+	common.Must2(io.ReadFull(control, reply))
+	if reply[1] != 0x00 {
+		t.Fatal("unexpected UDP ASSOCIATE reply: ", reply)
+	}
+	relay := &net.UDPAddr{
+		IP:   net.IP(reply[4:8]),
+		Port: int(reply[8])<<8 | int(reply[9]),
+	}
+
+	app, err := net.ListenUDP("udp", &net.UDPAddr{IP: []byte{127, 0, 0, 1}})
+	common.Must(err)
+	defer app.Close()
+
+	send := func(to *net.UDPAddr, payload string) {
+		b, err := socks.EncodeUDPPacket(&protocol.RequestHeader{
+			Address: net.IPAddress(to.IP),
+			Port:    net.Port(to.Port),
+		}, []byte(payload))
+		common.Must(err)
+		defer b.Release()
+		common.Must2(app.WriteTo(b.Bytes(), relay))
+	}
+	receive := func() (net.Destination, string) {
+		common.Must(app.SetReadDeadline(time.Now().Add(time.Second * 5)))
+		b := buf.New()
+		defer b.Release()
+		common.Must2(b.ReadFrom(app))
+		request, err := socks.DecodeUDPPacket(b)
+		common.Must(err)
+		return request.Destination(), b.String()
+	}
+
+	remoteRead := func(remote *net.UDPConn, payload string) *net.UDPAddr {
+		common.Must(remote.SetReadDeadline(time.Now().Add(time.Second * 5)))
+		b := make([]byte, 1024)
+		n, addr, err := remote.ReadFromUDP(b)
+		common.Must(err)
+		if string(b[:n]) != payload {
+			t.Fatal("unexpected payload: ", string(b[:n]))
+		}
+		return addr
+	}
+
+	remote0 := remotes[0].LocalAddr().(*net.UDPAddr)
+	send(remote0, "ping 0")
+	mapped := remoteRead(remotes[0], "ping 0")
+	common.Must2(remotes[0].WriteToUDP([]byte("pong 0"), mapped))
+	if source, payload := receive(); payload != "pong 0" || source != net.DestinationFromAddr(remote0) {
+		t.Fatal("unexpected response ", payload, " from ", source)
+	}
+
+	// The same source reaches another remote, which is routed on its own.
+	remote1 := remotes[1].LocalAddr().(*net.UDPAddr)
+	send(remote1, "ping 1")
+	remoteRead(remotes[1], "ping 1")
+
+	// Packets to remotes that are routed elsewhere don't leave through freedom.
+	send(blocked, "ping 3")
+	common.Must(remotes[3].SetReadDeadline(time.Now().Add(time.Second)))
+	if _, _, err := remotes[3].ReadFromUDP(make([]byte, 1024)); err == nil {
+		t.Fatal("packet to blocked remote is sent")
+	}
+
+	// Any remote can send to the mapped address.
+	remote2 := remotes[2].LocalAddr().(*net.UDPAddr)
+	common.Must2(remotes[2].WriteToUDP([]byte("hello"), mapped))
+	if source, payload := receive(); payload != "hello" || source != net.DestinationFromAddr(remote2) {
+		t.Fatal("unexpected response ", payload, " from ", source)
+	}
+}
@@ -0,0 +1,65 @@
+// Command sip003 is a trivial SIP003 plugin for tests. It forwards TCP connections between SS_LOCAL_HOST:SS_LOCAL_PORT
+// and SS_REMOTE_HOST:SS_REMOTE_PORT, and obfuscates the traffic in between by flipping all bits, so that
+// both sides of a connection must run the plugin.
+//
+// By default it runs on the client side, listening on the local address. It runs on the server side, listening on
+// the remote address, if SS_PLUGIN_OPTIONS is "server".
+package main
+
+import (
+	"io"
+	"log"
+	"net"
+	"os"
+)
+
+type flipWriter struct {
+	io.Writer
+}
+
+func (w flipWriter) Write(b []byte) (int, error) {
+	flipped := make([]byte, len(b))
+	for i := range b {
+		flipped[i] = ^b[i]
+	}
+	return w.Writer.Write(flipped)
+}
+
+func main() {
+	local := net.JoinHostPort(os.Getenv("SS_LOCAL_HOST"), os.Getenv("SS_LOCAL_PORT"))
+	remote := net.JoinHostPort(os.Getenv("SS_REMOTE_HOST"), os.Getenv("SS_REMOTE_PORT"))
+
+	listen, target := local, remote
+	if os.Getenv("SS_PLUGIN_OPTIONS") == "server" {
+		listen, target = remote, local
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Fatal(err)
+		}
+		go forward(conn, target)
+	}
+}
+
+func forward(conn net.Conn, target string) {
+	defer conn.Close()
+
+	targetConn, err := net.Dial("tcp", target)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	defer targetConn.Close()
+
+	go func() {
+		io.Copy(flipWriter{targetConn}, conn)  // nolint: errcheck
+		targetConn.(*net.TCPConn).CloseWrite() // nolint: errcheck
+	}()
+	io.Copy(flipWriter{conn}, targetConn) // nolint: errcheck
+}
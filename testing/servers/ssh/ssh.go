@@ -0,0 +1,165 @@
+package ssh
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"v2ray.com/core/common/net"
+)
+
+// Server is an SSH server that accepts direct-tcpip channels, and connects them to the requested targets.
+type Server struct {
+	Username string
+	Password string
+	// AuthorizedKey is the public key of the user, if not nil.
+	AuthorizedKey ssh.PublicKey
+	// ChannelDelay delays the response to each channel request.
+	ChannelDelay time.Duration
+
+	hostKey  ssh.Signer
+	listener net.Listener
+
+	access   sync.Mutex
+	conns    []*ssh.ServerConn
+	accepted int
+}
+
+// Start starts the server on a random port of localhost.
+func (server *Server) Start() (net.Destination, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return net.Destination{}, err
+	}
+	hostKey, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return net.Destination{}, err
+	}
+	server.hostKey = hostKey
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == server.Username && len(server.Password) > 0 && string(password) == server.Password {
+				return nil, nil
+			}
+			return nil, errors.New("wrong password")
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == server.Username && server.AuthorizedKey != nil && bytes.Equal(key.Marshal(), server.AuthorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown public key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return net.Destination{}, err
+	}
+	server.listener = listener
+	go server.acceptConnections(config)
+
+	return net.DestinationFromAddr(listener.Addr()), nil
+}
+
+// HostKey returns the public host key of the server.
+func (server *Server) HostKey() ssh.PublicKey {
+	return server.hostKey.PublicKey()
+}
+
+// Accepted returns the number of authenticated connections so far.
+func (server *Server) Accepted() int {
+	server.access.Lock()
+	defer server.access.Unlock()
+	return server.accepted
+}
+
+func (server *Server) acceptConnections(config *ssh.ServerConfig) {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			serverConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+			if err != nil {
+				conn.Close()
+				return
+			}
+			server.access.Lock()
+			server.conns = append(server.conns, serverConn)
+			server.accepted++
+			server.access.Unlock()
+
+			go ssh.DiscardRequests(reqs)
+			for newChannel := range chans {
+				go server.handleChannel(newChannel)
+			}
+		}()
+	}
+}
+
+func (server *Server) handleChannel(newChannel ssh.NewChannel) {
+	time.Sleep(server.ChannelDelay)
+	if newChannel.ChannelType() != "direct-tcpip" {
+		newChannel.Reject(ssh.UnknownChannelType, "unknown channel type") // nolint: errcheck
+		return
+	}
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error()) // nolint: errcheck
+		return
+	}
+	target, err := net.Dial("tcp", net.TCPDestination(net.ParseAddress(payload.Host), net.Port(payload.Port)).NetAddr())
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error()) // nolint: errcheck
+		return
+	}
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	go func() {
+		io.Copy(target, channel) // nolint: errcheck
+		if tcpConn, ok := target.(*net.TCPConn); ok {
+			tcpConn.CloseWrite() // nolint: errcheck
+		}
+	}()
+	io.Copy(channel, target) // nolint: errcheck
+	channel.Close()
+	target.Close()
+}
+
+// CloseConnections closes all accepted connections, as if the server restarts.
+func (server *Server) CloseConnections() {
+	server.access.Lock()
+	defer server.access.Unlock()
+
+	for _, conn := range server.conns {
+		conn.Close()
+	}
+	server.conns = nil
+}
+
+// Close stops the server, and closes all connections.
+func (server *Server) Close() error {
+	server.CloseConnections()
+	return server.listener.Close()
+}
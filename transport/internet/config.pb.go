@@ -309,12 +309,18 @@ type SocketConfig struct {
 	Tproxy SocketConfig_TProxyMode `protobuf:"varint,3,opt,name=tproxy,proto3,enum=v2ray.core.transport.internet.SocketConfig_TProxyMode" json:"tproxy,omitempty"`
 	// ReceiveOriginalDestAddress is for enabling IP_RECVORIGDSTADDR socket option.
 	// This option is for UDP only.
-	ReceiveOriginalDestAddress bool     `protobuf:"varint,4,opt,name=receive_original_dest_address,json=receiveOriginalDestAddress,proto3" json:"receive_original_dest_address,omitempty"`
-	BindAddress                []byte   `protobuf:"bytes,5,opt,name=bind_address,json=bindAddress,proto3" json:"bind_address,omitempty"`
-	BindPort                   uint32   `protobuf:"varint,6,opt,name=bind_port,json=bindPort,proto3" json:"bind_port,omitempty"`
-	XXX_NoUnkeyedLiteral       struct{} `json:"-"`
-	XXX_unrecognized           []byte   `json:"-"`
-	XXX_sizecache              int32    `json:"-"`
+	ReceiveOriginalDestAddress bool   `protobuf:"varint,4,opt,name=receive_original_dest_address,json=receiveOriginalDestAddress,proto3" json:"receive_original_dest_address,omitempty"`
+	BindAddress                []byte `protobuf:"bytes,5,opt,name=bind_address,json=bindAddress,proto3" json:"bind_address,omitempty"`
+	BindPort                   uint32 `protobuf:"varint,6,opt,name=bind_port,json=bindPort,proto3" json:"bind_port,omitempty"`
+	// Freebind is for enabling IP_FREEBIND socket option, which allows binding
+	// to an address that is not yet assigned to the host.
+	Freebind bool `protobuf:"varint,7,opt,name=freebind,proto3" json:"freebind,omitempty"`
+	// HappyEyeballs enables racing connection attempts to all addresses of a
+	// domain destination, as in RFC 8305. Only for outgoing TCP connections.
+	HappyEyeballs        *HappyEyeballsConfig `protobuf:"bytes,8,opt,name=happy_eyeballs,json=happyEyeballs,proto3" json:"happy_eyeballs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *SocketConfig) Reset()         { *m = SocketConfig{} }
@@ -384,6 +390,71 @@ func (m *SocketConfig) GetBindPort() uint32 {
 	return 0
 }
 
+func (m *SocketConfig) GetFreebind() bool {
+	if m != nil {
+		return m.Freebind
+	}
+	return false
+}
+
+func (m *SocketConfig) GetHappyEyeballs() *HappyEyeballsConfig {
+	if m != nil {
+		return m.HappyEyeballs
+	}
+	return nil
+}
+
+type HappyEyeballsConfig struct {
+	// Whether to try IPv4 addresses first. IPv6 addresses are tried first by
+	// default.
+	PreferIpv4 bool `protobuf:"varint,1,opt,name=prefer_ipv4,json=preferIpv4,proto3" json:"prefer_ipv4,omitempty"`
+	// Delay in milliseconds before the next attempt is started. 0 for the
+	// default of 250 milliseconds.
+	TryDelayMs           uint32   `protobuf:"varint,2,opt,name=try_delay_ms,json=tryDelayMs,proto3" json:"try_delay_ms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HappyEyeballsConfig) Reset()         { *m = HappyEyeballsConfig{} }
+func (m *HappyEyeballsConfig) String() string { return proto.CompactTextString(m) }
+func (*HappyEyeballsConfig) ProtoMessage()    {}
+func (*HappyEyeballsConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91dbc815c3d97a05, []int{4}
+}
+
+func (m *HappyEyeballsConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HappyEyeballsConfig.Unmarshal(m, b)
+}
+func (m *HappyEyeballsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HappyEyeballsConfig.Marshal(b, m, deterministic)
+}
+func (m *HappyEyeballsConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HappyEyeballsConfig.Merge(m, src)
+}
+func (m *HappyEyeballsConfig) XXX_Size() int {
+	return xxx_messageInfo_HappyEyeballsConfig.Size(m)
+}
+func (m *HappyEyeballsConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_HappyEyeballsConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HappyEyeballsConfig proto.InternalMessageInfo
+
+func (m *HappyEyeballsConfig) GetPreferIpv4() bool {
+	if m != nil {
+		return m.PreferIpv4
+	}
+	return false
+}
+
+func (m *HappyEyeballsConfig) GetTryDelayMs() uint32 {
+	if m != nil {
+		return m.TryDelayMs
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.transport.internet.TransportProtocol", TransportProtocol_name, TransportProtocol_value)
 	proto.RegisterEnum("v2ray.core.transport.internet.SocketConfig_TCPFastOpenState", SocketConfig_TCPFastOpenState_name, SocketConfig_TCPFastOpenState_value)
@@ -392,6 +463,7 @@ func init() {
 	proto.RegisterType((*StreamConfig)(nil), "v2ray.core.transport.internet.StreamConfig")
 	proto.RegisterType((*ProxyConfig)(nil), "v2ray.core.transport.internet.ProxyConfig")
 	proto.RegisterType((*SocketConfig)(nil), "v2ray.core.transport.internet.SocketConfig")
+	proto.RegisterType((*HappyEyeballsConfig)(nil), "v2ray.core.transport.internet.HappyEyeballsConfig")
 }
 
 func init() {
@@ -399,45 +471,51 @@ func init() {
 }
 
 var fileDescriptor_91dbc815c3d97a05 = []byte{
-	// 636 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0xdd, 0x6a, 0xdb, 0x4c,
-	0x10, 0x8d, 0x2c, 0xc7, 0x91, 0xc7, 0x8e, 0xa3, 0xec, 0x95, 0xc9, 0x47, 0xf8, 0x12, 0x17, 0x8a,
-	0x69, 0x41, 0x0a, 0x2a, 0xed, 0x55, 0x6f, 0x12, 0xbb, 0xa5, 0xa1, 0x4d, 0x2c, 0x64, 0xb5, 0x85,
-	0x40, 0x11, 0x6b, 0x69, 0x6c, 0x44, 0x2c, 0xad, 0xd9, 0xdd, 0x86, 0xfa, 0x95, 0x0a, 0xbd, 0xeb,
-	0x43, 0xf4, 0xb1, 0xca, 0xae, 0x7e, 0x6a, 0xd2, 0x92, 0x26, 0xf4, 0x6e, 0x34, 0x73, 0xe6, 0xcc,
-	0x9c, 0x39, 0x5a, 0x70, 0x6e, 0x3c, 0x4e, 0xd7, 0x4e, 0xcc, 0x32, 0x37, 0x66, 0x1c, 0x5d, 0xc9,
-	0x69, 0x2e, 0x56, 0x8c, 0x4b, 0x37, 0xcd, 0x25, 0xf2, 0x1c, 0xa5, 0x1b, 0xb3, 0x7c, 0x9e, 0x2e,
-	0x9c, 0x15, 0x67, 0x92, 0x91, 0xc3, 0x0a, 0xcf, 0xd1, 0xa9, 0xb1, 0x4e, 0x85, 0x3d, 0x38, 0xb9,
-	0x45, 0x17, 0xb3, 0x2c, 0x63, 0xb9, 0x2b, 0x90, 0xa7, 0x74, 0xe9, 0xca, 0xf5, 0x0a, 0x93, 0x28,
-	0x43, 0x21, 0xe8, 0x02, 0x0b, 0xc2, 0xc1, 0x0f, 0x03, 0xf6, 0xc2, 0x8a, 0x68, 0xa4, 0x47, 0x91,
-	0x77, 0x60, 0xe9, 0x62, 0xcc, 0x96, 0x7d, 0xe3, 0xc8, 0x18, 0xf6, 0xbc, 0x13, 0xe7, 0xce, 0xb9,
-	0x4e, 0xcd, 0xe0, 0x97, 0x7d, 0x41, 0xcd, 0x40, 0x1e, 0xc1, 0x6e, 0x15, 0x47, 0x39, 0xcd, 0xb0,
-	0x6f, 0x1e, 0x19, 0xc3, 0x76, 0xd0, 0xad, 0x92, 0x97, 0x34, 0x43, 0x72, 0x06, 0x96, 0x40, 0x29,
-	0xd3, 0x7c, 0x21, 0xfa, 0x8d, 0x23, 0x63, 0xd8, 0xf1, 0x1e, 0x6f, 0x8e, 0x2c, 0x74, 0x38, 0x85,
-	0x0e, 0x27, 0x54, 0x3a, 0x2e, 0x0a, 0x19, 0x41, 0xdd, 0x37, 0xf8, 0x6e, 0x42, 0x77, 0x2a, 0x39,
-	0xd2, 0xac, 0xd4, 0xe1, 0xff, 0xbb, 0x8e, 0xb3, 0x46, 0xdf, 0xb8, 0x4b, 0xcb, 0xf6, 0x1f, 0xb4,
-	0x7c, 0x02, 0x52, 0x53, 0x47, 0x1b, 0xaa, 0xcc, 0x61, 0xc7, 0x73, 0xee, 0xbb, 0x40, 0x21, 0x21,
-	0xd8, 0xaf, 0x31, 0xd3, 0x92, 0x48, 0xed, 0x20, 0x30, 0xfe, 0xcc, 0x53, 0xb9, 0x8e, 0x94, 0xa3,
-	0xd5, 0x3d, 0xab, 0xa4, 0xba, 0x0e, 0x99, 0xc2, 0x7e, 0x0d, 0xaa, 0x57, 0x68, 0xea, 0x15, 0xee,
-	0x7b, 0x58, 0xbb, 0x22, 0xa8, 0x27, 0x87, 0xb0, 0x27, 0x58, 0x7c, 0x8d, 0x1b, 0xaa, 0x5a, 0xda,
-	0xab, 0xa7, 0x7f, 0x51, 0x35, 0xd5, 0x5d, 0xa5, 0xa4, 0x5e, 0xc1, 0x51, 0xb1, 0x0e, 0xfe, 0x87,
-	0x8e, 0xcf, 0xd9, 0x97, 0x75, 0x69, 0x9a, 0x0d, 0xa6, 0xa4, 0x0b, 0xed, 0x57, 0x3b, 0x50, 0xe1,
-	0xe0, 0x9b, 0xf2, 0x75, 0x83, 0x81, 0x10, 0x68, 0x66, 0x94, 0x5f, 0x6b, 0xcc, 0x76, 0xa0, 0x63,
-	0x72, 0x09, 0xa6, 0x9c, 0x33, 0xfd, 0xef, 0xf4, 0xbc, 0x97, 0x0f, 0xd8, 0xc7, 0x09, 0x47, 0xfe,
-	0x6b, 0x2a, 0xe4, 0x64, 0x85, 0xf9, 0x54, 0x52, 0x89, 0x81, 0x22, 0x22, 0x97, 0xd0, 0x92, 0x2b,
-	0xb5, 0x96, 0x3e, 0x6f, 0xcf, 0x7b, 0xf1, 0x20, 0x4a, 0x2d, 0xe8, 0x82, 0x25, 0x18, 0x94, 0x2c,
-	0xe4, 0x14, 0x0e, 0x39, 0xc6, 0x98, 0xde, 0x60, 0xc4, 0x78, 0xba, 0x48, 0x73, 0xba, 0x8c, 0x12,
-	0x14, 0x32, 0xa2, 0x49, 0xc2, 0x51, 0x28, 0x73, 0x8c, 0xa1, 0x15, 0x1c, 0x94, 0xa0, 0x49, 0x89,
-	0x19, 0xa3, 0x90, 0xa7, 0x05, 0x82, 0x1c, 0x43, 0x77, 0x96, 0xe6, 0x49, 0xdd, 0xa1, 0xfe, 0xbd,
-	0x6e, 0xd0, 0x51, 0xb9, 0x0a, 0xf2, 0x1f, 0xb4, 0x35, 0x44, 0xed, 0xa6, 0xbd, 0xd9, 0x0d, 0x2c,
-	0x95, 0xf0, 0x19, 0x97, 0x83, 0xe7, 0x60, 0xdf, 0xd6, 0x4a, 0x2c, 0x68, 0x9e, 0x8a, 0x73, 0x61,
-	0x6f, 0x11, 0x80, 0xd6, 0xab, 0x9c, 0xce, 0x96, 0x68, 0x1b, 0xa4, 0x03, 0x3b, 0xe3, 0x54, 0xe8,
-	0x8f, 0xc6, 0xc0, 0x05, 0xf8, 0xa5, 0x87, 0xec, 0x80, 0x39, 0x99, 0xcf, 0x0b, 0x7c, 0x91, 0xb6,
-	0x0d, 0xd2, 0x05, 0x2b, 0xc0, 0x24, 0xe5, 0x18, 0x4b, 0xbb, 0xf1, 0xe4, 0x0a, 0xf6, 0x7f, 0x7b,
-	0x47, 0xaa, 0x2f, 0x1c, 0xf9, 0xf6, 0x96, 0x0a, 0xde, 0x8f, 0x7d, 0xdb, 0x50, 0xa3, 0x2f, 0xde,
-	0x8e, 0x7c, 0xbb, 0x41, 0x76, 0xa1, 0xfd, 0x11, 0x67, 0xc5, 0x05, 0x6d, 0x53, 0x15, 0xde, 0x84,
-	0xa1, 0x6f, 0x37, 0x89, 0x0d, 0xdd, 0x31, 0xcb, 0x68, 0x9a, 0x97, 0xb5, 0xed, 0xb3, 0x09, 0x1c,
-	0xc7, 0x2c, 0xbb, 0xdb, 0x0b, 0xdf, 0xb8, 0xb2, 0xaa, 0xf8, 0x6b, 0xe3, 0xf0, 0x83, 0x17, 0xd0,
-	0xb5, 0x33, 0x52, 0xd8, 0x7a, 0x2d, 0xe7, 0xbc, 0xac, 0xcf, 0x5a, 0xfa, 0xe9, 0x3e, 0xfb, 0x19,
-	0x00, 0x00, 0xff, 0xff, 0xce, 0xe9, 0xc8, 0x20, 0x89, 0x05, 0x00, 0x00,
+	// 732 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0x5d, 0x6f, 0xe3, 0x44,
+	0x14, 0x5d, 0xc7, 0x69, 0xea, 0xdc, 0x38, 0x59, 0x77, 0x78, 0x89, 0x8a, 0xaa, 0xcd, 0x06, 0x09,
+	0x45, 0x20, 0x39, 0x2b, 0xb3, 0xf0, 0xc4, 0x4b, 0x9b, 0x2c, 0xda, 0x0a, 0xd2, 0x58, 0x4e, 0xf8,
+	0x5a, 0x09, 0x59, 0x13, 0xfb, 0x26, 0xb5, 0x1a, 0x7b, 0xac, 0x99, 0x21, 0xc2, 0xff, 0x87, 0x27,
+	0x9e, 0xf9, 0x11, 0xfc, 0x2c, 0x34, 0xe3, 0x0f, 0xa2, 0x52, 0xb5, 0x54, 0xbc, 0xdd, 0xb9, 0x73,
+	0xee, 0xb9, 0xe7, 0xdc, 0x3b, 0x1a, 0x70, 0x0f, 0x1e, 0xa7, 0x85, 0x1b, 0xb1, 0x74, 0x1a, 0x31,
+	0x8e, 0x53, 0xc9, 0x69, 0x26, 0x72, 0xc6, 0xe5, 0x34, 0xc9, 0x24, 0xf2, 0x0c, 0xe5, 0x34, 0x62,
+	0xd9, 0x36, 0xd9, 0xb9, 0x39, 0x67, 0x92, 0x91, 0x8b, 0x1a, 0xcf, 0xd1, 0x6d, 0xb0, 0x6e, 0x8d,
+	0x3d, 0x7f, 0x73, 0x8f, 0x2e, 0x62, 0x69, 0xca, 0xb2, 0xa9, 0x40, 0x9e, 0xd0, 0xfd, 0x54, 0x16,
+	0x39, 0xc6, 0x61, 0x8a, 0x42, 0xd0, 0x1d, 0x96, 0x84, 0xe3, 0xbf, 0x0c, 0x78, 0xb9, 0xae, 0x89,
+	0x66, 0xba, 0x15, 0xf9, 0x0e, 0x2c, 0x7d, 0x19, 0xb1, 0xfd, 0xd0, 0x18, 0x19, 0x93, 0x81, 0xf7,
+	0xc6, 0x7d, 0xb4, 0xaf, 0xdb, 0x30, 0xf8, 0x55, 0x5d, 0xd0, 0x30, 0x90, 0x4f, 0xa0, 0x5f, 0xc7,
+	0x61, 0x46, 0x53, 0x1c, 0x9a, 0x23, 0x63, 0xd2, 0x0d, 0xec, 0x3a, 0x79, 0x43, 0x53, 0x24, 0x57,
+	0x60, 0x09, 0x94, 0x32, 0xc9, 0x76, 0x62, 0xd8, 0x1a, 0x19, 0x93, 0x9e, 0xf7, 0xe9, 0x71, 0xcb,
+	0xd2, 0x87, 0x5b, 0xfa, 0x70, 0xd7, 0xca, 0xc7, 0xa2, 0xb4, 0x11, 0x34, 0x75, 0xe3, 0x3f, 0x4d,
+	0xb0, 0x57, 0x92, 0x23, 0x4d, 0x2b, 0x1f, 0xfe, 0xff, 0xf7, 0x71, 0xd5, 0x1a, 0x1a, 0x8f, 0x79,
+	0x39, 0x79, 0xc0, 0xcb, 0x2f, 0x40, 0x1a, 0xea, 0xf0, 0xc8, 0x95, 0x39, 0xe9, 0x79, 0xee, 0x7f,
+	0x15, 0x50, 0x5a, 0x08, 0xce, 0x1a, 0xcc, 0xaa, 0x22, 0x52, 0x1a, 0x04, 0x46, 0xbf, 0xf2, 0x44,
+	0x16, 0xa1, 0xda, 0x68, 0x3d, 0xcf, 0x3a, 0xa9, 0xa6, 0x43, 0x56, 0x70, 0xd6, 0x80, 0x1a, 0x09,
+	0xed, 0x91, 0xf9, 0x8c, 0xc1, 0x3a, 0x35, 0x41, 0xd3, 0x79, 0x0d, 0x2f, 0x05, 0x8b, 0xee, 0xf0,
+	0xc8, 0x55, 0x47, 0xef, 0xea, 0xf3, 0x27, 0x5c, 0xad, 0x74, 0x55, 0x65, 0x69, 0x50, 0x72, 0xd4,
+	0xac, 0xe3, 0x57, 0xd0, 0xf3, 0x39, 0xfb, 0xad, 0xa8, 0x96, 0xe6, 0x80, 0x29, 0xe9, 0x4e, 0xef,
+	0xab, 0x1b, 0xa8, 0x70, 0xfc, 0x7b, 0x1b, 0xec, 0x63, 0x06, 0x42, 0xa0, 0x9d, 0x52, 0x7e, 0xa7,
+	0x31, 0x27, 0x81, 0x8e, 0xc9, 0x0d, 0x98, 0x72, 0xcb, 0xf4, 0xdb, 0x19, 0x78, 0x5f, 0x3f, 0x43,
+	0x8f, 0xbb, 0x9e, 0xf9, 0xdf, 0x50, 0x21, 0x97, 0x39, 0x66, 0x2b, 0x49, 0x25, 0x06, 0x8a, 0x88,
+	0xdc, 0x40, 0x47, 0xe6, 0x4a, 0x96, 0x1e, 0xef, 0xc0, 0xfb, 0xea, 0x59, 0x94, 0xda, 0xd0, 0x82,
+	0xc5, 0x18, 0x54, 0x2c, 0xe4, 0x12, 0x2e, 0x38, 0x46, 0x98, 0x1c, 0x30, 0x64, 0x3c, 0xd9, 0x25,
+	0x19, 0xdd, 0x87, 0x31, 0x0a, 0x19, 0xd2, 0x38, 0xe6, 0x28, 0xd4, 0x72, 0x8c, 0x89, 0x15, 0x9c,
+	0x57, 0xa0, 0x65, 0x85, 0x99, 0xa3, 0x90, 0x97, 0x25, 0x82, 0xbc, 0x06, 0x7b, 0x93, 0x64, 0x71,
+	0x53, 0xa1, 0xde, 0x9e, 0x1d, 0xf4, 0x54, 0xae, 0x86, 0x7c, 0x0c, 0x5d, 0x0d, 0x51, 0xda, 0xf4,
+	0x6e, 0xfa, 0x81, 0xa5, 0x12, 0x3e, 0xe3, 0x92, 0x9c, 0x83, 0xb5, 0xe5, 0x88, 0xea, 0x3c, 0x3c,
+	0xd5, 0xdd, 0x9a, 0x33, 0xf9, 0x19, 0x06, 0xb7, 0x34, 0xcf, 0x8b, 0x10, 0x0b, 0xdc, 0xd0, 0xfd,
+	0x5e, 0x0c, 0x2d, 0xbd, 0x59, 0xef, 0x09, 0xdb, 0xef, 0x55, 0xd1, 0xbb, 0xaa, 0xa6, 0x5a, 0x70,
+	0xff, 0xf6, 0x38, 0x39, 0xfe, 0x12, 0x9c, 0xfb, 0x23, 0x26, 0x16, 0xb4, 0x2f, 0xc5, 0xb5, 0x70,
+	0x5e, 0x10, 0x80, 0xce, 0xbb, 0x8c, 0x6e, 0xf6, 0xe8, 0x18, 0xa4, 0x07, 0xa7, 0xf3, 0x44, 0xe8,
+	0x43, 0x6b, 0x3c, 0x05, 0xf8, 0x67, 0x8c, 0xe4, 0x14, 0xcc, 0xe5, 0x76, 0x5b, 0xe2, 0xcb, 0xb4,
+	0x63, 0x10, 0x1b, 0xac, 0x00, 0xe3, 0x84, 0x63, 0x24, 0x9d, 0xd6, 0xf8, 0x27, 0xf8, 0xe8, 0x01,
+	0x35, 0xe4, 0x15, 0xf4, 0x72, 0x8e, 0x5b, 0xe4, 0x61, 0x92, 0x1f, 0xde, 0xea, 0x37, 0x63, 0x05,
+	0x50, 0xa6, 0xae, 0xf3, 0xc3, 0x5b, 0x32, 0x02, 0x5b, 0xf2, 0x22, 0x8c, 0x71, 0x4f, 0x8b, 0x30,
+	0x2d, 0xbf, 0x9f, 0x7e, 0x00, 0x92, 0x17, 0x73, 0x95, 0x5a, 0x88, 0xcf, 0x3e, 0xc0, 0xd9, 0xbf,
+	0x3e, 0x06, 0xa5, 0x68, 0x3d, 0xf3, 0x9d, 0x17, 0x2a, 0xf8, 0x7e, 0xee, 0x3b, 0x86, 0x32, 0xb5,
+	0xf8, 0x76, 0xe6, 0x3b, 0x2d, 0xd2, 0x87, 0xee, 0x8f, 0xb8, 0x29, 0x9f, 0x84, 0x63, 0xaa, 0x8b,
+	0xf7, 0xeb, 0xb5, 0xef, 0xb4, 0x89, 0x03, 0xf6, 0x9c, 0xa5, 0x34, 0xc9, 0xaa, 0xbb, 0x93, 0xab,
+	0x25, 0xbc, 0x8e, 0x58, 0xfa, 0xf8, 0x94, 0x7d, 0xe3, 0x83, 0x55, 0xc7, 0x7f, 0xb4, 0x2e, 0x7e,
+	0xf0, 0x02, 0x5a, 0xb8, 0x33, 0x85, 0x6d, 0x64, 0xb9, 0xd7, 0xd5, 0xfd, 0xa6, 0xa3, 0xff, 0xa2,
+	0x2f, 0xfe, 0x1e, 0x00, 0x67, 0xc0, 0x4d, 0xed, 0x5a, 0x06, 0x00, 0x00,
 }
@@ -68,5 +68,10 @@ func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig
 	if outbound := session.OutboundFromContext(ctx); outbound != nil {
 		src = outbound.Gateway
 	}
+	if config := sockopt.GetHappyEyeballs(); config != nil && dest.Network == net.Network_TCP && dest.Address.Family().IsDomain() {
+		return dialHappyEyeballs(ctx, src, dest, config, func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+			return effectiveSystemDialer.Dial(ctx, src, dest, sockopt)
+		})
+	}
 	return effectiveSystemDialer.Dial(ctx, src, dest, sockopt)
 }
@@ -0,0 +1,163 @@
+package internet
+
+import (
+	"context"
+	"time"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/features/dns"
+)
+
+const defaultHappyEyeballsDelay = 250 * time.Millisecond
+
+type dnsClientKey int
+
+const dnsClientSessionKey dnsClientKey = 0
+
+// ContextWithDNSClient returns a new context with the given DNS client, which is used for resolving domain
+// destinations in DialSystem.
+func ContextWithDNSClient(ctx context.Context, client dns.Client) context.Context {
+	return context.WithValue(ctx, dnsClientSessionKey, client)
+}
+
+func dnsClientFromContext(ctx context.Context) dns.Client {
+	if client, ok := ctx.Value(dnsClientSessionKey).(dns.Client); ok {
+		return client
+	}
+	return nil
+}
+
+func lookupIP(ctx context.Context, domain string) ([]net.IP, error) {
+	if client := dnsClientFromContext(ctx); client != nil {
+		return client.LookupIP(domain)
+	}
+
+	return net.LookupIP(domain)
+}
+
+// sortIPs interleaves IPv6 and IPv4 addresses, starting with the preferred family, as in RFC 8305 section 4.
+// If src is an IP address, only addresses of the same family are kept.
+func sortIPs(ips []net.IP, preferIPv4 bool, src net.Address) []net.IP {
+	var ip4, ip6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			ip4 = append(ip4, ip)
+		} else {
+			ip6 = append(ip6, ip)
+		}
+	}
+
+	if src != nil && src != net.AnyIP && src.Family().IsIP() {
+		if src.Family().IsIPv4() {
+			ip6 = nil
+		} else {
+			ip4 = nil
+		}
+	}
+
+	first, second := ip6, ip4
+	if preferIPv4 {
+		first, second = ip4, ip6
+	}
+
+	sorted := make([]net.IP, 0, len(first)+len(second))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			sorted = append(sorted, first[i])
+		}
+		if i < len(second) {
+			sorted = append(sorted, second[i])
+		}
+	}
+	return sorted
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dialHappyEyeballs resolves the domain of dest and races connection attempts to its addresses. A new attempt
+// starts when the previous attempt fails, or when it doesn't finish within the configured delay. The first
+// established connection is returned and all others are closed.
+func dialHappyEyeballs(ctx context.Context, src net.Address, dest net.Destination, config *HappyEyeballsConfig, dial func(context.Context, net.Destination) (net.Conn, error)) (net.Conn, error) {
+	ips, err := lookupIP(ctx, dest.Address.Domain())
+	if err != nil {
+		return nil, newError("failed to resolve ", dest.Address).Base(err)
+	}
+	ips = sortIPs(ips, config.PreferIpv4, src)
+	if len(ips) == 0 {
+		return nil, newError("no usable IP for ", dest.Address)
+	}
+
+	delay := time.Duration(config.TryDelayMs) * time.Millisecond
+	if delay == 0 {
+		delay = defaultHappyEyeballsDelay
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan dialResult, len(ips))
+	pending := 0
+	next := 0
+
+	startNext := func() {
+		ipDest := net.Destination{
+			Network: dest.Network,
+			Address: net.IPAddress(ips[next]),
+			Port:    dest.Port,
+		}
+		next++
+		pending++
+		go func() {
+			conn, err := dial(ctx, ipDest)
+			results <- dialResult{conn: conn, err: err}
+		}()
+	}
+
+	// abandon cancels all ongoing attempts, and closes connections that are established anyway.
+	abandon := func() {
+		cancel()
+		go func(pending int) {
+			for ; pending > 0; pending-- {
+				if r := <-results; r.conn != nil {
+					r.conn.Close()
+				}
+			}
+		}(pending)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	startNext()
+	var lastErr error
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				abandon()
+				return r.conn, nil
+			}
+			lastErr = r.err
+			if next < len(ips) {
+				startNext()
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(delay)
+			} else if pending == 0 {
+				cancel()
+				return nil, newError("failed to dial any IP of ", dest.Address).Base(lastErr)
+			}
+		case <-timer.C:
+			if next < len(ips) {
+				startNext()
+				timer.Reset(delay)
+			}
+		case <-ctx.Done():
+			abandon()
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package internet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/features/dns"
+	"v2ray.com/core/testing/servers/tcp"
+	. "v2ray.com/core/transport/internet"
+)
+
+type staticDNSClient struct {
+	ips []net.IP
+}
+
+func (*staticDNSClient) Type() interface{} { return dns.ClientType() }
+func (*staticDNSClient) Start() error      { return nil }
+func (*staticDNSClient) Close() error      { return nil }
+
+func (c *staticDNSClient) LookupIP(domain string) ([]net.IP, error) {
+	return c.ips, nil
+}
+
+// blackholeDialer never finishes dialing IPv6 destinations until the context is done.
+type blackholeDialer struct {
+	DefaultSystemDialer
+}
+
+func (d *blackholeDialer) Dial(ctx context.Context, src net.Address, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
+	if dest.Address.Family().IsIPv6() {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return d.DefaultSystemDialer.Dial(ctx, src, dest, sockopt)
+}
+
+func TestDialHappyEyeballs(t *testing.T) {
+	server := &tcp.Server{}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	UseAlternativeSystemDialer(&blackholeDialer{})
+	defer UseAlternativeSystemDialer(&DefaultSystemDialer{})
+
+	ctx := ContextWithDNSClient(context.Background(), &staticDNSClient{
+		ips: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+	})
+	sockopt := &SocketConfig{
+		HappyEyeballs: &HappyEyeballsConfig{
+			TryDelayMs: 100,
+		},
+	}
+
+	start := time.Now()
+	conn, err := DialSystem(ctx, net.TCPDestination(net.DomainAddress("example.com"), dest.Port), sockopt)
+	common.Must(err)
+	conn.Close()
+
+	if conn.RemoteAddr().String() != "127.0.0.1:"+dest.Port.String() {
+		t.Error("unexpected remote address: ", conn.RemoteAddr())
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 5*time.Second {
+		t.Error("unexpected dial time: ", elapsed)
+	}
+
+	sockopt.HappyEyeballs.PreferIpv4 = true
+	start = time.Now()
+	conn, err = DialSystem(ctx, net.TCPDestination(net.DomainAddress("example.com"), dest.Port), sockopt)
+	common.Must(err)
+	conn.Close()
+
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Error("IPv4 is not tried first: ", elapsed)
+	}
+}
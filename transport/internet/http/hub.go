@@ -126,10 +126,7 @@ func Listen(ctx context.Context, address net.Address, port net.Port, streamSetti
 
 	listener.server = server
 	go func() {
-		tcpListener, err := internet.ListenSystem(ctx, &net.TCPAddr{
-			IP:   address.IP(),
-			Port: int(port),
-		}, streamSettings.SocketSettings)
+		tcpListener, err := internet.ListenSystem(ctx, internet.ToListenAddr(address, port), streamSettings.SocketSettings)
 		if err != nil {
 			newError("failed to listen on", address, ":", port).Base(err).WriteToLog(session.ExportIDToError(ctx))
 			return
@@ -190,6 +190,9 @@ func (w *Writer) Close() error {
 }
 
 func ListenKCP(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (internet.Listener, error) {
+	if address.Family().IsDomain() {
+		return nil, newError("domain address is not allowed for listening mKCP")
+	}
 	return NewListener(ctx, address, port, streamSettings, addConn)
 }
 
@@ -71,6 +71,12 @@ func applyOutboundSocketOptions(network string, address string, fd uintptr, conf
 		}
 	}
 
+	if config.Freebind {
+		if err := syscall.SetsockoptInt(int(fd), syscall.SOL_IP, syscall.IP_FREEBIND, 1); err != nil {
+			return newError("failed to set IP_FREEBIND").Base(err)
+		}
+	}
+
 	return nil
 }
 
@@ -99,6 +105,12 @@ func applyInboundSocketOptions(network string, fd uintptr, config *SocketConfig)
 		}
 	}
 
+	if config.Freebind {
+		if err := syscall.SetsockoptInt(int(fd), syscall.SOL_IP, syscall.IP_FREEBIND, 1); err != nil {
+			return newError("failed to set IP_FREEBIND").Base(err)
+		}
+	}
+
 	if config.ReceiveOriginalDestAddress && isUDPSocket(network) {
 		err1 := syscall.SetsockoptInt(int(fd), syscall.SOL_IPV6, unix.IPV6_RECVORIGDSTADDR, 1)
 		err2 := syscall.SetsockoptInt(int(fd), syscall.SOL_IP, syscall.IP_RECVORIGDSTADDR, 1)
@@ -124,6 +124,14 @@ func (c *packetConnWrapper) Read(p []byte) (int, error) {
 	return n, err
 }
 
+func (c *packetConnWrapper) WriteTo(p []byte, addr net.Addr) (int, error) {
+	return c.conn.WriteTo(p, addr)
+}
+
+func (c *packetConnWrapper) ReadFrom(p []byte) (int, net.Addr, error) {
+	return c.conn.ReadFrom(p)
+}
+
 func (c *packetConnWrapper) SetDeadline(t time.Time) error {
 	return c.conn.SetDeadline(t)
 }
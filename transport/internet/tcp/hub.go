@@ -10,11 +10,15 @@ import (
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/proxyproto"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/transport/internet"
 	"v2ray.com/core/transport/internet/tls"
 )
 
+// proxyProtocolTimeout is the time limit for receiving the PROXY protocol header.
+const proxyProtocolTimeout = time.Second * 8
+
 // Listener is an internet.Listener that listens for TCP connections.
 type Listener struct {
 	listener   net.Listener
@@ -26,10 +30,7 @@ type Listener struct {
 
 // ListenTCP creates a new Listener based on configurations.
 func ListenTCP(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, handler internet.ConnHandler) (internet.Listener, error) {
-	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{
-		IP:   address.IP(),
-		Port: int(port),
-	}, streamSettings.SocketSettings)
+	listener, err := internet.ListenSystem(ctx, internet.ToListenAddr(address, port), streamSettings.SocketSettings)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +77,9 @@ func (v *Listener) keepAccepting() {
 			continue
 		}
 
+		if v.config.AcceptProxyProtocol {
+			conn = proxyproto.NewConn(conn, proxyProtocolTimeout)
+		}
 		if v.tlsConfig != nil {
 			conn = tls.Server(conn, v.tlsConfig)
 		}
@@ -2,6 +2,7 @@ package internet
 
 import (
 	"context"
+	"strings"
 
 	"v2ray.com/core/common/net"
 )
@@ -40,7 +41,7 @@ func ListenTCP(ctx context.Context, address net.Address, port net.Port, settings
 		address = net.LocalHostIP
 	}
 
-	if address.Family().IsDomain() {
+	if address.Family().IsDomain() && !IsUnixSocketAddress(address) {
 		return nil, newError("domain address is not allowed for listening: ", address.Domain())
 	}
 
@@ -56,6 +57,31 @@ func ListenTCP(ctx context.Context, address net.Address, port net.Port, settings
 	return listener, nil
 }
 
+// IsUnixSocketAddress returns true if the address is the path of a Unix domain socket,
+// i.e., a domain that starts with "/", or with "@" for an abstract socket.
+func IsUnixSocketAddress(address net.Address) bool {
+	if !address.Family().IsDomain() {
+		return false
+	}
+	domain := address.Domain()
+	return strings.HasPrefix(domain, "/") || strings.HasPrefix(domain, "@")
+}
+
+// ToListenAddr returns the address for a stream listener on the given address and port.
+// Port is ignored for Unix domain sockets.
+func ToListenAddr(address net.Address, port net.Port) net.Addr {
+	if IsUnixSocketAddress(address) {
+		return &net.UnixAddr{
+			Name: address.Domain(),
+			Net:  "unix",
+		}
+	}
+	return &net.TCPAddr{
+		IP:   address.IP(),
+		Port: int(port),
+	}
+}
+
 // ListenSystem listens on a local address for incoming TCP connections.
 //
 // v2ray:api:beta
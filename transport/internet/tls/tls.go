@@ -39,6 +39,14 @@ func (c *conn) HandshakeAddress() net.Address {
 	return net.ParseAddress(state.ServerName)
 }
 
+// NegotiatedProtocol returns the application protocol negotiated by ALPN, after the handshake is finished.
+func (c *conn) NegotiatedProtocol() (string, error) {
+	if err := c.Handshake(); err != nil {
+		return "", err
+	}
+	return c.Conn.ConnectionState().NegotiatedProtocol, nil
+}
+
 // Client initiates a TLS client handshake on the given connection.
 func Client(c net.Conn, config *tls.Config) net.Conn {
 	tlsConn := tls.Client(c, config)
@@ -113,9 +113,13 @@ func handleInput(ctx context.Context, conn *connEntry, dest net.Destination, cal
 		}
 		timer.Update()
 		for _, b := range mb {
+			source := dest
+			if b.UDP != nil {
+				source = net.DestinationFromAddr(b.UDP)
+			}
 			callback(ctx, &udp.Packet{
 				Payload: b,
-				Source:  dest,
+				Source:  source,
 			})
 		}
 	}
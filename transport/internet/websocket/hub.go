@@ -95,10 +95,7 @@ func ListenWS(ctx context.Context, address net.Address, port net.Port, streamSet
 }
 
 func listenTCP(ctx context.Context, address net.Address, port net.Port, tlsConfig *tls.Config, sockopt *internet.SocketConfig) (net.Listener, error) {
-	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{
-		IP:   address.IP(),
-		Port: int(port),
-	}, sockopt)
+	listener, err := internet.ListenSystem(ctx, internet.ToListenAddr(address, port), sockopt)
 	if err != nil {
 		return nil, newError("failed to listen TCP on", address, ":", port).Base(err)
 	}